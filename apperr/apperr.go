@@ -0,0 +1,310 @@
+// Package apperr holds the errors the API reports to its callers. Every error response carries one of the codes below,
+// they are part of the API and must not be renamed; the HTTP status of each code is fixed in statuses.
+package apperr
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
+)
+
+type Code string
+
+const (
+	BadRequest       Code = "BAD_REQUEST"
+	InvalidBody      Code = "INVALID_BODY"
+	InvalidInput     Code = "INVALID_INPUT"
+	ValidationFailed Code = "VALIDATION_FAILED"
+
+	Unauthorized       Code = "UNAUTHORIZED"
+	InvalidCredentials Code = "INVALID_CREDENTIALS"
+	TokenInvalid       Code = "TOKEN_INVALID"
+	SessionExpired     Code = "SESSION_EXPIRED"
+	APIKeyInvalid      Code = "API_KEY_INVALID"
+	APIKeyExpired      Code = "API_KEY_EXPIRED"
+
+	Forbidden             Code = "FORBIDDEN"
+	PermissionDenied      Code = "PERMISSION_DENIED"
+	ScopeMissing          Code = "SCOPE_MISSING"
+	IPNotAllowed          Code = "IP_NOT_ALLOWED"
+	AccountBlocked        Code = "ACCOUNT_BLOCKED"
+	EmailNotAuthorized    Code = "EMAIL_NOT_AUTHORIZED"
+	PasswordLoginDisabled Code = "PASSWORD_LOGIN_DISABLED"
+	NotApprover           Code = "NOT_APPROVER"
+
+	NotFound                 Code = "NOT_FOUND"
+	AssetNotFound            Code = "ASSET_NOT_FOUND"
+	EmployeeNotFound         Code = "EMPLOYEE_NOT_FOUND"
+	UserNotFound             Code = "USER_NOT_FOUND"
+	LocationNotFound         Code = "LOCATION_NOT_FOUND"
+	DepartmentNotFound       Code = "DEPARTMENT_NOT_FOUND"
+	HandoverNotFound         Code = "HANDOVER_NOT_FOUND"
+	ReservationNotFound      Code = "RESERVATION_NOT_FOUND"
+	EquipmentRequestNotFound Code = "EQUIPMENT_REQUEST_NOT_FOUND"
+	APIKeyNotFound           Code = "API_KEY_NOT_FOUND"
+	TokenNotFound            Code = "TOKEN_NOT_FOUND"
+	SyncRunNotFound          Code = "SYNC_RUN_NOT_FOUND"
+	WebhookNotFound          Code = "WEBHOOK_NOT_FOUND"
+	SSONotConfigured         Code = "SSO_NOT_CONFIGURED"
+
+	Conflict             Code = "CONFLICT"
+	AlreadyExists        Code = "ALREADY_EXISTS"
+	InUse                Code = "IN_USE"
+	ReferenceNotFound    Code = "REFERENCE_NOT_FOUND"
+	AssetAlreadyAssigned Code = "ASSET_ALREADY_ASSIGNED"
+	AssetNotAssigned     Code = "ASSET_NOT_ASSIGNED"
+	AssetReserved        Code = "ASSET_RESERVED"
+	AlreadyAcknowledged  Code = "ALREADY_ACKNOWLEDGED"
+	InvalidState         Code = "INVALID_STATE"
+
+	PayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	Internal        Code = "INTERNAL_ERROR"
+)
+
+var statuses = map[Code]int{
+	BadRequest:       http.StatusBadRequest,
+	InvalidBody:      http.StatusBadRequest,
+	InvalidInput:     http.StatusBadRequest,
+	ValidationFailed: http.StatusBadRequest,
+
+	Unauthorized:       http.StatusUnauthorized,
+	InvalidCredentials: http.StatusUnauthorized,
+	TokenInvalid:       http.StatusUnauthorized,
+	SessionExpired:     http.StatusUnauthorized,
+	APIKeyInvalid:      http.StatusUnauthorized,
+	APIKeyExpired:      http.StatusUnauthorized,
+
+	Forbidden:             http.StatusForbidden,
+	PermissionDenied:      http.StatusForbidden,
+	ScopeMissing:          http.StatusForbidden,
+	IPNotAllowed:          http.StatusForbidden,
+	AccountBlocked:        http.StatusForbidden,
+	EmailNotAuthorized:    http.StatusForbidden,
+	PasswordLoginDisabled: http.StatusForbidden,
+	NotApprover:           http.StatusForbidden,
+
+	NotFound:                 http.StatusNotFound,
+	AssetNotFound:            http.StatusNotFound,
+	EmployeeNotFound:         http.StatusNotFound,
+	UserNotFound:             http.StatusNotFound,
+	LocationNotFound:         http.StatusNotFound,
+	DepartmentNotFound:       http.StatusNotFound,
+	HandoverNotFound:         http.StatusNotFound,
+	ReservationNotFound:      http.StatusNotFound,
+	EquipmentRequestNotFound: http.StatusNotFound,
+	APIKeyNotFound:           http.StatusNotFound,
+	TokenNotFound:            http.StatusNotFound,
+	SyncRunNotFound:          http.StatusNotFound,
+	WebhookNotFound:          http.StatusNotFound,
+	SSONotConfigured:         http.StatusNotFound,
+
+	Conflict:             http.StatusConflict,
+	AlreadyExists:        http.StatusConflict,
+	InUse:                http.StatusConflict,
+	ReferenceNotFound:    http.StatusConflict,
+	AssetAlreadyAssigned: http.StatusConflict,
+	AssetNotAssigned:     http.StatusConflict,
+	AssetReserved:        http.StatusConflict,
+	AlreadyAcknowledged:  http.StatusConflict,
+	InvalidState:         http.StatusConflict,
+
+	PayloadTooLarge: http.StatusRequestEntityTooLarge,
+	Internal:        http.StatusInternalServerError,
+}
+
+// Status is the HTTP status responses with this code are sent with
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Codes lists every code, sorted
+func Codes() []string {
+	codes := make([]string, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// FieldError explains why one field of a request body was rejected, Field is its JSON path such as items[0].name
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error is an error with the code and message to report to the caller, Err is the cause kept for the logs. Details
+// carries anything else the caller needs to act on the error, such as the reservations a new one conflicts with
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Details interface{}
+	Err     error
+	// status overrides the status of the code for errors reported with a status no code has, such as 502
+	status int
+}
+
+func New(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Status() int {
+	if e.status != 0 {
+		return e.status
+	}
+	return e.Code.Status()
+}
+
+// From classifies an error: missing rows, constraint violations, malformed bodies and failed validation get their own
+// codes and anything else is internal
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return &Error{Code: ValidationFailed, Message: "request validation failed.", Fields: Fields(validationErrs), Err: err}
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return New(NotFound, "not found.", err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code.Name() {
+		case "unique_violation":
+			return New(AlreadyExists, "a record with these details already exists.", err)
+		case "foreign_key_violation":
+			if strings.Contains(pqErr.Message, "update or delete") {
+				return New(InUse, "the record is still referenced by other records.", err)
+			}
+			return New(ReferenceNotFound, "a referenced record does not exist.", err)
+		case "invalid_text_representation", "check_violation", "not_null_violation", "invalid_datetime_format",
+			"datetime_field_overflow", "string_data_right_truncation":
+			return New(InvalidInput, "invalid input.", err)
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return New(InvalidBody, "request body is not valid JSON for this request.", err)
+	}
+	return New(Internal, "internal server error.", err)
+}
+
+// FromStatus builds the error for a handler that reports a status and a message. A 5xx whose cause has a code of its
+// own, such as a missing row, takes that code so callers see a 404 rather than a 500
+func FromStatus(status int, err error, message string) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	if err != nil {
+		classified := From(err)
+		switch {
+		case status >= http.StatusInternalServerError && classified.Code != Internal,
+			status == http.StatusBadRequest && classified.Code.Status() == http.StatusBadRequest:
+			return &Error{Code: classified.Code, Message: message, Fields: classified.Fields, Err: err}
+		}
+	}
+
+	code := codeForStatus(status)
+	e := &Error{Code: code, Message: message, Err: err}
+	if code.Status() != status {
+		e.status = status
+	}
+	return e
+}
+
+func codeForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusConflict:
+		return Conflict
+	case http.StatusRequestEntityTooLarge:
+		return PayloadTooLarge
+	}
+	if status >= http.StatusInternalServerError {
+		return Internal
+	}
+	return BadRequest
+}
+
+// Fields turns validator errors into field errors, field names are the ones the validator was told to report
+func Fields(errs validator.ValidationErrors) []FieldError {
+	fields := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		field := fe.Namespace()
+		if i := strings.Index(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		fields = append(fields, FieldError{Field: field, Rule: fe.Tag(), Message: fieldMessage(fe)})
+	}
+	return fields
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_with", "required_without":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url", "uri":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid id"
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "len":
+		return "must have length " + fe.Param()
+	case "gtefield":
+		return "must not be less than " + fe.Param()
+	case "numeric":
+		return "must be a number"
+	case "base64":
+		return "must be base64 encoded"
+	case "ip", "ipv4", "ipv6", "cidr":
+		return "must be a valid " + fe.Tag() + " address"
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
@@ -0,0 +1,2311 @@
+// Code generated by openapi-gen from the v2 OpenAPI document. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type APIKey struct {
+	AllowedIps []string   `json:"allowedIps,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	LastUsedIp *string    `json:"lastUsedIp,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	Scopes     []string   `json:"scopes,omitempty"`
+}
+
+type AccessedByDetails struct {
+	AuthenticationTimes int        `json:"authenticationTimes,omitempty"`
+	Email               string     `json:"email,omitempty"`
+	ID                  string     `json:"id,omitempty"`
+	LastLoginTime       *time.Time `json:"lastLoginTime,omitempty"`
+	Name                string     `json:"name,omitempty"`
+	Status              string     `json:"status,omitempty"`
+}
+
+type ApprovalStep struct {
+	ApproverID *string `json:"approverId,omitempty"`
+	AssetType  *string `json:"assetType,omitempty"`
+	ID         string  `json:"id,omitempty"`
+	Name       string  `json:"name"`
+	StepOrder  int     `json:"stepOrder"`
+}
+
+type Asset struct {
+	AssetType    string `json:"assetType"`
+	DeleteReason string `json:"deleteReason,omitempty"`
+	ID           string `json:"id"`
+}
+
+type AssetHistory struct {
+	AcknowledgedAt  *time.Time `json:"acknowledgedAt,omitempty"`
+	AssetType       string     `json:"assetType,omitempty"`
+	AssignedDate    time.Time  `json:"assignedDate,omitempty"`
+	Brand           string     `json:"brand,omitempty"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	ID              string     `json:"id,omitempty"`
+	Model           string     `json:"model,omitempty"`
+	RetrievalReason string     `json:"retrievalReason,omitempty"`
+	RetrievedDate   *time.Time `json:"retrievedDate,omitempty"`
+	SerialNo        string     `json:"serialNo,omitempty"`
+}
+
+type AssetReport struct {
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+type AssetReportDetails struct {
+	AssetID      string     `json:"assetId,omitempty"`
+	AssetTag     string     `json:"assetTag,omitempty"`
+	Brand        string     `json:"brand,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt,omitempty"`
+	Description  string     `json:"description,omitempty"`
+	EmployeeID   string     `json:"employeeId,omitempty"`
+	EmployeeName string     `json:"employeeName,omitempty"`
+	ID           string     `json:"id,omitempty"`
+	Model        string     `json:"model,omitempty"`
+	Resolution   *string    `json:"resolution,omitempty"`
+	ResolvedAt   *time.Time `json:"resolvedAt,omitempty"`
+	ResolvedBy   *string    `json:"resolvedBy,omitempty"`
+	Status       string     `json:"status,omitempty"`
+	Type         string     `json:"type,omitempty"`
+}
+
+type AssetRetrievalDetails struct {
+	AssetID         string    `json:"assetId,omitempty"`
+	EmployeeID      string    `json:"employeeId,omitempty"`
+	LocationID      *string   `json:"locationId,omitempty"`
+	RetrievalReason string    `json:"retrievalReason,omitempty"`
+	RetrievedDate   time.Time `json:"retrievedDate,omitempty"`
+}
+
+type AssetTransfer struct {
+	AssetID      string  `json:"assetId"`
+	Note         string  `json:"note,omitempty"`
+	ToLocationID *string `json:"toLocationId,omitempty"`
+}
+
+type AssetTransferHistory struct {
+	AssetID          string    `json:"assetId,omitempty"`
+	FromLocationID   *string   `json:"fromLocationId,omitempty"`
+	FromLocationName *string   `json:"fromLocationName,omitempty"`
+	ID               string    `json:"id,omitempty"`
+	Note             string    `json:"note,omitempty"`
+	ToLocationID     *string   `json:"toLocationId,omitempty"`
+	ToLocationName   *string   `json:"toLocationName,omitempty"`
+	TransferredAt    time.Time `json:"transferredAt,omitempty"`
+	TransferredBy    *string   `json:"transferredBy,omitempty"`
+}
+
+type AssignAssetDetails struct {
+	Brand    string `json:"brand,omitempty"`
+	ID       string `json:"id,omitempty"`
+	Imei1    string `json:"imei1,omitempty"`
+	Model    string `json:"model,omitempty"`
+	SerialNo string `json:"serialNo,omitempty"`
+	SimNo    string `json:"simNo,omitempty"`
+}
+
+type ClientError struct {
+	Code          string          `json:"code,omitempty"`
+	Details       json.RawMessage `json:"details,omitempty"`
+	DeveloperInfo string          `json:"developerInfo,omitempty"`
+	Error         string          `json:"error,omitempty"`
+	Fields        []FieldError    `json:"fields,omitempty"`
+	ID            string          `json:"id,omitempty"`
+	IsClientError bool            `json:"isClientError,omitempty"`
+	MessageToUser string          `json:"messageToUser,omitempty"`
+	StatusCode    int             `json:"statusCode,omitempty"`
+}
+
+type ConsistencyRepair struct {
+	Action string   `json:"action,omitempty"`
+	Check  string   `json:"check,omitempty"`
+	Ids    []string `json:"ids,omitempty"`
+}
+
+type ConsistencyReport struct {
+	DryRun          bool                `json:"dryRun,omitempty"`
+	Inconsistencies []Inconsistency     `json:"inconsistencies,omitempty"`
+	Repairs         []ConsistencyRepair `json:"repairs,omitempty"`
+}
+
+type ConvertReservation struct {
+	AssetID        string     `json:"assetId,omitempty"`
+	AssignedDate   time.Time  `json:"assignedDate,omitempty"`
+	AssignmentType string     `json:"assignmentType,omitempty"`
+	DueDate        *time.Time `json:"dueDate,omitempty"`
+	EmployeeID     string     `json:"employeeId,omitempty"`
+}
+
+type CreateAPIKey struct {
+	AllowedIps []string   `json:"allowedIps,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+}
+
+type CreateAsset struct {
+	ArchiveReason      *string           `json:"archiveReason,omitempty"`
+	ArchivedAt         *time.Time        `json:"archivedAt,omitempty"`
+	AssetHistory       []EmployeeHistory `json:"assetHistory,omitempty"`
+	AssetTag           string            `json:"assetTag,omitempty"`
+	AssetType          string            `json:"assetType"`
+	Brand              string            `json:"brand"`
+	Charger            bool              `json:"charger,omitempty"`
+	ClientName         string            `json:"clientName,omitempty"`
+	DeletedBy          *string           `json:"deletedBy,omitempty"`
+	Imei1              string            `json:"imei1,omitempty"`
+	Imei2              string            `json:"imei2,omitempty"`
+	LocationID         *string           `json:"locationId,omitempty"`
+	Model              string            `json:"model,omitempty"`
+	OperatingSystem    string            `json:"operatingSystem,omitempty"`
+	OsType             string            `json:"osType,omitempty"`
+	OwnedBy            string            `json:"ownedBy,omitempty"`
+	PhoneNo            string            `json:"phoneNo,omitempty"`
+	Processor          string            `json:"processor,omitempty"`
+	PurchasedDate      time.Time         `json:"purchasedDate"`
+	Ram                string            `json:"ram,omitempty"`
+	ScreenResolution   string            `json:"screenResolution,omitempty"`
+	SerialNo           string            `json:"serialNo,omitempty"`
+	Series             string            `json:"series,omitempty"`
+	SimNo              string            `json:"simNo,omitempty"`
+	Status             string            `json:"status,omitempty"`
+	Storage            string            `json:"storage,omitempty"`
+	WarrantyExpiryDate time.Time         `json:"warrantyExpiryDate"`
+	WarrantyStartDate  time.Time         `json:"warrantyStartDate"`
+}
+
+type CreateHandover struct {
+	Accessories    []string `json:"accessories,omitempty"`
+	AssetID        string   `json:"assetId"`
+	ConditionNotes string   `json:"conditionNotes,omitempty"`
+	EmployeeID     string   `json:"employeeId"`
+	Kind           string   `json:"kind"`
+}
+
+type CreateWebhookSubscription struct {
+	EventTypes []string `json:"eventTypes"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+}
+
+type Department struct {
+	ID       string  `json:"id,omitempty"`
+	Name     string  `json:"name"`
+	ParentID *string `json:"parentId,omitempty"`
+}
+
+type DepartmentDetails struct {
+	CreatedAt     time.Time `json:"createdAt,omitempty"`
+	EmployeeCount int       `json:"employeeCount,omitempty"`
+	ID            string    `json:"id,omitempty"`
+	Name          string    `json:"name"`
+	ParentID      *string   `json:"parentId,omitempty"`
+	ParentName    *string   `json:"parentName,omitempty"`
+}
+
+type DepartmentQuantity struct {
+	Employees        int     `json:"employees,omitempty"`
+	HardDiskQuantity int     `json:"hardDiskQuantity,omitempty"`
+	HeldAssets       int     `json:"heldAssets,omitempty"`
+	ID               string  `json:"id,omitempty"`
+	LaptopQuantity   int     `json:"laptopQuantity,omitempty"`
+	MobileQuantity   int     `json:"mobileQuantity,omitempty"`
+	MouseQuantity    int     `json:"mouseQuantity,omitempty"`
+	Name             string  `json:"name,omitempty"`
+	ParentID         *string `json:"parentId,omitempty"`
+	PenDriveQuantity int     `json:"penDriveQuantity,omitempty"`
+	SimQuantity      int     `json:"simQuantity,omitempty"`
+}
+
+type Employee struct {
+	ArchiveReason string `json:"archiveReason,omitempty"`
+}
+
+type EmployeeAccount struct {
+	Password string `json:"password"`
+}
+
+type EmployeeAssetRelation struct {
+	AssetID            string     `json:"assetId,omitempty"`
+	AssignedDate       time.Time  `json:"assignedDate,omitempty"`
+	AssignmentType     string     `json:"assignmentType,omitempty"`
+	DueDate            *time.Time `json:"dueDate,omitempty"`
+	EmployeeID         string     `json:"employeeId,omitempty"`
+	EquipmentRequestID *string    `json:"equipmentRequestId,omitempty"`
+}
+
+type EmployeeDetails struct {
+	DepartmentID *string `json:"departmentId,omitempty"`
+	Email        string  `json:"email"`
+	ID           string  `json:"id,omitempty"`
+	ManagerID    *string `json:"managerId,omitempty"`
+	Name         string  `json:"name"`
+	PhoneNo      string  `json:"phoneNo"`
+	Status       string  `json:"status,omitempty"`
+	Type         string  `json:"type"`
+}
+
+type EmployeeHistory struct {
+	AssetType       string     `json:"assetType,omitempty"`
+	AssignedBy      string     `json:"assignedBy,omitempty"`
+	AssignedDate    string     `json:"assignedDate,omitempty"`
+	Email           string     `json:"email,omitempty"`
+	ID              string     `json:"id,omitempty"`
+	Name            string     `json:"name,omitempty"`
+	PhoneNo         string     `json:"phoneNo,omitempty"`
+	RetrievalReason string     `json:"retrievalReason,omitempty"`
+	RetrievedDate   *time.Time `json:"retrievedDate,omitempty"`
+}
+
+type EmployeePasswordChange struct {
+	CurrentPassword string `json:"currentPassword"`
+	Password        string `json:"password"`
+}
+
+type EquipmentRequest struct {
+	AssetType     string     `json:"assetType"`
+	EmployeeID    string     `json:"employeeId,omitempty"`
+	Justification string     `json:"justification"`
+	NeededBy      *time.Time `json:"neededBy,omitempty"`
+}
+
+type EquipmentRequestApproval struct {
+	ApproverID *string    `json:"approverId,omitempty"`
+	Comment    *string    `json:"comment,omitempty"`
+	DecidedAt  *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy  *string    `json:"decidedBy,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	RequestID  string     `json:"requestId,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	StepName   string     `json:"stepName,omitempty"`
+	StepOrder  int        `json:"stepOrder,omitempty"`
+}
+
+type EquipmentRequestDecision struct {
+	Comment string `json:"comment,omitempty"`
+	Status  string `json:"status"`
+}
+
+type EquipmentRequestDetails struct {
+	Approvals     []EquipmentRequestApproval `json:"approvals,omitempty"`
+	AssetType     string                     `json:"assetType,omitempty"`
+	CreatedAt     time.Time                  `json:"createdAt,omitempty"`
+	CurrentStep   *string                    `json:"currentStep,omitempty"`
+	EmployeeID    string                     `json:"employeeId,omitempty"`
+	EmployeeName  string                     `json:"employeeName,omitempty"`
+	FulfilledAt   *time.Time                 `json:"fulfilledAt,omitempty"`
+	FulfilledBy   *string                    `json:"fulfilledBy,omitempty"`
+	ID            string                     `json:"id,omitempty"`
+	Justification string                     `json:"justification,omitempty"`
+	NeededBy      *time.Time                 `json:"neededBy,omitempty"`
+	RelationID    *string                    `json:"relationId,omitempty"`
+	RequestedBy   *string                    `json:"requestedBy,omitempty"`
+	Status        string                     `json:"status,omitempty"`
+	UpdatedAt     *time.Time                 `json:"updatedAt,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+}
+
+type GetAsset struct {
+	AssetTag           string    `json:"assetTag,omitempty"`
+	AssetType          string    `json:"assetType,omitempty"`
+	AssignedTo         *string   `json:"assignedTo,omitempty"`
+	AssignedToID       *string   `json:"assignedToId,omitempty"`
+	Brand              string    `json:"brand,omitempty"`
+	ID                 string    `json:"id,omitempty"`
+	Location           *string   `json:"location,omitempty"`
+	Model              string    `json:"model,omitempty"`
+	PurchasedDate      time.Time `json:"purchasedDate,omitempty"`
+	SerialNo           string    `json:"serialNo,omitempty"`
+	Status             string    `json:"status,omitempty"`
+	WarrantyExpiryDate time.Time `json:"warrantyExpiryDate,omitempty"`
+	WarrantyStartDate  time.Time `json:"warrantyStartDate,omitempty"`
+}
+
+type GetAssetQuantity struct {
+	AvailableAssets   int `json:"availableAssets,omitempty"`
+	DistributedAssets int `json:"distributedAssets,omitempty"`
+	HardDiskQuantity  int `json:"hardDiskQuantity,omitempty"`
+	LaptopQuantity    int `json:"laptopQuantity,omitempty"`
+	MobileQuantity    int `json:"mobileQuantity,omitempty"`
+	MouseQuantity     int `json:"mouseQuantity,omitempty"`
+	PenDriveQuantity  int `json:"penDriveQuantity,omitempty"`
+	SimQuantity       int `json:"simQuantity,omitempty"`
+	TotalAssets       int `json:"totalAssets,omitempty"`
+}
+
+type GetEmployee struct {
+	ArchiveReason *string        `json:"archiveReason,omitempty"`
+	ArchivedAt    *time.Time     `json:"archivedAt,omitempty"`
+	AssetHistory  []AssetHistory `json:"assetHistory,omitempty"`
+	AssetQuantity int            `json:"assetQuantity,omitempty"`
+	DeletedBy     *string        `json:"deletedBy,omitempty"`
+	Department    *string        `json:"department,omitempty"`
+	DepartmentID  *string        `json:"departmentId,omitempty"`
+	Email         string         `json:"email,omitempty"`
+	ID            string         `json:"id,omitempty"`
+	ManagerID     *string        `json:"managerId,omitempty"`
+	ManagerName   *string        `json:"managerName,omitempty"`
+	Name          string         `json:"name,omitempty"`
+	PhoneNo       string         `json:"phoneNo,omitempty"`
+	Status        string         `json:"status,omitempty"`
+	Type          string         `json:"type,omitempty"`
+}
+
+type Handover struct {
+	Accessories      []string   `json:"accessories,omitempty"`
+	AcknowledgedAt   *time.Time `json:"acknowledgedAt,omitempty"`
+	AcknowledgedIp   *string    `json:"acknowledgedIp,omitempty"`
+	AcknowledgedName *string    `json:"acknowledgedName,omitempty"`
+	AssetID          string     `json:"assetId,omitempty"`
+	AssetTag         string     `json:"assetTag,omitempty"`
+	AssetType        string     `json:"assetType,omitempty"`
+	AssignedDate     time.Time  `json:"assignedDate,omitempty"`
+	Brand            string     `json:"brand,omitempty"`
+	ConditionNotes   string     `json:"conditionNotes,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt,omitempty"`
+	CreatedBy        *string    `json:"createdBy,omitempty"`
+	EmployeeEmail    string     `json:"employeeEmail,omitempty"`
+	EmployeeID       string     `json:"employeeId,omitempty"`
+	EmployeeName     string     `json:"employeeName,omitempty"`
+	HasReceipt       bool       `json:"hasReceipt,omitempty"`
+	HasSignature     bool       `json:"hasSignature,omitempty"`
+	ID               string     `json:"id,omitempty"`
+	Kind             string     `json:"kind,omitempty"`
+	Model            string     `json:"model,omitempty"`
+	Photos           []string   `json:"photos,omitempty"`
+	RelationID       string     `json:"relationId,omitempty"`
+	RetrievedDate    *time.Time `json:"retrievedDate,omitempty"`
+	SerialNo         string     `json:"serialNo,omitempty"`
+}
+
+type HandoverAcknowledgement struct {
+	SignatureImage string `json:"signatureImage,omitempty"`
+	TypedName      string `json:"typedName"`
+}
+
+type IDResponse struct {
+	ID  string `json:"id,omitempty"`
+	Msg string `json:"msg,omitempty"`
+}
+
+type Inconsistency struct {
+	Check  string `json:"check,omitempty"`
+	Detail string `json:"detail,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Label  string `json:"label,omitempty"`
+}
+
+type Loan struct {
+	AssetID            string     `json:"assetId,omitempty"`
+	AssetTag           string     `json:"assetTag,omitempty"`
+	AssetType          string     `json:"assetType,omitempty"`
+	AssignedDate       time.Time  `json:"assignedDate,omitempty"`
+	Brand              string     `json:"brand,omitempty"`
+	DueDate            time.Time  `json:"dueDate,omitempty"`
+	Email              string     `json:"email,omitempty"`
+	EmployeeID         string     `json:"employeeId,omitempty"`
+	EmployeeName       string     `json:"employeeName,omitempty"`
+	LastReminderAt     *time.Time `json:"lastReminderAt,omitempty"`
+	Model              string     `json:"model,omitempty"`
+	OverdueDays        int        `json:"overdueDays,omitempty"`
+	PendingExtensionID *string    `json:"pendingExtensionId,omitempty"`
+	RelationID         string     `json:"relationId,omitempty"`
+}
+
+type LoanExtension struct {
+	AssetID          string     `json:"assetId,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt,omitempty"`
+	CurrentDueDate   time.Time  `json:"currentDueDate,omitempty"`
+	DecidedAt        *time.Time `json:"decidedAt,omitempty"`
+	DecidedBy        *string    `json:"decidedBy,omitempty"`
+	EmployeeID       string     `json:"employeeId,omitempty"`
+	EmployeeName     string     `json:"employeeName,omitempty"`
+	ID               string     `json:"id,omitempty"`
+	Reason           string     `json:"reason,omitempty"`
+	RelationID       string     `json:"relationId,omitempty"`
+	RequestedDueDate time.Time  `json:"requestedDueDate,omitempty"`
+	Status           string     `json:"status,omitempty"`
+}
+
+type LoanExtensionDecision struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+type LoanExtensionRequest struct {
+	AssetID          string    `json:"assetId"`
+	Reason           string    `json:"reason,omitempty"`
+	RequestedDueDate time.Time `json:"requestedDueDate"`
+}
+
+type LoanStats struct {
+	ActiveLoans     int     `json:"activeLoans,omitempty"`
+	AverageLoanDays float64 `json:"averageLoanDays,omitempty"`
+	EmployeeID      string  `json:"employeeId,omitempty"`
+	EmployeeName    string  `json:"employeeName,omitempty"`
+	Extensions      int     `json:"extensions,omitempty"`
+	OverdueLoans    int     `json:"overdueLoans,omitempty"`
+	ReturnedLate    int     `json:"returnedLate,omitempty"`
+	TotalLoans      int     `json:"totalLoans,omitempty"`
+}
+
+type Location struct {
+	AssetCount   int       `json:"assetCount,omitempty"`
+	CreatedAt    time.Time `json:"createdAt,omitempty"`
+	EmployeeID   *string   `json:"employeeId,omitempty"`
+	EmployeeName *string   `json:"employeeName,omitempty"`
+	ID           string    `json:"id,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	ParentID     *string   `json:"parentId,omitempty"`
+	ParentName   *string   `json:"parentName,omitempty"`
+	Type         string    `json:"type,omitempty"`
+}
+
+type LocationDashboard struct {
+	Locations       []LocationQuantity `json:"locations,omitempty"`
+	UnknownLocation int                `json:"unknownLocation,omitempty"`
+	WithEmployees   int                `json:"withEmployees,omitempty"`
+}
+
+type LocationDetails struct {
+	ID       string  `json:"id,omitempty"`
+	Name     string  `json:"name"`
+	ParentID *string `json:"parentId,omitempty"`
+	Type     string  `json:"type"`
+}
+
+type LocationQuantity struct {
+	AssetCount int     `json:"assetCount,omitempty"`
+	ID         string  `json:"id,omitempty"`
+	Name       string  `json:"name,omitempty"`
+	ParentID   *string `json:"parentId,omitempty"`
+	Type       string  `json:"type,omitempty"`
+}
+
+type ProvisioningToken struct {
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Name       string     `json:"name"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+type ReassignAsset struct {
+	AssetID         string     `json:"assetId"`
+	AssignedDate    string     `json:"assignedDate"`
+	AssignmentType  string     `json:"assignmentType,omitempty"`
+	DueDate         *time.Time `json:"dueDate,omitempty"`
+	EmployeeID      string     `json:"employeeId"`
+	RetrievalReason string     `json:"retrievalReason"`
+	RetrievedDate   time.Time  `json:"retrievedDate"`
+}
+
+type RegisterUser struct {
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	PhoneNo  string `json:"phoneNo"`
+}
+
+type Reservation struct {
+	AssetID    *string   `json:"assetId,omitempty"`
+	AssetType  string    `json:"assetType,omitempty"`
+	Brand      *string   `json:"brand,omitempty"`
+	EmployeeID *string   `json:"employeeId,omitempty"`
+	EndDate    time.Time `json:"endDate"`
+	HolderName *string   `json:"holderName,omitempty"`
+	ID         string    `json:"id,omitempty"`
+	Model      *string   `json:"model,omitempty"`
+	Purpose    string    `json:"purpose"`
+	StartDate  time.Time `json:"startDate"`
+}
+
+type ReservationDetails struct {
+	AssetID      *string   `json:"assetId,omitempty"`
+	AssetTag     *string   `json:"assetTag,omitempty"`
+	AssetType    string    `json:"assetType,omitempty"`
+	Brand        *string   `json:"brand,omitempty"`
+	CreatedAt    time.Time `json:"createdAt,omitempty"`
+	CreatedBy    string    `json:"createdBy,omitempty"`
+	EmployeeID   *string   `json:"employeeId,omitempty"`
+	EmployeeName *string   `json:"employeeName,omitempty"`
+	EndDate      time.Time `json:"endDate"`
+	HolderName   *string   `json:"holderName,omitempty"`
+	ID           string    `json:"id,omitempty"`
+	Model        *string   `json:"model,omitempty"`
+	Purpose      string    `json:"purpose"`
+	RelationID   *string   `json:"relationId,omitempty"`
+	StartDate    time.Time `json:"startDate"`
+	Status       string    `json:"status,omitempty"`
+}
+
+type ResolveAssetReport struct {
+	ID         string `json:"id"`
+	Resolution string `json:"resolution"`
+}
+
+type ResponseMsg struct {
+	Msg string `json:"msg,omitempty"`
+}
+
+type RestoreAsset struct {
+	ID            string `json:"id"`
+	RestoreReason string `json:"restoreReason"`
+}
+
+type RestoreEmployee struct {
+	RestoreReason string `json:"restoreReason"`
+}
+
+type RestoreRecord struct {
+	ArchiveReason  *string   `json:"archiveReason,omitempty"`
+	ArchivedAt     time.Time `json:"archivedAt,omitempty"`
+	DeletedBy      *string   `json:"deletedBy,omitempty"`
+	DeletedByName  *string   `json:"deletedByName,omitempty"`
+	EntityID       string    `json:"entityId,omitempty"`
+	EntityType     string    `json:"entityType,omitempty"`
+	ID             string    `json:"id,omitempty"`
+	RestoreReason  string    `json:"restoreReason,omitempty"`
+	RestoredAt     time.Time `json:"restoredAt,omitempty"`
+	RestoredBy     string    `json:"restoredBy,omitempty"`
+	RestoredByName string    `json:"restoredByName,omitempty"`
+}
+
+type SyncChange struct {
+	Action     string          `json:"action,omitempty"`
+	Changes    json.RawMessage `json:"changes,omitempty"`
+	Email      string          `json:"email,omitempty"`
+	EmployeeID *string         `json:"employeeId,omitempty"`
+	ExternalID *string         `json:"externalId,omitempty"`
+	ID         string          `json:"id,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	RunID      string          `json:"runId,omitempty"`
+}
+
+type SyncRun struct {
+	Changes          []SyncChange `json:"changes,omitempty"`
+	CreatedCount     int          `json:"createdCount,omitempty"`
+	DeactivatedCount int          `json:"deactivatedCount,omitempty"`
+	DryRun           bool         `json:"dryRun,omitempty"`
+	ErrorCount       int          `json:"errorCount,omitempty"`
+	FileName         *string      `json:"fileName,omitempty"`
+	FinishedAt       *time.Time   `json:"finishedAt,omitempty"`
+	ID               string       `json:"id,omitempty"`
+	Source           string       `json:"source,omitempty"`
+	StartedAt        time.Time    `json:"startedAt,omitempty"`
+	TokenID          *string      `json:"tokenId,omitempty"`
+	TriggeredBy      *string      `json:"triggeredBy,omitempty"`
+	UpdatedCount     int          `json:"updatedCount,omitempty"`
+}
+
+type TokenResponse struct {
+	Token string `json:"token,omitempty"`
+}
+
+type TotalGetAsset struct {
+	GetAsset   []GetAsset `json:"getAsset,omitempty"`
+	NextCursor string     `json:"nextCursor,omitempty"`
+	TotalCount int        `json:"totalCount,omitempty"`
+}
+
+type TotalGetEmployee struct {
+	GetEmployee []GetEmployee `json:"getEmployee,omitempty"`
+	NextCursor  string        `json:"nextCursor,omitempty"`
+	TotalCount  int           `json:"totalCount,omitempty"`
+}
+
+type URLResponse struct {
+	Msg string `json:"msg,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+type UpdateAssetSpecification struct {
+	AssetType          string    `json:"assetType"`
+	Brand              string    `json:"brand"`
+	Charger            bool      `json:"charger,omitempty"`
+	ID                 string    `json:"id"`
+	Imei1              string    `json:"imei1,omitempty"`
+	Imei2              string    `json:"imei2,omitempty"`
+	Model              string    `json:"model,omitempty"`
+	OperatingSystem    string    `json:"operatingSystem,omitempty"`
+	OsType             string    `json:"osType,omitempty"`
+	PhoneNo            string    `json:"phoneNo,omitempty"`
+	Processor          string    `json:"processor,omitempty"`
+	PurchasedDate      time.Time `json:"purchasedDate"`
+	Ram                string    `json:"ram,omitempty"`
+	ScreenResolution   string    `json:"screenResolution,omitempty"`
+	SerialNo           string    `json:"serialNo,omitempty"`
+	Series             string    `json:"series,omitempty"`
+	SimNo              string    `json:"simNo,omitempty"`
+	Storage            string    `json:"storage,omitempty"`
+	WarrantyExpiryDate time.Time `json:"warrantyExpiryDate,omitempty"`
+	WarrantyStartDate  time.Time `json:"warrantyStartDate,omitempty"`
+}
+
+type UpdateWebhookSubscription struct {
+	Active     bool     `json:"active,omitempty"`
+	EventTypes []string `json:"eventTypes"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+}
+
+type UserDetails struct {
+	Email   string `json:"email,omitempty"`
+	Image   string `json:"image,omitempty"`
+	Name    string `json:"name,omitempty"`
+	PhoneNo string `json:"phoneNo,omitempty"`
+}
+
+type UsersLoginDetails struct {
+	Email    string `json:"email,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+type WarrantyDetails struct {
+	AssetID            string    `json:"assetId,omitempty"`
+	WarrantyExpiryDate time.Time `json:"warrantyExpiryDate,omitempty"`
+	WarrantyStartDate  time.Time `json:"warrantyStartDate,omitempty"`
+}
+
+type WebhookAttempt struct {
+	AttemptedAt time.Time `json:"attemptedAt,omitempty"`
+	DurationMs  int       `json:"durationMs,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	StatusCode  *int      `json:"statusCode,omitempty"`
+}
+
+type WebhookDelivery struct {
+	AttemptLog     []WebhookAttempt `json:"attemptLog,omitempty"`
+	Attempts       int              `json:"attempts,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt,omitempty"`
+	DeliveredAt    *time.Time       `json:"deliveredAt,omitempty"`
+	EventID        string           `json:"eventId,omitempty"`
+	EventType      string           `json:"eventType,omitempty"`
+	ID             string           `json:"id,omitempty"`
+	LastError      *string          `json:"lastError,omitempty"`
+	LastStatusCode *int             `json:"lastStatusCode,omitempty"`
+	NextAttemptAt  *time.Time       `json:"nextAttemptAt,omitempty"`
+	Payload        json.RawMessage  `json:"payload,omitempty"`
+	Status         string           `json:"status,omitempty"`
+	SubscriptionID string           `json:"subscriptionId,omitempty"`
+}
+
+type WebhookSubscription struct {
+	Active     bool       `json:"active,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	EventTypes []string   `json:"eventTypes,omitempty"`
+	ID         string     `json:"id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	URL        string     `json:"url,omitempty"`
+}
+
+// AccessedByDetailsParams are the query parameters of AccessedByDetails
+type AccessedByDetailsParams struct {
+	UserType string
+	// Sort field to order by, descending with a leading -, name when not set: authenticationTimes, email, lastLogin, name, status
+	Sort string
+	// Filter comma separated field:op:value conditions, op is eq, ne, lt, lte, gt, gte, contains or in with | separated values
+	Filter string
+	// Cursor nextCursor of the previous page, used instead of page
+	Cursor string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *AccessedByDetailsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.UserType != "" {
+		q.Set("userType", p.UserType)
+	}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	if p.Filter != "" {
+		q.Set("filter", p.Filter)
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// ApplyCSVSyncParams are the query parameters of ApplyCSVSync
+type ApplyCSVSyncParams struct {
+	// DeactivateMissing false keeps employees missing from the file
+	DeactivateMissing *bool
+}
+
+func (p *ApplyCSVSyncParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.DeactivateMissing != nil {
+		q.Set("deactivateMissing", strconv.FormatBool(*p.DeactivateMissing))
+	}
+	return q
+}
+
+// AssetTransfersParams are the query parameters of AssetTransfers
+type AssetTransfersParams struct {
+	AssetID string
+}
+
+func (p *AssetTransfersParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	return q
+}
+
+// AvailableAssetsParams are the query parameters of AvailableAssets
+type AvailableAssetsParams struct {
+	AssetType string
+	Brand     string
+	Model     string
+	// From YYYY-MM-DD
+	From string
+	// To YYYY-MM-DD
+	To string
+}
+
+func (p *AvailableAssetsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetType != "" {
+		q.Set("assetType", p.AssetType)
+	}
+	if p.Brand != "" {
+		q.Set("brand", p.Brand)
+	}
+	if p.Model != "" {
+		q.Set("model", p.Model)
+	}
+	if p.From != "" {
+		q.Set("from", p.From)
+	}
+	if p.To != "" {
+		q.Set("to", p.To)
+	}
+	return q
+}
+
+type CreateAPIKeyResponse struct {
+	ID  string `json:"id,omitempty"`
+	Key string `json:"key,omitempty"`
+	Msg string `json:"msg,omitempty"`
+}
+
+type CreateAssetResponse struct {
+	AssetTag string `json:"assetTag,omitempty"`
+	Msg      string `json:"msg,omitempty"`
+}
+
+type CreateProvisioningTokenResponse struct {
+	ID    string `json:"id,omitempty"`
+	Msg   string `json:"msg,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+type CreateWebhookSubscriptionResponse struct {
+	ID     string `json:"id,omitempty"`
+	Msg    string `json:"msg,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+type DecideEquipmentRequestResponse struct {
+	Msg    string `json:"msg,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// EmployeeHistoryParams are the query parameters of EmployeeHistory
+type EmployeeHistoryParams struct {
+	AssetID string
+}
+
+func (p *EmployeeHistoryParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	return q
+}
+
+// GetAssetHistoryParams are the query parameters of GetAssetHistory
+type GetAssetHistoryParams struct {
+	EmployeeID string
+}
+
+func (p *GetAssetHistoryParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	return q
+}
+
+// GetAssetLabelsParams are the query parameters of GetAssetLabels
+type GetAssetLabelsParams struct {
+	// AssetIds comma separated, every asset when left out
+	AssetIds string
+	// Format pdf or zpl
+	Format string
+}
+
+func (p *GetAssetLabelsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetIds != "" {
+		q.Set("assetIds", p.AssetIds)
+	}
+	if p.Format != "" {
+		q.Set("format", p.Format)
+	}
+	return q
+}
+
+// GetAssetListParams are the query parameters of GetAssetList
+type GetAssetListParams struct {
+	// Name search by brand, model or serial number
+	Name string
+	// AssetType comma separated asset types
+	AssetType string
+	// Available only unassigned assets
+	Available *bool
+	// Assigned only assigned assets
+	Assigned *bool
+	// Deleted only deleted assets
+	Deleted *bool
+	// Warranty warranty expiring within this many days, 0 for expired
+	Warranty     *int
+	LocationID   string
+	DepartmentID string
+	// Sort field to order by, descending with a leading -, purchaseDate when not set: assetType, assignee, brand, location, model, purchaseDate, status, warrantyExpiry
+	Sort string
+	// Filter comma separated field:op:value conditions, op is eq, ne, lt, lte, gt, gte, contains or in with | separated values
+	Filter string
+	// Cursor nextCursor of the previous page, used instead of page
+	Cursor string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetAssetListParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Name != "" {
+		q.Set("name", p.Name)
+	}
+	if p.AssetType != "" {
+		q.Set("assetType", p.AssetType)
+	}
+	if p.Available != nil {
+		q.Set("available", strconv.FormatBool(*p.Available))
+	}
+	if p.Assigned != nil {
+		q.Set("assigned", strconv.FormatBool(*p.Assigned))
+	}
+	if p.Deleted != nil {
+		q.Set("deleted", strconv.FormatBool(*p.Deleted))
+	}
+	if p.Warranty != nil {
+		q.Set("warranty", strconv.Itoa(*p.Warranty))
+	}
+	if p.LocationID != "" {
+		q.Set("locationId", p.LocationID)
+	}
+	if p.DepartmentID != "" {
+		q.Set("departmentId", p.DepartmentID)
+	}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	if p.Filter != "" {
+		q.Set("filter", p.Filter)
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// GetAssetReportsParams are the query parameters of GetAssetReports
+type GetAssetReportsParams struct {
+	Status     string
+	EmployeeID string
+}
+
+func (p *GetAssetReportsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	return q
+}
+
+// GetAssetRestoresParams are the query parameters of GetAssetRestores
+type GetAssetRestoresParams struct {
+	AssetID string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetAssetRestoresParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// GetAssetSpecParams are the query parameters of GetAssetSpec
+type GetAssetSpecParams struct {
+	AssetID   string
+	AssetType string
+}
+
+func (p *GetAssetSpecParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	if p.AssetType != "" {
+		q.Set("assetType", p.AssetType)
+	}
+	return q
+}
+
+// GetDashboardParams are the query parameters of GetDashboard
+type GetDashboardParams struct {
+	// DashBoardFilter total, available or assigned
+	DashBoardFilter string
+}
+
+func (p *GetDashboardParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.DashBoardFilter != "" {
+		q.Set("dashBoardFilter", p.DashBoardFilter)
+	}
+	return q
+}
+
+// GetEmployeeListParams are the query parameters of GetEmployeeList
+type GetEmployeeListParams struct {
+	// Name search by name or email
+	Name string
+	// NotAnEmployee only former employees
+	NotAnEmployee *bool
+	LocationID    string
+	DepartmentID  string
+	// Sort field to order by, descending with a leading -, createdAt when not set: assetQuantity, createdAt, department, email, manager, name, status, type
+	Sort string
+	// Filter comma separated field:op:value conditions, op is eq, ne, lt, lte, gt, gte, contains or in with | separated values
+	Filter string
+	// Cursor nextCursor of the previous page, used instead of page
+	Cursor string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetEmployeeListParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Name != "" {
+		q.Set("name", p.Name)
+	}
+	if p.NotAnEmployee != nil {
+		q.Set("notAnEmployee", strconv.FormatBool(*p.NotAnEmployee))
+	}
+	if p.LocationID != "" {
+		q.Set("locationId", p.LocationID)
+	}
+	if p.DepartmentID != "" {
+		q.Set("departmentId", p.DepartmentID)
+	}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	if p.Filter != "" {
+		q.Set("filter", p.Filter)
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// GetEmployeeRestoresParams are the query parameters of GetEmployeeRestores
+type GetEmployeeRestoresParams struct {
+	EmployeeID string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetEmployeeRestoresParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// GetEquipmentRequestsParams are the query parameters of GetEquipmentRequests
+type GetEquipmentRequestsParams struct {
+	EmployeeID string
+	Status     string
+}
+
+func (p *GetEquipmentRequestsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	return q
+}
+
+// GetHandoversParams are the query parameters of GetHandovers
+type GetHandoversParams struct {
+	AssetID    string
+	EmployeeID string
+}
+
+func (p *GetHandoversParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	return q
+}
+
+// GetLoanExtensionsParams are the query parameters of GetLoanExtensions
+type GetLoanExtensionsParams struct {
+	Status string
+}
+
+func (p *GetLoanExtensionsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	return q
+}
+
+// GetLoanStatsParams are the query parameters of GetLoanStats
+type GetLoanStatsParams struct {
+	EmployeeID string
+}
+
+func (p *GetLoanStatsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.EmployeeID != "" {
+		q.Set("employeeId", p.EmployeeID)
+	}
+	return q
+}
+
+// GetLocationsParams are the query parameters of GetLocations
+type GetLocationsParams struct {
+	Type string
+}
+
+func (p *GetLocationsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Type != "" {
+		q.Set("type", p.Type)
+	}
+	return q
+}
+
+type GetLoginOptionsResponse struct {
+	Password bool `json:"password,omitempty"`
+	Sso      bool `json:"sso,omitempty"`
+}
+
+// GetOwnAssetsParams are the query parameters of GetOwnAssets
+type GetOwnAssetsParams struct {
+	// Current only assets still held
+	Current *bool
+}
+
+func (p *GetOwnAssetsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Current != nil {
+		q.Set("current", strconv.FormatBool(*p.Current))
+	}
+	return q
+}
+
+// GetOwnEquipmentRequestsParams are the query parameters of GetOwnEquipmentRequests
+type GetOwnEquipmentRequestsParams struct {
+	Status string
+}
+
+func (p *GetOwnEquipmentRequestsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	return q
+}
+
+// GetReservationCalendarParams are the query parameters of GetReservationCalendar
+type GetReservationCalendarParams struct {
+	// From YYYY-MM-DD
+	From string
+	// To YYYY-MM-DD
+	To string
+}
+
+func (p *GetReservationCalendarParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.From != "" {
+		q.Set("from", p.From)
+	}
+	if p.To != "" {
+		q.Set("to", p.To)
+	}
+	return q
+}
+
+// GetReservationsParams are the query parameters of GetReservations
+type GetReservationsParams struct {
+	AssetID string
+	Status  string
+	// From YYYY-MM-DD
+	From string
+	// To YYYY-MM-DD
+	To string
+}
+
+func (p *GetReservationsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.AssetID != "" {
+		q.Set("assetId", p.AssetID)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.From != "" {
+		q.Set("from", p.From)
+	}
+	if p.To != "" {
+		q.Set("to", p.To)
+	}
+	return q
+}
+
+// GetSyncRunsParams are the query parameters of GetSyncRuns
+type GetSyncRunsParams struct {
+	// Source scim or csv
+	Source string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetSyncRunsParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Source != "" {
+		q.Set("source", p.Source)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+// GetWebhookDeliveriesParams are the query parameters of GetWebhookDeliveries
+type GetWebhookDeliveriesParams struct {
+	SubscriptionID string
+	// Status pending, delivered or dead
+	Status    string
+	EventType string
+	// Limit page size
+	Limit *int
+	// Page zero based page number
+	Page *int
+	// Pagination false returns every row
+	Pagination *bool
+}
+
+func (p *GetWebhookDeliveriesParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.SubscriptionID != "" {
+		q.Set("subscriptionId", p.SubscriptionID)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.EventType != "" {
+		q.Set("eventType", p.EventType)
+	}
+	if p.Limit != nil {
+		q.Set("limit", strconv.Itoa(*p.Limit))
+	}
+	if p.Page != nil {
+		q.Set("page", strconv.Itoa(*p.Page))
+	}
+	if p.Pagination != nil {
+		q.Set("pagination", strconv.FormatBool(*p.Pagination))
+	}
+	return q
+}
+
+type HealthResponse struct {
+	Status string `json:"status,omitempty"`
+}
+
+type LivenessResponse struct {
+	Status string `json:"status,omitempty"`
+}
+
+// LookupAssetParams are the query parameters of LookupAsset
+type LookupAssetParams struct {
+	Code string
+}
+
+func (p *LookupAssetParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Code != "" {
+		q.Set("code", p.Code)
+	}
+	return q
+}
+
+// PreviewCSVSyncParams are the query parameters of PreviewCSVSync
+type PreviewCSVSyncParams struct {
+	// DeactivateMissing false keeps employees missing from the file
+	DeactivateMissing *bool
+}
+
+func (p *PreviewCSVSyncParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.DeactivateMissing != nil {
+		q.Set("deactivateMissing", strconv.FormatBool(*p.DeactivateMissing))
+	}
+	return q
+}
+
+// PreviewConsistencyRepairParams are the query parameters of PreviewConsistencyRepair
+type PreviewConsistencyRepairParams struct {
+	// Checks comma separated checks to repair, every check when not set
+	Checks string
+}
+
+func (p *PreviewConsistencyRepairParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Checks != "" {
+		q.Set("checks", p.Checks)
+	}
+	return q
+}
+
+type ReadinessResponse struct {
+	Status string `json:"status,omitempty"`
+}
+
+// RepairConsistencyParams are the query parameters of RepairConsistency
+type RepairConsistencyParams struct {
+	// Checks comma separated checks to repair, every check when not set
+	Checks string
+}
+
+func (p *RepairConsistencyParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Checks != "" {
+		q.Set("checks", p.Checks)
+	}
+	return q
+}
+
+// SSOCallbackParams are the query parameters of SSOCallback
+type SSOCallbackParams struct {
+	Code  string
+	State string
+}
+
+func (p *SSOCallbackParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.Code != "" {
+		q.Set("code", p.Code)
+	}
+	if p.State != "" {
+		q.Set("state", p.State)
+	}
+	return q
+}
+
+// UpdateAccessedByParams are the query parameters of UpdateAccessedBy
+type UpdateAccessedByParams struct {
+	UserID   string
+	UserType string
+}
+
+func (p *UpdateAccessedByParams) values() url.Values {
+	q := url.Values{}
+	if p == nil {
+		return q
+	}
+	if p.UserID != "" {
+		q.Set("userId", p.UserID)
+	}
+	if p.UserType != "" {
+		q.Set("userType", p.UserType)
+	}
+	return q
+}
+
+// AccessedByDetails sends GET /user/accessed-by: Users and their sign in history, the X-Next-Cursor header holds the cursor of the next page
+func (c *Client) AccessedByDetails(ctx context.Context, params *AccessedByDetailsParams) ([]AccessedByDetails, error) {
+	var out []AccessedByDetails
+	err := c.do(ctx, http.MethodGet, "/user/accessed-by", params.values(), nil, &out)
+	return out, err
+}
+
+// AcknowledgeAsset sends PUT /employee/assets/{assetId}/acknowledge: Acknowledge receipt of an asset
+func (c *Client) AcknowledgeAsset(ctx context.Context, assetID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/employee/assets/{assetId}/acknowledge", "{assetId}", url.PathEscape(assetID), 1), nil, nil, &out)
+	return out, err
+}
+
+// AcknowledgeHandover sends PUT /user/asset/handover/{handoverId}/acknowledge: Sign a handover on behalf of the employee
+func (c *Client) AcknowledgeHandover(ctx context.Context, handoverID string, body HandoverAcknowledgement) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/asset/handover/{handoverId}/acknowledge", "{handoverId}", url.PathEscape(handoverID), 1), nil, body, &out)
+	return out, err
+}
+
+// AcknowledgeOwnHandover sends PUT /employee/handover/{handoverId}/acknowledge: Sign a handover
+func (c *Client) AcknowledgeOwnHandover(ctx context.Context, handoverID string, body HandoverAcknowledgement) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/employee/handover/{handoverId}/acknowledge", "{handoverId}", url.PathEscape(handoverID), 1), nil, body, &out)
+	return out, err
+}
+
+// AddHandoverPhoto sends POST /user/asset/handover/{handoverId}/photo: Attach a condition photo to a handover
+func (c *Client) AddHandoverPhoto(ctx context.Context, handoverID string, filename string, file io.Reader) (URLResponse, error) {
+	var out URLResponse
+	err := c.upload(ctx, http.MethodPost, strings.Replace("/user/asset/handover/{handoverId}/photo", "{handoverId}", url.PathEscape(handoverID), 1), nil, "image", filename, file, &out)
+	return out, err
+}
+
+// AddProfileImage sends PUT /user/image: Upload a profile image
+func (c *Client) AddProfileImage(ctx context.Context, filename string, file io.Reader) (URLResponse, error) {
+	var out URLResponse
+	err := c.upload(ctx, http.MethodPut, "/user/image", nil, "image", filename, file, &out)
+	return out, err
+}
+
+// ApplyCSVSync sends POST /user/employee/sync/csv: Apply an HR export
+func (c *Client) ApplyCSVSync(ctx context.Context, params *ApplyCSVSyncParams, filename string, file io.Reader) (SyncRun, error) {
+	var out SyncRun
+	err := c.upload(ctx, http.MethodPost, "/user/employee/sync/csv", params.values(), "file", filename, file, &out)
+	return out, err
+}
+
+// AssetTransfers sends GET /user/asset/transfers: Location history of an asset
+func (c *Client) AssetTransfers(ctx context.Context, params *AssetTransfersParams) ([]AssetTransferHistory, error) {
+	var out []AssetTransferHistory
+	err := c.do(ctx, http.MethodGet, "/user/asset/transfers", params.values(), nil, &out)
+	return out, err
+}
+
+// AvailableAssets sends GET /user/asset/brand: Assets available for assignment
+func (c *Client) AvailableAssets(ctx context.Context, params *AvailableAssetsParams) ([]AssignAssetDetails, error) {
+	var out []AssignAssetDetails
+	err := c.do(ctx, http.MethodGet, "/user/asset/brand", params.values(), nil, &out)
+	return out, err
+}
+
+// CancelOwnEquipmentRequest sends PUT /employee/equipment-request/{requestId}/cancel: Cancel an own equipment request
+func (c *Client) CancelOwnEquipmentRequest(ctx context.Context, requestID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/employee/equipment-request/{requestId}/cancel", "{requestId}", url.PathEscape(requestID), 1), nil, nil, &out)
+	return out, err
+}
+
+// CancelReservation sends PUT /user/asset/reservation/{reservationId}/cancel: Cancel a reservation
+func (c *Client) CancelReservation(ctx context.Context, reservationID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/asset/reservation/{reservationId}/cancel", "{reservationId}", url.PathEscape(reservationID), 1), nil, nil, &out)
+	return out, err
+}
+
+// ConvertReservation sends POST /user/asset/reservation/{reservationId}/convert: Turn a reservation into an assignment
+func (c *Client) ConvertReservation(ctx context.Context, reservationID string, body ConvertReservation) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, strings.Replace("/user/asset/reservation/{reservationId}/convert", "{reservationId}", url.PathEscape(reservationID), 1), nil, body, &out)
+	return out, err
+}
+
+// CreateAPIKey sends POST /user/api-key: Issue an API key, the key is only returned once
+func (c *Client) CreateAPIKey(ctx context.Context, body CreateAPIKey) (CreateAPIKeyResponse, error) {
+	var out CreateAPIKeyResponse
+	err := c.do(ctx, http.MethodPost, "/user/api-key", nil, body, &out)
+	return out, err
+}
+
+// CreateApprovalStep sends POST /user/employee/equipment-request/approval-step: Add an approval step
+func (c *Client) CreateApprovalStep(ctx context.Context, body ApprovalStep) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/employee/equipment-request/approval-step", nil, body, &out)
+	return out, err
+}
+
+// CreateAsset sends POST /user/asset: Create an asset
+func (c *Client) CreateAsset(ctx context.Context, body CreateAsset) (CreateAssetResponse, error) {
+	var out CreateAssetResponse
+	err := c.do(ctx, http.MethodPost, "/user/asset", nil, body, &out)
+	return out, err
+}
+
+// CreateDepartment sends POST /user/department: Create a department
+func (c *Client) CreateDepartment(ctx context.Context, body Department) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/department", nil, body, &out)
+	return out, err
+}
+
+// CreateEmployee sends POST /user/employee: Create an employee
+func (c *Client) CreateEmployee(ctx context.Context, body EmployeeDetails) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, "/user/employee", nil, body, &out)
+	return out, err
+}
+
+// CreateEmployeeAccount sends PUT /user/employee/{employeeId}/account: Give an employee a portal account
+func (c *Client) CreateEmployeeAccount(ctx context.Context, employeeID string, body EmployeeAccount) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/employee/{employeeId}/account", "{employeeId}", url.PathEscape(employeeID), 1), nil, body, &out)
+	return out, err
+}
+
+// CreateEmployeeAssetRelation sends POST /user/employee/asset: Assign an asset to an employee
+func (c *Client) CreateEmployeeAssetRelation(ctx context.Context, body EmployeeAssetRelation) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, "/user/employee/asset", nil, body, &out)
+	return out, err
+}
+
+// CreateEquipmentRequest sends POST /employee/equipment-request: Request equipment
+func (c *Client) CreateEquipmentRequest(ctx context.Context, body EquipmentRequest) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/employee/equipment-request", nil, body, &out)
+	return out, err
+}
+
+// CreateEquipmentRequestForEmployee sends POST /user/employee/equipment-request: Request equipment for an employee
+func (c *Client) CreateEquipmentRequestForEmployee(ctx context.Context, body EquipmentRequest) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/employee/equipment-request", nil, body, &out)
+	return out, err
+}
+
+// CreateHandover sends POST /user/asset/handover: Record the handover of an asset
+func (c *Client) CreateHandover(ctx context.Context, body CreateHandover) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/asset/handover", nil, body, &out)
+	return out, err
+}
+
+// CreateLocation sends POST /user/location: Create a location
+func (c *Client) CreateLocation(ctx context.Context, body LocationDetails) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/location", nil, body, &out)
+	return out, err
+}
+
+// CreateProvisioningToken sends POST /user/employee/sync/token: Issue a SCIM provisioning token, the token is only returned once
+func (c *Client) CreateProvisioningToken(ctx context.Context, body ProvisioningToken) (CreateProvisioningTokenResponse, error) {
+	var out CreateProvisioningTokenResponse
+	err := c.do(ctx, http.MethodPost, "/user/employee/sync/token", nil, body, &out)
+	return out, err
+}
+
+// CreateReservation sends POST /user/asset/reservation: Reserve an asset for a period
+func (c *Client) CreateReservation(ctx context.Context, body Reservation) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/asset/reservation", nil, body, &out)
+	return out, err
+}
+
+// CreateWebhookSubscription sends POST /user/webhook: Subscribe to events, the signing secret is only returned once
+func (c *Client) CreateWebhookSubscription(ctx context.Context, body CreateWebhookSubscription) (CreateWebhookSubscriptionResponse, error) {
+	var out CreateWebhookSubscriptionResponse
+	err := c.do(ctx, http.MethodPost, "/user/webhook", nil, body, &out)
+	return out, err
+}
+
+// DecideEquipmentRequest sends PUT /user/employee/equipment-request/{requestId}/decision: Approve or reject the current step of a request
+func (c *Client) DecideEquipmentRequest(ctx context.Context, requestID string, body EquipmentRequestDecision) (DecideEquipmentRequestResponse, error) {
+	var out DecideEquipmentRequestResponse
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/employee/equipment-request/{requestId}/decision", "{requestId}", url.PathEscape(requestID), 1), nil, body, &out)
+	return out, err
+}
+
+// DecideLoanExtension sends PUT /user/asset/loan/extension: Approve or reject a loan extension
+func (c *Client) DecideLoanExtension(ctx context.Context, body LoanExtensionDecision) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset/loan/extension", nil, body, &out)
+	return out, err
+}
+
+// DeleteApprovalStep sends DELETE /user/employee/equipment-request/approval-step/{stepId}: Remove an approval step
+func (c *Client) DeleteApprovalStep(ctx context.Context, stepID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/employee/equipment-request/approval-step/{stepId}", "{stepId}", url.PathEscape(stepID), 1), nil, nil, &out)
+	return out, err
+}
+
+// DeleteAsset sends DELETE /user/asset: Delete an unassigned asset
+func (c *Client) DeleteAsset(ctx context.Context, body Asset) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, "/user/asset", nil, body, &out)
+	return out, err
+}
+
+// DeleteDepartment sends DELETE /user/department/{departmentId}: Delete an empty department
+func (c *Client) DeleteDepartment(ctx context.Context, departmentID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/department/{departmentId}", "{departmentId}", url.PathEscape(departmentID), 1), nil, nil, &out)
+	return out, err
+}
+
+// DeleteEmployee sends DELETE /user/employee/{employeeId}: Delete an employee
+func (c *Client) DeleteEmployee(ctx context.Context, employeeID string, body Employee) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/employee/{employeeId}", "{employeeId}", url.PathEscape(employeeID), 1), nil, body, &out)
+	return out, err
+}
+
+// DeleteLocation sends DELETE /user/location/{locationId}: Delete an empty location
+func (c *Client) DeleteLocation(ctx context.Context, locationID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/location/{locationId}", "{locationId}", url.PathEscape(locationID), 1), nil, nil, &out)
+	return out, err
+}
+
+// DeleteWebhookSubscription sends DELETE /user/webhook/{subscriptionId}: Delete a webhook subscription
+func (c *Client) DeleteWebhookSubscription(ctx context.Context, subscriptionID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/webhook/{subscriptionId}", "{subscriptionId}", url.PathEscape(subscriptionID), 1), nil, nil, &out)
+	return out, err
+}
+
+// EmployeeHistory sends GET /user/asset/employee: Employees who held an asset
+func (c *Client) EmployeeHistory(ctx context.Context, params *EmployeeHistoryParams) ([]EmployeeHistory, error) {
+	var out []EmployeeHistory
+	err := c.do(ctx, http.MethodGet, "/user/asset/employee", params.values(), nil, &out)
+	return out, err
+}
+
+// EmployeeLogin sends POST /employee/login: Sign in to the employee portal
+func (c *Client) EmployeeLogin(ctx context.Context, body UsersLoginDetails) (TokenResponse, error) {
+	var out TokenResponse
+	err := c.do(ctx, http.MethodPost, "/employee/login", nil, body, &out)
+	return out, err
+}
+
+// EmployeeLogout sends PUT /employee/log-out: Sign out of the employee portal
+func (c *Client) EmployeeLogout(ctx context.Context) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/employee/log-out", nil, nil, &out)
+	return out, err
+}
+
+// GetAPIDocs sends GET /docs: Browsable API documentation
+func (c *Client) GetAPIDocs(ctx context.Context) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, "/docs", nil)
+}
+
+// GetAPIKeys sends GET /user/api-key: API keys
+func (c *Client) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var out []APIKey
+	err := c.do(ctx, http.MethodGet, "/user/api-key", nil, nil, &out)
+	return out, err
+}
+
+// GetApprovalSteps sends GET /user/employee/equipment-request/approval-step: Approval chain for equipment requests
+func (c *Client) GetApprovalSteps(ctx context.Context) ([]ApprovalStep, error) {
+	var out []ApprovalStep
+	err := c.do(ctx, http.MethodGet, "/user/employee/equipment-request/approval-step", nil, nil, &out)
+	return out, err
+}
+
+// GetAssetHistory sends GET /user/employee/asset-list: Assets an employee held
+func (c *Client) GetAssetHistory(ctx context.Context, params *GetAssetHistoryParams) ([]AssetHistory, error) {
+	var out []AssetHistory
+	err := c.do(ctx, http.MethodGet, "/user/employee/asset-list", params.values(), nil, &out)
+	return out, err
+}
+
+// GetAssetLabels sends GET /user/asset/labels: Print asset labels
+func (c *Client) GetAssetLabels(ctx context.Context, params *GetAssetLabelsParams) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, "/user/asset/labels", params.values())
+}
+
+// GetAssetList sends GET /user/asset: Assets
+func (c *Client) GetAssetList(ctx context.Context, params *GetAssetListParams) (TotalGetAsset, error) {
+	var out TotalGetAsset
+	err := c.do(ctx, http.MethodGet, "/user/asset", params.values(), nil, &out)
+	return out, err
+}
+
+// GetAssetReports sends GET /user/asset/reports: Problems reported by employees
+func (c *Client) GetAssetReports(ctx context.Context, params *GetAssetReportsParams) ([]AssetReportDetails, error) {
+	var out []AssetReportDetails
+	err := c.do(ctx, http.MethodGet, "/user/asset/reports", params.values(), nil, &out)
+	return out, err
+}
+
+// GetAssetRestores sends GET /user/asset/restores: Restores of deleted assets, newest first
+func (c *Client) GetAssetRestores(ctx context.Context, params *GetAssetRestoresParams) ([]RestoreRecord, error) {
+	var out []RestoreRecord
+	err := c.do(ctx, http.MethodGet, "/user/asset/restores", params.values(), nil, &out)
+	return out, err
+}
+
+// GetAssetSpec sends GET /user/asset/specifications: An asset with its specification and history
+func (c *Client) GetAssetSpec(ctx context.Context, params *GetAssetSpecParams) ([]CreateAsset, error) {
+	var out []CreateAsset
+	err := c.do(ctx, http.MethodGet, "/user/asset/specifications", params.values(), nil, &out)
+	return out, err
+}
+
+// GetDashboard sends GET /user/dashboard: Asset counts by type
+func (c *Client) GetDashboard(ctx context.Context, params *GetDashboardParams) (GetAssetQuantity, error) {
+	var out GetAssetQuantity
+	err := c.do(ctx, http.MethodGet, "/user/dashboard", params.values(), nil, &out)
+	return out, err
+}
+
+// GetDepartmentDashboard sends GET /user/dashboard/departments: Asset counts by department
+func (c *Client) GetDepartmentDashboard(ctx context.Context) ([]DepartmentQuantity, error) {
+	var out []DepartmentQuantity
+	err := c.do(ctx, http.MethodGet, "/user/dashboard/departments", nil, nil, &out)
+	return out, err
+}
+
+// GetDepartments sends GET /user/department: Departments
+func (c *Client) GetDepartments(ctx context.Context) ([]DepartmentDetails, error) {
+	var out []DepartmentDetails
+	err := c.do(ctx, http.MethodGet, "/user/department", nil, nil, &out)
+	return out, err
+}
+
+// GetEmployeeList sends GET /user/employee: Employees
+func (c *Client) GetEmployeeList(ctx context.Context, params *GetEmployeeListParams) (TotalGetEmployee, error) {
+	var out TotalGetEmployee
+	err := c.do(ctx, http.MethodGet, "/user/employee", params.values(), nil, &out)
+	return out, err
+}
+
+// GetEmployeeMoreInfo sends GET /user/employee/{employeeId}/info: An employee with their asset history
+func (c *Client) GetEmployeeMoreInfo(ctx context.Context, employeeID string) (TotalGetEmployee, error) {
+	var out TotalGetEmployee
+	err := c.do(ctx, http.MethodGet, strings.Replace("/user/employee/{employeeId}/info", "{employeeId}", url.PathEscape(employeeID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetEmployeeRestores sends GET /user/employee/restores: Restores of deleted employees, newest first
+func (c *Client) GetEmployeeRestores(ctx context.Context, params *GetEmployeeRestoresParams) ([]RestoreRecord, error) {
+	var out []RestoreRecord
+	err := c.do(ctx, http.MethodGet, "/user/employee/restores", params.values(), nil, &out)
+	return out, err
+}
+
+// GetEquipmentRequest sends GET /user/employee/equipment-request/{requestId}: An equipment request with its approvals
+func (c *Client) GetEquipmentRequest(ctx context.Context, requestID string) (EquipmentRequestDetails, error) {
+	var out EquipmentRequestDetails
+	err := c.do(ctx, http.MethodGet, strings.Replace("/user/employee/equipment-request/{requestId}", "{requestId}", url.PathEscape(requestID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetEquipmentRequests sends GET /user/employee/equipment-request: Equipment requests
+func (c *Client) GetEquipmentRequests(ctx context.Context, params *GetEquipmentRequestsParams) ([]EquipmentRequestDetails, error) {
+	var out []EquipmentRequestDetails
+	err := c.do(ctx, http.MethodGet, "/user/employee/equipment-request", params.values(), nil, &out)
+	return out, err
+}
+
+// GetHandoverReceipt sends GET /user/asset/handover/{handoverId}/receipt: Download a handover receipt
+func (c *Client) GetHandoverReceipt(ctx context.Context, handoverID string) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, strings.Replace("/user/asset/handover/{handoverId}/receipt", "{handoverId}", url.PathEscape(handoverID), 1), nil)
+}
+
+// GetHandovers sends GET /user/asset/handover: Handovers
+func (c *Client) GetHandovers(ctx context.Context, params *GetHandoversParams) ([]Handover, error) {
+	var out []Handover
+	err := c.do(ctx, http.MethodGet, "/user/asset/handover", params.values(), nil, &out)
+	return out, err
+}
+
+// GetInconsistencies sends GET /user/consistency: Rows breaking the data consistency checks
+func (c *Client) GetInconsistencies(ctx context.Context) ([]Inconsistency, error) {
+	var out []Inconsistency
+	err := c.do(ctx, http.MethodGet, "/user/consistency", nil, nil, &out)
+	return out, err
+}
+
+// GetLoanExtensions sends GET /user/asset/loan/extension: Loan extension requests
+func (c *Client) GetLoanExtensions(ctx context.Context, params *GetLoanExtensionsParams) ([]LoanExtension, error) {
+	var out []LoanExtension
+	err := c.do(ctx, http.MethodGet, "/user/asset/loan/extension", params.values(), nil, &out)
+	return out, err
+}
+
+// GetLoanStats sends GET /user/asset/loan/stats: Loan statistics per employee
+func (c *Client) GetLoanStats(ctx context.Context, params *GetLoanStatsParams) ([]LoanStats, error) {
+	var out []LoanStats
+	err := c.do(ctx, http.MethodGet, "/user/asset/loan/stats", params.values(), nil, &out)
+	return out, err
+}
+
+// GetLocationDashboard sends GET /user/dashboard/locations: Asset counts by location
+func (c *Client) GetLocationDashboard(ctx context.Context) (LocationDashboard, error) {
+	var out LocationDashboard
+	err := c.do(ctx, http.MethodGet, "/user/dashboard/locations", nil, nil, &out)
+	return out, err
+}
+
+// GetLocations sends GET /user/location: Locations
+func (c *Client) GetLocations(ctx context.Context, params *GetLocationsParams) ([]Location, error) {
+	var out []Location
+	err := c.do(ctx, http.MethodGet, "/user/location", params.values(), nil, &out)
+	return out, err
+}
+
+// GetLoginOptions sends GET /login/options: Sign in methods that are enabled
+func (c *Client) GetLoginOptions(ctx context.Context) (GetLoginOptionsResponse, error) {
+	var out GetLoginOptionsResponse
+	err := c.do(ctx, http.MethodGet, "/login/options", nil, nil, &out)
+	return out, err
+}
+
+// GetOpenAPISpec sends GET /openapi.json: This document
+func (c *Client) GetOpenAPISpec(ctx context.Context) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, "/openapi.json", nil)
+}
+
+// GetOverdueLoans sends GET /user/asset/loan/overdue: Loans past their due date
+func (c *Client) GetOverdueLoans(ctx context.Context) ([]Loan, error) {
+	var out []Loan
+	err := c.do(ctx, http.MethodGet, "/user/asset/loan/overdue", nil, nil, &out)
+	return out, err
+}
+
+// GetOwnAssets sends GET /employee/assets: Assets held by the signed in employee
+func (c *Client) GetOwnAssets(ctx context.Context, params *GetOwnAssetsParams) ([]AssetHistory, error) {
+	var out []AssetHistory
+	err := c.do(ctx, http.MethodGet, "/employee/assets", params.values(), nil, &out)
+	return out, err
+}
+
+// GetOwnEquipmentRequest sends GET /employee/equipment-request/{requestId}: An own equipment request with its approvals
+func (c *Client) GetOwnEquipmentRequest(ctx context.Context, requestID string) (EquipmentRequestDetails, error) {
+	var out EquipmentRequestDetails
+	err := c.do(ctx, http.MethodGet, strings.Replace("/employee/equipment-request/{requestId}", "{requestId}", url.PathEscape(requestID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetOwnEquipmentRequests sends GET /employee/equipment-request: Equipment requests of the signed in employee
+func (c *Client) GetOwnEquipmentRequests(ctx context.Context, params *GetOwnEquipmentRequestsParams) ([]EquipmentRequestDetails, error) {
+	var out []EquipmentRequestDetails
+	err := c.do(ctx, http.MethodGet, "/employee/equipment-request", params.values(), nil, &out)
+	return out, err
+}
+
+// GetOwnHandoverReceipt sends GET /employee/handover/{handoverId}/receipt: Download a handover receipt
+func (c *Client) GetOwnHandoverReceipt(ctx context.Context, handoverID string) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, strings.Replace("/employee/handover/{handoverId}/receipt", "{handoverId}", url.PathEscape(handoverID), 1), nil)
+}
+
+// GetOwnHandovers sends GET /employee/handover: Handovers of the signed in employee
+func (c *Client) GetOwnHandovers(ctx context.Context) ([]Handover, error) {
+	var out []Handover
+	err := c.do(ctx, http.MethodGet, "/employee/handover", nil, nil, &out)
+	return out, err
+}
+
+// GetProvisioningTokens sends GET /user/employee/sync/token: SCIM provisioning tokens
+func (c *Client) GetProvisioningTokens(ctx context.Context) ([]ProvisioningToken, error) {
+	var out []ProvisioningToken
+	err := c.do(ctx, http.MethodGet, "/user/employee/sync/token", nil, nil, &out)
+	return out, err
+}
+
+// GetReservationCalendar sends GET /user/asset/reservation/calendar.ics: Reservations as an iCalendar feed
+func (c *Client) GetReservationCalendar(ctx context.Context, params *GetReservationCalendarParams) ([]byte, error) {
+	return c.raw(ctx, http.MethodGet, "/user/asset/reservation/calendar.ics", params.values())
+}
+
+// GetReservations sends GET /user/asset/reservation: Reservations
+func (c *Client) GetReservations(ctx context.Context, params *GetReservationsParams) ([]ReservationDetails, error) {
+	var out []ReservationDetails
+	err := c.do(ctx, http.MethodGet, "/user/asset/reservation", params.values(), nil, &out)
+	return out, err
+}
+
+// GetSyncRun sends GET /user/employee/sync/runs/{runId}: An HR sync run with its changes
+func (c *Client) GetSyncRun(ctx context.Context, runID string) (SyncRun, error) {
+	var out SyncRun
+	err := c.do(ctx, http.MethodGet, strings.Replace("/user/employee/sync/runs/{runId}", "{runId}", url.PathEscape(runID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetSyncRuns sends GET /user/employee/sync/runs: HR sync runs, newest first
+func (c *Client) GetSyncRuns(ctx context.Context, params *GetSyncRunsParams) ([]SyncRun, error) {
+	var out []SyncRun
+	err := c.do(ctx, http.MethodGet, "/user/employee/sync/runs", params.values(), nil, &out)
+	return out, err
+}
+
+// GetUserDetails sends GET /user/info: The signed in user
+func (c *Client) GetUserDetails(ctx context.Context) (UserDetails, error) {
+	var out UserDetails
+	err := c.do(ctx, http.MethodGet, "/user/info", nil, nil, &out)
+	return out, err
+}
+
+// GetUserInfo sends GET /user/{userId}: A user
+func (c *Client) GetUserInfo(ctx context.Context, userID string) (UserDetails, error) {
+	var out UserDetails
+	err := c.do(ctx, http.MethodGet, strings.Replace("/user/{userId}", "{userId}", url.PathEscape(userID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetWebhookDeliveries sends GET /user/webhook/deliveries: Webhook deliveries, newest first
+func (c *Client) GetWebhookDeliveries(ctx context.Context, params *GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	var out []WebhookDelivery
+	err := c.do(ctx, http.MethodGet, "/user/webhook/deliveries", params.values(), nil, &out)
+	return out, err
+}
+
+// GetWebhookDelivery sends GET /user/webhook/deliveries/{deliveryId}: A webhook delivery with its payload and attempts
+func (c *Client) GetWebhookDelivery(ctx context.Context, deliveryID string) (WebhookDelivery, error) {
+	var out WebhookDelivery
+	err := c.do(ctx, http.MethodGet, strings.Replace("/user/webhook/deliveries/{deliveryId}", "{deliveryId}", url.PathEscape(deliveryID), 1), nil, nil, &out)
+	return out, err
+}
+
+// GetWebhookSubscriptions sends GET /user/webhook: Webhook subscriptions
+func (c *Client) GetWebhookSubscriptions(ctx context.Context) ([]WebhookSubscription, error) {
+	var out []WebhookSubscription
+	err := c.do(ctx, http.MethodGet, "/user/webhook", nil, nil, &out)
+	return out, err
+}
+
+// Health sends GET /health: Check the server is running
+func (c *Client) Health(ctx context.Context) (HealthResponse, error) {
+	var out HealthResponse
+	err := c.do(ctx, http.MethodGet, "/health", nil, nil, &out)
+	return out, err
+}
+
+// Liveness sends GET /health/live: Liveness probe, answers while the server serves
+func (c *Client) Liveness(ctx context.Context) (LivenessResponse, error) {
+	var out LivenessResponse
+	err := c.do(ctx, http.MethodGet, "/health/live", nil, nil, &out)
+	return out, err
+}
+
+// LoginUser sends POST /login: Sign in with email and password
+func (c *Client) LoginUser(ctx context.Context, body UsersLoginDetails) (TokenResponse, error) {
+	var out TokenResponse
+	err := c.do(ctx, http.MethodPost, "/login", nil, body, &out)
+	return out, err
+}
+
+// Logout sends PUT /user/log-out: Sign out
+func (c *Client) Logout(ctx context.Context) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/log-out", nil, nil, &out)
+	return out, err
+}
+
+// LookupAsset sends GET /user/asset/lookup: Find an asset by a scanned tag or serial number
+func (c *Client) LookupAsset(ctx context.Context, params *LookupAssetParams) ([]CreateAsset, error) {
+	var out []CreateAsset
+	err := c.do(ctx, http.MethodGet, "/user/asset/lookup", params.values(), nil, &out)
+	return out, err
+}
+
+// PreviewCSVSync sends POST /user/employee/sync/csv/preview: Preview the changes of an HR export
+func (c *Client) PreviewCSVSync(ctx context.Context, params *PreviewCSVSyncParams, filename string, file io.Reader) (SyncRun, error) {
+	var out SyncRun
+	err := c.upload(ctx, http.MethodPost, "/user/employee/sync/csv/preview", params.values(), "file", filename, file, &out)
+	return out, err
+}
+
+// PreviewConsistencyRepair sends POST /user/consistency/repair/preview: Preview the repair of inconsistencies
+func (c *Client) PreviewConsistencyRepair(ctx context.Context, params *PreviewConsistencyRepairParams) (ConsistencyReport, error) {
+	var out ConsistencyReport
+	err := c.do(ctx, http.MethodPost, "/user/consistency/repair/preview", params.values(), nil, &out)
+	return out, err
+}
+
+// Readiness sends GET /health/ready: Readiness probe, 503 unless the database answers and is migrated
+func (c *Client) Readiness(ctx context.Context) (ReadinessResponse, error) {
+	var out ReadinessResponse
+	err := c.do(ctx, http.MethodGet, "/health/ready", nil, nil, &out)
+	return out, err
+}
+
+// ReassignAsset sends POST /user/asset/reassign: Move an asset to another employee
+func (c *Client) ReassignAsset(ctx context.Context, body ReassignAsset) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, "/user/asset/reassign", nil, body, &out)
+	return out, err
+}
+
+// RedeliverWebhook sends POST /user/webhook/deliveries/{deliveryId}/redeliver: Send a webhook delivery again
+func (c *Client) RedeliverWebhook(ctx context.Context, deliveryID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, strings.Replace("/user/webhook/deliveries/{deliveryId}/redeliver", "{deliveryId}", url.PathEscape(deliveryID), 1), nil, nil, &out)
+	return out, err
+}
+
+// RegisterUser sends POST /user/register: Register an admin user
+func (c *Client) RegisterUser(ctx context.Context, body RegisterUser) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, "/user/register", nil, body, &out)
+	return out, err
+}
+
+// RepairConsistency sends POST /user/consistency/repair: Repair inconsistencies in one transaction
+func (c *Client) RepairConsistency(ctx context.Context, params *RepairConsistencyParams) (ConsistencyReport, error) {
+	var out ConsistencyReport
+	err := c.do(ctx, http.MethodPost, "/user/consistency/repair", params.values(), nil, &out)
+	return out, err
+}
+
+// ReportAsset sends POST /employee/assets/{assetId}/report: Report a problem with an asset
+func (c *Client) ReportAsset(ctx context.Context, assetID string, body AssetReport) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, strings.Replace("/employee/assets/{assetId}/report", "{assetId}", url.PathEscape(assetID), 1), nil, body, &out)
+	return out, err
+}
+
+// RequestLoanExtension sends POST /user/asset/loan/extension: Ask for a later due date
+func (c *Client) RequestLoanExtension(ctx context.Context, body LoanExtensionRequest) (IDResponse, error) {
+	var out IDResponse
+	err := c.do(ctx, http.MethodPost, "/user/asset/loan/extension", nil, body, &out)
+	return out, err
+}
+
+// ResolveAssetReport sends PUT /user/asset/reports: Resolve a reported problem
+func (c *Client) ResolveAssetReport(ctx context.Context, body ResolveAssetReport) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset/reports", nil, body, &out)
+	return out, err
+}
+
+// RestoreAsset sends PUT /user/asset/restore: Restore a deleted asset
+func (c *Client) RestoreAsset(ctx context.Context, body RestoreAsset) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset/restore", nil, body, &out)
+	return out, err
+}
+
+// RestoreEmployee sends PUT /user/employee/{employeeId}/restore: Restore a deleted employee
+func (c *Client) RestoreEmployee(ctx context.Context, employeeID string, body RestoreEmployee) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/employee/{employeeId}/restore", "{employeeId}", url.PathEscape(employeeID), 1), nil, body, &out)
+	return out, err
+}
+
+// RetrieveAsset sends PUT /user/asset/retrieve-asset: Take an asset back from an employee
+func (c *Client) RetrieveAsset(ctx context.Context, body AssetRetrievalDetails) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset/retrieve-asset", nil, body, &out)
+	return out, err
+}
+
+// RevokeAPIKey sends DELETE /user/api-key/{keyId}: Revoke an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, keyID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/api-key/{keyId}", "{keyId}", url.PathEscape(keyID), 1), nil, nil, &out)
+	return out, err
+}
+
+// RevokeProvisioningToken sends DELETE /user/employee/sync/token/{tokenId}: Revoke a SCIM provisioning token
+func (c *Client) RevokeProvisioningToken(ctx context.Context, tokenID string) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodDelete, strings.Replace("/user/employee/sync/token/{tokenId}", "{tokenId}", url.PathEscape(tokenID), 1), nil, nil, &out)
+	return out, err
+}
+
+// SSOCallback sends GET /sso/callback: Finish a single sign-on login
+func (c *Client) SSOCallback(ctx context.Context, params *SSOCallbackParams) (TokenResponse, error) {
+	var out TokenResponse
+	err := c.do(ctx, http.MethodGet, "/sso/callback", params.values(), nil, &out)
+	return out, err
+}
+
+// SSOLogin sends GET /sso/login: Start a single sign-on login
+func (c *Client) SSOLogin(ctx context.Context) error {
+	_, err := c.raw(ctx, http.MethodGet, "/sso/login", nil)
+	return err
+}
+
+// TransferAsset sends POST /user/asset/transfer: Move an asset to another location
+func (c *Client) TransferAsset(ctx context.Context, body AssetTransfer) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPost, "/user/asset/transfer", nil, body, &out)
+	return out, err
+}
+
+// UpdateAccessedBy sends PUT /user/accessed-by: Change the type of a user
+func (c *Client) UpdateAccessedBy(ctx context.Context, params *UpdateAccessedByParams) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/accessed-by", params.values(), nil, &out)
+	return out, err
+}
+
+// UpdateAsset sends PUT /user/asset: Update an asset
+func (c *Client) UpdateAsset(ctx context.Context, body UpdateAssetSpecification) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset", nil, body, &out)
+	return out, err
+}
+
+// UpdateDepartment sends PUT /user/department: Update a department
+func (c *Client) UpdateDepartment(ctx context.Context, body Department) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/department", nil, body, &out)
+	return out, err
+}
+
+// UpdateEmployee sends PUT /user/employee: Update an employee
+func (c *Client) UpdateEmployee(ctx context.Context, body EmployeeDetails) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/employee", nil, body, &out)
+	return out, err
+}
+
+// UpdateEmployeePassword sends PUT /employee/password: Change the portal password
+func (c *Client) UpdateEmployeePassword(ctx context.Context, body EmployeePasswordChange) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/employee/password", nil, body, &out)
+	return out, err
+}
+
+// UpdateLocation sends PUT /user/location: Update a location
+func (c *Client) UpdateLocation(ctx context.Context, body LocationDetails) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/location", nil, body, &out)
+	return out, err
+}
+
+// UpdateUser sends PUT /user/info: Update the signed in user
+func (c *Client) UpdateUser(ctx context.Context, body RegisterUser) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/info", nil, body, &out)
+	return out, err
+}
+
+// UpdateWarranty sends PUT /user/asset/warranty: Update the warranty of an asset
+func (c *Client) UpdateWarranty(ctx context.Context, body WarrantyDetails) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, "/user/asset/warranty", nil, body, &out)
+	return out, err
+}
+
+// UpdateWebhookSubscription sends PUT /user/webhook/{subscriptionId}: Update a webhook subscription
+func (c *Client) UpdateWebhookSubscription(ctx context.Context, subscriptionID string, body UpdateWebhookSubscription) (ResponseMsg, error) {
+	var out ResponseMsg
+	err := c.do(ctx, http.MethodPut, strings.Replace("/user/webhook/{subscriptionId}", "{subscriptionId}", url.PathEscape(subscriptionID), 1), nil, body, &out)
+	return out, err
+}
@@ -0,0 +1,510 @@
+package main
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+// exportPageSize is how many rows exports read at a time
+const exportPageSize = 500
+
+var store = repository.NewPostgres()
+
+var (
+	assetTypes    = []string{utils.Laptop, utils.Mouse, utils.Harddisk, utils.Pendrive, utils.Mobile, utils.Sim}
+	assetStatuses = []string{utils.Available, utils.Assigned, utils.Deleted}
+	assetOwners   = []string{utils.RemoteState, utils.Client}
+)
+
+func assetSearch(ctx context.Context, args []string) error {
+	flags := newFlags("asset search", "")
+	filters, err := assetFilterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	assets, err := store.Assets.List(ctx, filters)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TAG\tTYPE\tBRAND\tMODEL\tSERIAL NO\tSTATUS\tASSIGNED TO\tLOCATION\tWARRANTY UNTIL")
+	for _, asset := range assets.GetAsset {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", asset.AssetTag, asset.AssetType, asset.Brand, asset.Model,
+			asset.SerialNo, asset.Status, asset.AssignedTo.String, asset.Location.String, asset.WarrantyExpiryDate.Format(dateLayout))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d of %d assets\n", len(assets.GetAsset), assets.TotalCount)
+	return nil
+}
+
+// assetFilterFlags adds the list filters shared by search and export and parses them into filters
+func assetFilterFlags(flags *flag.FlagSet, args []string) (*models.FiltersCheck, error) {
+	name := flags.String("name", "", "only assets whose brand contains this")
+	types := flags.String("type", "", "comma separated asset types: "+strings.Join(assetTypes, ", "))
+	statuses := flags.String("status", "", "comma separated statuses: "+strings.Join(assetStatuses, ", ")+", all but deleted when not set")
+	locationID := flags.String("location", "", "id of a location, assets in the locations nested in it included")
+	departmentID := flags.String("department", "", "id of a department whose employees hold the assets")
+	warranty := flags.Int("warranty", 0, "only assets whose warranty ends within this many months")
+	expired := flags.Bool("expired", false, "only assets whose warranty has ended")
+	limit := flags.Int("limit", utils.DefaultLimit, "number of assets per page")
+	page := flags.Int("page", 0, "page to show, from 0")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+
+	filters := &models.FiltersCheck{
+		Pagination:   true,
+		SearchedName: *name,
+		IsSearched:   *name != "",
+		AssetTypes:   pq.StringArray(splitList(*types)),
+		LocationID:   *locationID,
+		DepartmentID: *departmentID,
+		Warranty:     *warranty,
+		IsExpired:    *expired,
+		Limit:        *limit,
+		Page:         *page,
+	}
+	for _, assetType := range filters.AssetTypes {
+		if !contains(assetTypes, assetType) {
+			return nil, usageError(flags, "-type %q is not one of %s", assetType, strings.Join(assetTypes, ", "))
+		}
+	}
+	for _, status := range splitList(*statuses) {
+		switch status {
+		case utils.Available:
+			filters.Available = true
+		case utils.Assigned:
+			filters.Assigned = true
+		case utils.Deleted:
+			filters.Deleted = true
+		default:
+			return nil, usageError(flags, "-status %q is not one of %s", status, strings.Join(assetStatuses, ", "))
+		}
+	}
+	return filters, nil
+}
+
+func assetAssign(ctx context.Context, args []string) error {
+	flags := newFlags("asset assign", "")
+	code := flags.String("asset", "", "tag, serial number or IMEI of the asset")
+	email := flags.String("employee", "", "email of the employee")
+	on := flags.String("date", "", "date of the assignment, "+dateLayout+", today when not set")
+	due := flags.String("due", "", "due date, "+dateLayout+", lends the asset instead of assigning it for good")
+	as := actingUserFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *code == "" || *email == "" {
+		return usageError(flags, "-asset and -employee are required")
+	}
+
+	relation := models.EmployeeAssetRelation{AssignmentType: utils.AssignmentPermanent}
+	var err error
+	if relation.AssignedDate, err = parseDate("date", *on); err != nil {
+		return err
+	}
+	if *due != "" {
+		dueDate, err := parseDate("due", *due)
+		if err != nil {
+			return err
+		}
+		if dueDate.Before(relation.AssignedDate) {
+			return fmt.Errorf("the due date %s is before the assignment", *due)
+		}
+		relation.AssignmentType = utils.AssignmentLoan
+		relation.DueDate = null.TimeFrom(dueDate)
+	}
+	userID, err := actingUser(ctx, *as)
+	if err != nil {
+		return err
+	}
+	asset, err := lookupAsset(ctx, *code)
+	if err != nil {
+		return err
+	}
+	relation.AssetID = asset.ID
+	if relation.EmployeeID, err = employeeIDByEmail(ctx, *email); err != nil {
+		return err
+	}
+
+	if err := store.Assets.Assign(ctx, &relation, userID); err != nil {
+		return err
+	}
+	fmt.Printf("assigned %s to %s\n", *code, *email)
+	return nil
+}
+
+func assetRetrieve(ctx context.Context, args []string) error {
+	flags := newFlags("asset retrieve", "")
+	code := flags.String("asset", "", "tag, serial number or IMEI of the asset")
+	email := flags.String("employee", "", "email of the employee returning the asset, whoever holds it when not set")
+	on := flags.String("date", "", "date of the return, "+dateLayout+", today when not set")
+	reason := flags.String("reason", "", "why the asset is taken back")
+	locationID := flags.String("location", "", "id of the location the asset is put in")
+	as := actingUserFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *code == "" {
+		return usageError(flags, "-asset is required")
+	}
+
+	retrieval := models.AssetRetrievalDetails{RetrievalReason: *reason}
+	var err error
+	if retrieval.RetrievedDate, err = parseDate("date", *on); err != nil {
+		return err
+	}
+	if *locationID != "" {
+		retrieval.LocationID = null.StringFrom(*locationID)
+	}
+	userID, err := actingUser(ctx, *as)
+	if err != nil {
+		return err
+	}
+	asset, err := lookupAsset(ctx, *code)
+	if err != nil {
+		return err
+	}
+	retrieval.AssetID = asset.ID
+
+	if *email != "" {
+		if retrieval.EmployeeID, err = employeeIDByEmail(ctx, *email); err != nil {
+			return err
+		}
+	} else {
+		history, err := store.Assets.History(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		for i := range history {
+			if !history[i].RetrievedDate.Valid {
+				retrieval.EmployeeID = history[i].ID
+				*email = history[i].Email
+			}
+		}
+		if retrieval.EmployeeID == "" {
+			return fmt.Errorf("%s is not assigned to anyone", *code)
+		}
+	}
+
+	if err := store.Assets.Retrieve(ctx, retrieval, userID); err != nil {
+		return err
+	}
+	fmt.Printf("retrieved %s from %s\n", *code, *email)
+	return nil
+}
+
+func assetRetire(ctx context.Context, args []string) error {
+	flags := newFlags("asset retire", "[<asset>...]")
+	reason := flags.String("reason", "", "why the assets are retired, required")
+	file := flags.String("file", "", "file listing the assets one per line, - for standard input")
+	dryRun := flags.Bool("dry-run", false, "only report what would be retired")
+	as := actingUserFlag(flags)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *reason == "" {
+		return usageError(flags, "-reason is required")
+	}
+	codes := flags.Args()
+	if *file != "" {
+		listed, err := readLines(*file)
+		if err != nil {
+			return err
+		}
+		codes = append(codes, listed...)
+	}
+	if len(codes) == 0 {
+		return usageError(flags, "name the assets by tag, serial number or IMEI, or list them in -file")
+	}
+	userID, err := actingUser(ctx, *as)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, code := range codes {
+		if err := retireAsset(ctx, code, *reason, userID, *dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", code, err)
+			failed++
+			continue
+		}
+		if *dryRun {
+			fmt.Printf("would retire %s\n", code)
+		} else {
+			fmt.Printf("retired %s\n", code)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d assets were not retired", failed, len(codes))
+	}
+	return nil
+}
+
+// retireAsset deletes an asset nobody holds, the way the admin panel does
+func retireAsset(ctx context.Context, code, reason, userID string, dryRun bool) error {
+	asset, err := lookupAsset(ctx, code)
+	if err != nil {
+		return err
+	}
+	holders, err := store.Assets.HolderCount(ctx, asset.ID)
+	if err != nil && holders < 0 {
+		return err
+	}
+	if holders > 0 {
+		return errors.New("the asset is assigned, retrieve it first")
+	}
+	if dryRun {
+		return nil
+	}
+	return store.Assets.Delete(ctx, models.Asset{ID: asset.ID, AssetType: models.AssetType(asset.AssetType), DeleteReason: reason}, userID)
+}
+
+// assetColumns sets the field of an asset a CSV column holds, the column names are those of the database
+var assetColumns = map[string]func(asset *models.CreateAsset, value string) error{
+	"asset_type": func(asset *models.CreateAsset, value string) error {
+		asset.AssetType = models.AssetType(strings.ToLower(value))
+		if !contains(assetTypes, string(asset.AssetType)) {
+			return fmt.Errorf("asset_type %q is not one of %s", value, strings.Join(assetTypes, ", "))
+		}
+		return nil
+	},
+	"brand":                textColumn(func(asset *models.CreateAsset) *string { return &asset.Brand }),
+	"model":                textColumn(func(asset *models.CreateAsset) *string { return &asset.Model }),
+	"serial_no":            textColumn(func(asset *models.CreateAsset) *string { return &asset.SerialNo }),
+	"purchased_date":       dateColumn("purchased_date", func(asset *models.CreateAsset) *time.Time { return &asset.PurchasedDate }),
+	"warranty_start_date":  dateColumn("warranty_start_date", func(asset *models.CreateAsset) *time.Time { return &asset.WarrantyStartDate }),
+	"warranty_expiry_date": dateColumn("warranty_expiry_date", func(asset *models.CreateAsset) *time.Time { return &asset.WarrantyExpiryDate }),
+	"owned_by": func(asset *models.CreateAsset, value string) error {
+		if value == "" {
+			return nil
+		}
+		if !contains(assetOwners, value) {
+			return fmt.Errorf("owned_by %q is not one of %s", value, strings.Join(assetOwners, ", "))
+		}
+		asset.OwnedBy = value
+		return nil
+	},
+	"client_name":       textColumn(func(asset *models.CreateAsset) *string { return &asset.ClientName }),
+	"series":            textColumn(func(asset *models.CreateAsset) *string { return &asset.Series }),
+	"processor":         textColumn(func(asset *models.CreateAsset) *string { return &asset.Processor }),
+	"ram":               textColumn(func(asset *models.CreateAsset) *string { return &asset.RAM }),
+	"operating_system":  textColumn(func(asset *models.CreateAsset) *string { return &asset.OperatingSystem }),
+	"screen_resolution": textColumn(func(asset *models.CreateAsset) *string { return &asset.ScreenResolution }),
+	"storage":           textColumn(func(asset *models.CreateAsset) *string { return &asset.Storage }),
+	"os_type":           textColumn(func(asset *models.CreateAsset) *string { return &asset.OsType }),
+	"imei_1":            textColumn(func(asset *models.CreateAsset) *string { return &asset.Imei1 }),
+	"imei_2":            textColumn(func(asset *models.CreateAsset) *string { return &asset.Imei2 }),
+	"sim_no":            textColumn(func(asset *models.CreateAsset) *string { return &asset.SimNo }),
+	"phone_no":          textColumn(func(asset *models.CreateAsset) *string { return &asset.PhoneNo }),
+	"charger": func(asset *models.CreateAsset, value string) error {
+		if value == "" {
+			return nil
+		}
+		charger, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("charger %q is not true or false", value)
+		}
+		asset.Charger = charger
+		return nil
+	},
+}
+
+func textColumn(field func(asset *models.CreateAsset) *string) func(asset *models.CreateAsset, value string) error {
+	return func(asset *models.CreateAsset, value string) error {
+		*field(asset) = value
+		return nil
+	}
+}
+
+func dateColumn(name string, field func(asset *models.CreateAsset) *time.Time) func(asset *models.CreateAsset, value string) error {
+	return func(asset *models.CreateAsset, value string) error {
+		if value == "" {
+			return nil
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return fmt.Errorf("%s %q is not a date like %s", name, value, dateLayout)
+		}
+		*field(asset) = date
+		return nil
+	}
+}
+
+func assetImport(ctx context.Context, args []string) error {
+	flags := newFlags("asset import", "")
+	file := flags.String("file", "-", "CSV file with a header row naming the columns, - for standard input")
+	dryRun := flags.Bool("dry-run", false, "only check the rows")
+	as := actingUserFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "usage: assetctl asset import [flags]\n\n"+
+			"Every row is checked before any asset is created, so a file with a bad row creates nothing. Columns:\n\n"+
+			"  asset_type, brand, purchased_date, warranty_start_date and warranty_expiry_date, all required\n"+
+			"  model, serial_no, owned_by (remote_state or client) and client_name\n"+
+			"  series, processor, ram, operating_system, charger, screen_resolution and storage for laptops\n"+
+			"  storage for hard disks and pen drives, os_type, imei_1, imei_2 and ram for mobiles, sim_no and phone_no for sims\n\n"+
+			"flags:\n")
+		flags.PrintDefaults()
+	}
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	userID, err := actingUser(ctx, *as)
+	if err != nil {
+		return err
+	}
+
+	input, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	assets, err := readAssets(input)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		fmt.Printf("%d assets can be imported\n", len(assets))
+		return nil
+	}
+
+	for i := range assets {
+		if assets[i].OwnedBy == "" || assets[i].OwnedBy == utils.RemoteState {
+			assets[i].OwnedBy = utils.RemoteState
+			assets[i].ClientName = ""
+		}
+		assetTag, err := store.Assets.Create(ctx, &assets[i], userID)
+		if err != nil {
+			return fmt.Errorf("created %d of %d assets, then failed on %s %s: %w", i, len(assets), assets[i].Brand, assets[i].Model, err)
+		}
+		fmt.Printf("created %s %s %s\n", assetTag, assets[i].Brand, assets[i].Model)
+	}
+	return nil
+}
+
+// readAssets reads and validates every row of an asset CSV, reporting the bad rows by line
+func readAssets(r io.Reader) ([]models.CreateAsset, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		if _, ok := assetColumns[header[i]]; !ok {
+			return nil, fmt.Errorf("unknown column %q", header[i])
+		}
+	}
+
+	assets := make([]models.CreateAsset, 0)
+	problems := make([]string, 0)
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		asset := models.CreateAsset{}
+		for i, value := range row {
+			if err := assetColumns[header[i]](&asset, strings.TrimSpace(value)); err != nil {
+				problems = append(problems, fmt.Sprintf("line %d: %v", line, err))
+			}
+		}
+		if err := validate.Struct(asset); err != nil {
+			problems = append(problems, fmt.Sprintf("line %d: %s", line, describeValidation(err)))
+		}
+		assets = append(assets, asset)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("nothing was imported:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return assets, nil
+}
+
+func assetExport(ctx context.Context, args []string) error {
+	flags := newFlags("asset export", "")
+	output := flags.String("o", "-", "file to write, - for standard output")
+	filters, err := assetFilterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	filters.Limit = exportPageSize
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(out)
+	_ = writer.Write([]string{"asset_tag", "asset_type", "brand", "model", "serial_no", "status", "purchased_date",
+		"warranty_expiry_date", "assigned_to", "location"})
+	for filters.Page = 0; ; filters.Page++ {
+		assets, err := store.Assets.List(ctx, filters)
+		if err != nil {
+			out.Close()
+			return err
+		}
+		for _, asset := range assets.GetAsset {
+			_ = writer.Write([]string{asset.AssetTag, string(asset.AssetType), asset.Brand, asset.Model, asset.SerialNo, asset.Status,
+				asset.PurchasedDate.Format(dateLayout), asset.WarrantyExpiryDate.Format(dateLayout), asset.AssignedTo.String,
+				asset.Location.String})
+		}
+		if len(assets.GetAsset) < exportPageSize {
+			break
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// lookupAsset finds an asset that is not deleted by its tag, serial number or IMEI
+func lookupAsset(ctx context.Context, code string) (models.AssetLookup, error) {
+	asset, err := store.Assets.Lookup(ctx, code)
+	if errors.Is(err, sql.ErrNoRows) {
+		return asset, fmt.Errorf("there is no asset %s", code)
+	}
+	return asset, err
+}
+
+// readLines reads the non empty lines of a file, standard input for "-"
+func readLines(path string) ([]string, error) {
+	input, err := openInput(path)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+func check(ctx context.Context, args []string) error {
+	flags := newFlags("check", "")
+	repair := flags.Bool("repair", false, "repair the inconsistencies found, in one transaction")
+	dryRun := flags.Bool("dry-run", false, "with -repair, make the repairs and roll them back to show what they would change")
+	only := flags.String("only", "", "comma separated checks to repair, every check when not set")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	checks := splitList(*only)
+	for _, name := range checks {
+		if !dbhelper.IsConsistencyCheck(name) {
+			return usageError(flags, "-only %q is not a check", name)
+		}
+	}
+	if (*dryRun || len(checks) > 0) && !*repair {
+		return usageError(flags, "-dry-run and -only go with -repair")
+	}
+
+	inconsistencies, err := dbhelper.CheckConsistency(ctx)
+	if err != nil {
+		return err
+	}
+	if len(inconsistencies) == 0 {
+		fmt.Println("no inconsistencies found")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tID\tLABEL\tDETAIL")
+	for _, inconsistency := range inconsistencies {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", inconsistency.Check, inconsistency.ID, inconsistency.Label, inconsistency.Detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if !*repair {
+		return fmt.Errorf("found %d inconsistencies, run check -repair -dry-run to preview their repair", len(inconsistencies))
+	}
+
+	repairs, err := dbhelper.RepairConsistency(ctx, checks, *dryRun)
+	if err != nil {
+		return err
+	}
+	verb := "repaired"
+	if *dryRun {
+		verb = "would repair"
+	}
+	fmt.Println()
+	for _, repair := range repairs {
+		fmt.Printf("%s %d rows of %s: %s\n  %s\n", verb, len(repair.IDs), repair.Check, repair.Action, strings.Join(repair.IDs, "\n  "))
+	}
+	if len(repairs) == 0 {
+		fmt.Println("nothing to repair")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/hrsync"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+func employeeSearch(ctx context.Context, args []string) error {
+	flags := newFlags("employee search", "")
+	filters, err := employeeFilterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+
+	employees, err := store.Employees.List(ctx, filters)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tTYPE\tSTATUS\tDEPARTMENT\tMANAGER\tASSETS")
+	for _, employee := range employees.GetEmployee {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\n", employee.ID, employee.Name, employee.Email, employee.Type, employee.Status,
+			employee.Department.String, employee.ManagerName.String, employee.AssetQuantity)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Printf("\n%d of %d employees\n", len(employees.GetEmployee), employees.TotalCount)
+	return nil
+}
+
+// employeeFilterFlags adds the list filters shared by search and export and parses them into filters
+func employeeFilterFlags(flags *flag.FlagSet, args []string) (*models.FiltersCheck, error) {
+	name := flags.String("name", "", "only employees whose name contains this")
+	employeeID := flags.String("id", "", "only the employee with this id")
+	deleted := flags.Bool("deleted", false, "list deleted employees instead of active ones")
+	notAnEmployee := flags.Bool("not-an-employee", false, "list former employees instead of active ones")
+	holding := flags.String("holding", "", "comma separated asset types the employees hold: "+strings.Join(assetTypes, ", "))
+	departmentID := flags.String("department", "", "id of a department, its sub departments included")
+	limit := flags.Int("limit", utils.DefaultLimit, "number of employees per page")
+	page := flags.Int("page", 0, "page to show, from 0")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+
+	filters := &models.FiltersCheck{
+		SearchedName:  *name,
+		IsSearched:    *name != "",
+		EmployeeID:    *employeeID,
+		Deleted:       *deleted,
+		NotAnEmployee: *notAnEmployee,
+		AssetTypes:    pq.StringArray(splitList(*holding)),
+		DepartmentID:  *departmentID,
+		Limit:         *limit,
+		Page:          *page,
+	}
+	for _, assetType := range filters.AssetTypes {
+		if !contains(assetTypes, assetType) {
+			return nil, usageError(flags, "-holding %q is not one of %s", assetType, strings.Join(assetTypes, ", "))
+		}
+	}
+	return filters, nil
+}
+
+func employeeImport(ctx context.Context, args []string) error {
+	flags := newFlags("employee import", "")
+	file := flags.String("file", "", "HR export with a header row, in the format of the HR_CSV_DIR drops")
+	dryRun := flags.Bool("dry-run", false, "only log and print the changes the file would make")
+	deactivateMissing := flags.Bool("deactivate-missing", false, "the file is the full roster, deactivate HR managed employees missing from it")
+	as := flags.String("as", os.Getenv(actingUserEnv), "email of the user the sync run is recorded against, defaults to $"+actingUserEnv)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return usageError(flags, "-file is required")
+	}
+	options := hrsync.Options{
+		Source:            hrsync.SourceCSV,
+		FileName:          null.StringFrom(filepath.Base(*file)),
+		DryRun:            *dryRun,
+		DeactivateMissing: *deactivateMissing,
+	}
+	if *as != "" {
+		userID, err := actingUser(ctx, *as)
+		if err != nil {
+			return err
+		}
+		options.TriggeredBy = null.StringFrom(userID)
+	}
+
+	input, err := openInput(*file)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	records, rejected, err := hrsync.ParseCSV(input)
+	if err != nil {
+		return err
+	}
+	run, err := hrsync.Run(ctx, records, rejected, options)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ACTION\tEMAIL\tMESSAGE")
+	for _, change := range run.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", change.Action, change.Email, change.Message)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	verb := "made"
+	if run.DryRun {
+		verb = "would make"
+	}
+	fmt.Printf("\nsync run %s %s %d creations, %d updates and %d deactivations with %d errors\n",
+		run.ID, verb, run.CreatedCount, run.UpdatedCount, run.DeactivatedCount, run.ErrorCount)
+	if run.ErrorCount > 0 {
+		return fmt.Errorf("%d rows were not synced", run.ErrorCount)
+	}
+	return nil
+}
+
+func employeeExport(ctx context.Context, args []string) error {
+	flags := newFlags("employee export", "")
+	output := flags.String("o", "-", "file to write, - for standard output")
+	filters, err := employeeFilterFlags(flags, args)
+	if err != nil {
+		return err
+	}
+	filters.Limit = exportPageSize
+
+	out, err := createOutput(*output)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(out)
+	_ = writer.Write([]string{"id", "name", "email", "phone_no", "type", "status", "department", "manager", "asset_quantity"})
+	for filters.Page = 0; ; filters.Page++ {
+		employees, err := store.Employees.List(ctx, filters)
+		if err != nil {
+			out.Close()
+			return err
+		}
+		for _, employee := range employees.GetEmployee {
+			_ = writer.Write([]string{employee.ID, employee.Name, employee.Email, employee.PhoneNo, employee.Type, employee.Status,
+				employee.Department.String, employee.ManagerName.String, strconv.Itoa(employee.AssetQuantity)})
+		}
+		if len(employees.GetEmployee) < exportPageSize {
+			break
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// employeeIDByEmail finds the employee who is not deleted with the email
+func employeeIDByEmail(ctx context.Context, email string) (string, error) {
+	employeeID, err := dbhelper.GetEmployeeIDByEmail(ctx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return employeeID, fmt.Errorf("there is no employee %s", email)
+	}
+	return employeeID, err
+}
@@ -0,0 +1,263 @@
+// Command assetctl runs the operational tasks of the asset management service that have no screen in the admin
+// panel: managing admin users, searching, assigning and retiring assets, importing and exporting data and checking the
+// data for inconsistencies. It talks to the database of the service directly, through the same helpers the service
+// uses, and reads the same DB_HOST, DB_PORT, DB_NAME, DB_USER and DB_PASSWORD configuration.
+package main
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// actingUserEnv is the email of the user changes are recorded against when -as is not given
+	actingUserEnv = "ASSETCTL_USER"
+)
+
+// command is a subcommand such as "user create", run with the arguments following its name
+type command struct {
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]map[string]command{
+	"user": {
+		"create":         {"create an admin panel user", userCreate},
+		"list":           {"list users with their access and last sign in", userList},
+		"set":            {"set the type, status or role of a user, e.g. to unblock them", userSet},
+		"reset-password": {"replace the password of a user and end their sessions", userResetPassword},
+	},
+	"asset": {
+		"search":   {"search assets", assetSearch},
+		"assign":   {"assign an asset to an employee", assetAssign},
+		"retrieve": {"take an asset back from an employee", assetRetrieve},
+		"retire":   {"delete assets in bulk", assetRetire},
+		"import":   {"create assets from a CSV file", assetImport},
+		"export":   {"write assets as CSV", assetExport},
+	},
+	"employee": {
+		"search": {"search employees", employeeSearch},
+		"import": {"sync employees from an HR export", employeeImport},
+		"export": {"write employees as CSV", employeeExport},
+	},
+	"check": {
+		"": {"report data inconsistencies and repair them", check},
+	},
+}
+
+// errUsage is returned by commands called with wrong arguments, the usage is printed by the flag set
+var errUsage = errors.New("wrong usage")
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("assetctl", flag.ContinueOnError)
+	verbose := flags.Bool("v", false, "log the database errors of the helpers")
+	flags.Usage = func() { printUsage(flags.Output()) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if !*verbose {
+		logrus.SetOutput(io.Discard)
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return 2
+	}
+	group, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		printUsage(os.Stderr)
+		return 2
+	}
+	cmd, ok := group[""]
+	args = args[1:]
+	if !ok {
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "%s needs a subcommand\n\n", flags.Arg(0))
+			printUsage(os.Stderr)
+			return 2
+		}
+		if cmd, ok = group[args[0]]; !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flags.Arg(0)+" "+args[0])
+			printUsage(os.Stderr)
+			return 2
+		}
+		args = args[1:]
+	}
+
+	err := cmd.run(context.Background(), args)
+	if database.AssetManagement != nil {
+		database.ShutdownDatabase()
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		}
+		fmt.Fprintf(os.Stderr, "assetctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, "usage: assetctl [-v] <command> [<subcommand>] [flags]\n\ncommands:\n")
+	groups := make([]string, 0, len(commands))
+	for name := range commands {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		names := make([]string, 0, len(commands[group]))
+		for name := range commands[group] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(w, "  %-24s %s\n", strings.TrimSpace(group+" "+name), commands[group][name].summary)
+		}
+	}
+	fmt.Fprintf(w, `
+Run a command with -h for its flags. The database is read from DB_HOST, DB_PORT, DB_NAME, DB_USER and DB_PASSWORD.
+Changes are recorded against the user whose email is given with -as or in %s.
+`, actingUserEnv)
+}
+
+// connect connects with the database of the service without migrating it, migrations are left to the service
+func connect() error {
+	err := database.Connect(
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		database.SSLModeDisable)
+	if err != nil {
+		return fmt.Errorf("cannot connect to the database: %w", err)
+	}
+	return nil
+}
+
+// newFlags returns the flag set of a subcommand, printing its usage line and flags on -h or wrong flags
+func newFlags(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: assetctl %s [flags] %s\n\nflags:\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses the flags of a subcommand, mapping bad flags onto errUsage, and connects with the database once
+// they are fine so that -h works without one
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return connect()
+}
+
+// usageError prints the problem and the usage of the subcommand and returns errUsage
+func usageError(flags *flag.FlagSet, format string, args ...interface{}) error {
+	fmt.Fprintf(flags.Output(), format+"\n\n", args...)
+	flags.Usage()
+	return errUsage
+}
+
+// actingUserFlag adds the -as flag of the commands that record who made a change
+func actingUserFlag(flags *flag.FlagSet) *string {
+	return flags.String("as", os.Getenv(actingUserEnv), "email of the user the change is recorded against, defaults to $"+actingUserEnv)
+}
+
+// actingUser returns the id of the user with the email given to -as
+func actingUser(ctx context.Context, email string) (string, error) {
+	if email == "" {
+		return "", fmt.Errorf("set -as or %s to the email of the user making the change", actingUserEnv)
+	}
+	return userIDByEmail(ctx, email)
+}
+
+func userIDByEmail(ctx context.Context, email string) (string, error) {
+	credentials, err := dbhelper.FetchPasswordAndID(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("there is no user %s", email)
+		}
+		return "", err
+	}
+	return credentials.ID, nil
+}
+
+// parseDate reads a date flag, today when it is empty
+func parseDate(name, value string) (time.Time, error) {
+	if value == "" {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return date, fmt.Errorf("-%s %q is not a date like %s", name, value, dateLayout)
+	}
+	return date, nil
+}
+
+// splitList splits a comma separated flag, ignoring empty items
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// openInput opens the file to import, standard input for "-"
+func openInput(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+// createOutput creates the file to export to, standard output for "-"
+func createOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	userTypes    = []string{utils.Authorized, utils.UnAuthorized, utils.Blocked}
+	userStatuses = []string{utils.Whitelisted, utils.Blocklisted, utils.Warned}
+	userRoles    = []string{utils.RoleAdmin, utils.RoleViewer}
+)
+
+var validate = validator.New()
+
+// describeValidation lists the fields that failed validation with the rule each of them broke
+func describeValidation(err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+	problems := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		problems = append(problems, fmt.Sprintf("%s fails %s", fieldErr.Field(), fieldErr.Tag()))
+	}
+	return "invalid " + strings.Join(problems, ", ")
+}
+
+func userCreate(ctx context.Context, args []string) error {
+	flags := newFlags("user create", "")
+	name := flags.String("name", "", "name of the user")
+	email := flags.String("email", "", "email the user signs in with")
+	phoneNo := flags.String("phone", "", "ten digit phone number")
+	role := flags.String("role", utils.RoleAdmin, "role of the user: "+strings.Join(userRoles, ", "))
+	password := flags.String("password", "", "password of the user, read from standard input when not set")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if !contains(userRoles, *role) {
+		return usageError(flags, "-role %q is not one of %s", *role, strings.Join(userRoles, ", "))
+	}
+	if *password == "" {
+		var err error
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+
+	user := models.RegisterUser{Name: *name, Email: *email, PhoneNo: *phoneNo, Password: *password}
+	if err := validate.Struct(user); err != nil {
+		return usageError(flags, "%s", describeValidation(err))
+	}
+	exists, err := dbhelper.IsUserExist(ctx, user.Email, user.PhoneNo)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("user %s already exists", user.Email)
+	}
+	hashedPassword, err := utils.HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	if err := dbhelper.CreateUserWithRole(ctx, user.Name, user.Email, hashedPassword, user.PhoneNo, *role); err != nil {
+		return fmt.Errorf("cannot create user: %w", err)
+	}
+	fmt.Printf("created %s %s\n", *role, user.Email)
+	return nil
+}
+
+func userList(ctx context.Context, args []string) error {
+	flags := newFlags("user list", "")
+	unauthorized := flags.Bool("unauthorized", false, "list the users who are not authorized, blocked ones included")
+	search := flags.String("name", "", "only list users whose name contains this")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	userType := utils.Authorized
+	if *unauthorized {
+		userType = utils.UnAuthorized
+	}
+	users, err := dbhelper.AccessedByDetails(ctx, userType, &models.FiltersCheck{IsSearched: *search != "", SearchedName: *search})
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tEMAIL\tSTATUS\tATTEMPTS\tLAST SIGN IN")
+	for _, user := range users.AccessedBy {
+		lastSignIn := "never"
+		if user.LastLoginTime.Valid {
+			lastSignIn = user.LastLoginTime.Time.Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", user.ID, user.Name, user.Email, user.Status, user.AuthenticationTimes, lastSignIn)
+	}
+	return w.Flush()
+}
+
+func userSet(ctx context.Context, args []string) error {
+	flags := newFlags("user set", "")
+	email := flags.String("email", "", "email of the user")
+	userType := flags.String("type", "", "type of the user: "+strings.Join(userTypes, ", "))
+	status := flags.String("status", "", "status of the user: "+strings.Join(userStatuses, ", "))
+	role := flags.String("role", "", "role of the user: "+strings.Join(userRoles, ", "))
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), "usage: assetctl user set -email <email> [-type <type>] [-status <status>] [-role <role>]\n\n"+
+			"Setting the type or status restarts the count of sign in attempts. To unblock a user:\n\n"+
+			"  assetctl user set -email someone@remotestate.com -type authorized -status white_listed\n\nflags:\n")
+		flags.PrintDefaults()
+	}
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	switch {
+	case *email == "":
+		return usageError(flags, "-email is required")
+	case *userType == "" && *status == "" && *role == "":
+		return usageError(flags, "set at least one of -type, -status and -role")
+	case *userType != "" && !contains(userTypes, *userType):
+		return usageError(flags, "-type %q is not one of %s", *userType, strings.Join(userTypes, ", "))
+	case *status != "" && !contains(userStatuses, *status):
+		return usageError(flags, "-status %q is not one of %s", *status, strings.Join(userStatuses, ", "))
+	case *role != "" && !contains(userRoles, *role):
+		return usageError(flags, "-role %q is not one of %s", *role, strings.Join(userRoles, ", "))
+	}
+
+	userID, err := userIDByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	if *userType != "" || *status != "" {
+		current, err := dbhelper.GetStatusDetails(ctx, *email)
+		if err != nil {
+			return err
+		}
+		if *userType == "" {
+			*userType = current.Type
+		}
+		if *status == "" {
+			*status = current.Status
+		}
+		if err := dbhelper.SetUserAccess(ctx, userID, *userType, *status); err != nil {
+			return err
+		}
+		fmt.Printf("%s is %s and %s\n", *email, *userType, *status)
+	}
+	if *role != "" {
+		if err := dbhelper.SetUserRole(ctx, userID, *role); err != nil {
+			return err
+		}
+		fmt.Printf("%s is a %s\n", *email, *role)
+	}
+	return nil
+}
+
+func userResetPassword(ctx context.Context, args []string) error {
+	flags := newFlags("user reset-password", "")
+	email := flags.String("email", "", "email of the user")
+	password := flags.String("password", "", "new password, read from standard input when not set")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usageError(flags, "-email is required")
+	}
+	userID, err := userIDByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	if *password == "" {
+		if *password, err = readPassword(); err != nil {
+			return err
+		}
+	}
+	if err := validate.Var(*password, "min=6"); err != nil {
+		return usageError(flags, "the password needs at least 6 characters")
+	}
+
+	hashedPassword, err := utils.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+	if err := dbhelper.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+	// whoever knew the old password is signed out
+	if err := dbhelper.Logout(ctx, userID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	fmt.Printf("reset the password of %s\n", *email)
+	return nil
+}
+
+// readPassword reads a password from the first line of standard input so that it stays out of the shell history
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		if err != nil {
+			return "", fmt.Errorf("cannot read the password: %w", err)
+		}
+		return "", errors.New("the password is empty")
+	}
+	return line, nil
+}
+
+func contains(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,38 +2,65 @@ package main
 
 import (
 	"InternalAssetManagement/database"
+	"InternalAssetManagement/handler"
+	"InternalAssetManagement/jobs"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/metrics"
+	"InternalAssetManagement/repository"
 	"InternalAssetManagement/server"
+	"InternalAssetManagement/tracing"
+	"context"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-const shutDownTimeOut = 10 * time.Second
+const (
+	shutDownTimeOut          = 10 * time.Second
+	defaultReminderInterval  = time.Hour
+	defaultReminderDaysAhead = 2
+	defaultHRImportInterval  = 15 * time.Minute
+	defaultWebhookInterval   = 10 * time.Second
+	defaultNotificationCheck = 10 * time.Second
+	defaultConsistencyCheck  = 24 * time.Hour
+	defaultRetentionPurge    = 24 * time.Hour
+	defaultQueryTimeout      = 30 * time.Second
+)
 
 func main() {
+	// LOG_LEVEL, e.g. debug, and LOG_FORMAT, json or text, default to info and json
+	if err := logging.Configure(os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT")); err != nil {
+		logrus.Panicf("Failed to configure logging with error: %+v", err)
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
+	// TRACING_EXPORTER is none, stdout or otlp, see tracing.Configure
+	flushTraces, err := tracing.Configure(context.Background(), os.Getenv("TRACING_EXPORTER"))
+	if err != nil {
+		logrus.Panicf("Failed to configure tracing with error: %+v", err)
+	}
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	srv := server.SetupRoutes()
-	if err := database.ConnectAndMigrate(
-		host,
-		port,
-		dbName,
-		user,
-		password,
-		database.SSLModeDisable); err != nil {
-		logrus.Panicf("Failed to initialize and migrate database with error: %+v", err)
-	}
-	logrus.Print("migration successful!!")
+	srv := server.SetupRoutes(handler.NewService(repository.NewPostgres()))
+	database.QueryTimeout = queryTimeout()
+	if autoMigrate() {
+		if err := connectDatabase(database.ConnectAndMigrate); err != nil {
+			logrus.Panicf("Failed to initialize and migrate database with error: %+v", err)
+		}
+		logrus.Print("migration successful!!")
+	} else if err := connectDatabase(database.Connect); err != nil {
+		logrus.Panicf("Failed to initialize database with error: %+v", err)
+	}
+	metrics.RegisterDatabase(database.AssetManagement)
 
 	go func() {
 		if err := srv.Run(":8080"); err != nil && err != http.ErrServerClosed {
@@ -42,13 +69,139 @@ func main() {
 	}()
 	logrus.Print("Server started at :8080")
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	go jobs.StartLoanReminders(jobsCtx, reminderInterval(), reminderDaysAhead())
+	if hrDir := os.Getenv("HR_CSV_DIR"); hrDir != "" {
+		go jobs.StartHRCSVImport(jobsCtx, hrDir, hrImportInterval())
+	}
+	go jobs.StartWebhookDelivery(jobsCtx, webhookInterval())
+	go jobs.StartNotificationDelivery(jobsCtx, notificationInterval())
+	go jobs.StartConsistencyCheck(jobsCtx, consistencyInterval(), consistencyAutoRepair())
+	if days := retentionDays(); days > 0 {
+		go jobs.StartRetentionPurge(jobsCtx, retentionPurgeInterval(), time.Duration(days)*24*time.Hour)
+	}
+
 	<-done
 
 	logrus.Info("shutting down server")
+	// requests still running when the server gives up waiting for them are cancelled before the database is closed
+	if err := srv.Shutdown(shutDownTimeOut); err != nil {
+		logrus.WithError(err).Error("failed to gracefully shutdown server")
+	}
+	stopJobs()
 	if err := database.ShutdownDatabase(); err != nil {
 		logrus.WithError(err).Error("failed to close database connection")
 	}
-	if err := srv.Shutdown(shutDownTimeOut); err != nil {
-		logrus.WithError(err).Panic("failed to gracefully shutdown server")
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), shutDownTimeOut)
+	defer cancelFlush()
+	if err := flushTraces(flushCtx); err != nil {
+		logrus.WithError(err).Error("failed to flush traces")
+	}
+}
+
+// connectDatabase connects with the database read from DB_HOST, DB_PORT, DB_NAME, DB_USER and DB_PASSWORD
+func connectDatabase(connect func(host, port, databaseName, user, password string, sslMode database.SSLMode) error) error {
+	return connect(
+		os.Getenv("DB_HOST"),
+		os.Getenv("DB_PORT"),
+		os.Getenv("DB_NAME"),
+		os.Getenv("DB_USER"),
+		os.Getenv("DB_PASSWORD"),
+		database.SSLModeDisable)
+}
+
+// queryTimeout reads how long a statement may run from DB_QUERY_TIMEOUT e.g. 1m, 0 leaves statements unbounded
+func queryTimeout() time.Duration {
+	timeout, err := time.ParseDuration(os.Getenv("DB_QUERY_TIMEOUT"))
+	if err != nil || timeout < 0 {
+		return defaultQueryTimeout
+	}
+	return timeout
+}
+
+// autoMigrate reads whether the database is migrated up at start from DB_AUTO_MIGRATE, on unless set to false
+func autoMigrate() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("DB_AUTO_MIGRATE"))
+	return err != nil || enabled
+}
+
+// reminderInterval reads how often loan reminders are checked from LOAN_REMINDER_INTERVAL e.g. 30m
+func reminderInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("LOAN_REMINDER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultReminderInterval
+	}
+	return interval
+}
+
+// reminderDaysAhead reads how many days before the due date borrowers are reminded from LOAN_REMINDER_DAYS
+func reminderDaysAhead() int {
+	days, err := strconv.Atoi(os.Getenv("LOAN_REMINDER_DAYS"))
+	if err != nil || days < 0 {
+		return defaultReminderDaysAhead
+	}
+	return days
+}
+
+// hrImportInterval reads how often HR_CSV_DIR is checked for new exports from HR_CSV_INTERVAL e.g. 1h
+func hrImportInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("HR_CSV_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultHRImportInterval
+	}
+	return interval
+}
+
+// webhookInterval reads how often the webhook outbox and due retries are processed from WEBHOOK_INTERVAL e.g. 5s
+func webhookInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("WEBHOOK_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultWebhookInterval
+	}
+	return interval
+}
+
+// notificationInterval reads how often queued emails and due retries are sent from NOTIFICATION_INTERVAL e.g. 5s
+func notificationInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("NOTIFICATION_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultNotificationCheck
+	}
+	return interval
+}
+
+// consistencyInterval reads how often the data consistency checks run from CONSISTENCY_CHECK_INTERVAL e.g. 6h
+func consistencyInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("CONSISTENCY_CHECK_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultConsistencyCheck
+	}
+	return interval
+}
+
+// consistencyAutoRepair reads whether the consistency check also repairs what it finds from CONSISTENCY_AUTO_REPAIR,
+// off unless set to true
+func consistencyAutoRepair() bool {
+	enabled, err := strconv.ParseBool(os.Getenv("CONSISTENCY_AUTO_REPAIR"))
+	return err == nil && enabled
+}
+
+// retentionDays reads after how many days deleted assets and employees are purged for good from RETENTION_DAYS, they
+// are kept forever when it is not set
+func retentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		return 0
+	}
+	return days
+}
+
+// retentionPurgeInterval reads how often deleted records past RETENTION_DAYS are purged from RETENTION_PURGE_INTERVAL
+// e.g. 12h
+func retentionPurgeInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("RETENTION_PURGE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultRetentionPurge
 	}
+	return interval
 }
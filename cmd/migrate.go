@@ -0,0 +1,156 @@
+package main
+
+import (
+	"InternalAssetManagement/database"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const migrateUsage = `usage: StoreX migrate <command>
+
+commands:
+  up [N]      apply all pending migrations, or only the next N
+  down N      roll back the last N migrations
+  goto V      migrate up or down to version V
+  version     print the version the database is at
+  force V     set the version without running migrations, to recover from a failed one; -1 clears it
+  status      list every migration and whether it is applied
+
+The database is read from DB_HOST, DB_PORT, DB_NAME, DB_USER and DB_PASSWORD.
+`
+
+// migrateLogger prints each migration as it runs
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...interface{}) {
+	fmt.Printf(format, v...)
+}
+
+func (migrateLogger) Verbose() bool {
+	return true
+}
+
+// runMigrate runs a migrate subcommand and returns the exit code of the process
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	command, args := args[0], args[1:]
+	switch command {
+	case "up", "down", "goto", "version", "force", "status":
+	case "help", "-h", "--help":
+		fmt.Print(migrateUsage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\n\n%s", command, migrateUsage)
+		return 2
+	}
+
+	if err := connectDatabase(database.Connect); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot connect to the database: %v\n", err)
+		return 1
+	}
+	m, err := database.NewMigrator(database.AssetManagement)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot read the migrations: %v\n", err)
+		return 1
+	}
+	defer m.Close()
+	m.Log = migrateLogger{}
+
+	if err := migrateCommand(m, command, args); err != nil {
+		if errors.Is(err, migrate.ErrNoChange) {
+			fmt.Println("no change")
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "migrate %s: %v\n", command, err)
+		return 1
+	}
+	return 0
+}
+
+func migrateCommand(m *migrate.Migrate, command string, args []string) error {
+	switch command {
+	case "up":
+		if len(args) == 0 {
+			return m.Up()
+		}
+		steps, err := migrateArg(args, 1)
+		if err != nil {
+			return err
+		}
+		return m.Steps(steps)
+	case "down":
+		// rolling back everything drops all the data, so the number of migrations is never implied
+		steps, err := migrateArg(args, 1)
+		if err != nil {
+			return err
+		}
+		return m.Steps(-steps)
+	case "goto":
+		version, err := migrateArg(args, 1)
+		if err != nil {
+			return err
+		}
+		return m.Migrate(uint(version))
+	case "force":
+		version, err := migrateArg(args, -1)
+		if err != nil {
+			return err
+		}
+		return m.Force(version)
+	case "version":
+		version, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("no migration applied")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Println(versionLine(version, dirty))
+		return nil
+	default:
+		statuses, version, dirty, err := database.MigrationsStatus(m)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%-8s %5d  %s\n", state, status.Version, status.Name)
+		}
+		if version == 0 {
+			fmt.Println("no migration applied")
+		} else {
+			fmt.Println(versionLine(version, dirty))
+		}
+		return nil
+	}
+}
+
+// migrateArg reads the single number a command takes, which must not be below least
+func migrateArg(args []string, least int) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("expected one number, got %d arguments", len(args))
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < least {
+		return 0, fmt.Errorf("%q is not a number from %d", args[0], least)
+	}
+	return n, nil
+}
+
+func versionLine(version uint, dirty bool) string {
+	if dirty {
+		return fmt.Sprintf("version %d (dirty: the last migration failed, fix it and run force)", version)
+	}
+	return fmt.Sprintf("version %d", version)
+}
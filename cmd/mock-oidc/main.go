@@ -0,0 +1,226 @@
+// Command mock-oidc is a local OpenID Connect provider for trying out single sign-on without a real identity provider.
+// Every authorization request is approved for the user configured through MOCK_OIDC_EMAIL, MOCK_OIDC_NAME and
+// MOCK_OIDC_GROUPS, a login_hint on the request overrides the email. Point the server at it with
+//
+//	OIDC_ISSUER=http://localhost:9090 OIDC_CLIENT_ID=asset-management \
+//	OIDC_REDIRECT_URL=http://localhost:8080/asset-management/sso/callback \
+//	OIDC_ALLOWED_DOMAINS=remotestate.com OIDC_ROLE_MAPPING=asset-admins=admin,asset-viewers=viewer
+//
+// and open http://localhost:8080/asset-management/sso/login in a browser.
+package main
+
+import (
+	"InternalAssetManagement/oidc"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	keyBits       = 2048
+	keyID         = "mock-oidc"
+	tokenValidity = 5 * time.Minute
+	codeValidity  = time.Minute
+	readTimeout   = 10 * time.Second
+)
+
+type authorization struct {
+	clientID      string
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	issuer string
+	key    *rsa.PrivateKey
+	name   string
+	email  string
+	groups []string
+
+	mu    sync.Mutex
+	codes map[string]authorization
+}
+
+func main() {
+	addr := envOr("MOCK_OIDC_ADDR", ":9090")
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		logrus.Panicf("cannot generate signing key: %+v", err)
+	}
+
+	p := &provider{
+		issuer: strings.TrimSuffix(envOr("MOCK_OIDC_ISSUER", "http://localhost"+addr), "/"),
+		key:    key,
+		name:   envOr("MOCK_OIDC_NAME", "Mock Admin"),
+		email:  envOr("MOCK_OIDC_EMAIL", "admin@remotestate.com"),
+		groups: strings.Split(envOr("MOCK_OIDC_GROUPS", "asset-admins"), ","),
+		codes:  make(map[string]authorization),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/jwks", p.jwks)
+
+	logrus.Printf("mock OIDC provider %s listening on %s", p.issuer, addr)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: readTimeout}
+	if err := srv.ListenAndServe(); err != nil {
+		logrus.Panicf("Failed to run mock provider with error: %+v", err)
+	}
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// authorize approves every request straight away and redirects back with a code
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "only the code flow with S256 PKCE is supported", http.StatusBadRequest)
+		return
+	}
+
+	code, err := oidc.RandomString()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	email := p.email
+	if hint := query.Get("login_hint"); hint != "" {
+		email = hint
+	}
+	p.mu.Lock()
+	p.codes[code] = authorization{
+		clientID:      query.Get("client_id"),
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		email:         email,
+		expiresAt:     time.Now().Add(codeValidity),
+	}
+	p.mu.Unlock()
+
+	values := redirectURI.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectURI.RawQuery = values.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		respond(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	challenge := oidc.CodeChallenge(r.PostForm.Get("code_verifier"))
+	switch {
+	case !ok || time.Now().After(auth.expiresAt):
+		respond(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown or expired code"})
+		return
+	case auth.redirectURI != r.PostForm.Get("redirect_uri"):
+		respond(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "redirect_uri mismatch"})
+		return
+	case subtle.ConstantTimeCompare([]byte(challenge), []byte(auth.codeChallenge)) != 1:
+		respond(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            p.issuer,
+		"sub":            "mock|" + auth.email,
+		"aud":            auth.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(tokenValidity).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": true,
+		"name":           p.name,
+		"groups":         p.groups,
+	})
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		respond(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	accessToken, err := oidc.RandomString()
+	if err != nil {
+		respond(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	respond(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(tokenValidity.Seconds()),
+		"id_token":     signed,
+	})
+}
+
+func (p *provider) jwks(w http.ResponseWriter, r *http.Request) {
+	respond(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": "RS256",
+			"kid": keyID,
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}},
+	})
+}
+
+func respond(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logrus.Errorf("Failed to respond JSON with error: %+v", err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,34 @@
+// Command openapi-gen writes the v2 OpenAPI document and the typed client generated from it. Run it after changing a
+// route or a model the API uses, with make openapi or go generate ./openapi, and commit both files.
+package main
+
+import (
+	"InternalAssetManagement/openapi"
+	"flag"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	specPath := flag.String("spec", "openapi/openapi.json", "where to write the OpenAPI document")
+	clientPath := flag.String("client", "client/client.gen.go", "where to write the generated client")
+	flag.Parse()
+
+	doc := openapi.Build()
+	spec, err := doc.JSON()
+	if err != nil {
+		logrus.Fatalf("cannot encode OpenAPI document: %+v", err)
+	}
+	if err := os.WriteFile(*specPath, spec, 0o644); err != nil {
+		logrus.Fatalf("cannot write %s: %+v", *specPath, err)
+	}
+
+	client, err := openapi.GenerateClient(doc)
+	if err != nil {
+		logrus.Fatalf("cannot generate client: %+v", err)
+	}
+	if err := os.WriteFile(*clientPath, client, 0o644); err != nil {
+		logrus.Fatalf("cannot write %s: %+v", *clientPath, err)
+	}
+}
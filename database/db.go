@@ -1,24 +1,28 @@
 package database
 
 import (
+	"InternalAssetManagement/logging"
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-
-	// source/file import is required for migration files to read
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
 
 	// load pq as database driver
 	_ "github.com/lib/pq"
-	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 var (
 	AssetManagement *sqlx.DB
+	// QueryTimeout is how long the database runs a statement before cancelling it, 0 keeps the setting of the database.
+	// It applies to connections made after it is set, migrations run without it as they can take longer
+	QueryTimeout time.Duration
 )
 
 type SSLMode string
@@ -30,62 +34,103 @@ const (
 
 // ConnectAndMigrate function connects with a given database and returns error if there is any error
 func ConnectAndMigrate(host, port, databaseName, user, password string, sslMode SSLMode) error {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", host, port, user, password, databaseName, sslMode)
-	DB, err := sqlx.Open("postgres", connStr)
-
+	migrationDB, err := open(dataSourceName(host, port, databaseName, user, password, sslMode))
 	if err != nil {
 		return err
 	}
+	defer migrationDB.Close()
+	if err := MigrateUp(migrationDB); err != nil {
+		return err
+	}
+	return Connect(host, port, databaseName, user, password, sslMode)
+}
 
-	err = DB.Ping()
+// Connect connects with a given database without migrating it
+func Connect(host, port, databaseName, user, password string, sslMode SSLMode) error {
+	connStr := dataSourceName(host, port, databaseName, user, password, sslMode)
+	if QueryTimeout > 0 {
+		// lib/pq passes settings it does not know on to the server for every connection of the pool
+		connStr += fmt.Sprintf(" statement_timeout=%d", QueryTimeout.Milliseconds())
+	}
+	DB, err := open(connStr)
 	if err != nil {
 		return err
 	}
 	AssetManagement = DB
-	return migrateUp(DB)
+	return nil
+}
+
+func dataSourceName(host, port, databaseName, user, password string, sslMode SSLMode) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s", host, port, user, password, databaseName, sslMode)
+}
+
+// open opens a pool of connections and checks it can connect
+func open(connStr string) (*sqlx.DB, error) {
+	// every statement gets a span, a child of the span of the request or job when it is run with their context
+	db, err := otelsql.Open("postgres", connStr,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true, DisableErrSkip: true}))
+	if err != nil {
+		return nil, err
+	}
+	DB := sqlx.NewDb(db, "postgres")
+
+	err = DB.Ping()
+	if err != nil {
+		DB.Close()
+		return nil, err
+	}
+	return DB, nil
 }
 
 func ShutdownDatabase() error {
 	return AssetManagement.Close()
 }
 
-// migrateUp function migrate the database and handles the migration logic
-func migrateUp(db *sqlx.DB) error {
-	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
+// Tx runs fn in a transaction that is committed when fn succeeds and rolled back otherwise. Cancelling ctx, such as
+// when the client of a request goes away, rolls the transaction back. A failed commit is returned like an error of fn
+// since nothing fn did is saved
+func Tx(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := AssetManagement.BeginTxx(ctx, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to start a transaction: %w", err)
 	}
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://database/migrations",
-		"postgres", driver)
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(ctx, tx)
+			panic(p)
+		}
+	}()
 
-	if err != nil {
+	if err := fn(tx); err != nil {
+		rollback(ctx, tx)
 		return err
 	}
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return err
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit the transaction: %w", err)
 	}
 	return nil
 }
 
-// Tx provides the transaction wrapper
-func Tx(fn func(tx *sqlx.Tx) error) error {
-	tx, err := AssetManagement.Beginx()
-	if err != nil {
-		return fmt.Errorf("failed to start a transaction: %+v", err)
+// rollback rolls tx back, a transaction already ended by the cancellation of its context needs no rollback
+func rollback(ctx context.Context, tx *sqlx.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		logging.FromContext(ctx).WithError(err).Error("failed to rollback tx.")
 	}
-	defer func() {
-		if err != nil {
-			if rollBackErr := tx.Rollback(); rollBackErr != nil {
-				logrus.Errorf("failed to rollback tx: %s", rollBackErr)
-			}
-			return
-		}
-		if commitErr := tx.Commit(); commitErr != nil {
-			logrus.Errorf("failed to commit tx: %s", commitErr)
+}
+
+// Savepoint runs fn inside a savepoint of tx so that a failing statement is rolled back without aborting the transaction
+func Savepoint(ctx context.Context, tx *sqlx.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT step"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %+v", err)
+	}
+	if err := fn(); err != nil {
+		if _, rollBackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT step"); rollBackErr != nil {
+			logging.FromContext(ctx).WithError(rollBackErr).Error("failed to rollback to savepoint.")
 		}
-	}()
-	err = fn(tx)
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT step")
 	return err
 }
 
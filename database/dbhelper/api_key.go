@@ -0,0 +1,108 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+func CreateAPIKey(ctx context.Context, apiKey *models.CreateAPIKey, prefix, keyHash, userID string) (string, error) {
+	SQL := `INSERT INTO api_keys(name, key_prefix, key_hash, scopes, allowed_ips, expires_at, created_by)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            RETURNING id`
+	allowedIPs := apiKey.AllowedIPs
+	if allowedIPs == nil {
+		allowedIPs = []string{}
+	}
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, apiKey.Name, prefix, keyHash, pq.Array(apiKey.Scopes), pq.Array(allowedIPs), apiKey.ExpiresAt, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateAPIKey: cannot create api key.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	SQL := `SELECT  id,
+       				name,
+       				key_prefix,
+       				scopes,
+       				allowed_ips,
+       				expires_at,
+       				created_by,
+       				created_at,
+       				last_used_at,
+       				last_used_ip,
+       				revoked_at
+			FROM   api_keys
+			ORDER BY created_at DESC`
+	apiKeys := make([]models.APIKey, 0)
+	err := database.AssetManagement.SelectContext(ctx, &apiKeys, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetAPIKeys: cannot get api keys.")
+		return apiKeys, err
+	}
+	return apiKeys, nil
+}
+
+// GetAPIKey returns the unrevoked key with the given hash whose creator is still an active user, with the role the
+// creator has now
+func GetAPIKey(ctx context.Context, keyHash string) (models.APIKey, error) {
+	SQL := `SELECT  k.id,
+       				k.name,
+       				k.key_prefix,
+       				k.scopes,
+       				k.allowed_ips,
+       				k.expires_at,
+       				k.created_by,
+       				k.created_at,
+       				k.last_used_at,
+       				k.last_used_ip,
+       				k.revoked_at,
+       				u.role AS creator_role
+			FROM   api_keys k
+			    JOIN users u ON u.id = k.created_by
+			WHERE  k.key_hash = $1
+			AND    k.revoked_at IS NULL
+			AND    u.archived_at IS NULL
+			AND    u.type != 'blocked'`
+	var apiKey models.APIKey
+	err := database.AssetManagement.GetContext(ctx, &apiKey, SQL, keyHash)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetAPIKey: cannot get api key.")
+	}
+	return apiKey, err
+}
+
+// TouchAPIKey records the use of a key, at most once a minute to keep busy integrations from writing on every call
+func TouchAPIKey(ctx context.Context, keyID, ip string) error {
+	SQL := `UPDATE api_keys
+            SET    last_used_at = NOW(),
+                   last_used_ip = $2
+            WHERE  id = $1
+            AND    (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute' OR last_used_ip IS DISTINCT FROM $2)`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, keyID, ip)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("TouchAPIKey: cannot record api key use.")
+		return err
+	}
+	return nil
+}
+
+func RevokeAPIKey(ctx context.Context, keyID string) (int64, error) {
+	SQL := `UPDATE api_keys
+            SET    revoked_at = NOW()
+            WHERE  id = $1
+            AND    revoked_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, keyID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RevokeAPIKey: cannot revoke api key.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -2,89 +2,108 @@ package dbhelper
 
 import (
 	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
 	"InternalAssetManagement/utils"
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
-	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
 )
 
-func CreateAsset(db *sqlx.Tx, assetDetails *models.CreateAsset, userID string) (string, error) {
+func NextAssetTag(ctx context.Context, db *sqlx.Tx, prefix string) (string, error) {
+	SQL := `INSERT INTO asset_tag_sequences (prefix, last_value)
+			VALUES ($1, 1)
+			ON CONFLICT (prefix) DO UPDATE
+			SET last_value = asset_tag_sequences.last_value + 1,
+				updated_at = NOW()
+			RETURNING last_value`
+	var sequence int
+	err := db.GetContext(ctx, &sequence, SQL, prefix)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("NextAssetTag: cannot allocate asset tag.")
+		return "", err
+	}
+	return utils.FormatAssetTag(prefix, sequence), nil
+}
+
+func CreateAsset(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, userID string) (string, error) {
 	SQL := `INSERT INTO assets (brand, model, serial_no, asset_type, purchased_date, warranty_start_date, warranty_expiry_date,
-								created_by, owned_by, client_name)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+								created_by, owned_by, client_name, asset_tag, location_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 			RETURNING id`
 	var id string
-	err := db.Get(&id, SQL, assetDetails.Brand, assetDetails.Model, assetDetails.SerialNo, assetDetails.AssetType, assetDetails.PurchasedDate, assetDetails.WarrantyStartDate, assetDetails.WarrantyExpiryDate, userID, assetDetails.OwnedBy, assetDetails.ClientName)
+	err := db.GetContext(ctx, &id, SQL, assetDetails.Brand, assetDetails.Model, assetDetails.SerialNo, assetDetails.AssetType, assetDetails.PurchasedDate, assetDetails.WarrantyStartDate, assetDetails.WarrantyExpiryDate, userID, assetDetails.OwnedBy, assetDetails.ClientName, assetDetails.AssetTag, assetDetails.LocationID)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("CreateAsset: cannot create asset.")
+		logging.FromContext(ctx).WithError(err).Error("CreateAsset: cannot create asset.")
 		return "", err
 	}
 	return id, nil
 }
 
-func CreateLaptopSpecification(db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
+func CreateLaptopSpecification(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
 	SQL := `INSERT INTO laptop_specifications (asset_id, series, processor, ram, operating_system, charger, screen_resolution,
 											   storage)
 			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
-	_, err := db.Exec(SQL, assetID, assetDetails.Series, assetDetails.Processor, assetDetails.RAM, assetDetails.OperatingSystem, assetDetails.Charger, assetDetails.ScreenResolution, assetDetails.Storage)
+	_, err := db.ExecContext(ctx, SQL, assetID, assetDetails.Series, assetDetails.Processor, assetDetails.RAM, assetDetails.OperatingSystem, assetDetails.Charger, assetDetails.ScreenResolution, assetDetails.Storage)
 	if err != nil {
-		logrus.WithError(err).Error("CreateLaptopSpecification: cannot create laptop specification.")
+		logging.FromContext(ctx).WithError(err).Error("CreateLaptopSpecification: cannot create laptop specification.")
 		return err
 	}
 	return nil
 }
 
-func CreatePenDriveSpecification(db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
+func CreatePenDriveSpecification(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
 	SQL := `INSERT INTO pen_drive_specifications (asset_id, storage)
 			VALUES ($1, $2)`
-	_, err := db.Exec(SQL, assetID, assetDetails.Storage)
+	_, err := db.ExecContext(ctx, SQL, assetID, assetDetails.Storage)
 	if err != nil {
-		logrus.WithError(err).Error("CreatePenDriveSpecification: cannot create pen drive specification.")
+		logging.FromContext(ctx).WithError(err).Error("CreatePenDriveSpecification: cannot create pen drive specification.")
 		return err
 	}
 	return nil
 }
 
-func CreateHardDiskSpecification(db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
+func CreateHardDiskSpecification(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
 	SQL := `INSERT INTO hard_disk_specifications (asset_id, storage)
 			VALUES ($1,$2)`
-	_, err := db.Exec(SQL, assetID, assetDetails.Storage)
+	_, err := db.ExecContext(ctx, SQL, assetID, assetDetails.Storage)
 	if err != nil {
-		logrus.WithError(err).Error("CreateHardDiskSpecification: cannot create hard disk specification.")
+		logging.FromContext(ctx).WithError(err).Error("CreateHardDiskSpecification: cannot create hard disk specification.")
 		return err
 	}
 	return nil
 }
 
-func CreateMobileSpecification(db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
+func CreateMobileSpecification(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
 	SQL := `INSERT INTO mobile_specifications (asset_id, os_type, imei_1, imei_2, ram)
 			VALUES ($1, $2, $3, $4, $5)`
-	_, err := db.Exec(SQL, assetID, assetDetails.OsType, assetDetails.Imei1, assetDetails.Imei2, assetDetails.RAM)
+	_, err := db.ExecContext(ctx, SQL, assetID, assetDetails.OsType, assetDetails.Imei1, assetDetails.Imei2, assetDetails.RAM)
 	if err != nil {
-		logrus.WithError(err).Error("CreateMobileSpecification: cannot create mobile specification.")
+		logging.FromContext(ctx).WithError(err).Error("CreateMobileSpecification: cannot create mobile specification.")
 		return err
 	}
 	return nil
 }
 
-func CreateSimSpecification(db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
+func CreateSimSpecification(ctx context.Context, db *sqlx.Tx, assetDetails *models.CreateAsset, assetID string) error {
 	SQL := `INSERT INTO sim_specifications (asset_id, sim_no, phone_no)
 			VALUES ($1, $2, $3)`
-	_, err := db.Exec(SQL, assetID, assetDetails.SimNo, assetDetails.PhoneNo)
+	_, err := db.ExecContext(ctx, SQL, assetID, assetDetails.SimNo, assetDetails.PhoneNo)
 	if err != nil {
-		logrus.WithError(err).Error("CreateSimSpecification: cannot create sim specification.")
+		logging.FromContext(ctx).WithError(err).Error("CreateSimSpecification: cannot create sim specification.")
 		return err
 	}
 	return nil
 }
 
-func GetAssetSpec(assetID, assetType string) ([]models.CreateAsset, error) {
+func GetAssetSpec(ctx context.Context, assetID, assetType string) ([]models.CreateAsset, error) {
 	SQL := `SELECT 
+    				coalesce(asset_tag, '') as asset_tag,
     				brand, 
     				model, 
     				serial_no, 
@@ -96,7 +115,8 @@ func GetAssetSpec(assetID, assetType string) ([]models.CreateAsset, error) {
        				archive_reason,
        				deleted_by,
        				owned_by,
-       				client_name
+       				client_name,
+       				location_id
           `
 	num := 1
 	values := make([]interface{}, 0)
@@ -127,19 +147,19 @@ func GetAssetSpec(assetID, assetType string) ([]models.CreateAsset, error) {
 		values = append(values, assetID)
 	}
 	var assetSpec = make([]models.CreateAsset, 0)
-	err := database.AssetManagement.Select(&assetSpec, SQL, values...)
+	err := database.AssetManagement.SelectContext(ctx, &assetSpec, SQL, values...)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAssetSpec: cannot get asset specifications.")
+		logging.FromContext(ctx).WithError(err).Error("GetAssetSpec: cannot get asset specifications.")
 		return assetSpec, err
 	}
 	return assetSpec, nil
 }
 
-func GetAssetsWithFilters(filterCheck *models.
+func GetAssetsWithFilters(ctx context.Context, filterCheck *models.
 	FiltersCheck) (models.TotalGetAsset, error) {
 	var totalGetAsset models.TotalGetAsset
-	SQL := `WITH cte_asset AS(  SELECT  count(*) over () as total_count,
-                        				a.id,
+	SQL := `WITH cte_asset AS(  SELECT  a.id,
+                                        coalesce(a.asset_tag, '') as asset_tag,
         								brand,
         								model,
         								serial_no,
@@ -148,42 +168,34 @@ func GetAssetsWithFilters(filterCheck *models.
         								a.status,
         								warranty_expiry_date,
                                         case when a.status = 'assigned' then e.id else null end as assigned_to_id,
-                                        case when a.status = 'assigned' OR a.status = 'deleted' then e.name else '' end as name
-								FROM assets a LEFT JOIN employee_asset_relation ear on a.id = ear.asset_id
+                                        case when a.status = 'assigned' OR a.status = 'deleted' then e.name else '' end as name,
+                                        loc.name as location_name
+								FROM assets a LEFT JOIN LATERAL (SELECT employee_id
+																 FROM employee_asset_relation
+																 WHERE asset_id = a.id
+																 ORDER BY retrieved_date DESC NULLS FIRST, assigned_date DESC
+																 LIMIT 1) ear ON true
 												   LEFT JOIN employee e on e.id = ear.employee_id
+												   LEFT JOIN locations loc on loc.id = a.location_id
 								WHERE 
   			`
 	values := make([]interface{}, 0)
 	args := 0
 
+	statuses := make([]string, 0)
 	if filterCheck.Available {
-		filterStr := fmt.Sprintf("a.status = $%d ", args+1)
-		SQL += filterStr
-		args++
-		values = append(values, utils.Available)
+		statuses = append(statuses, utils.Available)
 	}
 	if filterCheck.Assigned {
-		var filterStr string
-		if !filterCheck.Available {
-			filterStr = fmt.Sprintf("a.status = $%d ", args+1)
-		} else {
-			filterStr = fmt.Sprintf("OR a.status = $%d ", args+1)
-		}
-		SQL += filterStr
-		args++
-		values = append(values, utils.Assigned)
+		statuses = append(statuses, utils.Assigned)
 	}
 	if filterCheck.Deleted {
-		var filterStr string
-		if !filterCheck.Available && !filterCheck.Assigned {
-			filterStr = fmt.Sprintf("a.status = $%d ", args+1)
-		} else {
-			filterStr = fmt.Sprintf("OR a.status = $%d ", args+1)
-		}
-		SQL += filterStr
-		args++
-		values = append(values, utils.Deleted)
+		statuses = append(statuses, utils.Deleted)
 	}
+	statusStr := fmt.Sprintf("a.status::text =ANY($%d) ", args+1)
+	SQL += statusStr
+	args++
+	values = append(values, pq.Array(statuses))
 
 	if len(filterCheck.AssetTypes) > 0 {
 		assetStr := fmt.Sprintf("AND a.asset_type =ANY($%d)", args+1)
@@ -192,6 +204,20 @@ func GetAssetsWithFilters(filterCheck *models.
 		values = append(values, pq.Array(filterCheck.AssetTypes))
 	}
 
+	if filterCheck.LocationID != "" {
+		locationStr := fmt.Sprintf(" AND a.location_id IN (%s) ", locationSubtreeSQL(args+1))
+		SQL += locationStr
+		args++
+		values = append(values, filterCheck.LocationID)
+	}
+
+	if filterCheck.DepartmentID != "" {
+		departmentStr := fmt.Sprintf(" AND a.id IN (%s) ", heldByDepartmentSQL(args+1))
+		SQL += departmentStr
+		args++
+		values = append(values, filterCheck.DepartmentID)
+	}
+
 	if filterCheck.SearchedName != "" {
 		nameStr := fmt.Sprintf("AND (brand ilike '%%' || $%d || '%%')", args+1)
 		SQL += nameStr
@@ -211,38 +237,50 @@ func GetAssetsWithFilters(filterCheck *models.
 		values = append(values, time.Now())
 	}
 
+	limit := 0
 	if filterCheck.Pagination {
-		//nolint:gomnd // addition of constant
-		pageStr := fmt.Sprintf("ORDER BY id LIMIT $%d OFFSET $%d)SELECT total_count,id,brand,model,serial_no,asset_type,purchased_date,status,warranty_expiry_date, assigned_to_id, name FROM cte_asset", args+1, args+2)
-		SQL += pageStr
-		values = append(values, filterCheck.Limit, filterCheck.Limit*filterCheck.Page)
-	} else {
-		countStr := `)SELECT id,brand,model,serial_no,asset_type,purchased_date,status,warranty_expiry_date FROM cte_asset`
-		SQL += countStr
+		limit = filterCheck.Limit
 	}
+	pageStr, values, err := pageSQL("cte_asset", assetListColumns, models.AssetList, assetColumns, filterCheck, limit, values)
+	if err != nil {
+		return totalGetAsset, err
+	}
+	SQL += ")" + pageStr
 
 	var assets = make([]models.GetAsset, 0)
-	err := database.AssetManagement.Select(&assets, SQL, values...)
+	err = database.AssetManagement.SelectContext(ctx, &assets, SQL, values...)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAvailableAssets: cannot get available assets.")
+		logging.FromContext(ctx).WithError(err).Error("GetAvailableAssets: cannot get available assets.")
 		return totalGetAsset, err
 	}
+	return assetPage(filterCheck, limit, assets), nil
+}
+
+// assetListColumns are the columns of models.GetAsset the asset lists select
+const assetListColumns = "id, asset_tag, brand, model, serial_no, asset_type, purchased_date, status, warranty_expiry_date, assigned_to_id, name, location_name"
+
+// assetPage is the page of the assets read by pageSQL with limit
+func assetPage(filterCheck *models.FiltersCheck, limit int, assets []models.GetAsset) models.TotalGetAsset {
+	totalGetAsset := models.TotalGetAsset{GetAsset: assets}
 	if len(assets) == 0 {
-		totalGetAsset.GetAsset = []models.GetAsset{}
-		logrus.WithError(err).Error("GetAvailableAssets: got empty array.")
-		return totalGetAsset, err
+		return totalGetAsset
 	}
-
-	totalGetAsset.GetAsset = assets
 	totalGetAsset.TotalCount = assets[0].TotalCount
-	return totalGetAsset, nil
+	end, more := pageEnd(limit, len(assets))
+	totalGetAsset.GetAsset = assets[:end]
+	if more {
+		last := assets[end-1]
+		totalGetAsset.NextCursor = utils.NextCursor(models.AssetList, filterCheck, last.SortKey, last.ID)
+	}
+	return totalGetAsset
 }
 
-func GetAssets(filterCheck *models.FiltersCheck) (models.TotalGetAsset, error) {
+func GetAssets(ctx context.Context, filterCheck *models.FiltersCheck) (models.TotalGetAsset, error) {
 	var totalGetAsset models.TotalGetAsset
 	// language = sql
 	SQL := `with cte_asset AS(select distinct on(a.id)
                               a.id,
+                              coalesce(a.asset_tag, '') as asset_tag,
                               brand,
                               model,
                               serial_no,
@@ -251,12 +289,13 @@ func GetAssets(filterCheck *models.FiltersCheck) (models.TotalGetAsset, error) {
                               a.status,
                               warranty_expiry_date,
                               case when is_available = false then e.id else null end as assigned_to_id,
-                              case when is_available = false then e.name else '' end as name
+                              case when is_available = false then e.name else '' end as name,
+                              loc.name as location_name
                   FROM assets a
                            LEFT JOIN employee_asset_relation ear on a.id = ear.asset_id
-                           LEFT JOIN employee e on ear.employee_id = e.id
+                           LEFT JOIN employee e on ear.employee_id = e.id AND e.archived_at IS NULL
+                           LEFT JOIN locations loc on loc.id = a.location_id
                   WHERE a.archived_at IS NULL
-                    AND e.archived_at IS NULL
 `
 	values := make([]interface{}, 0)
 	args := 0
@@ -268,6 +307,20 @@ func GetAssets(filterCheck *models.FiltersCheck) (models.TotalGetAsset, error) {
 		values = append(values, pq.Array(filterCheck.AssetTypes))
 	}
 
+	if filterCheck.LocationID != "" {
+		locationStr := fmt.Sprintf(" AND a.location_id IN (%s) ", locationSubtreeSQL(args+1))
+		SQL += locationStr
+		args++
+		values = append(values, filterCheck.LocationID)
+	}
+
+	if filterCheck.DepartmentID != "" {
+		departmentStr := fmt.Sprintf(" AND a.id IN (%s) ", heldByDepartmentSQL(args+1))
+		SQL += departmentStr
+		args++
+		values = append(values, filterCheck.DepartmentID)
+	}
+
 	if filterCheck.SearchedName != "" {
 		nameStr := fmt.Sprintf("AND (brand ilike '%%' || $%d || '%%')", args+1)
 		SQL += nameStr
@@ -286,30 +339,31 @@ func GetAssets(filterCheck *models.FiltersCheck) (models.TotalGetAsset, error) {
 		args++
 		values = append(values, time.Now())
 	}
-	//nolint:gomnd // addition of constant
-	pageStr := fmt.Sprintf("ORDER BY a.id, ear.retrieved_date DESC)SELECT count(*) over() as total_count, id,  brand, model, serial_no, asset_type, purchased_date, status, warranty_expiry_date, assigned_to_id, name FROM  cte_asset LIMIT $%d OFFSET $%d", args+1, args+2)
-	SQL += pageStr
-	values = append(values, filterCheck.Limit, filterCheck.Limit*filterCheck.Page)
-
-	var assets = make([]models.GetAsset, 0)
-	err := database.AssetManagement.Select(&assets, SQL, values...)
-	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAssets: cannot get assets.")
+	pageStr, values, err := pageSQL("cte_asset", assetListColumns, models.AssetList, assetColumns, filterCheck, filterCheck.Limit, values)
+	if err != nil {
 		return totalGetAsset, err
 	}
+	SQL += "ORDER BY a.id, ear.retrieved_date DESC)" + pageStr
 
-	if len(assets) == 0 {
-		totalGetAsset.GetAsset = []models.GetAsset{}
-		logrus.WithError(err).Error("GetAssets: got empty array.")
+	var assets = make([]models.GetAsset, 0)
+	err = database.AssetManagement.SelectContext(ctx, &assets, SQL, values...)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetAssets: cannot get assets.")
 		return totalGetAsset, err
 	}
+	return assetPage(filterCheck, filterCheck.Limit, assets), nil
+}
 
-	totalGetAsset.GetAsset = assets
-	totalGetAsset.TotalCount = assets[0].TotalCount
-	return totalGetAsset, nil
+// locationSubtreeSQL returns a sub query selecting a location and all the locations nested inside it
+func locationSubtreeSQL(arg int) string {
+	return fmt.Sprintf(`WITH RECURSIVE cte_location AS (
+							SELECT id FROM locations WHERE id = $%d
+							UNION ALL
+							SELECT l.id FROM locations l JOIN cte_location cl ON l.parent_id = cl.id
+						) SELECT id FROM cte_location`, arg)
 }
 
-func UpdateAsset(assetDetails *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
+func UpdateAsset(ctx context.Context, assetDetails *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
 	SQL := `UPDATE assets
 			SET brand                = $1,
 				model                = $2,
@@ -320,15 +374,15 @@ func UpdateAsset(assetDetails *models.UpdateAssetSpecification, tx *sqlx.Tx) err
 				updated_at           = NOW()
 			WHERE id = $7
 			  AND archived_at IS NULL`
-	_, err := tx.Exec(SQL, assetDetails.Brand, assetDetails.Model, assetDetails.SerialNo, assetDetails.PurchasedDate, assetDetails.WarrantyStartDate, assetDetails.WarrantyExpiryDate, assetDetails.ID)
+	_, err := tx.ExecContext(ctx, SQL, assetDetails.Brand, assetDetails.Model, assetDetails.SerialNo, assetDetails.PurchasedDate, assetDetails.WarrantyStartDate, assetDetails.WarrantyExpiryDate, assetDetails.ID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateAsset: cannot update asset.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateAsset: cannot update asset.")
 		return err
 	}
 	return nil
 }
 
-func UpdateLaptopSpecifications(assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
+func UpdateLaptopSpecifications(ctx context.Context, assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
 	SQL := `UPDATE laptop_specifications
 			SET series            = $1,
 				processor         = $2,
@@ -340,41 +394,41 @@ func UpdateLaptopSpecifications(assetSpecifications *models.UpdateAssetSpecifica
 				updated_at        = NOW()
 			WHERE asset_id = $8
 			  AND archived_at IS NULL`
-	_, err := tx.Exec(SQL, assetSpecifications.Series, assetSpecifications.Processor, assetSpecifications.RAM, assetSpecifications.OperatingSystem, assetSpecifications.Charger, assetSpecifications.ScreenResolution, assetSpecifications.Storage, assetSpecifications.ID)
+	_, err := tx.ExecContext(ctx, SQL, assetSpecifications.Series, assetSpecifications.Processor, assetSpecifications.RAM, assetSpecifications.OperatingSystem, assetSpecifications.Charger, assetSpecifications.ScreenResolution, assetSpecifications.Storage, assetSpecifications.ID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateLaptopSpecifications: cannot update laptop specifications.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateLaptopSpecifications: cannot update laptop specifications.")
 		return err
 	}
 	return nil
 }
 
-func UpdateHardDiskSpecifications(storage, id string, tx *sqlx.Tx) error {
+func UpdateHardDiskSpecifications(ctx context.Context, storage, id string, tx *sqlx.Tx) error {
 	SQL := `UPDATE hard_disk_specifications
 			SET storage = $1
 			WHERE asset_id = $2
 			  AND archived_at IS NULL`
-	_, err := tx.Exec(SQL, storage, id)
+	_, err := tx.ExecContext(ctx, SQL, storage, id)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateHardDiskSpecifications: cannot update hard disk specifications.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateHardDiskSpecifications: cannot update hard disk specifications.")
 		return err
 	}
 	return nil
 }
 
-func UpdatePenDriveSpecifications(storage, id string, tx *sqlx.Tx) error {
+func UpdatePenDriveSpecifications(ctx context.Context, storage, id string, tx *sqlx.Tx) error {
 	SQL := `UPDATE pen_drive_specifications
 			SET storage = $1
 			WHERE asset_id = $2
 			  AND archived_at IS NULL`
-	_, err := tx.Exec(SQL, storage, id)
+	_, err := tx.ExecContext(ctx, SQL, storage, id)
 	if err != nil {
-		logrus.WithError(err).Error("UpdatePenDriveSpecifications: cannot update pen drive specifications.")
+		logging.FromContext(ctx).WithError(err).Error("UpdatePenDriveSpecifications: cannot update pen drive specifications.")
 		return err
 	}
 	return nil
 }
 
-func UpdateMobileSpecifications(assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
+func UpdateMobileSpecifications(ctx context.Context, assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
 	SQL := `UPDATE mobile_specifications
             SET    os_type = $1,
                    imei_1 = $2,
@@ -384,15 +438,15 @@ func UpdateMobileSpecifications(assetSpecifications *models.UpdateAssetSpecifica
             WHERE  asset_id = $5
             AND    archived_at IS NULL 
             `
-	_, err := tx.Exec(SQL, assetSpecifications.OsType, assetSpecifications.Imei1, assetSpecifications.Imei2, assetSpecifications.RAM, assetSpecifications.ID)
+	_, err := tx.ExecContext(ctx, SQL, assetSpecifications.OsType, assetSpecifications.Imei1, assetSpecifications.Imei2, assetSpecifications.RAM, assetSpecifications.ID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateMobileSpecifications: cannot update mobile specifications.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateMobileSpecifications: cannot update mobile specifications.")
 		return err
 	}
 	return nil
 }
 
-func UpdateSimSpecifications(assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
+func UpdateSimSpecifications(ctx context.Context, assetSpecifications *models.UpdateAssetSpecification, tx *sqlx.Tx) error {
 	SQL := `UPDATE sim_specifications
             SET    sim_no = $1,
                    phone_no = $2,
@@ -400,15 +454,15 @@ func UpdateSimSpecifications(assetSpecifications *models.UpdateAssetSpecificatio
             WHERE  asset_id = $3
             AND    archived_at IS NULL 
             `
-	_, err := tx.Exec(SQL, assetSpecifications.SimNo, assetSpecifications.PhoneNo, assetSpecifications.ID)
+	_, err := tx.ExecContext(ctx, SQL, assetSpecifications.SimNo, assetSpecifications.PhoneNo, assetSpecifications.ID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateSimSpecifications: cannot update sim specifications.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateSimSpecifications: cannot update sim specifications.")
 		return err
 	}
 	return nil
 }
 
-func RetrieveAssetByAssetID(db *sqlx.Tx, retrievalDetails *models.ReassignAsset) error {
+func RetrieveAssetByAssetID(ctx context.Context, db *sqlx.Tx, retrievalDetails *models.ReassignAsset) error {
 	SQL := `UPDATE employee_asset_relation
             SET    retrieved_date = $1,
                    retrieval_reason = $2,
@@ -416,15 +470,59 @@ func RetrieveAssetByAssetID(db *sqlx.Tx, retrievalDetails *models.ReassignAsset)
             WHERE  asset_id = $3
             AND    archived_at IS NULL 
             `
-	_, err := db.Exec(SQL, retrievalDetails.RetrievedDate, retrievalDetails.RetrievalReason, retrievalDetails.AssetID)
+	_, err := db.ExecContext(ctx, SQL, retrievalDetails.RetrievedDate, retrievalDetails.RetrievalReason, retrievalDetails.AssetID)
 	if err != nil {
-		logrus.WithError(err).Error("RetrieveAssetByAssetID: cannot retrieve asset.")
+		logging.FromContext(ctx).WithError(err).Error("RetrieveAssetByAssetID: cannot retrieve asset.")
 		return err
 	}
 	return nil
 }
 
-func UpdateAvailableAsset(assetID string, availableBool bool, status string, tx *sqlx.Tx) error {
+// IsAssetAvailable locks the asset row for the rest of the transaction so two assignments of one asset cannot race
+func IsAssetAvailable(ctx context.Context, tx *sqlx.Tx, assetID string) (bool, error) {
+	SQL := `SELECT is_available
+            FROM   assets
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            FOR UPDATE`
+
+	var available bool
+	err := tx.GetContext(ctx, &available, SQL, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetAvailable: cannot check asset availability.")
+		return false, err
+	}
+	return available, nil
+}
+
+// LockAsset locks an asset that is not deleted for the rest of the transaction, sql.ErrNoRows when there is none
+func LockAsset(ctx context.Context, tx *sqlx.Tx, assetID string) error {
+	SQL := `SELECT id
+            FROM   assets
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            FOR UPDATE`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, assetID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("LockAsset: cannot lock asset.")
+	}
+	return err
+}
+
+// IsEmployeeActive checks if an employee exists and is not deleted
+func IsEmployeeActive(ctx context.Context, tx *sqlx.Tx, employeeID string) (bool, error) {
+	SQL := `SELECT EXISTS(SELECT 1 FROM employee WHERE id = $1 AND archived_at IS NULL)`
+	var exists bool
+	err := tx.GetContext(ctx, &exists, SQL, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsEmployeeActive: cannot check employee.")
+		return false, err
+	}
+	return exists, nil
+}
+
+func UpdateAvailableAsset(ctx context.Context, assetID string, availableBool bool, status string, tx *sqlx.Tx) error {
 	SQL := `UPDATE assets
             SET    is_available = $1,
                    status = $3
@@ -432,68 +530,99 @@ func UpdateAvailableAsset(assetID string, availableBool bool, status string, tx
             AND archived_at IS NULL 
             `
 
-	_, err := tx.Exec(SQL, availableBool, assetID, status)
+	_, err := tx.ExecContext(ctx, SQL, availableBool, assetID, status)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateAvailableAsset: cannot update available asset.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateAvailableAsset: cannot update available asset.")
 		return err
 	}
 	return nil
 }
 
-func ReassignAsset(db *sqlx.Tx, reassignDetails *models.ReassignAsset, assignedBy string) error {
-	SQL := `INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_by, assigned_date)
-            VALUES ($1, $2, $3, $4)`
+func ReassignAsset(ctx context.Context, db *sqlx.Tx, reassignDetails *models.ReassignAsset, assignedBy string) error {
+	SQL := `INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_by, assigned_date, assignment_type, due_date)
+            VALUES ($1, $2, $3, $4, $5, $6)`
 
-	_, err := db.Exec(SQL, reassignDetails.EmployeeID, reassignDetails.AssetID, assignedBy, reassignDetails.AssignedDate)
+	_, err := db.ExecContext(ctx, SQL, reassignDetails.EmployeeID, reassignDetails.AssetID, assignedBy, reassignDetails.AssignedDate, reassignDetails.AssignmentType, reassignDetails.DueDate)
 	if err != nil {
-		logrus.WithError(err).Error("ReassignAsset: unable to reassign asset.")
+		logging.FromContext(ctx).WithError(err).Error("ReassignAsset: unable to reassign asset.")
 		return err
 	}
 	return nil
 }
 
-func AvailableAssets(brand, assetType, modelNo string) ([]models.AssignAssetDetails, error) {
+// AvailableAssets lists available assets that are not reserved for any part of the period from the given date until
+// the given end, an empty end meaning the asset is needed indefinitely
+func AvailableAssets(ctx context.Context, brand, assetType, modelNo string, from time.Time, to null.Time) ([]models.AssignAssetDetails, error) {
 	SQL := `SELECT `
 	values := make([]interface{}, 0)
 	args := 1
 	if assetType != "" && brand == "" {
 		assetStr := fmt.Sprintf("DISTINCT ON (brand) brand FROM assets WHERE asset_type = $%d AND archived_at IS NULL AND is_available = true", args)
-		SQL += assetStr
+		SQL += assetStr + notReservedSQL("id", args+1, args+2)
 		values = append(values, assetType)
 	} else if brand != "" {
 		switch {
 		case assetType == utils.Sim:
 			assetStr := fmt.Sprintf("a.id, sim_no FROM assets a LEFT JOIN sim_specifications ss ON a.id = ss.asset_id  WHERE brand = $%d AND a.archived_at IS NULL AND a.is_available = true AND ss.archived_at IS NULL", args)
-			SQL += assetStr
+			SQL += assetStr + notReservedSQL("a.id", args+1, args+2)
 			values = append(values, brand)
 		case modelNo == "":
 			//nolint:gomnd // constant value
 			assetStr := fmt.Sprintf("model FROM assets WHERE brand = $%d AND asset_type = $%d AND archived_at IS NULL AND is_available = true", args, 2)
-			SQL += assetStr
+			SQL += assetStr + notReservedSQL("id", args+2, args+3)
 			values = append(values, brand, assetType)
 		case assetType == utils.Mobile:
 			//nolint:gomnd // constant value
 			assetStr := fmt.Sprintf("a.id, imei_1 FROM assets a LEFT JOIN mobile_specifications ms ON a.id = ms.asset_id WHERE brand = $%d AND model = $%d AND a.archived_at IS NULL AND a.is_available = true AND ms.archived_at IS NULL", args, 2)
-			SQL += assetStr
+			SQL += assetStr + notReservedSQL("a.id", args+2, args+3)
 			values = append(values, brand, modelNo)
 		default:
 			//nolint:gomnd // constant value
 			assetStr := fmt.Sprintf("id, serial_no FROM assets WHERE brand = $%d AND model = $%d AND archived_at IS NULL AND is_available = true", args, 2)
-			SQL += assetStr
+			SQL += assetStr + notReservedSQL("id", args+2, args+3)
 			values = append(values, brand, modelNo)
 		}
 	}
+	if len(values) > 0 {
+		values = append(values, from, to)
+	}
 
 	brandName := make([]models.AssignAssetDetails, 0)
-	err := database.AssetManagement.Select(&brandName, SQL, values...)
+	err := database.AssetManagement.SelectContext(ctx, &brandName, SQL, values...)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("AvailableAssets: cannot get assigned asset details.")
+		return brandName, err
+	}
+	if brand == "" {
+		return brandName, nil
+	}
+
+	// units reserved as "any of this model" are held back from the end of the list
+	reserved, err := modelReservationCounts(ctx, brand, from, to)
 	if err != nil {
-		logrus.WithError(err).Error("AvailableAssets: cannot get assigned asset details.")
 		return brandName, err
 	}
-	return brandName, nil
+	held := make([]bool, len(brandName))
+	for i := len(brandName) - 1; i >= 0; i-- {
+		model := brandName[i].Model
+		if modelNo != "" {
+			model = modelNo
+		}
+		if reserved[model] > 0 {
+			reserved[model]--
+			held[i] = true
+		}
+	}
+	assets := make([]models.AssignAssetDetails, 0, len(brandName))
+	for i := range brandName {
+		if !held[i] {
+			assets = append(assets, brandName[i])
+		}
+	}
+	return assets, nil
 }
 
-func EmployeeHistory(assetID string) ([]models.EmployeeHistory, error) {
+func EmployeeHistory(ctx context.Context, assetID string) ([]models.EmployeeHistory, error) {
 	SQL := `SELECT  e.id,
        				name,
        				email,
@@ -508,29 +637,29 @@ func EmployeeHistory(assetID string) ([]models.EmployeeHistory, error) {
 			AND    asset_id = $1`
 
 	employeeHistory := make([]models.EmployeeHistory, 0)
-	err := database.AssetManagement.Select(&employeeHistory, SQL, assetID)
+	err := database.AssetManagement.SelectContext(ctx, &employeeHistory, SQL, assetID)
 	if err != nil {
-		logrus.WithError(err).Error("EmployeeHistory: cannot get employee history.")
+		logging.FromContext(ctx).WithError(err).Error("EmployeeHistory: cannot get employee history.")
 		return employeeHistory, err
 	}
 	return employeeHistory, nil
 }
 
-func UpdateWarranty(warrantyDetails models.WarrantyDetails) error {
+func UpdateWarranty(ctx context.Context, tx *sqlx.Tx, warrantyDetails models.WarrantyDetails) (int64, error) {
 	SQL := `UPDATE assets
             SET    warranty_start_date = $1,
                    warranty_expiry_date = $2
             WHERE archived_at IS NULL 
             AND   id = $3`
-	_, err := database.AssetManagement.Exec(SQL, warrantyDetails.WarrantyStartDate, warrantyDetails.WarrantyExpiryDate, warrantyDetails.AssetID)
+	result, err := tx.ExecContext(ctx, SQL, warrantyDetails.WarrantyStartDate, warrantyDetails.WarrantyExpiryDate, warrantyDetails.AssetID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateWarranty: cannot update warranty details.")
-		return err
+		logging.FromContext(ctx).WithError(err).Error("UpdateWarranty: cannot update warranty details.")
+		return 0, err
 	}
-	return nil
+	return result.RowsAffected()
 }
 
-func RetrieveAsset(assetRetrievalDetails models.AssetRetrievalDetails, tx *sqlx.Tx) error {
+func RetrieveAsset(ctx context.Context, assetRetrievalDetails models.AssetRetrievalDetails, tx *sqlx.Tx) error {
 	SQL := `UPDATE employee_asset_relation
             SET    retrieved_date = $1,
                    retrieval_reason = $2
@@ -538,110 +667,110 @@ func RetrieveAsset(assetRetrievalDetails models.AssetRetrievalDetails, tx *sqlx.
             AND    asset_id = $4
             AND    retrieved_date IS NULL 
            `
-	_, err := tx.Exec(SQL, assetRetrievalDetails.RetrievedDate, assetRetrievalDetails.RetrievalReason, assetRetrievalDetails.EmployeeID, assetRetrievalDetails.AssetID)
+	_, err := tx.ExecContext(ctx, SQL, assetRetrievalDetails.RetrievedDate, assetRetrievalDetails.RetrievalReason, assetRetrievalDetails.EmployeeID, assetRetrievalDetails.AssetID)
 	if err != nil {
-		logrus.WithError(err).Error("RetrieveAsset: cannot update retrieval details.")
+		logging.FromContext(ctx).WithError(err).Error("RetrieveAsset: cannot update retrieval details.")
 		return err
 	}
 	return nil
 }
 
-func GetAssignedEmployee(asset models.Asset) (int, error) {
+func GetAssignedEmployee(ctx context.Context, asset models.Asset) (int, error) {
 	SQL := `SELECT COUNT(id)
 			FROM employee_asset_relation
 			WHERE asset_id = $1
 			  AND retrieved_date IS NULL
 			  AND archived_at IS NULL`
 	var count int
-	err := database.AssetManagement.Get(&count, SQL, asset.ID)
+	err := database.AssetManagement.GetContext(ctx, &count, SQL, asset.ID)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAssignedEmployee: cannot get assigned employee.")
+		logging.FromContext(ctx).WithError(err).Error("GetAssignedEmployee: cannot get assigned employee.")
 		return -1, err
 	}
 	return count, nil
 }
 
-func GetAssignedAsset(employeeID string) (int, error) {
+func GetAssignedAsset(ctx context.Context, employeeID string) (int, error) {
 	SQL := `SELECT COUNT(id)
 			FROM employee_asset_relation
 			WHERE employee_id = $1
 			  AND retrieved_date IS NULL
 			  AND archived_at IS NULL`
 	var count int
-	err := database.AssetManagement.Get(&count, SQL, employeeID)
+	err := database.AssetManagement.GetContext(ctx, &count, SQL, employeeID)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAssignedAsset: cannot get assigned asset.")
+		logging.FromContext(ctx).WithError(err).Error("GetAssignedAsset: cannot get assigned asset.")
 		return -1, err
 	}
 	return count, nil
 }
 
-func DeleteLaptopSpec(db *sqlx.Tx, id string) error {
+func DeleteLaptopSpec(ctx context.Context, db *sqlx.Tx, id string) error {
 	SQL := `UPDATE laptop_specifications
 			SET archived_at = NOW()
 			WHERE asset_id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, id)
+	_, err := db.ExecContext(ctx, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("DeleteLaptopSpec: cannot delete laptop specifications.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteLaptopSpec: cannot delete laptop specifications.")
 		return err
 	}
 	return nil
 }
 
-func DeletePenDriveSpec(db *sqlx.Tx, id string) error {
+func DeletePenDriveSpec(ctx context.Context, db *sqlx.Tx, id string) error {
 	SQL := `UPDATE pen_drive_specifications
 			SET archived_at = NOW()
 			WHERE asset_id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, id)
+	_, err := db.ExecContext(ctx, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("DeletePenDriveSpec: cannot delete pen drive specifications.")
+		logging.FromContext(ctx).WithError(err).Error("DeletePenDriveSpec: cannot delete pen drive specifications.")
 		return err
 	}
 	return nil
 }
 
-func DeleteHardDiskSpec(db *sqlx.Tx, id string) error {
+func DeleteHardDiskSpec(ctx context.Context, db *sqlx.Tx, id string) error {
 	SQL := `UPDATE hard_disk_specifications
 			SET archived_at = NOW()
 			WHERE asset_id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, id)
+	_, err := db.ExecContext(ctx, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("DeleteHardDiskSpec: cannot delete hard disk specifications.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteHardDiskSpec: cannot delete hard disk specifications.")
 		return err
 	}
 	return nil
 }
 
-func DeleteMobileSpec(db *sqlx.Tx, id string) error {
+func DeleteMobileSpec(ctx context.Context, db *sqlx.Tx, id string) error {
 	SQL := `UPDATE mobile_specifications
 			SET archived_at = NOW()
 			WHERE asset_id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, id)
+	_, err := db.ExecContext(ctx, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("DeleteMobileSpec: cannot delete mobile specifications.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteMobileSpec: cannot delete mobile specifications.")
 		return err
 	}
 	return nil
 }
 
-func DeleteSimSpec(db *sqlx.Tx, id string) error {
+func DeleteSimSpec(ctx context.Context, db *sqlx.Tx, id string) error {
 	SQL := `UPDATE sim_specifications
 			SET archived_at = NOW()
 			WHERE asset_id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, id)
+	_, err := db.ExecContext(ctx, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("DeleteSimSpec: cannot delete sim specifications.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteSimSpec: cannot delete sim specifications.")
 		return err
 	}
 	return nil
 }
 
-func DeleteAsset(db *sqlx.Tx, assetDetails models.Asset, userID string) error {
+func DeleteAsset(ctx context.Context, db *sqlx.Tx, assetDetails models.Asset, userID string) error {
 	SQL := `UPDATE assets
 			SET archived_at = NOW(),
 			    status = $2,
@@ -649,10 +778,54 @@ func DeleteAsset(db *sqlx.Tx, assetDetails models.Asset, userID string) error {
 			    deleted_by = $4
 			WHERE id = $1
 			  AND archived_at IS NULL`
-	_, err := db.Exec(SQL, assetDetails.ID, utils.Deleted, assetDetails.DeleteReason, userID)
+	_, err := db.ExecContext(ctx, SQL, assetDetails.ID, utils.Deleted, assetDetails.DeleteReason, userID)
 	if err != nil {
-		logrus.WithError(err).Error("DeleteAsset: cannot delete asset.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteAsset: cannot delete asset.")
 		return err
 	}
 	return nil
 }
+
+func GetAssetLabels(ctx context.Context, assetIDs []string) ([]models.AssetLabel, error) {
+	SQL := `SELECT  id,
+       				asset_tag,
+       				brand,
+       				model,
+       				serial_no,
+       				asset_type
+			FROM    assets
+			WHERE   archived_at IS NULL
+			AND     asset_tag IS NOT NULL
+			AND     (CARDINALITY($1::uuid[]) = 0 OR id = ANY($1::uuid[]))
+			ORDER BY asset_tag`
+	labels := make([]models.AssetLabel, 0)
+	err := database.AssetManagement.SelectContext(ctx, &labels, SQL, pq.Array(assetIDs))
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetAssetLabels: cannot get asset labels.")
+		return labels, err
+	}
+	return labels, nil
+}
+
+func LookupAsset(ctx context.Context, code string) (models.AssetLookup, error) {
+	SQL := `SELECT  a.id,
+       				a.asset_type
+			FROM    assets a
+			LEFT JOIN mobile_specifications ms ON a.id = ms.asset_id AND ms.archived_at IS NULL
+			WHERE   a.archived_at IS NULL
+			AND     (upper(a.asset_tag) = upper($1)
+			    OR   a.serial_no = $1
+			    OR   ms.imei_1 = $1
+			    OR   ms.imei_2 = $1)
+			ORDER BY upper(a.asset_tag) = upper($1) DESC
+			LIMIT 1`
+	var asset models.AssetLookup
+	err := database.AssetManagement.GetContext(ctx, &asset, SQL, code)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("LookupAsset: cannot lookup asset.")
+		}
+		return asset, err
+	}
+	return asset, nil
+}
@@ -0,0 +1,226 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	CheckArchivedWithoutRetrieval = "archived_without_retrieval"
+	CheckMultipleOpenAssignments  = "multiple_open_assignments"
+	CheckAssignedWithoutHolder    = "assigned_without_holder"
+	CheckHeldButNotAssigned       = "held_but_not_assigned"
+	CheckAssetAvailability        = "asset_availability"
+	CheckMissingLaptopSpec        = "missing_laptop_specifications"
+)
+
+// consistencyChecks select the rows breaking each rule as id, label and detail, and repair them by returning the ids
+// of the rows they changed. An assignment is open while it has neither a retrieval date nor is archived. The checks
+// are repaired in this order so that assets left with one open assignment get their status and availability from it
+var consistencyChecks = []struct {
+	name   string
+	action string
+	SQL    string
+	repair string
+}{
+	{CheckArchivedWithoutRetrieval, "set the retrieval date of the assignment to the day it was archived",
+		`SELECT ear.id,
+                COALESCE(a.asset_tag, '') AS label,
+                'assignment to ' || e.name || ' archived on ' || to_char(ear.archived_at, 'YYYY-MM-DD') || ' without a retrieval date' AS detail
+         FROM   employee_asset_relation ear
+             JOIN assets a ON a.id = ear.asset_id
+             JOIN employee e ON e.id = ear.employee_id
+         WHERE  ear.archived_at IS NOT NULL
+         AND    ear.retrieved_date IS NULL`,
+		`WITH repaired AS (UPDATE employee_asset_relation
+                           SET    retrieved_date = GREATEST(archived_at::date, assigned_date),
+                                  retrieval_reason = COALESCE(retrieval_reason, 'closed by consistency repair')
+                           WHERE  archived_at IS NOT NULL
+                           AND    retrieved_date IS NULL
+                           RETURNING id)
+         SELECT id FROM repaired`},
+	{CheckMultipleOpenAssignments, "close every open assignment of the asset but the latest, on the day the latest began",
+		`SELECT a.id,
+                COALESCE(a.asset_tag, '') AS label,
+                COUNT(*) || ' open assignments, to ' || string_agg(e.name, ', ' ORDER BY ear.assigned_date, ear.created_at) AS detail
+         FROM   assets a
+             JOIN employee_asset_relation ear ON ear.asset_id = a.id
+                                             AND ear.retrieved_date IS NULL
+                                             AND ear.archived_at IS NULL
+             JOIN employee e ON e.id = ear.employee_id
+         GROUP BY a.id, a.asset_tag
+         HAVING COUNT(*) > 1`,
+		`WITH latest AS (SELECT DISTINCT ON (asset_id) id, asset_id, assigned_date
+                         FROM   employee_asset_relation
+                         WHERE  retrieved_date IS NULL
+                         AND    archived_at IS NULL
+                         ORDER BY asset_id, assigned_date DESC, created_at DESC),
+              repaired AS (UPDATE employee_asset_relation ear
+                           SET    retrieved_date = latest.assigned_date,
+                                  retrieval_reason = 'closed by consistency repair, assigned again',
+                                  archived_at = NOW()
+                           FROM   latest
+                           WHERE  ear.asset_id = latest.asset_id
+                           AND    ear.id != latest.id
+                           AND    ear.retrieved_date IS NULL
+                           AND    ear.archived_at IS NULL
+                           RETURNING ear.asset_id)
+         SELECT DISTINCT asset_id FROM repaired`},
+	{CheckAssignedWithoutHolder, "mark the asset available",
+		`SELECT a.id,
+                COALESCE(a.asset_tag, '') AS label,
+                'status assigned without an open assignment' AS detail
+         FROM   assets a
+         WHERE  a.archived_at IS NULL
+         AND    a.status = 'assigned'
+         AND    NOT EXISTS (SELECT 1
+                            FROM   employee_asset_relation ear
+                            WHERE  ear.asset_id = a.id
+                            AND    ear.retrieved_date IS NULL
+                            AND    ear.archived_at IS NULL)`,
+		`UPDATE assets a
+         SET    status = 'available',
+                is_available = true,
+                updated_at = NOW()
+         WHERE  a.archived_at IS NULL
+         AND    a.status = 'assigned'
+         AND    NOT EXISTS (SELECT 1
+                            FROM   employee_asset_relation ear
+                            WHERE  ear.asset_id = a.id
+                            AND    ear.retrieved_date IS NULL
+                            AND    ear.archived_at IS NULL)
+         RETURNING a.id`},
+	{CheckHeldButNotAssigned, "mark the asset assigned",
+		`SELECT a.id,
+                COALESCE(a.asset_tag, '') AS label,
+                'status ' || COALESCE(a.status::text, 'null') || ' but held by ' || string_agg(e.name, ', ') AS detail
+         FROM   assets a
+             JOIN employee_asset_relation ear ON ear.asset_id = a.id
+                                             AND ear.retrieved_date IS NULL
+                                             AND ear.archived_at IS NULL
+             JOIN employee e ON e.id = ear.employee_id
+         WHERE  a.archived_at IS NULL
+         AND    a.status IS DISTINCT FROM 'assigned'
+         GROUP BY a.id, a.asset_tag, a.status`,
+		`UPDATE assets a
+         SET    status = 'assigned',
+                is_available = false,
+                updated_at = NOW()
+         WHERE  a.archived_at IS NULL
+         AND    a.status IS DISTINCT FROM 'assigned'
+         AND    EXISTS (SELECT 1
+                        FROM   employee_asset_relation ear
+                        WHERE  ear.asset_id = a.id
+                        AND    ear.retrieved_date IS NULL
+                        AND    ear.archived_at IS NULL)
+         RETURNING a.id`},
+	{CheckAssetAvailability, "set is_available from the status",
+		`SELECT id,
+                COALESCE(asset_tag, '') AS label,
+                'status ' || COALESCE(status::text, 'null') || ' but is_available ' || COALESCE(is_available::text, 'null') AS detail
+         FROM   assets
+         WHERE  archived_at IS NULL
+         AND    (status = 'available') IS DISTINCT FROM is_available`,
+		`UPDATE assets
+         SET    is_available = COALESCE(status = 'available', false),
+                updated_at = NOW()
+         WHERE  archived_at IS NULL
+         AND    (status = 'available') IS DISTINCT FROM is_available
+         RETURNING id`},
+	{CheckMissingLaptopSpec, "add empty specifications to fill in from the admin panel",
+		`SELECT a.id,
+                COALESCE(a.asset_tag, '') AS label,
+                'laptop without specifications' AS detail
+         FROM   assets a
+         WHERE  a.archived_at IS NULL
+         AND    a.asset_type = 'laptop'
+         AND    NOT EXISTS (SELECT 1
+                            FROM   laptop_specifications ls
+                            WHERE  ls.asset_id = a.id
+                            AND    ls.archived_at IS NULL)`,
+		`INSERT INTO laptop_specifications(asset_id)
+         SELECT a.id
+         FROM   assets a
+         WHERE  a.archived_at IS NULL
+         AND    a.asset_type = 'laptop'
+         AND    NOT EXISTS (SELECT 1
+                            FROM   laptop_specifications ls
+                            WHERE  ls.asset_id = a.id
+                            AND    ls.archived_at IS NULL)
+         RETURNING asset_id`},
+}
+
+// errConsistencyDryRun rolls back the transaction of a dry run
+var errConsistencyDryRun = errors.New("consistency repair dry run")
+
+// IsConsistencyCheck tells whether name is one of the consistency checks
+func IsConsistencyCheck(name string) bool {
+	for _, check := range consistencyChecks {
+		if check.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckConsistency runs every consistency check and returns the rows breaking them, ordered by check
+func CheckConsistency(ctx context.Context) ([]models.Inconsistency, error) {
+	inconsistencies := make([]models.Inconsistency, 0)
+	for _, check := range consistencyChecks {
+		found := make([]models.Inconsistency, 0)
+		err := database.AssetManagement.SelectContext(ctx, &found, check.SQL+` ORDER BY label, id`)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Errorf("CheckConsistency: cannot run check %s.", check.name)
+			return nil, err
+		}
+		for i := range found {
+			found[i].Check = check.name
+		}
+		inconsistencies = append(inconsistencies, found...)
+	}
+	return inconsistencies, nil
+}
+
+// RepairConsistency repairs the named checks, or every check when none are named, in one transaction. Assets and
+// assignments are locked against writes meanwhile so the repairs cannot race an assignment. A dry run makes the same
+// changes and rolls them back, so it reports exactly what a real run would change
+func RepairConsistency(ctx context.Context, checks []string, dryRun bool) ([]models.ConsistencyRepair, error) {
+	selected := make(map[string]bool, len(checks))
+	for _, name := range checks {
+		selected[name] = true
+	}
+
+	repairs := make([]models.ConsistencyRepair, 0)
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `LOCK TABLE assets, employee_asset_relation IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+			logging.FromContext(ctx).WithError(err).Error("RepairConsistency: cannot lock assets.")
+			return err
+		}
+		for _, check := range consistencyChecks {
+			if len(selected) > 0 && !selected[check.name] {
+				continue
+			}
+			repair := models.ConsistencyRepair{Check: check.name, Action: check.action, IDs: make([]string, 0)}
+			if err := tx.SelectContext(ctx, &repair.IDs, check.repair); err != nil {
+				logging.FromContext(ctx).WithError(err).Errorf("RepairConsistency: cannot repair check %s.", check.name)
+				return err
+			}
+			if len(repair.IDs) > 0 {
+				repairs = append(repairs, repair)
+			}
+		}
+		if dryRun {
+			return errConsistencyDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errConsistencyDryRun) {
+		return nil, err
+	}
+	return repairs, nil
+}
@@ -2,42 +2,43 @@ package dbhelper
 
 import (
 	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
 	"database/sql"
-
-	"github.com/sirupsen/logrus"
 )
 
-func AddProfileImage(userID, url string) error {
+func AddProfileImage(ctx context.Context, userID, url string) error {
 	SQL := `UPDATE users
             SET    image = $1
             WHERE  id = $2
             AND    archived_at IS NULL 
             `
-	_, err := database.AssetManagement.Exec(SQL, url, userID)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, url, userID)
 	if err != nil {
-		logrus.WithError(err).Error("AddProfileImage: cannot add image.")
+		logging.FromContext(ctx).WithError(err).Error("AddProfileImage: cannot add image.")
 		return err
 	}
 	return nil
 }
 
-func AlterStatusDetails(userType, status string, authenticationTimes int, userID string) error {
+func AlterStatusDetails(ctx context.Context, userType, status string, authenticationTimes int, userID string) error {
 	SQL := `UPDATE users
             SET    authentication_times = $1,
                    status = $2, 
                    type = $3
             WHERE  id = $4
             `
-	_, err := database.AssetManagement.Exec(SQL, authenticationTimes+1, status, userType, userID)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, authenticationTimes+1, status, userType, userID)
 	if err != nil {
-		logrus.WithError(err).Error("AlterStatusDetails: cannot alter authentication times.")
+		logging.FromContext(ctx).WithError(err).Error("AlterStatusDetails: cannot alter authentication times.")
 		return err
 	}
 	return nil
 }
 
-func GetStatusDetails(email string) (models.StatusDetails, error) {
+func GetStatusDetails(ctx context.Context, email string) (models.StatusDetails, error) {
 	SQL := `SELECT  status,
        				type,
        				authentication_times
@@ -46,16 +47,16 @@ func GetStatusDetails(email string) (models.StatusDetails, error) {
            `
 	var statusDetails models.StatusDetails
 
-	err := database.AssetManagement.Get(&statusDetails, SQL, email)
+	err := database.AssetManagement.GetContext(ctx, &statusDetails, SQL, email)
 	if err != nil {
-		logrus.WithError(err).Error("GetStatusDetails: cannot get user status details.")
+		logging.FromContext(ctx).WithError(err).Error("GetStatusDetails: cannot get user status details.")
 		return statusDetails, err
 	}
 	return statusDetails, nil
 }
 
-func AccessedByDetails(userType string, filterCheck *models.FiltersCheck) ([]models.AccessedByDetails, error) {
-	SQL := `SELECT  users.id as id,
+func AccessedByDetails(ctx context.Context, userType string, filterCheck *models.FiltersCheck) (models.TotalAccessedBy, error) {
+	SQL := `WITH cte_user AS (SELECT  users.id as id,
        				name,
        				email,
        				authentication_times,
@@ -65,40 +66,62 @@ func AccessedByDetails(userType string, filterCheck *models.FiltersCheck) ([]mod
 			WHERE
 			CASE WHEN $1 = 'authorized' THEN type = 'authorized' ELSE type != 'authorized' END
 				AND  ($3 OR (name ilike '%%' || $2 || '%%'))
-				GROUP BY ( users.id,name,email,authentication_times,status)
+				GROUP BY ( users.id,name,email,authentication_times,status))
 			`
+	values := []interface{}{userType, filterCheck.SearchedName, !filterCheck.IsSearched}
+
+	totalAccessedBy := models.TotalAccessedBy{AccessedBy: make([]models.AccessedByDetails, 0)}
+	limit := 0
+	if filterCheck.Pagination {
+		limit = filterCheck.Limit
+	}
+	pageStr, values, err := pageSQL("cte_user", "id, name, email, authentication_times, status, start_time",
+		models.AccessedByList, accessedByColumns, filterCheck, limit, values)
+	if err != nil {
+		return totalAccessedBy, err
+	}
 
-	accessedByDetails := make([]models.AccessedByDetails, 0)
-	err := database.AssetManagement.Select(&accessedByDetails, SQL, userType, filterCheck.SearchedName, !filterCheck.IsSearched)
+	err = database.AssetManagement.SelectContext(ctx, &totalAccessedBy.AccessedBy, SQL+pageStr, values...)
 	if err != nil {
-		logrus.WithError(err).Error("AccessedByDetails: cannot accessed by details.")
-		return accessedByDetails, err
+		logging.FromContext(ctx).WithError(err).Error("AccessedByDetails: cannot accessed by details.")
+		return totalAccessedBy, err
 	}
-	return accessedByDetails, nil
+	if len(totalAccessedBy.AccessedBy) == 0 {
+		return totalAccessedBy, nil
+	}
+
+	totalAccessedBy.TotalCount = totalAccessedBy.AccessedBy[0].TotalCount
+	end, more := pageEnd(limit, len(totalAccessedBy.AccessedBy))
+	totalAccessedBy.AccessedBy = totalAccessedBy.AccessedBy[:end]
+	if more {
+		last := totalAccessedBy.AccessedBy[end-1]
+		totalAccessedBy.NextCursor = utils.NextCursor(models.AccessedByList, filterCheck, last.SortKey, last.ID)
+	}
+	return totalAccessedBy, nil
 }
 
-func Logout(userID string) error {
+func Logout(ctx context.Context, userID string) error {
 	SQL := `UPDATE sessions
             SET    end_time=now()
             WHERE  user_id=$1`
 
-	_, err := database.AssetManagement.Exec(SQL, userID)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, userID)
 	if err != nil {
-		logrus.WithError(err).Error("Logout: cannot do logout.")
+		logging.FromContext(ctx).WithError(err).Error("Logout: cannot do logout.")
 		return err
 	}
 	return nil
 }
 
-func IsUserExist(email, phoneNo string) (bool, error) {
+func IsUserExist(ctx context.Context, email, phoneNo string) (bool, error) {
 	SQL := `SELECT id FROM users 
             where email = $1 
             AND phone_no = $2 
             AND archived_at IS NULL`
 	var id string
-	err := database.AssetManagement.Get(&id, SQL, email, phoneNo)
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, email, phoneNo)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("IsUserExist: cannot get if user exist or not.")
+		logging.FromContext(ctx).WithError(err).Error("IsUserExist: cannot get if user exist or not.")
 		return false, err
 	}
 	if err == sql.ErrNoRows {
@@ -107,46 +130,52 @@ func IsUserExist(email, phoneNo string) (bool, error) {
 	return true, nil
 }
 
-func CreateUser(name, email, password, phoneNo string) error {
-	SQL := `INSERT INTO users (name, email, password, phone_no) 
-            VALUES ($1,$2,$3,$4)`
-	_, err := database.AssetManagement.Exec(SQL, name, email, password, phoneNo)
+func CreateUser(ctx context.Context, name, email, password, phoneNo string) error {
+	return CreateUserWithRole(ctx, name, email, password, phoneNo, utils.RoleAdmin)
+}
+
+// CreateUserWithRole creates a user who has the given role from the start
+func CreateUserWithRole(ctx context.Context, name, email, password, phoneNo, role string) error {
+	SQL := `INSERT INTO users (name, email, password, phone_no, role) 
+            VALUES ($1,$2,$3,$4,$5)`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, name, email, password, phoneNo, role)
 	if err != nil {
-		logrus.WithError(err).Error("CreateUser: cannot create user.")
+		logging.FromContext(ctx).WithError(err).Error("CreateUserWithRole: cannot create user.")
 		return err
 	}
 	return nil
 }
 
-func FetchPasswordAndID(email string) (models.UserCredentials, error) {
+func FetchPasswordAndID(ctx context.Context, email string) (models.UserCredentials, error) {
 	SQL := `SELECT  users.id,
-       				password
+       				password,
+       				COALESCE(role::text, 'admin') AS role
             FROM   users
             WHERE  email=$1 
             `
 
 	var userCredentials models.UserCredentials
 
-	err := database.AssetManagement.Get(&userCredentials, SQL, email)
+	err := database.AssetManagement.GetContext(ctx, &userCredentials, SQL, email)
 	if err != nil {
-		logrus.WithError(err).Error("FetchPasswordAndID: Not able to fetch password, ID.")
+		logging.FromContext(ctx).WithError(err).Error("FetchPasswordAndID: Not able to fetch password, ID.")
 		return userCredentials, err
 	}
 	return userCredentials, nil
 }
 
-func CreateSession(claims *models.Claims) error {
+func CreateSession(ctx context.Context, claims *models.Claims) error {
 	SQL := `INSERT INTO sessions(user_id)
             VALUES   ($1)`
-	_, err := database.AssetManagement.Exec(SQL, claims.ID)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, claims.ID)
 	if err != nil {
-		logrus.WithError(err).Error("CreateSession: cannot create user session.")
+		logging.FromContext(ctx).WithError(err).Error("CreateSession: cannot create user session.")
 		return err
 	}
 	return nil
 }
 
-func CheckSession(userID string) (string, error) {
+func CheckSession(ctx context.Context, userID string) (string, error) {
 	SQL := `SELECT id
            FROM    sessions
            WHERE   sessions.end_time IS NULL
@@ -155,29 +184,29 @@ func CheckSession(userID string) (string, error) {
            LIMIT 1`
 	var sessionID string
 
-	err := database.AssetManagement.Get(&sessionID, SQL, userID)
+	err := database.AssetManagement.GetContext(ctx, &sessionID, SQL, userID)
 	if err != nil {
-		logrus.WithError(err).Error("CheckSession: session expired.")
+		logging.FromContext(ctx).WithError(err).Error("CheckSession: session expired.")
 		return sessionID, err
 	}
 	return sessionID, nil
 }
 
-func GetUserDetails(id string) (*models.UserDetails, error) {
+func GetUserDetails(ctx context.Context, id string) (*models.UserDetails, error) {
 	SQL := `SELECT name, email, phone_no, coalesce(image, '') as image
 			FROM users
 			WHERE id = $1
 			  AND archived_at IS NULL`
 	var user models.UserDetails
-	err := database.AssetManagement.Get(&user, SQL, id)
+	err := database.AssetManagement.GetContext(ctx, &user, SQL, id)
 	if err != nil {
-		logrus.WithError(err).Error("GetUserDetails: cannot get user details.")
+		logging.FromContext(ctx).WithError(err).Error("GetUserDetails: cannot get user details.")
 		return nil, err
 	}
 	return &user, nil
 }
 
-func GetTotalAssetQuantities(assetQuantities models.GetAssetQuantity) (models.GetAssetQuantity, error) {
+func GetTotalAssetQuantities(ctx context.Context, assetQuantities models.GetAssetQuantity) (models.GetAssetQuantity, error) {
 	SQL := `WITH cte_total AS(
     							SELECT count(id) AS total_assets    
     							FROM   assets
@@ -193,15 +222,15 @@ func GetTotalAssetQuantities(assetQuantities models.GetAssetQuantity) (models.Ge
        			 ct.total_assets - cd.distributed_assets AS available_assets
 		  FROM cte_total ct, cte_distributed cd
 		  `
-	err := database.AssetManagement.Get(&assetQuantities, SQL)
+	err := database.AssetManagement.GetContext(ctx, &assetQuantities, SQL)
 	if err != nil {
-		logrus.WithError(err).Error("GetTotalAssetQuantities: cannot get total asset quantities.")
+		logging.FromContext(ctx).WithError(err).Error("GetTotalAssetQuantities: cannot get total asset quantities.")
 		return assetQuantities, err
 	}
 	return assetQuantities, nil
 }
 
-func GetAssetQuantities(dashBoardFilter string) (models.GetAssetQuantity, error) {
+func GetAssetQuantities(ctx context.Context, dashBoardFilter string) (models.GetAssetQuantity, error) {
 	SQL := `SELECT count(*) filter ( where asset_type = 'laptop' )    AS laptop_quantity,
 				   count(*) filter ( where asset_type = 'mouse' )     AS mouse_quantity,
 				   count(*) filter ( where asset_type = 'pen drive' ) AS pen_drive_quantity,
@@ -220,15 +249,15 @@ func GetAssetQuantities(dashBoardFilter string) (models.GetAssetQuantity, error)
 		SQL += "WHERE a.archived_at IS NULL"
 	}
 	var assetQuantity models.GetAssetQuantity
-	err := database.AssetManagement.Get(&assetQuantity, SQL)
+	err := database.AssetManagement.GetContext(ctx, &assetQuantity, SQL)
 	if err != nil {
-		logrus.WithError(err).Error("GetAssetQuantities: cannot get asset quantities.")
+		logging.FromContext(ctx).WithError(err).Error("GetAssetQuantities: cannot get asset quantities.")
 		return assetQuantity, err
 	}
 	return assetQuantity, nil
 }
 
-func UpdateUser(user models.RegisterUser, password, id string) error {
+func UpdateUser(ctx context.Context, user models.RegisterUser, password, id string) error {
 	SQL := `UPDATE users
             SET name       = $1,
                 email      = $2,
@@ -237,23 +266,69 @@ func UpdateUser(user models.RegisterUser, password, id string) error {
                 updated_at = NOW()
             WHERE id = $5
               AND archived_at IS NULL`
-	_, err := database.AssetManagement.Exec(SQL, user.Name, user.Email, user.PhoneNo, password, id)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, user.Name, user.Email, user.PhoneNo, password, id)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateUser: cannot update user details.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateUser: cannot update user details.")
 		return err
 	}
 	return nil
 }
 
-func UpdateAccessedBy(userID, userType string) error {
+func UpdateAccessedBy(ctx context.Context, userID, userType string) error {
 	SQL := `UPDATE users
             SET   type = $1
             WHERE id = $2
             AND   archived_at IS NULL 
             `
-	_, err := database.AssetManagement.Exec(SQL, userType, userID)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, userType, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateAccessedBy: cannot update AccessedBy.")
+		return err
+	}
+	return nil
+}
+
+// SetUserAccess sets the type and status of a user and restarts the count of sign in attempts, so that an unblocked
+// user is not blocked again by their next attempt
+func SetUserAccess(ctx context.Context, userID, userType, status string) error {
+	SQL := `UPDATE users
+            SET    type = $1,
+                   status = $2,
+                   authentication_times = 0,
+                   updated_at = NOW()
+            WHERE  id = $3
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, userType, status, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("SetUserAccess: cannot set user access.")
+		return err
+	}
+	return nil
+}
+
+func SetUserRole(ctx context.Context, userID, role string) error {
+	SQL := `UPDATE users
+            SET    role = $1,
+                   updated_at = NOW()
+            WHERE  id = $2
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, role, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("SetUserRole: cannot set user role.")
+		return err
+	}
+	return nil
+}
+
+func UpdatePassword(ctx context.Context, userID, password string) error {
+	SQL := `UPDATE users
+            SET    password = $1,
+                   updated_at = NOW()
+            WHERE  id = $2
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, password, userID)
 	if err != nil {
-		logrus.WithError(err).Error("UpdateAccessedBy: cannot update AccessedBy.")
+		logging.FromContext(ctx).WithError(err).Error("UpdatePassword: cannot update password.")
 		return err
 	}
 	return nil
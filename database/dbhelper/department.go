@@ -0,0 +1,225 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+func CreateDepartment(ctx context.Context, department *models.Department, userID string) (string, error) {
+	SQL := `INSERT INTO departments(name, parent_id, created_by)
+            VALUES ($1, $2, $3)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, department.Name, department.ParentID, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateDepartment: cannot create department.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetDepartments(ctx context.Context) ([]models.DepartmentDetails, error) {
+	SQL := `SELECT  d.id,
+       				d.name,
+       				d.parent_id,
+       				p.name AS parent_name,
+       				d.created_at,
+       				COUNT(e.id) AS employee_count
+			FROM   departments d
+			    LEFT JOIN departments p ON p.id = d.parent_id
+			    LEFT JOIN employee e ON e.department_id = d.id AND e.archived_at IS NULL
+			WHERE  d.archived_at IS NULL
+			GROUP BY (d.id, d.name, d.parent_id, p.name, d.created_at)
+			ORDER BY p.name NULLS FIRST, d.name`
+	departments := make([]models.DepartmentDetails, 0)
+	err := database.AssetManagement.SelectContext(ctx, &departments, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetDepartments: cannot get departments.")
+		return departments, err
+	}
+	return departments, nil
+}
+
+func UpdateDepartment(ctx context.Context, department *models.Department) error {
+	SQL := `UPDATE departments
+            SET    name = $1,
+                   parent_id = $2,
+                   updated_at = NOW()
+            WHERE  id = $3
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, department.Name, department.ParentID, department.ID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateDepartment: cannot update department.")
+		return err
+	}
+	return nil
+}
+
+// IsDepartmentInSubtree checks if candidateID is departmentID itself or one of its teams
+func IsDepartmentInSubtree(ctx context.Context, departmentID, candidateID string) (bool, error) {
+	SQL := `WITH RECURSIVE cte_subtree AS (
+    				SELECT id FROM departments WHERE id = $1
+    				UNION ALL
+    				SELECT d.id FROM departments d JOIN cte_subtree s ON d.parent_id = s.id
+			)
+			SELECT EXISTS(SELECT 1 FROM cte_subtree WHERE id = $2)`
+	var exists bool
+	err := database.AssetManagement.GetContext(ctx, &exists, SQL, departmentID, candidateID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsDepartmentInSubtree: cannot check department tree.")
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetDepartmentUsage returns the number of active employees and teams in a department
+func GetDepartmentUsage(ctx context.Context, departmentID string) (int, error) {
+	SQL := `SELECT (SELECT COUNT(id) FROM employee WHERE department_id = $1 AND archived_at IS NULL) +
+				   (SELECT COUNT(id) FROM departments WHERE parent_id = $1 AND archived_at IS NULL)`
+	var count int
+	err := database.AssetManagement.GetContext(ctx, &count, SQL, departmentID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetDepartmentUsage: cannot get department usage.")
+		return -1, err
+	}
+	return count, nil
+}
+
+func DeleteDepartment(ctx context.Context, departmentID string) error {
+	SQL := `UPDATE departments
+            SET    archived_at = NOW()
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, departmentID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DeleteDepartment: cannot delete department.")
+		return err
+	}
+	return nil
+}
+
+// departmentSubtreeSQL returns a sub query selecting a department and all of its teams
+func departmentSubtreeSQL(arg int) string {
+	return fmt.Sprintf(`WITH RECURSIVE cte_department AS (
+							SELECT id FROM departments WHERE id = $%d
+							UNION ALL
+							SELECT d.id FROM departments d JOIN cte_department cd ON d.parent_id = cd.id
+						) SELECT id FROM cte_department`, arg)
+}
+
+// heldByDepartmentSQL returns a sub query selecting the assets currently held by employees of a department or its teams
+func heldByDepartmentSQL(arg int) string {
+	return fmt.Sprintf(`SELECT hr.asset_id
+						FROM   employee_asset_relation hr
+						    JOIN employee he ON he.id = hr.employee_id
+						WHERE  hr.retrieved_date IS NULL
+						AND    hr.archived_at IS NULL
+						AND    he.department_id IN (%s)`, departmentSubtreeSQL(arg))
+}
+
+// IsManagerCycle checks if making managerID the manager of employeeID would make the employee manage themselves
+func IsManagerCycle(ctx context.Context, db sqlx.QueryerContext, employeeID, managerID string) (bool, error) {
+	SQL := `WITH RECURSIVE cte_chain AS (
+    				SELECT id, manager_id FROM employee WHERE id = $2
+    				UNION
+    				SELECT e.id, e.manager_id FROM employee e JOIN cte_chain c ON e.id = c.manager_id
+			)
+			SELECT EXISTS(SELECT 1 FROM cte_chain WHERE id = $1)`
+	var cycle bool
+	err := sqlx.GetContext(ctx, db, &cycle, SQL, employeeID, managerID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsManagerCycle: cannot check manager chain.")
+		return false, err
+	}
+	return cycle, nil
+}
+
+// GetDepartmentQuantities returns per department the assets currently held by its employees, rolled up from teams
+func GetDepartmentQuantities(ctx context.Context) ([]models.DepartmentQuantity, error) {
+	SQL := `WITH RECURSIVE cte_tree AS (
+    				SELECT id AS root_id, id
+    				FROM   departments
+    				WHERE  archived_at IS NULL
+    				UNION ALL
+    				SELECT t.root_id, d.id
+    				FROM   departments d
+    				    JOIN cte_tree t ON d.parent_id = t.id
+    				WHERE  d.archived_at IS NULL
+			), held AS (
+    				SELECT e.department_id, e.id AS employee_id, a.asset_type
+    				FROM   employee e
+    				    LEFT JOIN employee_asset_relation ear ON ear.employee_id = e.id
+    				        AND ear.retrieved_date IS NULL AND ear.archived_at IS NULL
+    				    LEFT JOIN assets a ON a.id = ear.asset_id AND a.archived_at IS NULL
+    				WHERE  e.archived_at IS NULL
+			)
+			SELECT  d.id,
+       				d.name,
+       				d.parent_id,
+       				COUNT(DISTINCT h.employee_id) AS employees,
+       				COUNT(h.asset_type) AS held_assets,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'laptop' ) AS laptop_quantity,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'mouse' ) AS mouse_quantity,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'pen drive' ) AS pen_drive_quantity,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'hard disk' ) AS hard_disk_quantity,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'mobile' ) AS mobile_quantity,
+       				COUNT(*) FILTER ( WHERE h.asset_type = 'sim' ) AS sim_quantity
+			FROM   departments d
+			    JOIN cte_tree t ON t.root_id = d.id
+			    LEFT JOIN held h ON h.department_id = t.id
+			GROUP BY (d.id, d.name, d.parent_id)
+			ORDER BY held_assets DESC, d.name`
+	quantities := make([]models.DepartmentQuantity, 0)
+	err := database.AssetManagement.SelectContext(ctx, &quantities, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetDepartmentQuantities: cannot get department quantities.")
+		return quantities, err
+	}
+	return quantities, nil
+}
+
+// GetAssetHolder returns the employee currently holding an asset, if any
+func GetAssetHolder(ctx context.Context, tx *sqlx.Tx, assetID string) (null.String, error) {
+	SQL := `SELECT employee_id
+            FROM   employee_asset_relation
+            WHERE  asset_id = $1
+            AND    retrieved_date IS NULL
+            AND    archived_at IS NULL
+            ORDER BY assigned_date DESC
+            LIMIT 1`
+	var employeeID null.String
+	err := tx.GetContext(ctx, &employeeID, SQL, assetID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetAssetHolder: cannot get asset holder.")
+		return employeeID, err
+	}
+	return employeeID, nil
+}
+
+// GetManagerNotice returns the manager of an employee with the asset details, sql.ErrNoRows when there is no manager
+func GetManagerNotice(ctx context.Context, employeeID, assetID string) (models.ManagerNotice, error) {
+	SQL := `SELECT  m.name AS manager_name,
+       				m.email AS manager_email,
+       				e.name AS employee_name,
+       				COALESCE(a.asset_tag, '') AS asset_tag,
+       				a.brand,
+       				a.model,
+       				a.asset_type
+			FROM   employee e
+			    JOIN employee m ON m.id = e.manager_id AND m.archived_at IS NULL
+			    JOIN assets a ON a.id = $2
+			WHERE  e.id = $1`
+	var notice models.ManagerNotice
+	err := database.AssetManagement.GetContext(ctx, &notice, SQL, employeeID, assetID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetManagerNotice: cannot get manager details.")
+	}
+	return notice, err
+}
@@ -2,45 +2,51 @@ package dbhelper
 
 import (
 	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
 	"InternalAssetManagement/utils"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/jmoiron/sqlx"
-
-	"github.com/sirupsen/logrus"
 )
 
-func CreateEmployee(employeeDetails *models.EmployeeDetails) error {
-	SQL := `INSERT INTO employee(name, email, phone_no, type) 
-            VALUES($1, $2, $3, $4)
+func CreateEmployee(ctx context.Context, employeeDetails *models.EmployeeDetails) error {
+	SQL := `INSERT INTO employee(name, email, phone_no, type, department_id, manager_id) 
+            VALUES($1, $2, $3, $4, $5, $6)
             ON CONFLICT (email) DO UPDATE 
             SET email = $2`
 
-	_, err := database.AssetManagement.Exec(SQL, employeeDetails.Name, employeeDetails.Email, employeeDetails.PhoneNo, employeeDetails.Type)
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, employeeDetails.Name, employeeDetails.Email, employeeDetails.PhoneNo, employeeDetails.Type, employeeDetails.DepartmentID, employeeDetails.ManagerID)
 	if err != nil {
-		logrus.WithError(err).Error("CreateEmployee: cannot create employee.")
+		logging.FromContext(ctx).WithError(err).Error("CreateEmployee: cannot create employee.")
 		return err
 	}
 	return nil
 }
 
-func GetEmployee(filterCheck *models.FiltersCheck) (models.TotalGetEmployee, error) {
+func GetEmployee(ctx context.Context, filterCheck *models.FiltersCheck) (models.TotalGetEmployee, error) {
 	var totalGetEmployee models.TotalGetEmployee
-	SQL := `WITH cte_employee AS (SELECT count(*) over () as total_count,
-                             e.id             as id,
-                             name,
-                             email,
-                             phone_no,
+	SQL := `WITH cte_employee AS (SELECT e.id             as id,
+                             e.name,
+                             e.email,
+                             e.phone_no,
                              e.status,
                              e.type,
                              e.archived_at,
                              e.archive_reason,
                              e.deleted_by,
-                             COUNT(ear.id)    AS asset_quantity
+                             COUNT(ear.id)    AS asset_quantity,
+                             e.department_id,
+                             d.name           AS department_name,
+                             e.manager_id,
+                             m.name           AS manager_name,
+                             e.created_at
                       FROM employee e
                                LEFT JOIN employee_asset_relation ear ON e.id = ear.employee_id
                                LEFT JOIN assets a on a.id = ear.asset_id
+                               LEFT JOIN departments d on d.id = e.department_id
+                               LEFT JOIN employee m on m.id = e.manager_id
                       WHERE e.id IS NOT NULL
 							
 `
@@ -48,11 +54,18 @@ func GetEmployee(filterCheck *models.FiltersCheck) (models.TotalGetEmployee, err
 	values := make([]interface{}, 0)
 	args := 0
 
-	sqlStr := fmt.Sprintf(" AND (LENGTH($%d) != 0 OR $%d OR e.archived_at IS NULL)   AND (CARDINALITY(Array[$%d::asset_type[]]) = 0 OR ( a.asset_type =ANY(ARRAY [$%d::asset_type[]])) ) AND ( NULLIF(LENGTH($%d), 0) IS NULL OR (name ilike '%%' || $%d || '%%') AND (NULLIF(LENGTH($%d), 0)) IS NULL OR e.id::text = $%d) ", args+1, args+2, args+3, args+4, args+5, args+6, args+7, args+8)
+	sqlStr := fmt.Sprintf(" AND (LENGTH($%d) != 0 OR $%d OR e.archived_at IS NULL)   AND (CARDINALITY(Array[$%d::asset_type[]]) = 0 OR ( a.asset_type =ANY(ARRAY [$%d::asset_type[]])) ) AND ( NULLIF(LENGTH($%d), 0) IS NULL OR e.name ilike '%%' || $%d || '%%') AND ( NULLIF(LENGTH($%d), 0) IS NULL OR e.id::text = $%d) ", args+1, args+2, args+3, args+4, args+5, args+6, args+7, args+8)
 	SQL += sqlStr
 	args += 8
 	values = append(values, filterCheck.EmployeeID, filterCheck.Deleted, filterCheck.AssetTypes, filterCheck.AssetTypes, filterCheck.SearchedName, filterCheck.SearchedName, filterCheck.EmployeeID, filterCheck.EmployeeID)
 
+	if filterCheck.DepartmentID != "" {
+		departmentStr := fmt.Sprintf(" AND e.department_id IN (%s) ", departmentSubtreeSQL(args+1))
+		SQL += departmentStr
+		args++
+		values = append(values, filterCheck.DepartmentID)
+	}
+
 	if filterCheck.Deleted && !filterCheck.NotAnEmployee {
 		statusStr := fmt.Sprintf("AND e.status = $%d ", args+1)
 		SQL += statusStr
@@ -66,7 +79,7 @@ func GetEmployee(filterCheck *models.FiltersCheck) (models.TotalGetEmployee, err
 		values = append(values, utils.NotAnEmployee)
 	}
 	if filterCheck.Deleted && filterCheck.NotAnEmployee {
-		statusStr := fmt.Sprintf("AND e.status = $%d OR e.status = $%d ", args+1, args+2)
+		statusStr := fmt.Sprintf("AND (e.status = $%d OR e.status = $%d) ", args+1, args+2)
 		SQL += statusStr
 		args += 2
 		values = append(values, utils.Deleted, utils.NotAnEmployee)
@@ -78,46 +91,63 @@ func GetEmployee(filterCheck *models.FiltersCheck) (models.TotalGetEmployee, err
 		values = append(values, utils.Active)
 	}
 
-	pageStr := fmt.Sprintf(" GROUP BY (e.id, name, email, phone_no, e.status, e.type, e.archived_at, e.archive_reason, e.deleted_by) LIMIT $%d OFFSET $%d)SELECT total_count, id, name, email, phone_no, status,type,archived_at,archive_reason,deleted_by,asset_quantity FROM cte_employee", args+1, args+2)
-	SQL += pageStr
-	values = append(values, filterCheck.Limit, filterCheck.Limit*filterCheck.Page)
+	pageStr, values, err := pageSQL("cte_employee", "id, name, email, phone_no, status, type, archived_at, archive_reason, deleted_by, asset_quantity, department_id, department_name, manager_id, manager_name",
+		models.EmployeeList, employeeColumns, filterCheck, filterCheck.Limit, values)
+	if err != nil {
+		return totalGetEmployee, err
+	}
+	SQL += " GROUP BY (e.id, e.name, e.email, e.phone_no, e.status, e.type, e.archived_at, e.archive_reason, e.deleted_by, d.name, m.name))" + pageStr
 
 	var getEmployee = make([]models.GetEmployee, 0)
-	err := database.AssetManagement.Select(&getEmployee, SQL, values...)
+	err = database.AssetManagement.SelectContext(ctx, &getEmployee, SQL, values...)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetEmployee: cannot get employee list.")
+		logging.FromContext(ctx).WithError(err).Error("GetEmployee: cannot get employee list.")
 		return totalGetEmployee, err
 	}
 
 	totalGetEmployee.GetEmployee = getEmployee
 	if len(getEmployee) == 0 {
-		logrus.WithError(err).Error("GetEmployee: got empty array.")
-		return totalGetEmployee, err
+		return totalGetEmployee, nil
 	}
 
 	totalGetEmployee.TotalCount = getEmployee[0].TotalCount
+	end, more := pageEnd(filterCheck.Limit, len(getEmployee))
+	totalGetEmployee.GetEmployee = getEmployee[:end]
+	if more {
+		last := getEmployee[end-1]
+		totalGetEmployee.NextCursor = utils.NextCursor(models.EmployeeList, filterCheck, last.SortKey, last.ID)
+	}
 	return totalGetEmployee, nil
 }
 
-func UpdateEmployee(user *models.EmployeeDetails) error {
+// UpdateEmployee saves the details of an employee and ends their portal sessions when they are no longer active
+func UpdateEmployee(ctx context.Context, user *models.EmployeeDetails) error {
 	SQL := `UPDATE employee
             SET name       = $1,
                 email      = $2,
                 phone_no   = $3,
                 updated_at = NOW(),
                 status     = $5,
-                type       = $6
+                type       = $6,
+                department_id = $7,
+                manager_id = $8
             WHERE id = $4
               AND archived_at IS NULL`
-	_, err := database.AssetManagement.Exec(SQL, user.Name, user.Email, user.PhoneNo, user.ID, user.Status, user.Type)
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, SQL, user.Name, user.Email, user.PhoneNo, user.ID, user.Status, user.Type, user.DepartmentID, user.ManagerID)
+		if err != nil || user.Status == utils.Active {
+			return err
+		}
+		return endEmployeeSessions(ctx, tx, user.ID)
+	})
 	if err != nil {
-		logrus.WithError(err).Error("UpdateEmployee: cannot update employee details.")
+		logging.FromContext(ctx).WithError(err).Error("UpdateEmployee: cannot update employee details.")
 		return err
 	}
 	return nil
 }
 
-func DeleteEmployee(employeeID, userID string, employeeBody models.Employee) error {
+func DeleteEmployee(ctx context.Context, employeeID, userID string, employeeBody models.Employee) error {
 	SQL := `UPDATE employee
             SET    archived_at = now(),
                    archive_reason = $2,
@@ -126,27 +156,35 @@ func DeleteEmployee(employeeID, userID string, employeeBody models.Employee) err
             WHERE  id = $1
             AND    archived_at IS NULL 
             `
-	_, err := database.AssetManagement.Exec(SQL, employeeID, employeeBody.ArchiveReason, userID, utils.Deleted)
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, SQL, employeeID, employeeBody.ArchiveReason, userID, utils.Deleted)
+		if err != nil {
+			return err
+		}
+		return endEmployeeSessions(ctx, tx, employeeID)
+	})
 	if err != nil {
-		logrus.WithError(err).Error("DeleteEmployee: cannot delete employee.")
+		logging.FromContext(ctx).WithError(err).Error("DeleteEmployee: cannot delete employee.")
 		return err
 	}
 	return nil
 }
 
-func CreateEmployeeAssetRelation(employeeAssetRelation models.EmployeeAssetRelation, assignedBy string, tx *sqlx.Tx) error {
-	SQL := `INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_by, assigned_date)
-            VALUES ($1, $2, $3, $4)`
+func CreateEmployeeAssetRelation(ctx context.Context, employeeAssetRelation models.EmployeeAssetRelation, assignedBy string, tx *sqlx.Tx) (string, error) {
+	SQL := `INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_by, assigned_date, assignment_type, due_date)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id`
 
-	_, err := tx.Exec(SQL, employeeAssetRelation.EmployeeID, employeeAssetRelation.AssetID, assignedBy, employeeAssetRelation.AssignedDate)
+	var relationID string
+	err := tx.GetContext(ctx, &relationID, SQL, employeeAssetRelation.EmployeeID, employeeAssetRelation.AssetID, assignedBy, employeeAssetRelation.AssignedDate, employeeAssetRelation.AssignmentType, employeeAssetRelation.DueDate)
 	if err != nil {
-		logrus.WithError(err).Error("CreateEmployeeAssetRelation: cannot create employee asset relation.")
-		return err
+		logging.FromContext(ctx).WithError(err).Error("CreateEmployeeAssetRelation: cannot create employee asset relation.")
+		return "", err
 	}
-	return nil
+	return relationID, nil
 }
 
-func GetAssetHistory(employeeID string) ([]models.AssetHistory, error) {
+func GetAssetHistory(ctx context.Context, employeeID string) ([]models.AssetHistory, error) {
 	SQL := `SELECT  a.id, 
        				brand, 
        				model, 
@@ -154,7 +192,9 @@ func GetAssetHistory(employeeID string) ([]models.AssetHistory, error) {
        				asset_type, 
        				assigned_date, 
        				retrieved_date,
-       				coalesce(retrieval_reason, '') as retrieval_reason
+       				coalesce(retrieval_reason, '') as retrieval_reason,
+       				ear.due_date,
+       				ear.acknowledged_at
 			FROM employee_asset_relation ear
 			JOIN assets a ON ear.asset_id = a.id
 			WHERE a.archived_at IS NULL
@@ -164,10 +204,25 @@ func GetAssetHistory(employeeID string) ([]models.AssetHistory, error) {
 			)
 `
 	assetHistory := make([]models.AssetHistory, 0)
-	err := database.AssetManagement.Select(&assetHistory, SQL, employeeID)
+	err := database.AssetManagement.SelectContext(ctx, &assetHistory, SQL, employeeID)
 	if err != nil && err != sql.ErrNoRows {
-		logrus.WithError(err).Error("GetAssetHistory: cannot get asset history.")
+		logging.FromContext(ctx).WithError(err).Error("GetAssetHistory: cannot get asset history.")
 		return nil, err
 	}
 	return assetHistory, nil
 }
+
+// GetEmployeeIDByEmail finds the employee who is not deleted with the email in any case, sql.ErrNoRows when there is none
+func GetEmployeeIDByEmail(ctx context.Context, email string) (string, error) {
+	SQL := `SELECT id
+            FROM   employee
+            WHERE  LOWER(email) = LOWER($1)
+            AND    archived_at IS NULL`
+	var employeeID string
+	err := database.AssetManagement.GetContext(ctx, &employeeID, SQL, email)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetEmployeeIDByEmail: cannot get employee.")
+		return employeeID, err
+	}
+	return employeeID, nil
+}
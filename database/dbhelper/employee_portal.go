@@ -0,0 +1,222 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// CreateEmployeeAccount gives an employee a portal account or resets its password, which ends the sessions opened
+// with the old one
+func CreateEmployeeAccount(ctx context.Context, employeeID, password, createdBy string) error {
+	SQL := `INSERT INTO employee_accounts(employee_id, password, created_by)
+            VALUES ($1, $2, $3)
+            ON CONFLICT (employee_id) WHERE archived_at IS NULL
+            DO UPDATE SET password = EXCLUDED.password,
+                          updated_at = NOW()`
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, SQL, employeeID, password, createdBy); err != nil {
+			return err
+		}
+		return endEmployeeSessions(ctx, tx, employeeID)
+	})
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateEmployeeAccount: cannot create employee account.")
+		return err
+	}
+	return nil
+}
+
+func FetchEmployeeCredentials(ctx context.Context, email string) (models.EmployeeCredentials, error) {
+	SQL := `SELECT  ea.employee_id,
+       				ea.password
+            FROM    employee_accounts ea
+                JOIN employee e ON e.id = ea.employee_id
+            WHERE   e.email = $1
+            AND     e.status = $2
+            AND     e.archived_at IS NULL
+            AND     ea.archived_at IS NULL`
+	var credentials models.EmployeeCredentials
+	err := database.AssetManagement.GetContext(ctx, &credentials, SQL, email, utils.Active)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("FetchEmployeeCredentials: cannot fetch employee credentials.")
+		return credentials, err
+	}
+	return credentials, nil
+}
+
+// GetEmployeePassword returns the password hash of the portal account of an employee
+func GetEmployeePassword(ctx context.Context, employeeID string) (string, error) {
+	SQL := `SELECT password
+            FROM   employee_accounts
+            WHERE  employee_id = $1
+            AND    archived_at IS NULL`
+	var password string
+	err := database.AssetManagement.GetContext(ctx, &password, SQL, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetEmployeePassword: cannot get employee password.")
+		return "", err
+	}
+	return password, nil
+}
+
+func UpdateEmployeePassword(ctx context.Context, employeeID, password string) error {
+	SQL := `UPDATE employee_accounts
+            SET    password = $2,
+                   updated_at = NOW()
+            WHERE  employee_id = $1
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, employeeID, password)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateEmployeePassword: cannot update employee password.")
+		return err
+	}
+	return nil
+}
+
+func CreateEmployeeSession(ctx context.Context, employeeID string) error {
+	SQL := `INSERT INTO employee_sessions(employee_id)
+            VALUES ($1)`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateEmployeeSession: cannot create employee session.")
+		return err
+	}
+	return nil
+}
+
+// CheckEmployeeSession returns the open portal session of an active employee who still has a portal account
+func CheckEmployeeSession(ctx context.Context, employeeID string) (string, error) {
+	SQL := `SELECT  es.id
+            FROM    employee_sessions es
+                JOIN employee e ON e.id = es.employee_id
+                JOIN employee_accounts ea ON ea.employee_id = e.id AND ea.archived_at IS NULL
+            WHERE   es.end_time IS NULL
+            AND     es.employee_id = $1
+            AND     e.status = $2
+            AND     e.archived_at IS NULL
+            ORDER BY es.start_time DESC
+            LIMIT 1`
+	var sessionID string
+	err := database.AssetManagement.GetContext(ctx, &sessionID, SQL, employeeID, utils.Active)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CheckEmployeeSession: session expired.")
+		return sessionID, err
+	}
+	return sessionID, nil
+}
+
+func EmployeeLogout(ctx context.Context, employeeID string) error {
+	err := endEmployeeSessions(ctx, database.AssetManagement, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("EmployeeLogout: cannot do logout.")
+		return err
+	}
+	return nil
+}
+
+// endEmployeeSessions ends every open portal session of an employee
+func endEmployeeSessions(ctx context.Context, db sqlx.ExecerContext, employeeID string) error {
+	SQL := `UPDATE employee_sessions
+            SET    end_time = NOW()
+            WHERE  employee_id = $1
+            AND    end_time IS NULL`
+	_, err := db.ExecContext(ctx, SQL, employeeID)
+	return err
+}
+
+// IsAssetHeldBy checks if an asset is currently assigned to the employee
+func IsAssetHeldBy(ctx context.Context, employeeID, assetID string) (bool, error) {
+	SQL := `SELECT EXISTS(SELECT 1
+                          FROM   employee_asset_relation
+                          WHERE  employee_id = $1
+                          AND    asset_id = $2
+                          AND    retrieved_date IS NULL
+                          AND    archived_at IS NULL)`
+	var held bool
+	err := database.AssetManagement.GetContext(ctx, &held, SQL, employeeID, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetHeldBy: cannot check asset holder.")
+		return false, err
+	}
+	return held, nil
+}
+
+func AcknowledgeAsset(ctx context.Context, employeeID, assetID string) (int64, error) {
+	SQL := `UPDATE employee_asset_relation
+            SET    acknowledged_at = NOW()
+            WHERE  employee_id = $1
+            AND    asset_id = $2
+            AND    retrieved_date IS NULL
+            AND    archived_at IS NULL
+            AND    acknowledged_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, employeeID, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("AcknowledgeAsset: cannot acknowledge asset.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func CreateAssetReport(ctx context.Context, employeeID, assetID string, report *models.AssetReport) (string, error) {
+	SQL := `INSERT INTO asset_reports(asset_id, employee_id, type, description)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, assetID, employeeID, report.Type, report.Description)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateAssetReport: cannot create asset report.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetAssetReports(ctx context.Context, status, employeeID string) ([]models.AssetReportDetails, error) {
+	SQL := `SELECT  ar.id,
+       				ar.asset_id,
+       				COALESCE(a.asset_tag, '') AS asset_tag,
+       				a.brand,
+       				a.model,
+       				ar.employee_id,
+       				e.name AS employee_name,
+       				ar.type,
+       				ar.description,
+       				ar.status,
+       				ar.resolution,
+       				ar.resolved_by,
+       				ar.resolved_at,
+       				ar.created_at
+			FROM   asset_reports ar
+			    JOIN assets a ON a.id = ar.asset_id
+			    JOIN employee e ON e.id = ar.employee_id
+			WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR ar.status::text = $1)
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR ar.employee_id::text = $2)
+			ORDER BY ar.created_at DESC`
+	reports := make([]models.AssetReportDetails, 0)
+	err := database.AssetManagement.SelectContext(ctx, &reports, SQL, status, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetAssetReports: cannot get asset reports.")
+		return reports, err
+	}
+	return reports, nil
+}
+
+func ResolveAssetReport(ctx context.Context, resolve *models.ResolveAssetReport, userID string) (int64, error) {
+	SQL := `UPDATE asset_reports
+            SET    status = 'resolved',
+                   resolution = $2,
+                   resolved_by = $3,
+                   resolved_at = NOW()
+            WHERE  id = $1
+            AND    status = 'open'`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, resolve.ID, resolve.Resolution, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ResolveAssetReport: cannot resolve asset report.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,300 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+const equipmentRequestSelectSQL = `SELECT  er.id,
+       				er.employee_id,
+       				e.name AS employee_name,
+       				e.email AS employee_email,
+       				er.asset_type,
+       				er.justification,
+       				er.needed_by,
+       				er.status,
+       				(SELECT era.step_name FROM equipment_request_approvals era
+       				 WHERE era.request_id = er.id AND era.status = 'pending'
+       				 ORDER BY era.step_order LIMIT 1) AS current_step,
+       				er.requested_by,
+       				er.relation_id,
+       				er.fulfilled_by,
+       				er.fulfilled_at,
+       				er.created_at,
+       				er.updated_at
+			FROM   equipment_requests er
+			    JOIN employee e ON e.id = er.employee_id
+`
+
+// CreateEquipmentRequest files the request and copies the approval chain configured for its asset type onto it,
+// falling back to a single admin approval when no chain is configured
+func CreateEquipmentRequest(ctx context.Context, tx *sqlx.Tx, request *models.EquipmentRequest, requestedBy null.String) (string, error) {
+	SQL := `INSERT INTO equipment_requests(employee_id, asset_type, justification, needed_by, requested_by)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, request.EmployeeID, request.AssetType, request.Justification, request.NeededBy, requestedBy)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateEquipmentRequest: cannot create equipment request.")
+		return "", err
+	}
+
+	SQL = `INSERT INTO equipment_request_approvals(request_id, step_order, step_name, approver_id)
+           SELECT $1, step_order, name, approver_id
+           FROM   equipment_approval_steps
+           WHERE  archived_at IS NULL
+           AND    (asset_type IS NULL OR asset_type = $2)`
+	result, err := tx.ExecContext(ctx, SQL, id, request.AssetType)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateEquipmentRequest: cannot create approval chain.")
+		return "", err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if rows > 0 {
+		return id, nil
+	}
+
+	SQL = `INSERT INTO equipment_request_approvals(request_id, step_order, step_name)
+           VALUES ($1, 1, 'Admin approval')`
+	_, err = tx.ExecContext(ctx, SQL, id)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateEquipmentRequest: cannot create default approval step.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetEquipmentRequests(ctx context.Context, employeeID, status string) ([]models.EquipmentRequestDetails, error) {
+	SQL := equipmentRequestSelectSQL + `WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR er.employee_id::text = $1)
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR er.status::text = $2)
+			ORDER BY er.created_at DESC`
+	requests := make([]models.EquipmentRequestDetails, 0)
+	err := database.AssetManagement.SelectContext(ctx, &requests, SQL, employeeID, status)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetEquipmentRequests: cannot get equipment requests.")
+		return requests, err
+	}
+	return requests, nil
+}
+
+// GetEquipmentRequest returns a request with its approval steps, restricted to the given employee when employeeID is not empty
+func GetEquipmentRequest(ctx context.Context, requestID, employeeID string) (models.EquipmentRequestDetails, error) {
+	SQL := equipmentRequestSelectSQL + `WHERE  er.id = $1
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR er.employee_id::text = $2)`
+	var request models.EquipmentRequestDetails
+	err := database.AssetManagement.GetContext(ctx, &request, SQL, requestID, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetEquipmentRequest: cannot get equipment request.")
+		return request, err
+	}
+
+	SQL = `SELECT id, request_id, step_order, step_name, approver_id, status, comment, decided_by, decided_at
+           FROM   equipment_request_approvals
+           WHERE  request_id = $1
+           ORDER BY step_order`
+	request.Approvals = make([]models.EquipmentRequestApproval, 0)
+	err = database.AssetManagement.SelectContext(ctx, &request.Approvals, SQL, requestID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetEquipmentRequest: cannot get equipment request approvals.")
+		return request, err
+	}
+	return request, nil
+}
+
+// GetCurrentApproval locks and returns the first pending approval step of a pending request
+func GetCurrentApproval(ctx context.Context, tx *sqlx.Tx, requestID string) (models.EquipmentRequestApproval, error) {
+	SQL := `SELECT era.id, era.request_id, era.step_order, era.step_name, era.approver_id, era.status, era.comment, era.decided_by, era.decided_at
+            FROM   equipment_request_approvals era
+                JOIN equipment_requests er ON er.id = era.request_id
+            WHERE  era.request_id = $1
+            AND    er.status = 'pending'
+            AND    era.status = 'pending'
+            ORDER BY era.step_order
+            LIMIT 1
+            FOR UPDATE`
+	var approval models.EquipmentRequestApproval
+	err := tx.GetContext(ctx, &approval, SQL, requestID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetCurrentApproval: cannot get current approval step.")
+		return approval, err
+	}
+	return approval, nil
+}
+
+// DecideApproval records the decision on an approval step and moves the request to its resulting status
+func DecideApproval(ctx context.Context, tx *sqlx.Tx, approval *models.EquipmentRequestApproval, decision *models.EquipmentRequestDecision, userID string) (string, error) {
+	SQL := `UPDATE equipment_request_approvals
+            SET    status = $2,
+                   comment = NULLIF($3, ''),
+                   decided_by = $4,
+                   decided_at = NOW()
+            WHERE  id = $1`
+	_, err := tx.ExecContext(ctx, SQL, approval.ID, decision.Status, decision.Comment, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DecideApproval: cannot decide approval step.")
+		return "", err
+	}
+
+	status := utils.RequestPending
+	if decision.Status == utils.RequestRejected {
+		status = utils.RequestRejected
+		SQL = `UPDATE equipment_request_approvals
+               SET    status = 'skipped'
+               WHERE  request_id = $1
+               AND    status = 'pending'`
+		_, err = tx.ExecContext(ctx, SQL, approval.RequestID)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("DecideApproval: cannot skip remaining approval steps.")
+			return "", err
+		}
+	} else {
+		SQL = `SELECT NOT EXISTS(SELECT 1 FROM equipment_request_approvals WHERE request_id = $1 AND status = 'pending')`
+		var allApproved bool
+		err = tx.GetContext(ctx, &allApproved, SQL, approval.RequestID)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("DecideApproval: cannot check remaining approval steps.")
+			return "", err
+		}
+		if allApproved {
+			status = utils.RequestApproved
+		}
+	}
+
+	SQL = `UPDATE equipment_requests
+           SET    status = $2,
+                  updated_at = NOW()
+           WHERE  id = $1`
+	_, err = tx.ExecContext(ctx, SQL, approval.RequestID, status)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DecideApproval: cannot update equipment request status.")
+		return "", err
+	}
+	return status, nil
+}
+
+func CancelEquipmentRequest(ctx context.Context, requestID, employeeID string) (int64, error) {
+	SQL := `WITH cancelled AS (
+                UPDATE equipment_requests
+                SET    status = 'cancelled',
+                       updated_at = NOW()
+                WHERE  id = $1
+                AND    employee_id = $2
+                AND    status IN ('pending', 'approved')
+                RETURNING id
+            ), skipped AS (
+                UPDATE equipment_request_approvals
+                SET    status = 'skipped'
+                WHERE  request_id IN (SELECT id FROM cancelled)
+                AND    status = 'pending'
+            )
+            SELECT COUNT(*) FROM cancelled`
+	var rows int64
+	err := database.AssetManagement.GetContext(ctx, &rows, SQL, requestID, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CancelEquipmentRequest: cannot cancel equipment request.")
+		return 0, err
+	}
+	return rows, nil
+}
+
+// FulfilEquipmentRequest links an approved request of the employee to the assignment that fulfils it
+func FulfilEquipmentRequest(ctx context.Context, tx *sqlx.Tx, requestID, employeeID, relationID, userID string) (int64, error) {
+	SQL := `UPDATE equipment_requests
+            SET    status = 'fulfilled',
+                   relation_id = $3,
+                   fulfilled_by = $4,
+                   fulfilled_at = NOW(),
+                   updated_at = NOW()
+            WHERE  id = $1
+            AND    employee_id = $2
+            AND    status = 'approved'`
+	result, err := tx.ExecContext(ctx, SQL, requestID, employeeID, relationID, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("FulfilEquipmentRequest: cannot fulfil equipment request.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// IsAssetOfRequestedType checks if an asset is of the type an equipment request asks for
+func IsAssetOfRequestedType(ctx context.Context, tx *sqlx.Tx, assetID, requestID string) (bool, error) {
+	SQL := `SELECT EXISTS(SELECT 1
+                          FROM   assets a
+                              JOIN equipment_requests r ON r.asset_type = a.asset_type
+                          WHERE  a.id = $1
+                          AND    r.id = $2)`
+	var matches bool
+	err := tx.GetContext(ctx, &matches, SQL, assetID, requestID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetOfRequestedType: cannot check asset type.")
+		return false, err
+	}
+	return matches, nil
+}
+
+func CreateApprovalStep(ctx context.Context, step *models.ApprovalStep, userID string) (string, error) {
+	SQL := `INSERT INTO equipment_approval_steps(step_order, name, asset_type, approver_id, created_by)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, step.StepOrder, step.Name, step.AssetType, step.ApproverID, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateApprovalStep: cannot create approval step.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetApprovalSteps(ctx context.Context) ([]models.ApprovalStep, error) {
+	SQL := `SELECT id, step_order, name, asset_type, approver_id
+            FROM   equipment_approval_steps
+            WHERE  archived_at IS NULL
+            ORDER BY step_order, name`
+	steps := make([]models.ApprovalStep, 0)
+	err := database.AssetManagement.SelectContext(ctx, &steps, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetApprovalSteps: cannot get approval steps.")
+		return steps, err
+	}
+	return steps, nil
+}
+
+func DeleteApprovalStep(ctx context.Context, stepID string) (int64, error) {
+	SQL := `UPDATE equipment_approval_steps
+            SET    archived_at = NOW()
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, stepID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DeleteApprovalStep: cannot delete approval step.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetApproverContacts returns the approver of a step, or every admin when the step has no specific approver
+func GetApproverContacts(ctx context.Context, approverID null.String) ([]models.AdminContact, error) {
+	if !approverID.Valid {
+		return GetAdminContacts(ctx)
+	}
+	SQL := `SELECT name, email
+            FROM   users
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	approvers := make([]models.AdminContact, 0)
+	err := database.AssetManagement.SelectContext(ctx, &approvers, SQL, approverID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetApproverContacts: cannot get approver contacts.")
+		return approvers, err
+	}
+	return approvers, nil
+}
@@ -0,0 +1,180 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const handoverSelectSQL = `SELECT  h.id,
+       				h.relation_id,
+       				h.kind,
+       				ear.asset_id,
+       				COALESCE(a.asset_tag, '') AS asset_tag,
+       				a.brand,
+       				a.model,
+       				a.serial_no,
+       				a.asset_type,
+       				ear.employee_id,
+       				e.name AS employee_name,
+       				e.email AS employee_email,
+       				ear.assigned_date,
+       				ear.retrieved_date,
+       				COALESCE(h.condition_notes, '') AS condition_notes,
+       				h.accessories,
+       				h.photos,
+       				h.acknowledged_name,
+       				h.acknowledged_at,
+       				h.acknowledged_ip,
+       				h.signature_image IS NOT NULL AS has_signature,
+       				h.receipt_pdf IS NOT NULL AS has_receipt,
+       				h.created_by,
+       				h.created_at
+			FROM   handover_records h
+			    JOIN employee_asset_relation ear ON ear.id = h.relation_id
+			    JOIN assets a ON a.id = ear.asset_id
+			    JOIN employee e ON e.id = ear.employee_id
+			WHERE  h.archived_at IS NULL
+`
+
+// GetRelationForHandover finds the assignment a handover belongs to, the open one for assignments and the latest one for retrievals
+func GetRelationForHandover(ctx context.Context, assetID, employeeID, kind string) (string, error) {
+	SQL := `SELECT id
+            FROM   employee_asset_relation
+            WHERE  asset_id = $1
+            AND    employee_id = $2
+            AND    ($3 != 'assignment' OR (retrieved_date IS NULL AND archived_at IS NULL))
+            ORDER BY assigned_date DESC, created_at DESC
+            LIMIT 1`
+	var relationID string
+	err := database.AssetManagement.GetContext(ctx, &relationID, SQL, assetID, employeeID, kind)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetRelationForHandover: cannot get employee asset relation.")
+		return "", err
+	}
+	return relationID, nil
+}
+
+func CreateHandover(ctx context.Context, relationID string, handover *models.CreateHandover, userID string) (string, error) {
+	SQL := `INSERT INTO handover_records(relation_id, kind, condition_notes, accessories, created_by)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, relationID, handover.Kind, handover.ConditionNotes, handover.Accessories, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateHandover: cannot create handover record.")
+		return "", err
+	}
+	return id, nil
+}
+
+// LockHandover locks a handover until the transaction ends and reports whether it is already acknowledged
+func LockHandover(ctx context.Context, tx *sqlx.Tx, handoverID string) (bool, error) {
+	SQL := `SELECT acknowledged_at IS NOT NULL
+            FROM   handover_records
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            FOR UPDATE`
+	var acknowledged bool
+	err := tx.GetContext(ctx, &acknowledged, SQL, handoverID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("LockHandover: cannot lock handover record.")
+	}
+	return acknowledged, err
+}
+
+func AddHandoverPhoto(ctx context.Context, tx *sqlx.Tx, handoverID, url string) (int64, error) {
+	SQL := `UPDATE handover_records
+            SET    photos = array_append(photos, $2)
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            AND    acknowledged_at IS NULL`
+	result, err := tx.ExecContext(ctx, SQL, handoverID, url)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("AddHandoverPhoto: cannot add handover photo.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetHandover returns a handover record, restricted to the given employee when employeeID is not empty
+func GetHandover(ctx context.Context, handoverID, employeeID string) (models.Handover, error) {
+	SQL := handoverSelectSQL + `AND h.id = $1
+			AND (NULLIF(LENGTH($2), 0) IS NULL OR ear.employee_id::text = $2)`
+	var handover models.Handover
+	err := database.AssetManagement.GetContext(ctx, &handover, SQL, handoverID, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetHandover: cannot get handover record.")
+		return handover, err
+	}
+	return handover, nil
+}
+
+func GetHandovers(ctx context.Context, assetID, employeeID string) ([]models.Handover, error) {
+	SQL := handoverSelectSQL + `AND (NULLIF(LENGTH($1), 0) IS NULL OR ear.asset_id::text = $1)
+			AND (NULLIF(LENGTH($2), 0) IS NULL OR ear.employee_id::text = $2)
+			ORDER BY h.acknowledged_at IS NOT NULL, h.created_at DESC`
+	handovers := make([]models.Handover, 0)
+	err := database.AssetManagement.SelectContext(ctx, &handovers, SQL, assetID, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetHandovers: cannot get handover records.")
+		return handovers, err
+	}
+	return handovers, nil
+}
+
+// AcknowledgeHandover stores the acknowledgement together with the rendered receipt, only once per handover
+func AcknowledgeHandover(ctx context.Context, tx *sqlx.Tx, handover *models.Handover, receipt []byte) (int64, error) {
+	SQL := `UPDATE handover_records
+            SET    acknowledged_name = $2,
+                   acknowledged_at = $3,
+                   acknowledged_ip = $4,
+                   signature_image = $5,
+                   receipt_pdf = $6
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            AND    acknowledged_at IS NULL`
+	result, err := tx.ExecContext(ctx, SQL, handover.ID, handover.AcknowledgedName, handover.AcknowledgedAt, handover.AcknowledgedIP, handover.SignatureImage, receipt)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("AcknowledgeHandover: cannot acknowledge handover.")
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil || rows == 0 {
+		return rows, err
+	}
+
+	if handover.Kind == utils.HandoverAssignment {
+		SQL = `UPDATE employee_asset_relation
+               SET    acknowledged_at = COALESCE(acknowledged_at, $2)
+               WHERE  id = $1`
+		_, err = tx.ExecContext(ctx, SQL, handover.RelationID, handover.AcknowledgedAt)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("AcknowledgeHandover: cannot acknowledge asset assignment.")
+			return 0, err
+		}
+	}
+	return rows, nil
+}
+
+func GetHandoverReceipt(ctx context.Context, handoverID, employeeID string) ([]byte, error) {
+	SQL := `SELECT h.receipt_pdf
+            FROM   handover_records h
+                JOIN employee_asset_relation ear ON ear.id = h.relation_id
+            WHERE  h.id = $1
+            AND    h.archived_at IS NULL
+            AND    h.receipt_pdf IS NOT NULL
+            AND    (NULLIF(LENGTH($2), 0) IS NULL OR ear.employee_id::text = $2)`
+	var receipt []byte
+	err := database.AssetManagement.GetContext(ctx, &receipt, SQL, handoverID, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetHandoverReceipt: cannot get handover receipt.")
+		return nil, err
+	}
+	return receipt, nil
+}
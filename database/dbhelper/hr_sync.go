@@ -0,0 +1,363 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+// ErrManagerNotFound is returned when a synced manager email does not belong to an active employee
+var ErrManagerNotFound = errors.New("manager not found")
+
+// syncEmployeeSQL selects employees in the shape the HR sync compares against
+const syncEmployeeSQL = `SELECT  e.id,
+       							e.external_id,
+       							e.name,
+       							e.email,
+       							e.phone_no,
+       							COALESCE(e.type::text, 'employee') AS type,
+       							COALESCE(e.status::text, 'active') AS status,
+       							COALESCE(d.name, '') AS department_name,
+       							e.manager_id,
+       							COALESCE(m.email, '') AS manager_email,
+       							(SELECT COUNT(ear.id)
+       							 FROM   employee_asset_relation ear
+       							 WHERE  ear.employee_id = e.id
+       							 AND    ear.retrieved_date IS NULL
+       							 AND    ear.archived_at IS NULL) AS held_assets,
+       							e.created_at,
+       							e.updated_at
+						FROM   employee e
+						    LEFT JOIN departments d ON d.id = e.department_id
+						    LEFT JOIN employee m ON m.id = e.manager_id`
+
+func CreateProvisioningToken(ctx context.Context, name, tokenHash, userID string) (string, error) {
+	SQL := `INSERT INTO provisioning_tokens(name, token_hash, created_by)
+            VALUES ($1, $2, $3)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, name, tokenHash, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateProvisioningToken: cannot create provisioning token.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetProvisioningTokens(ctx context.Context) ([]models.ProvisioningToken, error) {
+	SQL := `SELECT  id,
+       				name,
+       				created_by,
+       				created_at,
+       				last_used_at,
+       				revoked_at
+			FROM   provisioning_tokens
+			ORDER BY created_at DESC`
+	tokens := make([]models.ProvisioningToken, 0)
+	err := database.AssetManagement.SelectContext(ctx, &tokens, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetProvisioningTokens: cannot get provisioning tokens.")
+		return tokens, err
+	}
+	return tokens, nil
+}
+
+func RevokeProvisioningToken(ctx context.Context, tokenID string) (int64, error) {
+	SQL := `UPDATE provisioning_tokens
+            SET    revoked_at = NOW()
+            WHERE  id = $1
+            AND    revoked_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, tokenID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RevokeProvisioningToken: cannot revoke provisioning token.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CheckProvisioningToken returns the id of the unrevoked token with the given hash and records its use
+func CheckProvisioningToken(ctx context.Context, tokenHash string) (string, error) {
+	SQL := `UPDATE provisioning_tokens
+            SET    last_used_at = NOW()
+            WHERE  token_hash = $1
+            AND    revoked_at IS NULL
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, tokenHash)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("CheckProvisioningToken: cannot check provisioning token.")
+	}
+	return id, err
+}
+
+// GetSyncEmployees returns every employee including deleted ones, so that the sync never recreates an email in use
+func GetSyncEmployees(ctx context.Context) ([]models.SyncEmployee, error) {
+	employees := make([]models.SyncEmployee, 0)
+	err := database.AssetManagement.SelectContext(ctx, &employees, syncEmployeeSQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetSyncEmployees: cannot get employees.")
+		return employees, err
+	}
+	return employees, nil
+}
+
+func GetSyncEmployee(ctx context.Context, employeeID string) (models.SyncEmployee, error) {
+	SQL := syncEmployeeSQL + `
+			WHERE  e.id = $1
+			AND    e.archived_at IS NULL`
+	var employee models.SyncEmployee
+	err := database.AssetManagement.GetContext(ctx, &employee, SQL, employeeID)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetSyncEmployee: cannot get employee.")
+	}
+	return employee, err
+}
+
+// GetScimEmployees returns a page of employees matching the optional email and external id filters and the total count,
+// startIndex is 1-based as in SCIM
+func GetScimEmployees(ctx context.Context, email, externalID string, startIndex, count int) ([]models.SyncEmployee, int, error) {
+	SQL := `WITH cte_employee AS (` + syncEmployeeSQL + `
+				WHERE  e.archived_at IS NULL
+				AND    (NULLIF(LENGTH($1), 0) IS NULL OR LOWER(e.email) = LOWER($1))
+				AND    (NULLIF(LENGTH($2), 0) IS NULL OR e.external_id = $2)
+			)
+			SELECT COUNT(*) FROM cte_employee`
+	var total int
+	err := database.AssetManagement.GetContext(ctx, &total, SQL, email, externalID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetScimEmployees: cannot count employees.")
+		return nil, 0, err
+	}
+
+	SQL = syncEmployeeSQL + `
+			WHERE  e.archived_at IS NULL
+			AND    (NULLIF(LENGTH($1), 0) IS NULL OR LOWER(e.email) = LOWER($1))
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR e.external_id = $2)
+			ORDER BY e.created_at, e.id
+			LIMIT $3 OFFSET $4`
+	employees := make([]models.SyncEmployee, 0)
+	err = database.AssetManagement.SelectContext(ctx, &employees, SQL, email, externalID, count, startIndex-1)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetScimEmployees: cannot get employees.")
+		return employees, 0, err
+	}
+	return employees, total, nil
+}
+
+// GetOrCreateDepartment returns the department with the given name, top level departments first, creating it when missing
+func GetOrCreateDepartment(ctx context.Context, tx *sqlx.Tx, name string, createdBy null.String) (string, error) {
+	SQL := `SELECT id
+            FROM   departments
+            WHERE  LOWER(name) = LOWER($1)
+            AND    archived_at IS NULL
+            ORDER BY parent_id NULLS FIRST
+            LIMIT 1`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, name)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetOrCreateDepartment: cannot get department.")
+		return "", err
+	}
+
+	SQL = `INSERT INTO departments(name, created_by)
+           VALUES ($1, $2)
+           RETURNING id`
+	err = tx.GetContext(ctx, &id, SQL, name, createdBy)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetOrCreateDepartment: cannot create department.")
+		return "", err
+	}
+	return id, nil
+}
+
+func CreateSyncedEmployee(ctx context.Context, tx *sqlx.Tx, employee *models.SyncEmployee, departmentID null.String) (string, error) {
+	SQL := `INSERT INTO employee(external_id, name, email, phone_no, type, status, department_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)
+            RETURNING id`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, employee.ExternalID, employee.Name, employee.Email, employee.PhoneNo, employee.Type, employee.Status, departmentID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateSyncedEmployee: cannot create employee.")
+		return "", err
+	}
+	return id, nil
+}
+
+func UpdateSyncedEmployee(ctx context.Context, tx *sqlx.Tx, employee *models.SyncEmployee, departmentID null.String) error {
+	SQL := `UPDATE employee
+            SET    external_id = $2,
+                   name = $3,
+                   email = $4,
+                   phone_no = $5,
+                   type = $6,
+                   status = $7,
+                   department_id = COALESCE($8, department_id),
+                   updated_at = NOW()
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	_, err := tx.ExecContext(ctx, SQL, employee.ID, employee.ExternalID, employee.Name, employee.Email, employee.PhoneNo, employee.Type, employee.Status, departmentID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateSyncedEmployee: cannot update employee.")
+		return err
+	}
+	if employee.Status == utils.Active {
+		return nil
+	}
+	return endEmployeeSessions(ctx, tx, employee.ID)
+}
+
+// SetSyncedManager points an employee at the active employee with the given email, ErrManagerNotFound when there is none
+func SetSyncedManager(ctx context.Context, tx *sqlx.Tx, employeeID, managerEmail string) error {
+	SQL := `SELECT id
+            FROM   employee
+            WHERE  LOWER(email) = LOWER($1)
+            AND    archived_at IS NULL`
+	var managerID string
+	err := tx.GetContext(ctx, &managerID, SQL, managerEmail)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrManagerNotFound
+		}
+		logging.FromContext(ctx).WithError(err).Error("SetSyncedManager: cannot get manager.")
+		return err
+	}
+
+	isCycle, err := IsManagerCycle(ctx, tx, employeeID, managerID)
+	if err != nil {
+		return err
+	}
+	if isCycle {
+		return errors.New("manager would report to the employee")
+	}
+
+	SQL = `UPDATE employee
+           SET    manager_id = $2,
+                  updated_at = NOW()
+           WHERE  id = $1`
+	_, err = tx.ExecContext(ctx, SQL, employeeID, managerID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("SetSyncedManager: cannot set manager.")
+		return err
+	}
+	return nil
+}
+
+func CreateSyncRun(ctx context.Context, tx *sqlx.Tx, run *models.SyncRun) (string, error) {
+	SQL := `INSERT INTO sync_runs(source, dry_run, file_name, triggered_by, token_id)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, run.Source, run.DryRun, run.FileName, run.TriggeredBy, run.TokenID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateSyncRun: cannot create sync run.")
+		return "", err
+	}
+	return id, nil
+}
+
+func CreateSyncChange(ctx context.Context, tx *sqlx.Tx, runID string, change *models.SyncChange) error {
+	SQL := `INSERT INTO sync_changes(run_id, employee_id, external_id, email, action, changes, message)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.ExecContext(ctx, SQL, runID, change.EmployeeID, change.ExternalID, change.Email, change.Action, change.Changes, change.Message)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateSyncChange: cannot create sync change.")
+		return err
+	}
+	return nil
+}
+
+func FinishSyncRun(ctx context.Context, tx *sqlx.Tx, run *models.SyncRun) error {
+	SQL := `UPDATE sync_runs
+            SET    created_count = $2,
+                   updated_count = $3,
+                   deactivated_count = $4,
+                   error_count = $5,
+                   finished_at = NOW()
+            WHERE  id = $1`
+	_, err := tx.ExecContext(ctx, SQL, run.ID, run.CreatedCount, run.UpdatedCount, run.DeactivatedCount, run.ErrorCount)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("FinishSyncRun: cannot finish sync run.")
+		return err
+	}
+	return nil
+}
+
+func GetSyncRuns(ctx context.Context, source string, limit, page int) ([]models.SyncRun, error) {
+	SQL := `SELECT  id,
+       				source,
+       				dry_run,
+       				file_name,
+       				created_count,
+       				updated_count,
+       				deactivated_count,
+       				error_count,
+       				triggered_by,
+       				token_id,
+       				started_at,
+       				finished_at
+			FROM   sync_runs
+			WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR source::text = $1)
+			ORDER BY started_at DESC
+			LIMIT $2 OFFSET $3`
+	runs := make([]models.SyncRun, 0)
+	err := database.AssetManagement.SelectContext(ctx, &runs, SQL, source, limit, limit*page)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetSyncRuns: cannot get sync runs.")
+		return runs, err
+	}
+	return runs, nil
+}
+
+func GetSyncRun(ctx context.Context, runID string) (models.SyncRun, error) {
+	SQL := `SELECT  id,
+       				source,
+       				dry_run,
+       				file_name,
+       				created_count,
+       				updated_count,
+       				deactivated_count,
+       				error_count,
+       				triggered_by,
+       				token_id,
+       				started_at,
+       				finished_at
+			FROM   sync_runs
+			WHERE  id = $1`
+	var run models.SyncRun
+	err := database.AssetManagement.GetContext(ctx, &run, SQL, runID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("GetSyncRun: cannot get sync run.")
+		}
+		return run, err
+	}
+
+	SQL = `SELECT  id,
+       			   run_id,
+       			   employee_id,
+       			   external_id,
+       			   COALESCE(email, '') AS email,
+       			   action,
+       			   changes,
+       			   COALESCE(message, '') AS message
+		   FROM   sync_changes
+		   WHERE  run_id = $1
+		   ORDER BY created_at, action`
+	run.Changes = make([]models.SyncChange, 0)
+	err = database.AssetManagement.SelectContext(ctx, &run.Changes, SQL, runID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetSyncRun: cannot get sync changes.")
+		return run, err
+	}
+	return run, nil
+}
@@ -0,0 +1,132 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// listColumn is the SQL expression of a list field over the columns of the query that lists it and the type values
+// of the field are cast to
+type listColumn struct {
+	expr string
+	cast string
+}
+
+var (
+	assetColumns = map[string]listColumn{
+		"purchaseDate":   {"purchased_date", "date"},
+		"warrantyExpiry": {"warranty_expiry_date", "date"},
+		"brand":          {"brand", "text"},
+		"model":          {"model", "text"},
+		"assetType":      {"asset_type::text", "text"},
+		"status":         {"status::text", "text"},
+		"assignee":       {"coalesce(name, '')", "text"},
+		"location":       {"coalesce(location_name, '')", "text"},
+	}
+	employeeColumns = map[string]listColumn{
+		"name":          {"name", "text"},
+		"email":         {"email", "text"},
+		"type":          {"type::text", "text"},
+		"status":        {"status::text", "text"},
+		"department":    {"coalesce(department_name, '')", "text"},
+		"manager":       {"coalesce(manager_name, '')", "text"},
+		"assetQuantity": {"asset_quantity", "bigint"},
+		"createdAt":     {"created_at", "timestamptz"},
+	}
+	accessedByColumns = map[string]listColumn{
+		"name":                {"name", "text"},
+		"email":               {"email", "text"},
+		"status":              {"status::text", "text"},
+		"authenticationTimes": {"authentication_times", "bigint"},
+		"lastLogin":           {"coalesce(start_time, '-infinity')", "timestamptz"},
+	}
+)
+
+var operators = map[string]string{
+	models.OpEq:  "=",
+	models.OpNe:  "<>",
+	models.OpLt:  "<",
+	models.OpLte: "<=",
+	models.OpGt:  ">",
+	models.OpGte: ">=",
+}
+
+// likeEscaper escapes the wildcards of ilike so that contains matches the text as typed
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// pageSQL selects the given columns of the rows of the query named from that meet the conditions of filters, in the
+// order of its sort, with the total count of those rows and the sort key of every row. With a limit it reads the page
+// after the cursor of filters, or at its page without one, and one row more than the limit so that pageEnd can tell
+// whether another page follows
+func pageSQL(from, selected string, list models.ListSpec, columns map[string]listColumn, filters *models.FiltersCheck,
+	limit int, values []interface{}) (string, []interface{}, error) {
+	sort := utils.SortOf(list, filters)
+	sortColumn, ok := columns[sort.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("cannot sort %s by %s", list.Name, sort.Field)
+	}
+
+	conditions := make([]string, 0, len(filters.Conditions))
+	for _, condition := range filters.Conditions {
+		column, ok := columns[condition.Field]
+		if !ok {
+			return "", nil, fmt.Errorf("cannot filter %s by %s", list.Name, condition.Field)
+		}
+		switch condition.Op {
+		case models.OpContains:
+			conditions = append(conditions, fmt.Sprintf("%s ilike '%%' || $%d || '%%'", column.expr, len(values)+1))
+			values = append(values, likeEscaper.Replace(fmt.Sprint(condition.Value)))
+			continue
+		case models.OpIn:
+			conditions = append(conditions, fmt.Sprintf("%s =ANY($%d)", column.expr, len(values)+1))
+			values = append(values, pq.Array(condition.Value))
+			continue
+		default:
+			operator, ok := operators[condition.Op]
+			if !ok {
+				return "", nil, fmt.Errorf("unknown filter operator %s", condition.Op)
+			}
+			conditions = append(conditions, fmt.Sprintf("%s %s $%d::%s", column.expr, operator, len(values)+1, column.cast))
+		}
+		values = append(values, condition.Value)
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	direction, after := "ASC", ">"
+	if sort.Desc {
+		direction, after = "DESC", "<"
+	}
+	SQL := fmt.Sprintf(`SELECT total_count, %s, sort_value::text AS sort_key
+						FROM (SELECT count(*) over () AS total_count, %s, %s AS sort_value FROM %s %s) listed `,
+		selected, selected, sortColumn.expr, from, where)
+
+	if limit > 0 && filters.Cursor != nil {
+		SQL += fmt.Sprintf("WHERE (sort_value, id) %s ($%d::%s, $%d::uuid) ", after, len(values)+1, sortColumn.cast, len(values)+2)
+		values = append(values, filters.Cursor.Key, filters.Cursor.ID)
+	}
+	SQL += fmt.Sprintf("ORDER BY sort_value %s, id %s", direction, direction)
+	if limit > 0 {
+		offset := limit * filters.Page
+		if filters.Cursor != nil {
+			offset = 0
+		}
+		SQL += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(values)+1, len(values)+2)
+		values = append(values, limit+1, offset)
+	}
+	return SQL, values, nil
+}
+
+// pageEnd is how many of the n rows read by pageSQL with limit belong to the page and whether another page follows
+func pageEnd(limit, n int) (int, bool) {
+	if limit > 0 && n > limit {
+		return limit, true
+	}
+	return n, false
+}
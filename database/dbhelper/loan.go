@@ -0,0 +1,256 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+const loanSelectSQL = `SELECT  ear.id AS relation_id,
+       				ear.asset_id,
+       				COALESCE(a.asset_tag, '') AS asset_tag,
+       				a.brand,
+       				a.model,
+       				a.asset_type,
+       				ear.employee_id,
+       				e.name AS employee_name,
+       				e.email,
+       				ear.assigned_date,
+       				ear.due_date,
+       				GREATEST(CURRENT_DATE - ear.due_date, 0) AS overdue_days,
+       				ear.last_reminder_at,
+       				(SELECT ler.id FROM loan_extension_requests ler
+       				 WHERE ler.relation_id = ear.id AND ler.status = 'pending'
+       				 ORDER BY ler.created_at DESC LIMIT 1) AS pending_extension_id
+			FROM   employee_asset_relation ear
+			    JOIN assets a ON a.id = ear.asset_id
+			    JOIN employee e ON e.id = ear.employee_id
+			WHERE  ear.assignment_type = 'loan'
+			AND    ear.retrieved_date IS NULL
+			AND    ear.archived_at IS NULL
+			AND    a.archived_at IS NULL
+`
+
+func GetOverdueLoans(ctx context.Context) ([]models.Loan, error) {
+	SQL := loanSelectSQL + `AND ear.due_date < CURRENT_DATE
+			ORDER BY ear.due_date`
+	loans := make([]models.Loan, 0)
+	err := database.AssetManagement.SelectContext(ctx, &loans, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetOverdueLoans: cannot get overdue loans.")
+		return loans, err
+	}
+	return loans, nil
+}
+
+// ClaimLoansToRemind marks the open loans due within the given days that were not reminded since remindAfter as
+// reminded now and returns them with the reminder time they had before. Rows are locked with SKIP LOCKED so that
+// instances running side by side remind every borrower once
+func ClaimLoansToRemind(ctx context.Context, dueWithinDays int, remindAfter time.Time) ([]models.Loan, error) {
+	SQL := `WITH cte_due AS (
+                SELECT ear.id
+                FROM   employee_asset_relation ear
+                    JOIN assets a ON a.id = ear.asset_id
+                WHERE  ear.assignment_type = 'loan'
+                AND    ear.retrieved_date IS NULL
+                AND    ear.archived_at IS NULL
+                AND    a.archived_at IS NULL
+                AND    ear.due_date <= CURRENT_DATE + $1::int
+                AND    (ear.last_reminder_at IS NULL OR ear.last_reminder_at < $2)
+                FOR UPDATE OF ear SKIP LOCKED
+            ), cte_claimed AS (
+                UPDATE employee_asset_relation ear
+                SET    last_reminder_at = NOW()
+                FROM   cte_due d
+                WHERE  ear.id = d.id
+                RETURNING ear.id
+            )
+            ` + loanSelectSQL + `AND ear.id IN (SELECT id FROM cte_claimed)
+			ORDER BY ear.due_date`
+	loans := make([]models.Loan, 0)
+	err := database.AssetManagement.SelectContext(ctx, &loans, SQL, dueWithinDays, remindAfter)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ClaimLoansToRemind: cannot claim loans to remind.")
+		return loans, err
+	}
+	return loans, nil
+}
+
+// ReleaseLoanReminder restores the reminder time a loan had before it was claimed, so that a reminder that could not
+// be sent is tried again
+func ReleaseLoanReminder(ctx context.Context, relationID string, lastReminder null.Time) error {
+	SQL := `UPDATE employee_asset_relation
+            SET    last_reminder_at = $2
+            WHERE  id = $1`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, relationID, lastReminder)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ReleaseLoanReminder: cannot release loan reminder.")
+		return err
+	}
+	return nil
+}
+
+// GetOpenLoanByAssetID returns the open loan relation id and due date of an asset
+func GetOpenLoanByAssetID(ctx context.Context, assetID string) (string, time.Time, error) {
+	SQL := `SELECT id, due_date
+            FROM   employee_asset_relation
+            WHERE  asset_id = $1
+            AND    assignment_type = 'loan'
+            AND    retrieved_date IS NULL
+            AND    archived_at IS NULL
+            ORDER BY assigned_date DESC
+            LIMIT 1`
+	var loan struct {
+		ID      string    `db:"id"`
+		DueDate time.Time `db:"due_date"`
+	}
+	err := database.AssetManagement.GetContext(ctx, &loan, SQL, assetID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("GetOpenLoanByAssetID: cannot get open loan.")
+		}
+		return "", time.Time{}, err
+	}
+	return loan.ID, loan.DueDate, nil
+}
+
+// CreateLoanExtensionRequest asks for a later due date, sql.ErrNoRows when an extension of the loan is already pending
+func CreateLoanExtensionRequest(ctx context.Context, relationID string, request *models.LoanExtensionRequest, userID string) (string, error) {
+	SQL := `INSERT INTO loan_extension_requests(relation_id, requested_due_date, reason, requested_by)
+            VALUES ($1, $2, $3, $4)
+            ON CONFLICT (relation_id) WHERE status = 'pending' DO NOTHING
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, relationID, request.RequestedDueDate, request.Reason, userID)
+	if err == sql.ErrNoRows {
+		return "", err
+	}
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateLoanExtensionRequest: cannot create loan extension request.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetLoanExtensions(ctx context.Context, status string) ([]models.LoanExtension, error) {
+	SQL := `SELECT  ler.id,
+       				ler.relation_id,
+       				ear.asset_id,
+       				ear.employee_id,
+       				e.name AS employee_name,
+       				ear.due_date AS current_due_date,
+       				ler.requested_due_date,
+       				COALESCE(ler.reason, '') AS reason,
+       				ler.status,
+       				ler.decided_by,
+       				ler.decided_at,
+       				ler.created_at
+			FROM   loan_extension_requests ler
+			    JOIN employee_asset_relation ear ON ear.id = ler.relation_id
+			    JOIN employee e ON e.id = ear.employee_id
+			WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR ler.status::text = $1)
+			ORDER BY ler.created_at DESC`
+	extensions := make([]models.LoanExtension, 0)
+	err := database.AssetManagement.SelectContext(ctx, &extensions, SQL, status)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLoanExtensions: cannot get loan extensions.")
+		return extensions, err
+	}
+	return extensions, nil
+}
+
+// ErrLoanClosed is returned when an extension is approved for a loan that was returned or archived in the meantime
+var ErrLoanClosed = errors.New("loan is returned or archived")
+
+// DecideLoanExtension closes a pending extension request and moves the due date when it is approved. Approving an
+// extension of a loan that is no longer open fails with ErrLoanClosed, the caller rolls the decision back
+func DecideLoanExtension(ctx context.Context, tx *sqlx.Tx, decision *models.LoanExtensionDecision, userID string) error {
+	SQL := `UPDATE loan_extension_requests
+            SET    status = $2,
+                   decided_by = $3,
+                   decided_at = NOW()
+            WHERE  id = $1
+            AND    status = 'pending'
+            RETURNING relation_id, requested_due_date`
+	var extension struct {
+		RelationID       string    `db:"relation_id"`
+		RequestedDueDate time.Time `db:"requested_due_date"`
+	}
+	err := tx.GetContext(ctx, &extension, SQL, decision.ID, decision.Status, userID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("DecideLoanExtension: cannot decide loan extension.")
+		}
+		return err
+	}
+	if decision.Status != utils.ExtensionApproved {
+		return nil
+	}
+
+	SQL = `UPDATE employee_asset_relation
+           SET    due_date = $2,
+                  last_reminder_at = NULL
+           WHERE  id = $1
+           AND    retrieved_date IS NULL
+           AND    archived_at IS NULL`
+	result, err := tx.ExecContext(ctx, SQL, extension.RelationID, extension.RequestedDueDate)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DecideLoanExtension: cannot extend loan due date.")
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrLoanClosed
+	}
+	return nil
+}
+
+func GetLoanStats(ctx context.Context, employeeID string) ([]models.LoanStats, error) {
+	SQL := `SELECT  e.id AS employee_id,
+       				e.name AS employee_name,
+       				COUNT(ear.id) AS total_loans,
+       				COUNT(ear.id) FILTER ( WHERE ear.retrieved_date IS NULL ) AS active_loans,
+       				COUNT(ear.id) FILTER ( WHERE ear.retrieved_date IS NULL AND ear.due_date < CURRENT_DATE ) AS overdue_loans,
+       				COUNT(ear.id) FILTER ( WHERE ear.retrieved_date > ear.due_date ) AS returned_late,
+       				(SELECT COUNT(ler.id) FROM loan_extension_requests ler
+       				    JOIN employee_asset_relation r ON r.id = ler.relation_id
+       				 WHERE r.employee_id = e.id) AS extensions,
+       				COALESCE(AVG(COALESCE(ear.retrieved_date, CURRENT_DATE) - ear.assigned_date), 0)::float AS average_loan_days
+			FROM   employee e
+			    JOIN employee_asset_relation ear ON ear.employee_id = e.id AND ear.assignment_type = 'loan'
+			WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR e.id::text = $1)
+			GROUP BY (e.id, e.name)
+			ORDER BY overdue_loans DESC, total_loans DESC`
+	stats := make([]models.LoanStats, 0)
+	err := database.AssetManagement.SelectContext(ctx, &stats, SQL, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLoanStats: cannot get loan stats.")
+		return stats, err
+	}
+	return stats, nil
+}
+
+func GetAdminContacts(ctx context.Context) ([]models.AdminContact, error) {
+	SQL := `SELECT name, email
+            FROM   users
+            WHERE  type = 'authorized'
+            AND    archived_at IS NULL`
+	admins := make([]models.AdminContact, 0)
+	err := database.AssetManagement.SelectContext(ctx, &admins, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetAdminContacts: cannot get admin contacts.")
+		return admins, err
+	}
+	return admins, nil
+}
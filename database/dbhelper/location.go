@@ -0,0 +1,279 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func CreateLocation(ctx context.Context, location *models.LocationDetails, userID string) (string, error) {
+	SQL := `INSERT INTO locations(name, type, parent_id, created_by)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, location.Name, location.Type, location.ParentID, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateLocation: cannot create location.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetLocations(ctx context.Context, locationType string) ([]models.Location, error) {
+	SQL := `SELECT  l.id,
+       				l.name,
+       				l.type,
+       				l.parent_id,
+       				p.name AS parent_name,
+       				l.employee_id,
+       				e.name AS employee_name,
+       				l.created_at,
+       				COUNT(a.id) AS asset_count
+			FROM   locations l
+			    LEFT JOIN locations p ON p.id = l.parent_id
+			    LEFT JOIN employee e ON e.id = l.employee_id
+			    LEFT JOIN assets a ON a.location_id = l.id AND a.archived_at IS NULL
+			WHERE  l.archived_at IS NULL
+			AND    (NULLIF(LENGTH($1), 0) IS NULL OR l.type::text = $1)
+			GROUP BY (l.id, l.name, l.type, l.parent_id, p.name, l.employee_id, e.name, l.created_at)
+			ORDER BY l.type, l.name`
+	locations := make([]models.Location, 0)
+	err := database.AssetManagement.SelectContext(ctx, &locations, SQL, locationType)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLocations: cannot get locations.")
+		return locations, err
+	}
+	return locations, nil
+}
+
+func UpdateLocation(ctx context.Context, location *models.LocationDetails) error {
+	SQL := `UPDATE locations
+            SET    name = $1,
+                   type = $2,
+                   parent_id = $3,
+                   updated_at = NOW()
+            WHERE  id = $4
+            AND    archived_at IS NULL
+            AND    type != 'employee'`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, location.Name, location.Type, location.ParentID, location.ID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateLocation: cannot update location.")
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// IsLocationInSubtree checks if candidateID is locationID itself or one of its descendants
+func IsLocationInSubtree(ctx context.Context, locationID, candidateID string) (bool, error) {
+	SQL := `WITH RECURSIVE cte_subtree AS (
+    				SELECT id FROM locations WHERE id = $1
+    				UNION ALL
+    				SELECT l.id FROM locations l JOIN cte_subtree s ON l.parent_id = s.id
+			)
+			SELECT EXISTS(SELECT 1 FROM cte_subtree WHERE id = $2)`
+	var exists bool
+	err := database.AssetManagement.GetContext(ctx, &exists, SQL, locationID, candidateID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsLocationInSubtree: cannot check location tree.")
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetLocationUsage returns the number of active assets and child locations placed in a location
+func GetLocationUsage(ctx context.Context, locationID string) (int, error) {
+	SQL := `SELECT (SELECT COUNT(id) FROM assets WHERE location_id = $1 AND archived_at IS NULL) +
+				   (SELECT COUNT(id) FROM locations WHERE parent_id = $1 AND archived_at IS NULL)`
+	var count int
+	err := database.AssetManagement.GetContext(ctx, &count, SQL, locationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLocationUsage: cannot get location usage.")
+		return -1, err
+	}
+	return count, nil
+}
+
+func DeleteLocation(ctx context.Context, locationID string) error {
+	SQL := `UPDATE locations
+            SET    archived_at = NOW()
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, locationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("DeleteLocation: cannot delete location.")
+		return err
+	}
+	return nil
+}
+
+// EmployeeLocation returns the "with employee" location of an employee, creating it on first use
+func EmployeeLocation(ctx context.Context, tx *sqlx.Tx, employeeID, userID string) (string, error) {
+	SQL := `INSERT INTO locations(name, type, employee_id, created_by)
+            SELECT name, $2, id, $3
+            FROM   employee
+            WHERE  id = $1
+            ON CONFLICT (employee_id) WHERE employee_id IS NOT NULL AND archived_at IS NULL
+            DO UPDATE SET updated_at = NOW()
+            RETURNING id`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, employeeID, utils.LocationEmployee, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("EmployeeLocation: cannot get employee location.")
+		return "", err
+	}
+	return id, nil
+}
+
+// IsAssetTransferable locks the asset row for the rest of the transaction and checks that it is neither assigned to
+// an employee nor held by a reservation running today
+func IsAssetTransferable(ctx context.Context, tx *sqlx.Tx, assetID string) (bool, error) {
+	SQL := `SELECT NOT EXISTS(SELECT 1 FROM employee_asset_relation ear
+                              WHERE  ear.asset_id = a.id
+                              AND    ear.retrieved_date IS NULL
+                              AND    ear.archived_at IS NULL)
+               AND NOT EXISTS(SELECT 1 FROM reservations r
+                              WHERE  r.asset_id = a.id
+                              AND    r.status = 'active'
+                              AND    r.start_date <= CURRENT_DATE
+                              AND    r.end_date >= CURRENT_DATE)
+            FROM   assets a
+            WHERE  a.id = $1
+            AND    a.archived_at IS NULL
+            FOR UPDATE OF a`
+	var transferable bool
+	err := tx.GetContext(ctx, &transferable, SQL, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetTransferable: cannot check asset.")
+		return false, err
+	}
+	return transferable, nil
+}
+
+// IsStorageLocation checks that a location exists, is not archived and is not the location of an employee
+func IsStorageLocation(ctx context.Context, tx *sqlx.Tx, locationID string) (bool, error) {
+	SQL := `SELECT EXISTS(
+                SELECT 1 FROM locations
+                WHERE  id::text = $1
+                AND    archived_at IS NULL
+                AND    type != 'employee')`
+	var exists bool
+	err := tx.GetContext(ctx, &exists, SQL, locationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsStorageLocation: cannot check location.")
+		return false, err
+	}
+	return exists, nil
+}
+
+// TransferAsset moves an asset to a new location and records where it came from
+func TransferAsset(ctx context.Context, tx *sqlx.Tx, transfer *models.AssetTransfer, userID string) error {
+	SQL := `WITH cte_previous AS (
+    				SELECT id, location_id
+    				FROM   assets
+    				WHERE  id = $1
+    				AND    archived_at IS NULL
+    				FOR UPDATE
+			), cte_moved AS (
+			    UPDATE assets a
+			    SET    location_id = $2,
+			           updated_at = NOW()
+			    FROM   cte_previous p
+			    WHERE  a.id = p.id
+			    RETURNING a.id, p.location_id AS from_location_id
+			)
+			INSERT INTO asset_transfers(asset_id, from_location_id, to_location_id, transferred_by, note)
+			SELECT id, from_location_id, $2, $3, $4
+			FROM   cte_moved`
+	result, err := tx.ExecContext(ctx, SQL, transfer.AssetID, transfer.ToLocationID, userID, transfer.Note)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("TransferAsset: cannot transfer asset.")
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func AssetTransfers(ctx context.Context, assetID string) ([]models.AssetTransferHistory, error) {
+	SQL := `SELECT  t.id,
+       				t.asset_id,
+       				t.from_location_id,
+       				fl.name AS from_location_name,
+       				t.to_location_id,
+       				tl.name AS to_location_name,
+       				t.transferred_by,
+       				t.transferred_at,
+       				COALESCE(t.note, '') AS note
+			FROM   asset_transfers t
+			    LEFT JOIN locations fl ON fl.id = t.from_location_id
+			    LEFT JOIN locations tl ON tl.id = t.to_location_id
+			WHERE  t.asset_id = $1
+			ORDER BY t.transferred_at DESC`
+	transfers := make([]models.AssetTransferHistory, 0)
+	err := database.AssetManagement.SelectContext(ctx, &transfers, SQL, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("AssetTransfers: cannot get asset transfers.")
+		return transfers, err
+	}
+	return transfers, nil
+}
+
+func GetLocationQuantities(ctx context.Context) (models.LocationDashboard, error) {
+	var dashboard models.LocationDashboard
+	// counts roll up from shelves to rooms to sites
+	SQL := `WITH RECURSIVE cte_tree AS (
+    				SELECT id AS root_id, id
+    				FROM   locations
+    				WHERE  archived_at IS NULL
+    				AND    type != 'employee'
+    				UNION ALL
+    				SELECT t.root_id, l.id
+    				FROM   locations l JOIN cte_tree t ON l.parent_id = t.id
+    				WHERE  l.archived_at IS NULL
+			)
+			SELECT  l.id,
+			        l.name,
+			        l.type,
+			        l.parent_id,
+			        COUNT(a.id) AS asset_count
+			FROM   locations l
+			    JOIN cte_tree t ON t.root_id = l.id
+			    LEFT JOIN assets a ON a.location_id = t.id AND a.archived_at IS NULL
+			GROUP BY (l.id, l.name, l.type, l.parent_id)
+			ORDER BY l.type, l.name`
+	dashboard.Locations = make([]models.LocationQuantity, 0)
+	err := database.AssetManagement.SelectContext(ctx, &dashboard.Locations, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLocationQuantities: cannot get location quantities.")
+		return dashboard, err
+	}
+
+	SQL = `SELECT count(*) FILTER ( WHERE l.type = 'employee' ) AS with_employees,
+				  count(*) FILTER ( WHERE a.location_id IS NULL ) AS unknown_location
+		   FROM   assets a
+		       LEFT JOIN locations l ON l.id = a.location_id
+		   WHERE  a.archived_at IS NULL`
+	err = database.AssetManagement.GetContext(ctx, &dashboard, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetLocationQuantities: cannot get employee location quantities.")
+		return dashboard, err
+	}
+	return dashboard, nil
+}
@@ -0,0 +1,40 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"time"
+)
+
+// GetInventoryMetrics counts the assets by type and status, deleted assets included, the assignments that are not
+// retrieved yet and the warranties of assets in use that expire within the given window
+func GetInventoryMetrics(ctx context.Context, warrantyWindow time.Duration) (models.InventoryMetrics, error) {
+	var metrics models.InventoryMetrics
+	SQL := `SELECT   asset_type,
+                     CASE WHEN archived_at IS NOT NULL THEN 'deleted' ELSE status::text END AS status,
+                     count(*) AS count
+            FROM     assets
+            GROUP BY 1, 2`
+	err := database.AssetManagement.SelectContext(ctx, &metrics.Assets, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetInventoryMetrics: cannot count assets.")
+		return metrics, err
+	}
+
+	SQL = `SELECT (SELECT count(*)
+                   FROM   employee_asset_relation
+                   WHERE  retrieved_date IS NULL
+                   AND    archived_at IS NULL) AS open_assignments,
+                  (SELECT count(*)
+                   FROM   assets
+                   WHERE  archived_at IS NULL
+                   AND    warranty_expiry_date BETWEEN now() AND now() + $1 * interval '1 second') AS expiring_warranties`
+	err = database.AssetManagement.GetContext(ctx, &metrics, SQL, warrantyWindow.Seconds())
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetInventoryMetrics: cannot count assignments and warranties.")
+		return metrics, err
+	}
+	return metrics, nil
+}
@@ -0,0 +1,74 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+// EnqueueNotification writes an email to the notification outbox inside the caller's transaction, so it is only sent
+// once the change it tells about is committed
+func EnqueueNotification(ctx context.Context, tx *sqlx.Tx, kind string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	SQL := `INSERT INTO notification_outbox(kind, payload)
+            VALUES ($1, $2)`
+	_, err = tx.ExecContext(ctx, SQL, kind, payload)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("EnqueueNotification: cannot enqueue notification.")
+		return err
+	}
+	return nil
+}
+
+// ClaimNotifications returns unsent notifications that are due and have been tried fewer than maxAttempts times, and
+// pushes their next attempt out by lease so that another worker does not send them again while they are in flight
+func ClaimNotifications(ctx context.Context, limit, maxAttempts int, lease time.Duration) ([]models.DueNotification, error) {
+	SQL := `WITH due AS (
+                SELECT id
+                FROM   notification_outbox
+                WHERE  sent_at IS NULL
+                AND    attempts < $2
+                AND    next_attempt_at <= NOW()
+                ORDER BY next_attempt_at
+                LIMIT $1
+                FOR UPDATE SKIP LOCKED
+            )
+            UPDATE notification_outbox n
+            SET    next_attempt_at = NOW() + make_interval(secs => $3)
+            FROM   due
+            WHERE  n.id = due.id
+            RETURNING n.id, n.kind, n.payload, n.attempts`
+	notifications := make([]models.DueNotification, 0)
+	err := database.AssetManagement.SelectContext(ctx, &notifications, SQL, limit, maxAttempts, lease.Seconds())
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ClaimNotifications: cannot claim notifications.")
+		return notifications, err
+	}
+	return notifications, nil
+}
+
+// RecordNotificationAttempt marks a notification sent, or counts the failed attempt and schedules the next one at
+// nextAttemptAt
+func RecordNotificationAttempt(ctx context.Context, notificationID string, sendErr null.String, nextAttemptAt null.Time) error {
+	SQL := `UPDATE notification_outbox
+            SET    attempts = attempts + 1,
+                   last_error = $2,
+                   next_attempt_at = $3,
+                   sent_at = CASE WHEN $2::text IS NULL THEN NOW() END
+            WHERE  id = $1`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, notificationID, sendErr, nextAttemptAt)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RecordNotificationAttempt: cannot record notification attempt.")
+		return err
+	}
+	return nil
+}
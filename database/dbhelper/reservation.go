@@ -0,0 +1,295 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+const reservationSelectSQL = `SELECT  r.id,
+       				r.asset_id,
+       				r.asset_type,
+       				COALESCE(r.brand, a.brand) AS brand,
+       				COALESCE(r.model, a.model) AS model,
+       				r.employee_id,
+       				r.holder_name,
+       				r.purpose,
+       				r.start_date,
+       				r.end_date,
+       				a.asset_tag,
+       				e.name AS employee_name,
+       				r.status,
+       				r.relation_id,
+       				r.created_by,
+       				r.created_at
+			FROM   reservations r
+			    LEFT JOIN assets a ON a.id = r.asset_id
+			    LEFT JOIN employee e ON e.id = r.employee_id
+`
+
+// notReservedSQL excludes assets holding an active reservation that overlaps the period starting at the from argument
+// and ending at the to argument, an empty end meaning the asset is needed indefinitely
+func notReservedSQL(idColumn string, fromArg, toArg int) string {
+	return fmt.Sprintf(` AND %s NOT IN (
+			SELECT r.asset_id FROM reservations r
+			WHERE  r.status = 'active'
+			AND    r.asset_id IS NOT NULL
+			AND    r.end_date >= $%d
+			AND    ($%d::date IS NULL OR r.start_date <= $%d))`, idColumn, fromArg, toArg, toArg)
+}
+
+func CreateReservation(ctx context.Context, tx *sqlx.Tx, reservation *models.Reservation, userID string) (string, error) {
+	SQL := `INSERT INTO reservations(asset_id, asset_type, brand, model, employee_id, holder_name, purpose, start_date, end_date, created_by)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+            RETURNING id`
+	var id string
+	err := tx.GetContext(ctx, &id, SQL, reservation.AssetID, reservation.AssetType, reservation.Brand, reservation.Model, reservation.EmployeeID,
+		reservation.HolderName, reservation.Purpose, reservation.StartDate, reservation.EndDate, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateReservation: cannot create reservation.")
+		return "", err
+	}
+	return id, nil
+}
+
+// GetReservationAsset fills in the type, brand and model of a reservation for a specific asset
+func GetReservationAsset(ctx context.Context, tx *sqlx.Tx, reservation *models.Reservation) error {
+	SQL := `SELECT asset_type, brand, model
+            FROM   assets
+            WHERE  id = $1
+            AND    archived_at IS NULL
+            FOR UPDATE`
+	err := tx.QueryRowxContext(ctx, SQL, reservation.AssetID).Scan(&reservation.AssetType, &reservation.Brand, &reservation.Model)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetReservationAsset: cannot get reserved asset.")
+		return err
+	}
+	return nil
+}
+
+// GetReservationConflicts returns what prevents the reservation from being honoured: overlapping reservations and
+// assignments of a specific asset, or for a model reservation the overlapping reservations once every unit is taken
+func GetReservationConflicts(ctx context.Context, tx *sqlx.Tx, reservation *models.Reservation, excludeID string) ([]models.ReservationConflict, error) {
+	conflicts := make([]models.ReservationConflict, 0)
+	if reservation.AssetID.Valid {
+		SQL := `SELECT r.id AS reservation_id, r.asset_id, 'asset is already reserved' AS reason, r.start_date, r.end_date
+                FROM   reservations r
+                WHERE  r.status = 'active'
+                AND    r.asset_id = $1
+                AND    r.id::text != $4
+                AND    r.start_date <= $3
+                AND    r.end_date >= $2
+                UNION ALL
+                SELECT ear.id AS reservation_id, ear.asset_id, 'asset is assigned' AS reason, ear.assigned_date AS start_date, COALESCE(ear.due_date, $3::date) AS end_date
+                FROM   employee_asset_relation ear
+                WHERE  ear.asset_id = $1
+                AND    ear.retrieved_date IS NULL
+                AND    ear.archived_at IS NULL
+                AND    (ear.due_date IS NULL OR ear.due_date >= $2)`
+		err := tx.SelectContext(ctx, &conflicts, SQL, reservation.AssetID, reservation.StartDate, reservation.EndDate, excludeID)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("GetReservationConflicts: cannot check asset reservation conflicts.")
+			return conflicts, err
+		}
+		return conflicts, nil
+	}
+
+	// reservations of the same model are checked one at a time until the transaction ends, otherwise two of them could
+	// both count the last free unit
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1 || '/' || $2))`, reservation.Brand, reservation.Model)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetReservationConflicts: cannot lock model reservations.")
+		return conflicts, err
+	}
+
+	// a unit is free when it is neither assigned past the start date nor reserved on its own for the period
+	SQL := `WITH units AS (
+                SELECT a.id
+                FROM   assets a
+                WHERE  a.brand = $1
+                AND    a.model = $2
+                AND    a.archived_at IS NULL
+                AND    NOT EXISTS(SELECT 1 FROM employee_asset_relation ear
+                                  WHERE ear.asset_id = a.id AND ear.retrieved_date IS NULL AND ear.archived_at IS NULL
+                                  AND (ear.due_date IS NULL OR ear.due_date >= $3))
+                AND    NOT EXISTS(SELECT 1 FROM reservations r
+                                  WHERE r.asset_id = a.id AND r.status = 'active' AND r.id::text != $5
+                                  AND r.start_date <= $4 AND r.end_date >= $3)
+            ), demand AS (
+                SELECT r.id AS reservation_id, r.start_date, r.end_date
+                FROM   reservations r
+                WHERE  r.status = 'active'
+                AND    r.asset_id IS NULL
+                AND    r.brand = $1
+                AND    r.model = $2
+                AND    r.id::text != $5
+                AND    r.start_date <= $4
+                AND    r.end_date >= $3
+            )
+            SELECT d.reservation_id::text AS reservation_id, '' AS asset_id, 'every unit of this model is taken for the period' AS reason, d.start_date, d.end_date
+            FROM   demand d
+            WHERE  (SELECT COUNT(*) FROM demand) >= (SELECT COUNT(*) FROM units)
+            UNION ALL
+            SELECT '' AS reservation_id, '' AS asset_id, 'no unit of this model exists' AS reason, $3::date AS start_date, $4::date AS end_date
+            WHERE  NOT EXISTS(SELECT 1 FROM units)`
+	err = tx.SelectContext(ctx, &conflicts, SQL, reservation.Brand, reservation.Model, reservation.StartDate, reservation.EndDate, excludeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetReservationConflicts: cannot check model reservation conflicts.")
+		return conflicts, err
+	}
+	return conflicts, nil
+}
+
+// IsAssetReserved checks if an asset holds an active reservation overlapping an assignment from the given date until
+// its due date, or indefinitely when there is no due date
+func IsAssetReserved(ctx context.Context, tx *sqlx.Tx, assetID, from string, to null.Time, excludeID string) (bool, error) {
+	SQL := `SELECT EXISTS(
+                SELECT 1 FROM reservations r
+                WHERE  r.status = 'active'
+                AND    r.asset_id = $1
+                AND    r.id::text != $4
+                AND    r.end_date >= GREATEST($2::date, CURRENT_DATE)
+                AND    ($3::date IS NULL OR r.start_date <= $3))`
+	var reserved bool
+	err := tx.GetContext(ctx, &reserved, SQL, assetID, from, to, excludeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetReserved: cannot check asset reservations.")
+		return false, err
+	}
+	return reserved, nil
+}
+
+func GetReservations(ctx context.Context, assetID, status, from, to string) ([]models.ReservationDetails, error) {
+	SQL := reservationSelectSQL + `WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR r.asset_id::text = $1)
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR r.status::text = $2)
+			AND    (NULLIF($3, '') IS NULL OR r.end_date >= NULLIF($3, '')::date)
+			AND    (NULLIF($4, '') IS NULL OR r.start_date <= NULLIF($4, '')::date)
+			ORDER BY r.start_date, r.created_at`
+	reservations := make([]models.ReservationDetails, 0)
+	err := database.AssetManagement.SelectContext(ctx, &reservations, SQL, assetID, status, from, to)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetReservations: cannot get reservations.")
+		return reservations, err
+	}
+	return reservations, nil
+}
+
+// GetActiveReservation locks an active reservation for conversion
+func GetActiveReservation(ctx context.Context, tx *sqlx.Tx, reservationID string) (models.ReservationDetails, error) {
+	SQL := reservationSelectSQL + `WHERE  r.id = $1
+			AND    r.status = 'active'
+			FOR UPDATE OF r`
+	var reservation models.ReservationDetails
+	err := tx.GetContext(ctx, &reservation, SQL, reservationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetActiveReservation: cannot get reservation.")
+		return reservation, err
+	}
+	return reservation, nil
+}
+
+// IsAssetOfModel checks if an asset can fulfil a reservation for any unit of the brand and model
+func IsAssetOfModel(ctx context.Context, tx *sqlx.Tx, assetID, brand, model string) (bool, error) {
+	SQL := `SELECT EXISTS(SELECT 1 FROM assets WHERE id = $1 AND brand = $2 AND model = $3 AND archived_at IS NULL)`
+	var matches bool
+	err := tx.GetContext(ctx, &matches, SQL, assetID, brand, model)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsAssetOfModel: cannot check asset model.")
+		return false, err
+	}
+	return matches, nil
+}
+
+func ConvertReservation(ctx context.Context, tx *sqlx.Tx, reservationID, relationID string) error {
+	SQL := `UPDATE reservations
+            SET    status = 'converted',
+                   relation_id = $2,
+                   updated_at = NOW()
+            WHERE  id = $1`
+	_, err := tx.ExecContext(ctx, SQL, reservationID, relationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ConvertReservation: cannot convert reservation.")
+		return err
+	}
+	return nil
+}
+
+func CancelReservation(ctx context.Context, reservationID string) (int64, error) {
+	SQL := `UPDATE reservations
+            SET    status = 'cancelled',
+                   updated_at = NOW()
+            WHERE  id = $1
+            AND    status = 'active'`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, reservationID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CancelReservation: cannot cancel reservation.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// modelReservationCounts returns per model of a brand how many units are reserved without a specific asset for the period
+func modelReservationCounts(ctx context.Context, brand string, from time.Time, to null.Time) (map[string]int, error) {
+	SQL := `SELECT model, COUNT(*) AS reserved
+            FROM   reservations
+            WHERE  status = 'active'
+            AND    asset_id IS NULL
+            AND    brand = $1
+            AND    end_date >= $2
+            AND    ($3::date IS NULL OR start_date <= $3)
+            GROUP BY model`
+	rows := make([]struct {
+		Model    string `db:"model"`
+		Reserved int    `db:"reserved"`
+	}, 0)
+	err := database.AssetManagement.SelectContext(ctx, &rows, SQL, brand, from, to)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("modelReservationCounts: cannot get model reservations.")
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for i := range rows {
+		counts[rows[i].Model] = rows[i].Reserved
+	}
+	return counts, nil
+}
+
+// GetCalendarEvents returns active reservations and the due dates of open loans ending on or after the given date
+func GetCalendarEvents(ctx context.Context, from time.Time) ([]models.CalendarEvent, error) {
+	SQL := `SELECT  'reservation-' || r.id AS uid,
+       				'Reserved: ' || COALESCE(a.asset_tag, r.brand || ' ' || r.model) || ' for ' || COALESCE(e.name, r.holder_name, 'unassigned') AS summary,
+       				r.purpose AS description,
+       				r.start_date,
+       				r.end_date
+			FROM   reservations r
+			    LEFT JOIN assets a ON a.id = r.asset_id
+			    LEFT JOIN employee e ON e.id = r.employee_id
+			WHERE  r.status = 'active'
+			AND    r.end_date >= $1
+			UNION ALL
+			SELECT  'return-' || ear.id AS uid,
+       				'Return due: ' || COALESCE(a.asset_tag, a.brand || ' ' || a.model) || ' from ' || e.name AS summary,
+       				a.brand || ' ' || a.model || ' lent on ' || TO_CHAR(ear.assigned_date, 'DD Mon YYYY') AS description,
+       				ear.due_date AS start_date,
+       				ear.due_date AS end_date
+			FROM   employee_asset_relation ear
+			    JOIN assets a ON a.id = ear.asset_id
+			    JOIN employee e ON e.id = ear.employee_id
+			WHERE  ear.retrieved_date IS NULL
+			AND    ear.archived_at IS NULL
+			AND    ear.due_date >= $1
+			ORDER BY start_date`
+	events := make([]models.CalendarEvent, 0)
+	err := database.AssetManagement.SelectContext(ctx, &events, SQL, from)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetCalendarEvents: cannot get calendar events.")
+		return events, err
+	}
+	return events, nil
+}
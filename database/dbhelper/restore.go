@@ -0,0 +1,293 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+// specTables are the tables holding the specification of each asset type, mice have none
+var specTables = map[models.AssetType]string{
+	models.Laptop:   "laptop_specifications",
+	models.Harddisk: "hard_disk_specifications",
+	models.Pendrive: "pen_drive_specifications",
+	models.Mobile:   "mobile_specifications",
+	models.Sim:      "sim_specifications",
+}
+
+// GetArchivedAsset locks the asset for the rest of the transaction and returns its type and deletion, the deletion
+// is empty when the asset is not deleted
+func GetArchivedAsset(ctx context.Context, tx *sqlx.Tx, assetID string) (models.AssetType, null.Time, error) {
+	SQL := `SELECT asset_type, archived_at
+            FROM   assets
+            WHERE  id = $1
+            FOR UPDATE`
+	var asset struct {
+		AssetType  models.AssetType `db:"asset_type"`
+		ArchivedAt null.Time        `db:"archived_at"`
+	}
+	err := tx.GetContext(ctx, &asset, SQL, assetID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetArchivedAsset: cannot get asset.")
+		return asset.AssetType, asset.ArchivedAt, err
+	}
+	return asset.AssetType, asset.ArchivedAt, nil
+}
+
+// GetAssetRestoreConflict describes the identifier of a deleted asset that an asset which is not deleted has taken
+// since, the serial number of an asset of the same type or an IMEI. It is empty when there is none
+func GetAssetRestoreConflict(ctx context.Context, tx *sqlx.Tx, assetID string) (string, error) {
+	SQL := `SELECT 'serial number ' || o.serial_no
+            FROM   assets a
+                JOIN assets o ON LOWER(o.serial_no) = LOWER(a.serial_no)
+                             AND o.asset_type = a.asset_type
+                             AND o.id != a.id
+                             AND o.archived_at IS NULL
+            WHERE  a.id = $1
+            AND    a.serial_no != ''
+            UNION ALL
+            SELECT 'IMEI ' || COALESCE(NULLIF(ms.imei_1, ''), ms.imei_2)
+            FROM   mobile_specifications ms
+                JOIN assets a ON a.id = ms.asset_id
+                JOIN mobile_specifications os ON os.asset_id != ms.asset_id
+                                             AND os.archived_at IS NULL
+                                             AND (os.imei_1 IN (NULLIF(ms.imei_1, ''), NULLIF(ms.imei_2, ''))
+                                                  OR os.imei_2 IN (NULLIF(ms.imei_1, ''), NULLIF(ms.imei_2, '')))
+            WHERE  ms.asset_id = $1
+            AND    ms.archived_at = a.archived_at
+            LIMIT  1`
+	var conflict string
+	err := tx.GetContext(ctx, &conflict, SQL, assetID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logging.FromContext(ctx).WithError(err).Error("GetAssetRestoreConflict: cannot check restore conflicts.")
+		return "", err
+	}
+	return conflict, nil
+}
+
+// RestoreAsset reverses DeleteAsset and the deletion of the specification archived along with the asset, the
+// deletion is returned for the restore history
+func RestoreAsset(ctx context.Context, tx *sqlx.Tx, assetID string, assetType models.AssetType) (models.Archived, error) {
+	var archived models.Archived
+	if table, ok := specTables[assetType]; ok {
+		SQL := `UPDATE ` + table + `
+                SET    archived_at = NULL
+                WHERE  asset_id = $1
+                AND    archived_at = (SELECT archived_at FROM assets WHERE id = $1)`
+		if _, err := tx.ExecContext(ctx, SQL, assetID); err != nil {
+			logging.FromContext(ctx).WithError(err).Error("RestoreAsset: cannot restore asset specifications.")
+			return archived, err
+		}
+	}
+
+	SQL := `UPDATE assets a
+            SET    archived_at = NULL,
+                   archive_reason = NULL,
+                   deleted_by = NULL,
+                   status = $2,
+                   is_available = true,
+                   updated_at = NOW()
+            FROM   assets deleted
+            WHERE  a.id = $1
+            AND    deleted.id = a.id
+            AND    a.archived_at IS NOT NULL
+            RETURNING deleted.archived_at, deleted.archive_reason, deleted.deleted_by`
+	err := tx.GetContext(ctx, &archived, SQL, assetID, utils.Available)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RestoreAsset: cannot restore asset.")
+		return archived, err
+	}
+	return archived, nil
+}
+
+// GetArchivedEmployee locks the employee for the rest of the transaction and returns their email and deletion, the
+// deletion is empty when the employee is not deleted
+func GetArchivedEmployee(ctx context.Context, tx *sqlx.Tx, employeeID string) (string, null.Time, error) {
+	SQL := `SELECT email, archived_at
+            FROM   employee
+            WHERE  id = $1
+            FOR UPDATE`
+	var employee struct {
+		Email      string    `db:"email"`
+		ArchivedAt null.Time `db:"archived_at"`
+	}
+	err := tx.GetContext(ctx, &employee, SQL, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetArchivedEmployee: cannot get employee.")
+		return employee.Email, employee.ArchivedAt, err
+	}
+	return employee.Email, employee.ArchivedAt, nil
+}
+
+// IsEmployeeEmailTaken checks if an employee who is not deleted has the email in any case
+func IsEmployeeEmailTaken(ctx context.Context, tx *sqlx.Tx, email, employeeID string) (bool, error) {
+	SQL := `SELECT count(*) > 0
+            FROM   employee
+            WHERE  LOWER(email) = LOWER($1)
+            AND    id != $2
+            AND    archived_at IS NULL`
+	var taken bool
+	err := tx.GetContext(ctx, &taken, SQL, email, employeeID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("IsEmployeeEmailTaken: cannot check employee email.")
+		return false, err
+	}
+	return taken, nil
+}
+
+// RestoreEmployee reverses DeleteEmployee, the employee comes back active and the deletion is returned for the
+// restore history
+func RestoreEmployee(ctx context.Context, tx *sqlx.Tx, employeeID string) (models.Archived, error) {
+	SQL := `UPDATE employee e
+            SET    archived_at = NULL,
+                   archive_reason = NULL,
+                   deleted_by = NULL,
+                   status = $2,
+                   updated_at = NOW()
+            FROM   employee deleted
+            WHERE  e.id = $1
+            AND    deleted.id = e.id
+            AND    e.archived_at IS NOT NULL
+            RETURNING deleted.archived_at, deleted.archive_reason, deleted.deleted_by`
+	var archived models.Archived
+	err := tx.GetContext(ctx, &archived, SQL, employeeID, utils.Active)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RestoreEmployee: cannot restore employee.")
+		return archived, err
+	}
+	return archived, nil
+}
+
+func CreateRestoreRecord(ctx context.Context, tx *sqlx.Tx, entityType, entityID string, archived models.Archived, restoreReason, userID string) error {
+	SQL := `INSERT INTO restore_history(entity_type, entity_id, archived_at, archive_reason, deleted_by, restore_reason, restored_by)
+            VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := tx.ExecContext(ctx, SQL, entityType, entityID, archived.ArchivedAt, archived.ArchiveReason, archived.DeletedBy, restoreReason, userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateRestoreRecord: cannot create restore record.")
+		return err
+	}
+	return nil
+}
+
+// GetRestoreHistory lists the restores of an entity type, newest first, for one entity when entityID is set
+func GetRestoreHistory(ctx context.Context, entityType, entityID string, limit, page int) ([]models.RestoreRecord, error) {
+	SQL := `SELECT rh.id,
+                   rh.entity_type,
+                   rh.entity_id,
+                   rh.archived_at,
+                   rh.archive_reason,
+                   rh.deleted_by,
+                   d.name AS deleted_by_name,
+                   rh.restore_reason,
+                   rh.restored_by,
+                   r.name AS restored_by_name,
+                   rh.restored_at
+            FROM   restore_history rh
+                JOIN users r ON r.id = rh.restored_by
+                LEFT JOIN users d ON d.id = rh.deleted_by
+            WHERE  rh.entity_type = $1
+            AND    (NULLIF($2, '') IS NULL OR rh.entity_id::text = $2)
+            ORDER BY rh.restored_at DESC
+            LIMIT  $3 OFFSET $4`
+	records := make([]models.RestoreRecord, 0)
+	err := database.AssetManagement.SelectContext(ctx, &records, SQL, entityType, entityID, limit, limit*page)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetRestoreHistory: cannot get restore history.")
+		return records, err
+	}
+	return records, nil
+}
+
+// PurgeArchived hard deletes the assets and employees archived before the cutoff in one transaction. An asset goes
+// with its specification, assignments, handovers, transfers, reservations and reports. An employee goes once no
+// assignment refers to them any more, i.e. after the assets they held are purged, along with their portal account,
+// requests, reservations and reports; employees they managed and sync logs naming them are kept
+func PurgeArchived(ctx context.Context, cutoff time.Time) (models.PurgeCount, error) {
+	var count models.PurgeCount
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		assetIDs := make([]string, 0)
+		err := tx.SelectContext(ctx, &assetIDs, `SELECT id FROM assets WHERE archived_at < $1 FOR UPDATE`, cutoff)
+		if err != nil {
+			return err
+		}
+		if len(assetIDs) > 0 {
+			assets := pq.Array(assetIDs)
+			statements := []string{
+				`DELETE FROM handover_records
+                 WHERE  relation_id IN (SELECT id FROM employee_asset_relation WHERE asset_id = ANY($1::uuid[]))`,
+				`DELETE FROM loan_extension_requests
+                 WHERE  relation_id IN (SELECT id FROM employee_asset_relation WHERE asset_id = ANY($1::uuid[]))`,
+				`UPDATE equipment_requests
+                 SET    relation_id = NULL
+                 WHERE  relation_id IN (SELECT id FROM employee_asset_relation WHERE asset_id = ANY($1::uuid[]))`,
+				`DELETE FROM reservations WHERE asset_id = ANY($1::uuid[])`,
+				`DELETE FROM employee_asset_relation WHERE asset_id = ANY($1::uuid[])`,
+				`DELETE FROM asset_transfers WHERE asset_id = ANY($1::uuid[])`,
+				`DELETE FROM asset_reports WHERE asset_id = ANY($1::uuid[])`,
+				`DELETE FROM restore_history WHERE entity_type = 'asset' AND entity_id = ANY($1::uuid[])`,
+			}
+			for _, table := range specTables {
+				statements = append(statements, `DELETE FROM `+table+` WHERE asset_id = ANY($1::uuid[])`)
+			}
+			statements = append(statements, `DELETE FROM assets WHERE id = ANY($1::uuid[])`)
+			for _, SQL := range statements {
+				if _, err := tx.ExecContext(ctx, SQL, assets); err != nil {
+					return err
+				}
+			}
+			count.Assets = len(assetIDs)
+		}
+
+		employeeIDs := make([]string, 0)
+		err = tx.SelectContext(ctx, &employeeIDs, `SELECT e.id
+                                       FROM   employee e
+                                       WHERE  e.archived_at < $1
+                                       AND    NOT EXISTS (SELECT 1 FROM employee_asset_relation ear WHERE ear.employee_id = e.id)
+                                       FOR UPDATE`, cutoff)
+		if err != nil {
+			return err
+		}
+		if len(employeeIDs) == 0 {
+			return nil
+		}
+		employees := pq.Array(employeeIDs)
+		statements := []string{
+			`DELETE FROM employee_sessions WHERE employee_id = ANY($1::uuid[])`,
+			`DELETE FROM employee_accounts WHERE employee_id = ANY($1::uuid[])`,
+			`DELETE FROM equipment_request_approvals
+             WHERE  request_id IN (SELECT id FROM equipment_requests WHERE employee_id = ANY($1::uuid[]))`,
+			`DELETE FROM equipment_requests WHERE employee_id = ANY($1::uuid[])`,
+			`DELETE FROM reservations WHERE employee_id = ANY($1::uuid[])`,
+			`DELETE FROM asset_reports WHERE employee_id = ANY($1::uuid[])`,
+			`UPDATE sync_changes SET employee_id = NULL WHERE employee_id = ANY($1::uuid[])`,
+			`UPDATE employee SET manager_id = NULL WHERE manager_id = ANY($1::uuid[])`,
+			`UPDATE locations
+             SET    employee_id = NULL,
+                    archived_at = COALESCE(archived_at, NOW())
+             WHERE  employee_id = ANY($1::uuid[])`,
+			`DELETE FROM restore_history WHERE entity_type = 'employee' AND entity_id = ANY($1::uuid[])`,
+			`DELETE FROM employee WHERE id = ANY($1::uuid[])`,
+		}
+		for _, SQL := range statements {
+			if _, err := tx.ExecContext(ctx, SQL, employees); err != nil {
+				return err
+			}
+		}
+		count.Employees = len(employeeIDs)
+		return nil
+	})
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("PurgeArchived: cannot purge archived records.")
+		return models.PurgeCount{}, err
+	}
+	return count, nil
+}
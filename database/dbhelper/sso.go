@@ -0,0 +1,101 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+// CreateLoginState stores the state, nonce and PKCE verifier of a pending SSO login and clears abandoned ones
+func CreateLoginState(ctx context.Context, state *models.LoginState, maxAge time.Duration) error {
+	SQL := `DELETE FROM oidc_login_states
+            WHERE  created_at < $1`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, time.Now().Add(-maxAge))
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateLoginState: cannot clear expired login states.")
+		return err
+	}
+
+	SQL = `INSERT INTO oidc_login_states(state, nonce, code_verifier)
+           VALUES ($1, $2, $3)`
+	_, err = database.AssetManagement.ExecContext(ctx, SQL, state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateLoginState: cannot create login state.")
+		return err
+	}
+	return nil
+}
+
+// ConsumeLoginState removes and returns a pending login, sql.ErrNoRows when it is unknown or older than maxAge
+func ConsumeLoginState(ctx context.Context, state string, maxAge time.Duration) (models.LoginState, error) {
+	SQL := `DELETE FROM oidc_login_states
+            WHERE  state = $1
+            RETURNING state, nonce, code_verifier, created_at >= $2 AS fresh`
+	var loginState struct {
+		models.LoginState
+		Fresh bool `db:"fresh"`
+	}
+	err := database.AssetManagement.GetContext(ctx, &loginState, SQL, state, time.Now().Add(-maxAge))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("ConsumeLoginState: cannot get login state.")
+		}
+		return loginState.LoginState, err
+	}
+	if !loginState.Fresh {
+		return loginState.LoginState, sql.ErrNoRows
+	}
+	return loginState.LoginState, nil
+}
+
+// GetSSOUser finds the user linked to an identity provider subject, falling back to an unlinked user with the email
+func GetSSOUser(ctx context.Context, subject, email string) (models.SSOUser, error) {
+	SQL := `SELECT  id,
+       				COALESCE(role::text, 'admin') AS role,
+       				COALESCE(type::text, 'authorized') AS type
+            FROM    users
+            WHERE   archived_at IS NULL
+            AND     (oidc_subject = $1 OR (oidc_subject IS NULL AND LOWER(email) = LOWER($2)))
+            ORDER BY oidc_subject = $1 DESC NULLS LAST
+            LIMIT 1`
+	var user models.SSOUser
+	err := database.AssetManagement.GetContext(ctx, &user, SQL, subject, email)
+	if err != nil && err != sql.ErrNoRows {
+		logging.FromContext(ctx).WithError(err).Error("GetSSOUser: cannot get user.")
+	}
+	return user, err
+}
+
+// CreateSSOUser provisions a user signing in for the first time, they have no password so password login never matches
+func CreateSSOUser(ctx context.Context, name, email, subject, role string) (string, error) {
+	SQL := `INSERT INTO users(name, email, phone_no, password, oidc_subject, role)
+            VALUES ($1, $2, '', '', $3, $4)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, name, email, subject, role)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateSSOUser: cannot create user.")
+		return "", err
+	}
+	return id, nil
+}
+
+// LinkSSOUser links a user to their identity provider subject and, when the provider decides roles, updates the role
+func LinkSSOUser(ctx context.Context, userID, subject string, role null.String) error {
+	SQL := `UPDATE users
+            SET    oidc_subject = $2,
+                   role = COALESCE($3::user_role, role),
+                   updated_at = NOW()
+            WHERE  id = $1`
+	_, err := database.AssetManagement.ExecContext(ctx, SQL, userID, subject, role)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("LinkSSOUser: cannot link user.")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package dbhelper
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+// EnqueueWebhookEvent writes an event to the outbox inside the caller's transaction, so it is only delivered when
+// the change it describes is committed
+func EnqueueWebhookEvent(ctx context.Context, tx *sqlx.Tx, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	SQL := `INSERT INTO webhook_outbox(event_type, payload)
+            VALUES ($1, $2)`
+	_, err = tx.ExecContext(ctx, SQL, eventType, payload)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("EnqueueWebhookEvent: cannot enqueue webhook event.")
+		return err
+	}
+	return nil
+}
+
+func CreateWebhookSubscription(ctx context.Context, subscription *models.CreateWebhookSubscription, secret, userID string) (string, error) {
+	SQL := `INSERT INTO webhook_subscriptions(name, url, secret, event_types, created_by)
+            VALUES ($1, $2, $3, $4, $5)
+            RETURNING id`
+	var id string
+	err := database.AssetManagement.GetContext(ctx, &id, SQL, subscription.Name, subscription.URL, secret, pq.Array(subscription.EventTypes), userID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("CreateWebhookSubscription: cannot create webhook subscription.")
+		return "", err
+	}
+	return id, nil
+}
+
+func GetWebhookSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	SQL := `SELECT  id,
+       				name,
+       				url,
+       				event_types,
+       				active,
+       				created_by,
+       				created_at,
+       				updated_at
+			FROM   webhook_subscriptions
+			WHERE  archived_at IS NULL
+			ORDER BY created_at DESC`
+	subscriptions := make([]models.WebhookSubscription, 0)
+	err := database.AssetManagement.SelectContext(ctx, &subscriptions, SQL)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetWebhookSubscriptions: cannot get webhook subscriptions.")
+		return subscriptions, err
+	}
+	return subscriptions, nil
+}
+
+func UpdateWebhookSubscription(ctx context.Context, subscriptionID string, subscription *models.UpdateWebhookSubscription) (int64, error) {
+	SQL := `UPDATE webhook_subscriptions
+            SET    name = $2,
+                   url = $3,
+                   event_types = $4,
+                   active = $5,
+                   updated_at = NOW()
+            WHERE  id = $1
+            AND    archived_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, subscriptionID, subscription.Name, subscription.URL, pq.Array(subscription.EventTypes), subscription.Active)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("UpdateWebhookSubscription: cannot update webhook subscription.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ArchiveWebhookSubscription stops a subscription, deliveries still pending for it are dead-lettered
+func ArchiveWebhookSubscription(ctx context.Context, subscriptionID string) (int64, error) {
+	var rows int64
+	txErr := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		SQL := `UPDATE webhook_subscriptions
+                SET    archived_at = NOW(),
+                       active = FALSE
+                WHERE  id = $1
+                AND    archived_at IS NULL`
+		result, err := tx.ExecContext(ctx, SQL, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if rows, err = result.RowsAffected(); err != nil || rows == 0 {
+			return err
+		}
+
+		SQL = `UPDATE webhook_deliveries
+               SET    status = 'dead',
+                      last_error = 'subscription deleted'
+               WHERE  subscription_id = $1
+               AND    status = 'pending'`
+		_, err = tx.ExecContext(ctx, SQL, subscriptionID)
+		return err
+	})
+	if txErr != nil {
+		logging.FromContext(ctx).WithError(txErr).Error("ArchiveWebhookSubscription: cannot archive webhook subscription.")
+		return 0, txErr
+	}
+	return rows, nil
+}
+
+// DispatchWebhookEvents fans undispatched outbox events out into a delivery per active subscription to their type.
+// Rows are locked with SKIP LOCKED so several instances can dispatch side by side
+func DispatchWebhookEvents(ctx context.Context, limit int) (int64, error) {
+	var dispatched int64
+	txErr := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		SQL := `WITH events AS (
+                    SELECT id, event_type
+                    FROM   webhook_outbox
+                    WHERE  dispatched_at IS NULL
+                    ORDER BY created_at
+                    LIMIT $1
+                    FOR UPDATE SKIP LOCKED
+                ), deliveries AS (
+                    INSERT INTO webhook_deliveries(outbox_id, subscription_id)
+                    SELECT e.id, s.id
+                    FROM   events e
+                        JOIN webhook_subscriptions s ON e.event_type = ANY(s.event_types)
+                    WHERE  s.active
+                    AND    s.archived_at IS NULL
+                    ON CONFLICT (outbox_id, subscription_id) DO NOTHING
+                )
+                UPDATE webhook_outbox o
+                SET    dispatched_at = NOW()
+                FROM   events e
+                WHERE  o.id = e.id`
+		result, err := tx.ExecContext(ctx, SQL, limit)
+		if err != nil {
+			return err
+		}
+		dispatched, err = result.RowsAffected()
+		return err
+	})
+	if txErr != nil {
+		logging.FromContext(ctx).WithError(txErr).Error("DispatchWebhookEvents: cannot dispatch webhook events.")
+		return 0, txErr
+	}
+	return dispatched, nil
+}
+
+// ClaimWebhookDeliveries returns the pending deliveries that are due and pushes their next attempt out by lease,
+// so that another worker does not send them again while they are in flight
+func ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.DueWebhookDelivery, error) {
+	SQL := `WITH due AS (
+                SELECT id
+                FROM   webhook_deliveries
+                WHERE  status = 'pending'
+                AND    next_attempt_at <= NOW()
+                ORDER BY next_attempt_at
+                LIMIT $1
+                FOR UPDATE SKIP LOCKED
+            ), claimed AS (
+                UPDATE webhook_deliveries d
+                SET    next_attempt_at = NOW() + make_interval(secs => $2)
+                FROM   due
+                WHERE  d.id = due.id
+                RETURNING d.id, d.outbox_id, d.subscription_id, d.attempts
+            )
+            SELECT  c.id,
+                    c.outbox_id,
+                    c.attempts,
+                    s.url,
+                    s.secret,
+                    o.event_type,
+                    o.payload,
+                    o.created_at AS occurred_at
+            FROM   claimed c
+                JOIN webhook_subscriptions s ON s.id = c.subscription_id
+                JOIN webhook_outbox o ON o.id = c.outbox_id`
+	deliveries := make([]models.DueWebhookDelivery, 0)
+	err := database.AssetManagement.SelectContext(ctx, &deliveries, SQL, limit, lease.Seconds())
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("ClaimWebhookDeliveries: cannot claim webhook deliveries.")
+		return deliveries, err
+	}
+	return deliveries, nil
+}
+
+// RecordWebhookAttempt logs an attempt and moves the delivery to status, nextAttemptAt is only used while it stays pending
+func RecordWebhookAttempt(ctx context.Context, attempt *models.WebhookAttempt, status string, nextAttemptAt null.Time) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		SQL := `INSERT INTO webhook_delivery_attempts(delivery_id, status_code, error, duration_ms)
+                VALUES ($1, $2, $3, $4)`
+		_, err := tx.ExecContext(ctx, SQL, attempt.DeliveryID, attempt.StatusCode, attempt.Error, attempt.DurationMS)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("RecordWebhookAttempt: cannot log webhook attempt.")
+			return err
+		}
+
+		SQL = `UPDATE webhook_deliveries
+               SET    status = $2,
+                      attempts = attempts + 1,
+                      next_attempt_at = $3,
+                      last_status_code = $4,
+                      last_error = $5,
+                      delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() ELSE delivered_at END
+               WHERE  id = $1`
+		_, err = tx.ExecContext(ctx, SQL, attempt.DeliveryID, status, nextAttemptAt, attempt.StatusCode, attempt.Error)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("RecordWebhookAttempt: cannot update webhook delivery.")
+			return err
+		}
+		return nil
+	})
+}
+
+func GetWebhookDeliveries(ctx context.Context, subscriptionID, status, eventType string, limit, page int) ([]models.WebhookDelivery, error) {
+	SQL := `SELECT  d.id,
+       				d.outbox_id,
+       				d.subscription_id,
+       				o.event_type,
+       				d.status,
+       				d.attempts,
+       				d.next_attempt_at,
+       				d.last_status_code,
+       				d.last_error,
+       				d.delivered_at,
+       				d.created_at
+			FROM   webhook_deliveries d
+			    JOIN webhook_outbox o ON o.id = d.outbox_id
+			WHERE  (NULLIF(LENGTH($1), 0) IS NULL OR d.subscription_id::text = $1)
+			AND    (NULLIF(LENGTH($2), 0) IS NULL OR d.status::text = $2)
+			AND    (NULLIF(LENGTH($3), 0) IS NULL OR o.event_type = $3)
+			ORDER BY d.created_at DESC
+			LIMIT $4 OFFSET $5`
+	deliveries := make([]models.WebhookDelivery, 0)
+	err := database.AssetManagement.SelectContext(ctx, &deliveries, SQL, subscriptionID, status, eventType, limit, limit*page)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetWebhookDeliveries: cannot get webhook deliveries.")
+		return deliveries, err
+	}
+	return deliveries, nil
+}
+
+// GetWebhookDelivery returns a delivery with its payload and every attempt made to send it
+func GetWebhookDelivery(ctx context.Context, deliveryID string) (models.WebhookDelivery, error) {
+	SQL := `SELECT  d.id,
+       				d.outbox_id,
+       				d.subscription_id,
+       				o.event_type,
+       				d.status,
+       				d.attempts,
+       				d.next_attempt_at,
+       				d.last_status_code,
+       				d.last_error,
+       				d.delivered_at,
+       				d.created_at,
+       				o.payload
+			FROM   webhook_deliveries d
+			    JOIN webhook_outbox o ON o.id = d.outbox_id
+			WHERE  d.id = $1`
+	var delivery models.WebhookDelivery
+	err := database.AssetManagement.GetContext(ctx, &delivery, SQL, deliveryID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			logging.FromContext(ctx).WithError(err).Error("GetWebhookDelivery: cannot get webhook delivery.")
+		}
+		return delivery, err
+	}
+
+	SQL = `SELECT  delivery_id,
+       			   status_code,
+       			   error,
+       			   duration_ms,
+       			   attempted_at
+		   FROM   webhook_delivery_attempts
+		   WHERE  delivery_id = $1
+		   ORDER BY attempted_at`
+	delivery.AttemptLog = make([]models.WebhookAttempt, 0)
+	err = database.AssetManagement.SelectContext(ctx, &delivery.AttemptLog, SQL, deliveryID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("GetWebhookDelivery: cannot get webhook delivery attempts.")
+		return delivery, err
+	}
+	return delivery, nil
+}
+
+// RedeliverWebhook queues a delivery to be sent again straight away with a fresh retry budget, the attempt log is kept
+func RedeliverWebhook(ctx context.Context, deliveryID string) (int64, error) {
+	SQL := `UPDATE webhook_deliveries d
+            SET    status = 'pending',
+                   attempts = 0,
+                   next_attempt_at = NOW(),
+                   delivered_at = NULL
+            FROM   webhook_subscriptions s
+            WHERE  d.id = $1
+            AND    s.id = d.subscription_id
+            AND    s.archived_at IS NULL`
+	result, err := database.AssetManagement.ExecContext(ctx, SQL, deliveryID)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Error("RedeliverWebhook: cannot queue webhook redelivery.")
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+)
+
+// migrations are built into the binary so that it migrates the database from any working directory
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// MigrationStatus is a migration known to the binary and whether the database has it applied
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// NewMigrator returns a migrator of db that reads the embedded migrations
+func NewMigrator(db *sqlx.DB) (*migrate.Migrate, error) {
+	migrationSource, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	driver, err := postgres.WithInstance(db.DB, &postgres.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return migrate.NewWithInstance("iofs", migrationSource, "postgres", driver)
+}
+
+// MigrateUp function migrate the database and handles the migration logic
+func MigrateUp(db *sqlx.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+	return nil
+}
+
+// MigrationsStatus lists the embedded migrations in order along with the version the database is at and whether the
+// last migration run on it failed half way
+func MigrationsStatus(m *migrate.Migrate) (statuses []MigrationStatus, version uint, dirty bool, err error) {
+	version, dirty, err = m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, 0, false, err
+	}
+	migrationSource, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, 0, false, err
+	}
+	defer migrationSource.Close()
+
+	next, err := migrationSource.First()
+	for err == nil {
+		status := MigrationStatus{Version: next, Applied: next <= version}
+		if up, identifier, readErr := migrationSource.ReadUp(next); readErr == nil {
+			status.Name = identifier
+			up.Close()
+		}
+		statuses = append(statuses, status)
+		next, err = migrationSource.Next(next)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, false, err
+	}
+	return statuses, version, dirty, nil
+}
+
+// LatestMigration returns the version of the last embedded migration, the version a database migrated up is at
+func LatestMigration() (uint, error) {
+	migrationSource, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return 0, err
+	}
+	defer migrationSource.Close()
+
+	latest, err := migrationSource.First()
+	for err == nil {
+		var next uint
+		if next, err = migrationSource.Next(latest); err == nil {
+			latest = next
+		}
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+	return latest, nil
+}
+
+// MigrationVersion reads the version db is at and whether the last migration run on it failed half way. Unlike the
+// migrator it neither takes a lock nor creates the version table, so that it can be called on every readiness probe
+func MigrationVersion(ctx context.Context, db *sqlx.DB) (version uint, dirty bool, err error) {
+	var migration struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+	err = db.GetContext(ctx, &migration, `SELECT version, dirty FROM schema_migrations LIMIT 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(migration.Version), migration.Dirty, nil
+}
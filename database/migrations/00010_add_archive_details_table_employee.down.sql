@@ -0,0 +1,3 @@
+ALTER TABLE employee DROP COLUMN IF EXISTS deleted_by;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS archive_reason;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS unique_asset_tag;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS asset_tag;
+
+DROP TABLE IF EXISTS asset_tag_sequences;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS asset_tag_sequences (
+    prefix     TEXT PRIMARY KEY,
+    last_value INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS asset_tag TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_asset_tag ON assets(asset_tag)
+    WHERE asset_tag IS NOT NULL;
+
+WITH cte_untagged AS (
+    SELECT id,
+           'RS-' || CASE asset_type
+                        WHEN 'laptop' THEN 'LAP'
+                        WHEN 'mouse' THEN 'MOU'
+                        WHEN 'hard disk' THEN 'HDD'
+                        WHEN 'pen drive' THEN 'PEN'
+                        WHEN 'mobile' THEN 'MOB'
+                        WHEN 'sim' THEN 'SIM'
+                    END AS prefix,
+           row_number() OVER (PARTITION BY asset_type ORDER BY created_at, id) AS seq
+    FROM assets
+    WHERE asset_tag IS NULL
+)
+UPDATE assets a
+SET asset_tag = cu.prefix || '-' || lpad(cu.seq::text, 5, '0')
+FROM cte_untagged cu
+WHERE a.id = cu.id;
+
+INSERT INTO asset_tag_sequences (prefix, last_value)
+SELECT substring(asset_tag FROM '^(.*)-[0-9]+$'),
+       max(substring(asset_tag FROM '[0-9]+$')::int)
+FROM assets
+WHERE asset_tag IS NOT NULL
+GROUP BY 1
+ON CONFLICT (prefix) DO NOTHING;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS asset_transfers;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS location_id;
+
+DROP TABLE IF EXISTS locations;
+
+DROP TYPE IF EXISTS location_type;
@@ -0,0 +1,35 @@
+CREATE TYPE location_type AS ENUM (
+    'site',
+    'room',
+    'shelf',
+    'employee'
+    );
+
+CREATE TABLE IF NOT EXISTS locations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    type location_type NOT NULL,
+    parent_id UUID REFERENCES locations(id),
+    employee_id UUID REFERENCES employee(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_employee_location ON locations(employee_id)
+    WHERE employee_id IS NOT NULL AND archived_at IS NULL;
+
+ALTER TABLE assets ADD COLUMN IF NOT EXISTS location_id UUID REFERENCES locations(id);
+
+CREATE TABLE IF NOT EXISTS asset_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID REFERENCES assets(id) NOT NULL,
+    from_location_id UUID REFERENCES locations(id),
+    to_location_id UUID REFERENCES locations(id),
+    transferred_by UUID REFERENCES users(id),
+    transferred_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    note TEXT
+);
+
+CREATE INDEX IF NOT EXISTS asset_transfers_asset_id ON asset_transfers(asset_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS employee_asset_relation_open_loans;
+
+DROP TABLE IF EXISTS loan_extension_requests;
+
+DROP TYPE IF EXISTS extension_status;
+
+ALTER TABLE employee_asset_relation DROP COLUMN IF EXISTS last_reminder_at;
+ALTER TABLE employee_asset_relation DROP COLUMN IF EXISTS due_date;
+ALTER TABLE employee_asset_relation DROP COLUMN IF EXISTS assignment_type;
+
+DROP TYPE IF EXISTS assignment_type;
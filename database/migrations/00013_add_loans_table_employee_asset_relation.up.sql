@@ -0,0 +1,29 @@
+CREATE TYPE assignment_type AS ENUM (
+    'permanent',
+    'loan'
+    );
+
+ALTER TABLE employee_asset_relation ADD COLUMN IF NOT EXISTS assignment_type assignment_type DEFAULT 'permanent';
+ALTER TABLE employee_asset_relation ADD COLUMN IF NOT EXISTS due_date DATE;
+ALTER TABLE employee_asset_relation ADD COLUMN IF NOT EXISTS last_reminder_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TYPE extension_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+    );
+
+CREATE TABLE IF NOT EXISTS loan_extension_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    relation_id UUID REFERENCES employee_asset_relation(id) NOT NULL,
+    requested_due_date DATE NOT NULL,
+    reason TEXT,
+    status extension_status DEFAULT 'pending',
+    requested_by UUID REFERENCES users(id),
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS employee_asset_relation_open_loans ON employee_asset_relation(due_date)
+    WHERE assignment_type = 'loan' AND retrieved_date IS NULL;
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS equipment_requests;
+
+DROP TYPE IF EXISTS equipment_request_status;
+
+DROP TABLE IF EXISTS asset_reports;
+
+DROP TYPE IF EXISTS asset_report_status;
+
+DROP TYPE IF EXISTS asset_report_type;
+
+ALTER TABLE employee_asset_relation DROP COLUMN IF EXISTS acknowledged_at;
+
+DROP TABLE IF EXISTS employee_sessions;
+
+DROP TABLE IF EXISTS employee_accounts;
@@ -0,0 +1,62 @@
+CREATE TABLE IF NOT EXISTS employee_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id UUID REFERENCES employee(id) NOT NULL,
+    password TEXT NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_employee_account ON employee_accounts(employee_id)
+    WHERE archived_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS employee_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id UUID REFERENCES employee(id) NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    end_time TIMESTAMP WITH TIME ZONE
+);
+
+ALTER TABLE employee_asset_relation ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TYPE asset_report_type AS ENUM (
+    'problem',
+    'loss'
+    );
+
+CREATE TYPE asset_report_status AS ENUM (
+    'open',
+    'resolved'
+    );
+
+CREATE TABLE IF NOT EXISTS asset_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID REFERENCES assets(id) NOT NULL,
+    employee_id UUID REFERENCES employee(id) NOT NULL,
+    type asset_report_type NOT NULL,
+    description TEXT NOT NULL,
+    status asset_report_status DEFAULT 'open',
+    resolution TEXT,
+    resolved_by UUID REFERENCES users(id),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TYPE equipment_request_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'fulfilled',
+    'cancelled'
+    );
+
+CREATE TABLE IF NOT EXISTS equipment_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employee_id UUID REFERENCES employee(id) NOT NULL,
+    asset_type asset_type NOT NULL,
+    justification TEXT NOT NULL,
+    status equipment_request_status DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS handover_records;
+
+DROP TYPE IF EXISTS handover_kind;
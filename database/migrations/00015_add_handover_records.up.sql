@@ -0,0 +1,24 @@
+CREATE TYPE handover_kind AS ENUM (
+    'assignment',
+    'retrieval'
+    );
+
+CREATE TABLE IF NOT EXISTS handover_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    relation_id UUID REFERENCES employee_asset_relation(id) NOT NULL,
+    kind handover_kind NOT NULL,
+    condition_notes TEXT,
+    accessories TEXT[] DEFAULT '{}',
+    photos TEXT[] DEFAULT '{}',
+    acknowledged_name TEXT,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_ip TEXT,
+    signature_image BYTEA,
+    receipt_pdf BYTEA,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_handover_per_relation ON handover_records(relation_id, kind)
+    WHERE archived_at IS NULL;
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS equipment_request_approvals;
+
+DROP TYPE IF EXISTS approval_status;
+
+DROP TABLE IF EXISTS equipment_approval_steps;
+
+ALTER TABLE equipment_requests
+    DROP COLUMN IF EXISTS fulfilled_at,
+    DROP COLUMN IF EXISTS fulfilled_by,
+    DROP COLUMN IF EXISTS relation_id,
+    DROP COLUMN IF EXISTS requested_by,
+    DROP COLUMN IF EXISTS needed_by;
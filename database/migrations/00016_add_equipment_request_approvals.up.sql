@@ -0,0 +1,46 @@
+ALTER TABLE equipment_requests
+    ADD COLUMN IF NOT EXISTS needed_by DATE,
+    ADD COLUMN IF NOT EXISTS requested_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS relation_id UUID REFERENCES employee_asset_relation(id),
+    ADD COLUMN IF NOT EXISTS fulfilled_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS fulfilled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS equipment_approval_steps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    step_order INT NOT NULL,
+    name TEXT NOT NULL,
+    asset_type asset_type,
+    approver_id UUID REFERENCES users(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TYPE approval_status AS ENUM (
+    'pending',
+    'approved',
+    'rejected',
+    'skipped'
+    );
+
+CREATE TABLE IF NOT EXISTS equipment_request_approvals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    request_id UUID REFERENCES equipment_requests(id) NOT NULL,
+    step_order INT NOT NULL,
+    step_name TEXT NOT NULL,
+    approver_id UUID REFERENCES users(id),
+    status approval_status DEFAULT 'pending',
+    comment TEXT,
+    decided_by UUID REFERENCES users(id),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS equipment_request_approvals_request ON equipment_request_approvals(request_id, step_order);
+
+-- requests filed before approval chains existed get a single admin approval step
+INSERT INTO equipment_request_approvals(request_id, step_order, step_name, status)
+SELECT id, 1, 'Admin approval', CASE WHEN status = 'pending' THEN 'pending'::approval_status
+                                     WHEN status = 'rejected' THEN 'rejected'::approval_status
+                                     ELSE 'approved'::approval_status END
+FROM   equipment_requests
+WHERE  status != 'cancelled';
@@ -0,0 +1,31 @@
+CREATE TYPE reservation_status AS ENUM (
+    'active',
+    'converted',
+    'cancelled'
+    );
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID REFERENCES assets(id),
+    asset_type asset_type NOT NULL,
+    brand TEXT,
+    model TEXT,
+    employee_id UUID REFERENCES employee(id),
+    holder_name TEXT,
+    purpose TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    status reservation_status DEFAULT 'active',
+    relation_id UUID REFERENCES employee_asset_relation(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CHECK (end_date >= start_date),
+    CHECK (asset_id IS NOT NULL OR model IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS active_reservations_asset ON reservations(asset_id, start_date, end_date)
+    WHERE status = 'active';
+
+CREATE INDEX IF NOT EXISTS active_reservations_model ON reservations(brand, model, start_date, end_date)
+    WHERE status = 'active';
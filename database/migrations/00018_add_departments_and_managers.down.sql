@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS employee_manager;
+
+DROP INDEX IF EXISTS employee_department;
+
+ALTER TABLE employee DROP CONSTRAINT IF EXISTS employee_not_own_manager;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS manager_id;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS department_id;
+
+DROP TABLE IF EXISTS departments;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS departments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    parent_id UUID REFERENCES departments(id),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE,
+    archived_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_department_name ON departments(COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), LOWER(name))
+    WHERE archived_at IS NULL;
+
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS department_id UUID REFERENCES departments(id);
+
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS manager_id UUID REFERENCES employee(id);
+
+ALTER TABLE employee ADD CONSTRAINT employee_not_own_manager CHECK (manager_id IS NULL OR manager_id != id);
+
+CREATE INDEX IF NOT EXISTS employee_department ON employee(department_id);
+
+CREATE INDEX IF NOT EXISTS employee_manager ON employee(manager_id);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS sync_changes;
+
+DROP TABLE IF EXISTS sync_runs;
+
+DROP TYPE IF EXISTS sync_action;
+
+DROP TYPE IF EXISTS sync_source;
+
+DROP TABLE IF EXISTS provisioning_tokens;
+
+DROP INDEX IF EXISTS unique_employee_external_id;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS external_id;
@@ -0,0 +1,55 @@
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS external_id TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_employee_external_id ON employee(external_id)
+    WHERE external_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS provisioning_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    token_hash TEXT UNIQUE NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TYPE sync_source AS ENUM (
+    'scim',
+    'csv'
+    );
+
+CREATE TYPE sync_action AS ENUM (
+    'create',
+    'update',
+    'deactivate',
+    'error'
+    );
+
+CREATE TABLE IF NOT EXISTS sync_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source sync_source NOT NULL,
+    dry_run BOOLEAN DEFAULT FALSE,
+    file_name TEXT,
+    created_count INT DEFAULT 0,
+    updated_count INT DEFAULT 0,
+    deactivated_count INT DEFAULT 0,
+    error_count INT DEFAULT 0,
+    triggered_by UUID REFERENCES users(id),
+    token_id UUID REFERENCES provisioning_tokens(id),
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE IF NOT EXISTS sync_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID REFERENCES sync_runs(id) NOT NULL,
+    employee_id UUID REFERENCES employee(id),
+    external_id TEXT,
+    email TEXT,
+    action sync_action NOT NULL,
+    changes JSONB,
+    message TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS sync_changes_run ON sync_changes(run_id);
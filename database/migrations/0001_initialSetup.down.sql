@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS sessions;
+
+DROP TABLE IF EXISTS pen_drive_specifications;
+
+DROP TABLE IF EXISTS hard_disk_specifications;
+
+DROP TABLE IF EXISTS laptop_specifications;
+
+DROP TABLE IF EXISTS employee_asset_relation;
+
+DROP TABLE IF EXISTS assets;
+
+DROP TYPE IF EXISTS asset_type;
+
+DROP TABLE IF EXISTS employee;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS oidc_login_states;
+
+DROP INDEX IF EXISTS unique_user_phone_no;
+
+-- fails while users provisioned from the identity provider, who share an empty phone number, are still stored
+CREATE UNIQUE INDEX IF NOT EXISTS unique_employee on users(phone_no)
+    WHERE archived_at IS NULL;
+
+DROP INDEX IF EXISTS unique_user_oidc_subject;
+
+ALTER TABLE users DROP COLUMN IF EXISTS oidc_subject;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+DROP TYPE IF EXISTS user_role;
@@ -0,0 +1,24 @@
+CREATE TYPE user_role AS ENUM (
+    'admin',
+    'viewer'
+    );
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role user_role DEFAULT 'admin';
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS oidc_subject TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_user_oidc_subject ON users(oidc_subject)
+    WHERE oidc_subject IS NOT NULL;
+
+-- users provisioned from the identity provider have no phone number
+DROP INDEX IF EXISTS unique_employee;
+
+CREATE UNIQUE INDEX IF NOT EXISTS unique_user_phone_no ON users(phone_no)
+    WHERE archived_at IS NULL AND phone_no <> '';
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state TEXT PRIMARY KEY,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
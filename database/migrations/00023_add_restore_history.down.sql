@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS restore_history;
+DROP TYPE IF EXISTS restore_entity;
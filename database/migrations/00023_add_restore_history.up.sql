@@ -0,0 +1,18 @@
+CREATE TYPE restore_entity AS ENUM (
+    'asset',
+    'employee'
+    );
+
+CREATE TABLE IF NOT EXISTS restore_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    entity_type restore_entity NOT NULL,
+    entity_id UUID NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    archive_reason TEXT,
+    deleted_by UUID REFERENCES users(id),
+    restore_reason TEXT NOT NULL,
+    restored_by UUID REFERENCES users(id) NOT NULL,
+    restored_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS restore_history_entity ON restore_history(entity_type, entity_id);
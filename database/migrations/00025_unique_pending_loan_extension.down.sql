@@ -0,0 +1 @@
+DROP INDEX IF EXISTS loan_extension_requests_one_pending;
@@ -0,0 +1,12 @@
+-- only the latest pending extension of a loan stays pending
+UPDATE loan_extension_requests ler
+SET    status = 'rejected',
+       decided_at = NOW()
+WHERE  ler.status = 'pending'
+AND    EXISTS(SELECT 1 FROM loan_extension_requests newer
+              WHERE  newer.relation_id = ler.relation_id
+              AND    newer.status = 'pending'
+              AND    (newer.created_at, newer.id) > (ler.created_at, ler.id));
+
+CREATE UNIQUE INDEX IF NOT EXISTS loan_extension_requests_one_pending ON loan_extension_requests(relation_id)
+    WHERE status = 'pending';
@@ -0,0 +1,9 @@
+ALTER TABLE users DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS status_type;
+
+ALTER TABLE users DROP COLUMN IF EXISTS authentication_times;
+
+ALTER TABLE users DROP COLUMN IF EXISTS type;
+
+DROP TYPE IF EXISTS user_type;
@@ -0,0 +1 @@
+ALTER TABLE users ALTER COLUMN authentication_times DROP DEFAULT;
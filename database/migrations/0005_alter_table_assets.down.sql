@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS sim_specifications;
+
+DROP TABLE IF EXISTS mobile_specifications;
+
+-- enum values cannot be dropped, so the type is recreated; this fails while mobiles or sims are still stored
+ALTER TYPE asset_type RENAME TO asset_type_with_mobiles;
+
+CREATE TYPE asset_type AS ENUM (
+    'laptop',
+    'mouse',
+    'hard disk',
+    'pen drive'
+);
+
+ALTER TABLE assets ALTER COLUMN asset_type TYPE asset_type USING asset_type::text::asset_type;
+
+DROP TYPE asset_type_with_mobiles;
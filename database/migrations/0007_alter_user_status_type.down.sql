@@ -0,0 +1 @@
+ALTER TYPE status_type RENAME VALUE 'black_listed' TO 'block_listed';
@@ -0,0 +1,13 @@
+ALTER TABLE assets DROP COLUMN IF EXISTS archive_reason;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS deleted_by;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS status;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS client_name;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS owned_by;
+
+DROP TYPE IF EXISTS asset_status;
+
+DROP TYPE IF EXISTS asset_owned_status;
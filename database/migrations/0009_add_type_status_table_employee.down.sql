@@ -0,0 +1,7 @@
+ALTER TABLE employee DROP COLUMN IF EXISTS type;
+
+ALTER TABLE employee DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS employee_type;
+
+DROP TYPE IF EXISTS employee_status;
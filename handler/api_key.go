@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	apiKeyBytes  = 32
+	apiKeyPrefix = "amk_"
+	// apiKeyPrefixLength is how much of a key is kept in clear to tell keys apart
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+)
+
+// CreateAPIKey issues a scoped API key for an integration, the key is only shown in this response
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	body := models.CreateAPIKey{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+	if err := checkAPIKey(&body); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	token, err := utils.GenerateToken(apiKeyBytes)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateAPIKey: cannot generate key.")
+		return
+	}
+	key := apiKeyPrefix + token
+
+	keyID, err := dbhelper.CreateAPIKey(r.Context(), &body, key[:apiKeyPrefixLength], utils.HashString(key), userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateAPIKey: cannot create api key.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}{
+		Msg: "API key created, it will not be shown again.",
+		ID:  keyID,
+		Key: key,
+	})
+}
+
+func GetAPIKeys(w http.ResponseWriter, r *http.Request) {
+	apiKeys, err := dbhelper.GetAPIKeys(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetAPIKeys: cannot get api keys.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, apiKeys)
+}
+
+func RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.RevokeAPIKey(r.Context(), chi.URLParam(r, "keyID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RevokeAPIKey: cannot revoke api key.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.APIKeyNotFound, "API key not found or already revoked.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "API key revoked.",
+	})
+}
+
+// checkAPIKey validates the scopes, the allowlist entries, which are addresses or CIDR ranges, and the expiry
+func checkAPIKey(apiKey *models.CreateAPIKey) error {
+	for _, scope := range apiKey.Scopes {
+		if !containsString(utils.APIKeyScopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	for _, entry := range apiKey.AllowedIPs {
+		if net.ParseIP(entry) == nil {
+			if _, _, err := net.ParseCIDR(entry); err != nil {
+				return fmt.Errorf("allowedIps entry %q is not an address or CIDR range", entry)
+			}
+		}
+	}
+	if apiKey.ExpiresAt.Valid && apiKey.ExpiresAt.Time.Before(time.Now()) {
+		return errors.New("expiresAt cannot be in the past")
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for i := range list {
+		if list[i] == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,16 +1,24 @@
 package handler
 
 import (
+	"InternalAssetManagement/apperr"
 	"InternalAssetManagement/database"
 	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
 	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
 
-func CreateAsset(w http.ResponseWriter, r *http.Request) {
+func (s *Service) CreateAsset(w http.ResponseWriter, r *http.Request) {
 	body := models.CreateAsset{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
@@ -33,94 +41,130 @@ func CreateAsset(w http.ResponseWriter, r *http.Request) {
 		body.ClientName = ""
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		assetID, assetErr := dbhelper.CreateAsset(tx, &body, userID)
-		if assetErr != nil {
-			return assetErr
-		}
-
-		switch body.AssetType {
-		case models.Laptop:
-			err := dbhelper.CreateLaptopSpecification(tx, &body, assetID)
-			if err != nil {
-				return err
-			}
-		case models.Pendrive:
-			err := dbhelper.CreatePenDriveSpecification(tx, &body, assetID)
-			if err != nil {
-				return err
-			}
-		case models.Harddisk:
-			err := dbhelper.CreateHardDiskSpecification(tx, &body, assetID)
-			if err != nil {
-				return err
-			}
-		case models.Mobile:
-			err := dbhelper.CreateMobileSpecification(tx, &body, assetID)
-			if err != nil {
-				return err
-			}
-		case models.Sim:
-			err := dbhelper.CreateSimSpecification(tx, &body, assetID)
-			if err != nil {
-				return err
-			}
-		case models.Mouse:
-			break
-		}
-
-		return nil
-	})
-	if txErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, txErr, "failed to create asset.")
+	assetTag, err := s.Assets.Create(r.Context(), &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to create asset.")
 		return
 	}
 
-	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
-		Msg: "Asset created.",
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg      string `json:"msg"`
+		AssetTag string `json:"assetTag"`
+	}{
+		Msg:      "Asset created.",
+		AssetTag: assetTag,
 	})
 }
 
-func GetAssetSpec(w http.ResponseWriter, r *http.Request) {
+func (s *Service) GetAssetSpec(w http.ResponseWriter, r *http.Request) {
 	assetID := r.URL.Query().Get("assetId")
 	assetType := r.URL.Query().Get("assetType")
 
-	assetSpec, err := dbhelper.GetAssetSpec(assetID, assetType)
+	assetSpec, err := s.assetSpecWithHistory(r.Context(), assetID, assetType)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetSpec: cannot asset spec.")
 		return
 	}
 
-	employeeHistory, err := dbhelper.EmployeeHistory(assetID)
-	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeHistory: cannot get employee history.")
+	utils.RespondJSON(w, http.StatusOK, assetSpec)
+}
+
+func (s *Service) LookupAsset(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimSpace(r.URL.Query().Get("code"))
+	if code == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "code is required.")
 		return
 	}
 
-	assetSpec[0].AssetHistory = employeeHistory
+	asset, err := s.Assets.Lookup(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "no asset found for scanned code.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "LookupAsset: cannot lookup asset.")
+		return
+	}
 
-	if assetSpec == nil {
-		utils.RespondJSON(w, http.StatusOK, []models.CreateAsset{})
+	assetSpec, err := s.assetSpecWithHistory(r.Context(), asset.ID, asset.AssetType)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "LookupAsset: cannot get asset spec.")
 		return
 	}
 
 	utils.RespondJSON(w, http.StatusOK, assetSpec)
 }
 
-func GetAssetList(w http.ResponseWriter, r *http.Request) {
-	filterCheck, err := utils.Filters(r)
+func GetAssetLabels(w http.ResponseWriter, r *http.Request) {
+	assetIDs := make([]string, 0)
+	if strAssetIDs := r.URL.Query().Get("assetIds"); strAssetIDs != "" {
+		assetIDs = strings.Split(strAssetIDs, ",")
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = utils.LabelFormatPDF
+	}
+
+	labels, err := dbhelper.GetAssetLabels(r.Context(), assetIDs)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetLIst: cannot get filters properly: ")
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetLabels: cannot get asset labels.")
 		return
 	}
-	var assets models.TotalGetAsset
-	var assetErr error
-	switch {
-	case filterCheck.Available || filterCheck.Assigned || filterCheck.Deleted:
-		assets, assetErr = dbhelper.GetAssetsWithFilters(&filterCheck)
+	if len(labels) == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "no assets found to print labels for.", nil))
+		return
+	}
+
+	var content []byte
+	switch format {
+	case utils.LabelFormatPDF:
+		content, err = utils.GenerateLabelsPDF(labels)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetLabels: cannot generate label sheet.")
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+	case utils.LabelFormatZPL:
+		content = utils.GenerateLabelsZPL(labels)
+		w.Header().Set("Content-Type", "application/zpl")
 	default:
-		assets, assetErr = dbhelper.GetAssets(&filterCheck)
+		utils.RespondError(w, http.StatusBadRequest, nil, "format must be either pdf or zpl.")
+		return
 	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=asset-labels.%s", format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(content); err != nil {
+		logging.FromContext(r.Context()).Errorf("GetAssetLabels: failed to write labels with error: %+v", err)
+	}
+}
+
+// assetSpecWithHistory returns the asset specification along with the employees who held it
+func (s *Service) assetSpecWithHistory(ctx context.Context, assetID, assetType string) ([]models.CreateAsset, error) {
+	assetSpec, err := s.Assets.Spec(ctx, assetID, assetType)
+	if err != nil {
+		return nil, err
+	}
+	if len(assetSpec) == 0 {
+		return []models.CreateAsset{}, nil
+	}
+
+	employeeHistory, err := s.Assets.History(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	assetSpec[0].AssetHistory = employeeHistory
+	return assetSpec, nil
+}
+
+func (s *Service) GetAssetList(w http.ResponseWriter, r *http.Request) {
+	filterCheck, err := utils.ListFilters(r, models.AssetList)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "GetAssetList: invalid filters.")
+		return
+	}
+	assets, assetErr := s.Assets.List(r.Context(), &filterCheck)
 	if assetErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, assetErr, "Failed to get Asset List.")
 		return
@@ -141,35 +185,35 @@ func UpdateAsset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		assetErr := dbhelper.UpdateAsset(&body, tx)
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		assetErr := dbhelper.UpdateAsset(r.Context(), &body, tx)
 		if assetErr != nil {
 			return assetErr
 		}
 
 		switch body.AssetType {
 		case models.Laptop:
-			updateErr := dbhelper.UpdateLaptopSpecifications(&body, tx)
+			updateErr := dbhelper.UpdateLaptopSpecifications(r.Context(), &body, tx)
 			if updateErr != nil {
 				return updateErr
 			}
 		case models.Harddisk:
-			updateErr := dbhelper.UpdateHardDiskSpecifications(body.Storage, body.ID, tx)
+			updateErr := dbhelper.UpdateHardDiskSpecifications(r.Context(), body.Storage, body.ID, tx)
 			if updateErr != nil {
 				return updateErr
 			}
 		case models.Pendrive:
-			updateErr := dbhelper.UpdatePenDriveSpecifications(body.Storage, body.ID, tx)
+			updateErr := dbhelper.UpdatePenDriveSpecifications(r.Context(), body.Storage, body.ID, tx)
 			if updateErr != nil {
 				return updateErr
 			}
 		case models.Mobile:
-			updateErr := dbhelper.UpdateMobileSpecifications(&body, tx)
+			updateErr := dbhelper.UpdateMobileSpecifications(r.Context(), &body, tx)
 			if updateErr != nil {
 				return updateErr
 			}
 		case models.Sim:
-			updateErr := dbhelper.UpdateSimSpecifications(&body, tx)
+			updateErr := dbhelper.UpdateSimSpecifications(r.Context(), &body, tx)
 			if updateErr != nil {
 				return updateErr
 			}
@@ -189,7 +233,7 @@ func UpdateAsset(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func ReassignAsset(w http.ResponseWriter, r *http.Request) {
+func (s *Service) ReassignAsset(w http.ResponseWriter, r *http.Request) {
 	body := models.ReassignAsset{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
@@ -202,32 +246,31 @@ func ReassignAsset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if loanErr := checkAssignmentType(&body.AssignmentType, &body.DueDate); loanErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, loanErr, loanErr.Error())
+		return
+	}
+
 	userID, userErr := utils.UserContext(r)
 	if userErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
 		return
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		retrieveErr := dbhelper.RetrieveAssetByAssetID(tx, &body)
-		if retrieveErr != nil {
-			return retrieveErr
-		}
-
-		err := dbhelper.UpdateAvailableAsset(body.AssetID, false, utils.Assigned, tx)
-		if err != nil {
-			return err
-		}
-
-		reassignErr := dbhelper.ReassignAsset(tx, &body, userID)
-		if reassignErr != nil {
-			return reassignErr
+	_, err := s.Assets.Reassign(r.Context(), &body, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAssetNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", err))
+			return
+		case errors.Is(err, repository.ErrEmployeeNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.EmployeeNotFound, "employee not found.", err))
+			return
+		case errors.Is(err, repository.ErrAssetReserved):
+			utils.RespondAppError(w, apperr.New(apperr.AssetReserved, "asset is reserved for part of this period.", err))
+			return
 		}
-
-		return nil
-	})
-	if txErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, txErr, "failed to re-assign asset.")
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to re-assign asset.")
 		return
 	}
 
@@ -240,7 +283,12 @@ func AvailableAssets(w http.ResponseWriter, r *http.Request) {
 	assetType := r.URL.Query().Get("assetType")
 	brand := r.URL.Query().Get("brand")
 	modelNo := r.URL.Query().Get("model")
-	assets, err := dbhelper.AvailableAssets(brand, assetType, modelNo)
+	from, to, periodErr := parseAvailabilityPeriod(r)
+	if periodErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, periodErr, "from and to must be dates in YYYY-MM-DD format.")
+		return
+	}
+	assets, err := dbhelper.AvailableAssets(r.Context(), brand, assetType, modelNo, from, to)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "AvailableAssets: cannot get assigned asset details.")
 		return
@@ -256,7 +304,7 @@ func AvailableAssets(w http.ResponseWriter, r *http.Request) {
 func EmployeeHistory(w http.ResponseWriter, r *http.Request) {
 	assetID := r.URL.Query().Get("assetID")
 
-	employeeHistory, err := dbhelper.EmployeeHistory(assetID)
+	employeeHistory, err := dbhelper.EmployeeHistory(r.Context(), assetID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeHistory: cannot get employee history.")
 		return
@@ -277,9 +325,34 @@ func UpdateWarranty(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := dbhelper.UpdateWarranty(warrantyDetails)
-	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateWarranty: cannot update warranty.")
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		rows, err := dbhelper.UpdateWarranty(r.Context(), tx, warrantyDetails)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		return dbhelper.EnqueueWebhookEvent(r.Context(), tx, models.EventAssetWarrantyUpdated, models.AssetWarrantyUpdatedEvent{
+			AssetID:            warrantyDetails.AssetID,
+			WarrantyStartDate:  warrantyDetails.WarrantyStartDate,
+			WarrantyExpiryDate: warrantyDetails.WarrantyExpiryDate,
+			UpdatedBy:          userID,
+		})
+	})
+	if errors.Is(txErr, sql.ErrNoRows) {
+		utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", txErr))
+		return
+	}
+	if txErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, txErr, "UpdateWarranty: cannot update warranty.")
 		return
 	}
 
@@ -288,7 +361,7 @@ func UpdateWarranty(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func RetrieveAsset(w http.ResponseWriter, r *http.Request) {
+func (s *Service) RetrieveAsset(w http.ResponseWriter, r *http.Request) {
 	var assetRetrievalDetails models.AssetRetrievalDetails
 
 	if parseErr := utils.ParseBody(r.Body, &assetRetrievalDetails); parseErr != nil {
@@ -296,21 +369,15 @@ func RetrieveAsset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		err := dbhelper.RetrieveAsset(assetRetrievalDetails, tx)
-		if err != nil {
-			return err
-		}
-
-		err = dbhelper.UpdateAvailableAsset(assetRetrievalDetails.AssetID, true, utils.Available, tx)
-		if err != nil {
-			return err
-		}
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
 
-		return nil
-	})
-	if txErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, txErr, "RetrieveAsset: cannot update retrieval details.")
+	err := s.Assets.Retrieve(r.Context(), assetRetrievalDetails, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RetrieveAsset: cannot update retrieval details.")
 		return
 	}
 
@@ -319,7 +386,7 @@ func RetrieveAsset(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func DeleteAsset(w http.ResponseWriter, r *http.Request) {
+func (s *Service) DeleteAsset(w http.ResponseWriter, r *http.Request) {
 	body := models.Asset{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
@@ -338,58 +405,64 @@ func DeleteAsset(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := dbhelper.GetAssignedEmployee(body)
+	count, err := s.Assets.HolderCount(r.Context(), body.ID)
 	switch {
 	case err != nil && count < 0:
 		utils.RespondError(w, http.StatusInternalServerError, err, "cannot check if asset is assigned.")
 		return
 	case count > 0:
-		utils.RespondError(w, http.StatusBadRequest, err, "Asset is assigned to someone.")
+		utils.RespondAppError(w, apperr.New(apperr.AssetAlreadyAssigned, "Asset is assigned to someone.", err))
 		return
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		switch {
-		case body.AssetType == models.Laptop:
-			err := dbhelper.DeleteLaptopSpec(tx, body.ID)
-			if err != nil {
-				return err
-			}
-		case body.AssetType == models.Pendrive:
-			err := dbhelper.DeletePenDriveSpec(tx, body.ID)
-			if err != nil {
-				return err
-			}
-		case body.AssetType == models.Harddisk:
-			err := dbhelper.DeleteHardDiskSpec(tx, body.ID)
-			if err != nil {
-				return err
-			}
-		case body.AssetType == models.Mobile:
-			err := dbhelper.DeleteMobileSpec(tx, body.ID)
-			if err != nil {
-				return err
-			}
-		case body.AssetType == models.Sim:
-			err := dbhelper.DeleteSimSpec(tx, body.ID)
-			if err != nil {
-				return err
-			}
-		}
-
-		deleteErr := dbhelper.DeleteAsset(tx, body, userID)
-		if deleteErr != nil {
-			return deleteErr
-		}
+	err = s.Assets.Delete(r.Context(), body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to delete asset.")
+		return
+	}
 
-		return nil
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Asset deleted successfully.",
 	})
-	if txErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, txErr, "failed to delete asset.")
+}
+
+// RestoreAsset brings back a deleted asset as available, unless an asset that is not deleted has taken its serial
+// number or IMEI since
+func (s *Service) RestoreAsset(w http.ResponseWriter, r *http.Request) {
+	body := models.RestoreAsset{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user id.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error.")
+		return
+	}
+
+	err := s.Assets.Restore(r.Context(), body, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAssetNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", err))
+		case errors.Is(err, repository.ErrNotDeleted):
+			utils.RespondAppError(w, apperr.New(apperr.InvalidState, "asset is not deleted.", err))
+		case errors.Is(err, repository.ErrRestoreConflict):
+			utils.RespondAppError(w, apperr.New(apperr.AlreadyExists, err.Error()+".", err))
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, err, "failed to restore asset.")
+		}
 		return
 	}
 
 	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
-		Msg: "Asset deleted successfully.",
+		Msg: "Asset restored successfully.",
 	})
 }
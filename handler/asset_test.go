@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateAsset(t *testing.T) {
+	s := newService()
+	id, tag := createLaptop(t, s, "Dell")
+	if tag != utils.FormatAssetTag(utils.AssetTagPrefix(models.Laptop), 1) {
+		t.Errorf("got asset tag %s for the first laptop", tag)
+	}
+	if _, second := createLaptop(t, s, "Lenovo"); second == tag {
+		t.Errorf("two laptops got the asset tag %s", tag)
+	}
+
+	var spec []models.CreateAsset
+	expect(t, serve(t, s.GetAssetSpec, request{method: http.MethodGet, target: "/?assetId=" + id + "&assetType=laptop"}), http.StatusOK, &spec)
+	if len(spec) != 1 || spec[0].Brand != "Dell" || spec[0].RAM != "16GB" || spec[0].Status != utils.Available {
+		t.Errorf("got specification %+v", spec)
+	}
+
+	var found []models.CreateAsset
+	expect(t, serve(t, s.LookupAsset, request{method: http.MethodGet, target: "/?code=" + tag}), http.StatusOK, &found)
+	if len(found) != 1 || found[0].AssetTag != tag {
+		t.Errorf("looking up %s found %+v", tag, found)
+	}
+	expectError(t, serve(t, s.LookupAsset, request{method: http.MethodGet, target: "/?code=RS-LAP-99999"}), http.StatusNotFound, string(apperr.AssetNotFound))
+}
+
+func TestCreateAssetValidation(t *testing.T) {
+	s := newService()
+	w := serve(t, s.CreateAsset, request{method: http.MethodPost, target: "/", body: models.CreateAsset{Model: "XPS 13"}})
+	var clientErr utils.ClientError
+	expect(t, w, http.StatusBadRequest, &clientErr)
+	if clientErr.Code != apperr.ValidationFailed || len(clientErr.Fields) == 0 || clientErr.Fields[0].Field != "brand" {
+		t.Errorf("got %+v", clientErr)
+	}
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/"}), http.StatusOK, &assets)
+	if assets.TotalCount != 0 {
+		t.Errorf("a rejected asset was stored: %+v", assets)
+	}
+}
+
+func TestAssignRetrieveAndDeleteAsset(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	assetID, _ := createLaptop(t, s, "Dell")
+	assign := models.EmployeeAssetRelation{EmployeeID: employeeID, AssetID: assetID, AssignedDate: assignedOn}
+
+	expect(t, serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: assign}), http.StatusOK, nil)
+	expectError(t, serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: assign}),
+		http.StatusConflict, string(apperr.AssetAlreadyAssigned))
+
+	var assigned models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?assigned=true"}), http.StatusOK, &assigned)
+	if len(assigned.GetAsset) != 1 || assigned.GetAsset[0].AssignedToID.String != employeeID || assigned.GetAsset[0].AssignedTo.String != "Asha" {
+		t.Fatalf("got assigned assets %+v", assigned.GetAsset)
+	}
+
+	var history []models.AssetHistory
+	expect(t, serve(t, s.GetAssetHistory, request{method: http.MethodGet, target: "/?employeeId=" + employeeID}), http.StatusOK, &history)
+	if len(history) != 1 || history[0].ID != assetID || history[0].RetrievedDate != nil {
+		t.Fatalf("got asset history %+v", history)
+	}
+
+	deleteBody := models.Asset{ID: assetID, AssetType: models.Laptop, DeleteReason: "broken"}
+	expectError(t, serve(t, s.DeleteAsset, request{method: http.MethodDelete, target: "/", body: deleteBody}),
+		http.StatusConflict, string(apperr.AssetAlreadyAssigned))
+
+	expect(t, serve(t, s.RetrieveAsset, request{method: http.MethodPut, target: "/", body: models.AssetRetrievalDetails{
+		RetrievedDate:   assignedOn.AddDate(0, 2, 0),
+		RetrievalReason: "upgrade",
+		EmployeeID:      employeeID,
+		AssetID:         assetID,
+	}}), http.StatusOK, nil)
+
+	var spec []models.CreateAsset
+	expect(t, serve(t, s.GetAssetSpec, request{method: http.MethodGet, target: "/?assetId=" + assetID + "&assetType=laptop"}), http.StatusOK, &spec)
+	if spec[0].Status != utils.Available || len(spec[0].AssetHistory) != 1 || spec[0].AssetHistory[0].RetrievalReason != "upgrade" {
+		t.Fatalf("got specification %+v after retrieval", spec[0])
+	}
+
+	expect(t, serve(t, s.DeleteAsset, request{method: http.MethodDelete, target: "/", body: deleteBody}), http.StatusOK, nil)
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/"}), http.StatusOK, &assets)
+	if assets.TotalCount != 0 {
+		t.Errorf("deleted asset is still listed: %+v", assets.GetAsset)
+	}
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?deleted=true"}), http.StatusOK, &assets)
+	if assets.TotalCount != 1 || assets.GetAsset[0].Status != utils.Deleted {
+		t.Errorf("got deleted assets %+v", assets.GetAsset)
+	}
+	expectError(t, serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: assign}),
+		http.StatusNotFound, string(apperr.AssetNotFound))
+}
+
+func TestAssignAssetErrors(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	assetID, _ := createLaptop(t, s, "Dell")
+
+	cases := []struct {
+		name     string
+		relation models.EmployeeAssetRelation
+		status   int
+		code     apperr.Code
+	}{
+		{"unknown asset", models.EmployeeAssetRelation{EmployeeID: employeeID, AssetID: uuid.NewString(), AssignedDate: assignedOn},
+			http.StatusNotFound, apperr.AssetNotFound},
+		{"unknown employee", models.EmployeeAssetRelation{EmployeeID: uuid.NewString(), AssetID: assetID, AssignedDate: assignedOn},
+			http.StatusNotFound, apperr.EmployeeNotFound},
+		{"loan without due date", models.EmployeeAssetRelation{EmployeeID: employeeID, AssetID: assetID, AssignedDate: assignedOn,
+			AssignmentType: utils.AssignmentLoan}, http.StatusBadRequest, apperr.BadRequest},
+		{"unknown assignment type", models.EmployeeAssetRelation{EmployeeID: employeeID, AssetID: assetID, AssignedDate: assignedOn,
+			AssignmentType: "rental"}, http.StatusBadRequest, apperr.ValidationFailed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: c.relation})
+			expectError(t, w, c.status, string(c.code))
+		})
+	}
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?available=true"}), http.StatusOK, &assets)
+	if assets.TotalCount != 1 {
+		t.Errorf("a failed assignment changed the asset: %+v", assets.GetAsset)
+	}
+}
+
+func TestReassignAsset(t *testing.T) {
+	s := newService()
+	first := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	second := createEmployee(t, s, "Ravi", "ravi@remotestate.com")
+	assetID, _ := createLaptop(t, s, "Dell")
+
+	expect(t, serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: models.EmployeeAssetRelation{
+		EmployeeID: first, AssetID: assetID, AssignedDate: assignedOn,
+	}}), http.StatusOK, nil)
+	expect(t, serve(t, s.ReassignAsset, request{method: http.MethodPost, target: "/", body: models.ReassignAsset{
+		AssetID:         assetID,
+		EmployeeID:      second,
+		RetrievedDate:   assignedOn.AddDate(0, 1, 0),
+		RetrievalReason: "team change",
+		AssignedDate:    assignedOn.AddDate(0, 1, 0).Format("2006-01-02T15:04:05Z07:00"),
+	}}), http.StatusOK, nil)
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?assigned=true"}), http.StatusOK, &assets)
+	if len(assets.GetAsset) != 1 || assets.GetAsset[0].AssignedToID.String != second {
+		t.Fatalf("got assigned assets %+v after reassigning", assets.GetAsset)
+	}
+
+	var count struct{ first, second int }
+	count.first, _ = s.Employees.AssignedAssetCount(context.Background(), first)
+	count.second, _ = s.Employees.AssignedAssetCount(context.Background(), second)
+	if count.first != 0 || count.second != 1 {
+		t.Errorf("got %d assets held by the previous holder and %d by the new one", count.first, count.second)
+	}
+}
+
+func TestReassignAssetErrors(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	assetID, _ := createLaptop(t, s, "Dell")
+	reservedID, _ := createLaptop(t, s, "Lenovo")
+	repository.ReserveInMemory(s.Assets, reservedID, time.Now().AddDate(0, 0, 7), time.Now().AddDate(0, 0, 14))
+	deletedID, _ := createLaptop(t, s, "HP")
+	expect(t, serve(t, s.DeleteAsset, request{method: http.MethodDelete, target: "/", body: models.Asset{
+		ID: deletedID, AssetType: models.Laptop, DeleteReason: "lost",
+	}}), http.StatusOK, nil)
+
+	reassign := func(assetID, employeeID string) models.ReassignAsset {
+		return models.ReassignAsset{
+			AssetID:         assetID,
+			EmployeeID:      employeeID,
+			RetrievedDate:   assignedOn,
+			RetrievalReason: "team change",
+			AssignedDate:    assignedOn.Format(time.RFC3339),
+		}
+	}
+	cases := []struct {
+		name     string
+		reassign models.ReassignAsset
+		status   int
+		code     apperr.Code
+	}{
+		{"unknown asset", reassign(uuid.NewString(), employeeID), http.StatusNotFound, apperr.AssetNotFound},
+		{"deleted asset", reassign(deletedID, employeeID), http.StatusNotFound, apperr.AssetNotFound},
+		{"unknown employee", reassign(assetID, uuid.NewString()), http.StatusNotFound, apperr.EmployeeNotFound},
+		{"reserved asset", reassign(reservedID, employeeID), http.StatusConflict, apperr.AssetReserved},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := serve(t, s.ReassignAsset, request{method: http.MethodPost, target: "/", body: c.reassign})
+			expectError(t, w, c.status, string(c.code))
+		})
+	}
+}
+
+func TestRestoreAsset(t *testing.T) {
+	s := newService()
+	assetID, _ := createLaptop(t, s, "Dell")
+	restore := models.RestoreAsset{ID: assetID, RestoreReason: "deleted by mistake"}
+	expectError(t, serve(t, s.RestoreAsset, request{method: http.MethodPut, target: "/", body: restore}),
+		http.StatusConflict, string(apperr.InvalidState))
+
+	deleteBody := models.Asset{ID: assetID, AssetType: models.Laptop, DeleteReason: "lost"}
+	expect(t, serve(t, s.DeleteAsset, request{method: http.MethodDelete, target: "/", body: deleteBody}), http.StatusOK, nil)
+	expect(t, serve(t, s.RestoreAsset, request{method: http.MethodPut, target: "/", body: restore}), http.StatusOK, nil)
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/"}), http.StatusOK, &assets)
+	if assets.TotalCount != 1 || assets.GetAsset[0].Status != utils.Available {
+		t.Fatalf("got assets %+v after the restore", assets.GetAsset)
+	}
+	expectError(t, serve(t, s.RestoreAsset, request{method: http.MethodPut, target: "/", body: restore}),
+		http.StatusConflict, string(apperr.InvalidState))
+
+	// a replacement with the same serial number was bought while the asset was deleted
+	expect(t, serve(t, s.DeleteAsset, request{method: http.MethodDelete, target: "/", body: deleteBody}), http.StatusOK, nil)
+	createLaptop(t, s, "Dell")
+	expectError(t, serve(t, s.RestoreAsset, request{method: http.MethodPut, target: "/", body: restore}),
+		http.StatusConflict, string(apperr.AlreadyExists))
+
+	expectError(t, serve(t, s.RestoreAsset, request{method: http.MethodPut, target: "/", body: models.RestoreAsset{
+		ID: "0b7a4a43-5f3c-4d8e-9f6a-2c1d0e9b8a7f", RestoreReason: "deleted by mistake",
+	}}), http.StatusNotFound, string(apperr.AssetNotFound))
+}
+
+func TestAssetListSortFilterAndCursor(t *testing.T) {
+	s := newService()
+	for _, brand := range []string{"Dell", "HP", "Lenovo", "Asus", "Acer"} {
+		createLaptop(t, s, brand)
+	}
+	brands := func(assets models.TotalGetAsset) string {
+		listed := make([]string, 0, len(assets.GetAsset))
+		for _, asset := range assets.GetAsset {
+			listed = append(listed, asset.Brand)
+		}
+		return strings.Join(listed, ",")
+	}
+
+	var pages []string
+	target := "/?sort=brand&limit=2"
+	for target != "" {
+		var assets models.TotalGetAsset
+		expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: target}), http.StatusOK, &assets)
+		if assets.TotalCount != 5 {
+			t.Fatalf("page %d has total %d, want 5", len(pages), assets.TotalCount)
+		}
+		pages = append(pages, brands(assets))
+		target = ""
+		if assets.NextCursor != "" {
+			target = "/?limit=2&cursor=" + assets.NextCursor
+		}
+	}
+	if got := strings.Join(pages, " | "); got != "Acer,Asus | Dell,HP | Lenovo" {
+		t.Errorf("got pages %s", got)
+	}
+
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"sort=-brand&filter=brand:in:Dell|HP|Lenovo", "Lenovo,HP,Dell"},
+		{"sort=brand&filter=brand:ne:Dell,warrantyExpiry:gt:2026-05-31", "Acer,Asus,HP,Lenovo"},
+		{"sort=brand&filter=warrantyExpiry:lt:2026-05-31", ""},
+		{"sort=brand&filter=brand:contains:a", "Acer,Asus"},
+		{"sort=brand&filter=purchaseDate:eq:2023-06-01,assetType:eq:laptop,brand:gte:H", "HP,Lenovo"},
+	}
+	for _, c := range cases {
+		var assets models.TotalGetAsset
+		expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?" + c.query}), http.StatusOK, &assets)
+		if got := brands(assets); got != c.want {
+			t.Errorf("%s: got %s, want %s", c.query, got, c.want)
+		}
+	}
+
+	var first models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?sort=brand&limit=2"}), http.StatusOK, &first)
+	for _, query := range []string{
+		"sort=serialNo",
+		"filter=brand",
+		"filter=brand:like:D",
+		"filter=purchaseDate:lt:yesterday",
+		"filter=purchaseDate:contains:2023",
+		"cursor=not-a-cursor",
+		"sort=-brand&cursor=" + first.NextCursor,
+	} {
+		expectError(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?" + query}), http.StatusBadRequest, string(apperr.InvalidInput))
+	}
+}
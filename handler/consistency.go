@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"net/http"
+	"strings"
+)
+
+// GetInconsistencies reports the rows breaking each consistency check
+func GetInconsistencies(w http.ResponseWriter, r *http.Request) {
+	inconsistencies, err := dbhelper.CheckConsistency(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetInconsistencies: cannot check consistency.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, inconsistencies)
+}
+
+// PreviewConsistencyRepair shows what a repair would change, the changes are made and rolled back
+func PreviewConsistencyRepair(w http.ResponseWriter, r *http.Request) {
+	repairConsistency(w, r, true)
+}
+
+func RepairConsistency(w http.ResponseWriter, r *http.Request) {
+	repairConsistency(w, r, false)
+}
+
+// repairConsistency repairs the checks named in the comma separated checks parameter, every check when it is not set
+func repairConsistency(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	checks := make([]string, 0)
+	if strChecks := r.URL.Query().Get("checks"); strChecks != "" {
+		checks = strings.Split(strChecks, ",")
+	}
+	for _, check := range checks {
+		if !dbhelper.IsConsistencyCheck(check) {
+			utils.RespondAppError(w, apperr.New(apperr.InvalidInput, "unknown check "+check+".", nil))
+			return
+		}
+	}
+
+	report := models.ConsistencyReport{DryRun: dryRun}
+	var err error
+	report.Inconsistencies, err = dbhelper.CheckConsistency(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RepairConsistency: cannot check consistency.")
+		return
+	}
+	report.Repairs, err = dbhelper.RepairConsistency(r.Context(), checks, dryRun)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RepairConsistency: cannot repair inconsistencies.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateDepartment(w http.ResponseWriter, r *http.Request) {
+	body := models.Department{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	departmentID, err := dbhelper.CreateDepartment(r.Context(), &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateDepartment: cannot create department.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Department created.",
+		ID:  departmentID,
+	})
+}
+
+func GetDepartments(w http.ResponseWriter, r *http.Request) {
+	departments, err := dbhelper.GetDepartments(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetDepartments: cannot get departments.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, departments)
+}
+
+func UpdateDepartment(w http.ResponseWriter, r *http.Request) {
+	body := models.Department{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	if body.ParentID.Valid {
+		isCycle, err := dbhelper.IsDepartmentInSubtree(r.Context(), body.ID, body.ParentID.String)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "UpdateDepartment: cannot check parent department.")
+			return
+		}
+		if isCycle {
+			utils.RespondError(w, http.StatusBadRequest, nil, "department cannot be placed inside itself.")
+			return
+		}
+	}
+
+	err := dbhelper.UpdateDepartment(r.Context(), &body)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateDepartment: cannot update department.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Department updated.",
+	})
+}
+
+func DeleteDepartment(w http.ResponseWriter, r *http.Request) {
+	departmentID := chi.URLParam(r, "departmentID")
+
+	count, err := dbhelper.GetDepartmentUsage(r.Context(), departmentID)
+	switch {
+	case err != nil && count < 0:
+		utils.RespondError(w, http.StatusInternalServerError, err, "cannot check if department is in use.")
+		return
+	case count > 0:
+		utils.RespondAppError(w, apperr.New(apperr.InUse, "Cannot delete: department still has employees or teams.", err))
+		return
+	}
+
+	err = dbhelper.DeleteDepartment(r.Context(), departmentID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "Failed to delete department.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Department deleted successfully.",
+	})
+}
+
+func GetDepartmentDashboard(w http.ResponseWriter, r *http.Request) {
+	quantities, err := dbhelper.GetDepartmentQuantities(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to get department quantities.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, quantities)
+}
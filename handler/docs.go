@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/openapi"
+	"net/http"
+)
+
+// docsPage renders the OpenAPI document with Swagger UI, the document is fetched from the sibling openapi.json route
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Internal Asset Management API</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		SwaggerUIBundle({url: "openapi.json", dom_id: "#swagger-ui"});
+	</script>
+</body>
+</html>
+`
+
+// GetOpenAPISpec serves the committed document, it is mounted outside middlewares.V2Naming as its keys are not fields
+func GetOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	if _, err := w.Write(openapi.Spec()); err != nil {
+		logging.FromContext(r.Context()).Errorf("GetOpenAPISpec: failed to write response with error: %+v", err)
+	}
+}
+
+func GetAPIDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write([]byte(docsPage)); err != nil {
+		logging.FromContext(r.Context()).Errorf("GetAPIDocs: failed to write response with error: %+v", err)
+	}
+}
@@ -1,18 +1,17 @@
 package handler
 
 import (
-	"InternalAssetManagement/database"
-	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/apperr"
 	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
 	"InternalAssetManagement/utils"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-
-	"github.com/jmoiron/sqlx"
 )
 
-func CreateEmployee(w http.ResponseWriter, r *http.Request) {
+func (s *Service) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	var EmployeeDetails models.EmployeeDetails
 
 	if parseErr := utils.ParseBody(r.Body, &EmployeeDetails); parseErr != nil {
@@ -26,7 +25,7 @@ func CreateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := dbhelper.CreateEmployee(&EmployeeDetails)
+	err := s.Employees.Create(r.Context(), &EmployeeDetails)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "CreateEmployee: cannot create employee.")
 		return
@@ -37,7 +36,7 @@ func CreateEmployee(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func GetEmployeeMoreInfo(w http.ResponseWriter, r *http.Request) {
+func (s *Service) GetEmployeeMoreInfo(w http.ResponseWriter, r *http.Request) {
 	employeeID := chi.URLParam(r, "employeeID")
 
 	filterCheck, err := utils.Filters(r)
@@ -48,13 +47,18 @@ func GetEmployeeMoreInfo(w http.ResponseWriter, r *http.Request) {
 
 	filterCheck.EmployeeID = employeeID
 
-	employee, empErr := dbhelper.GetEmployee(&filterCheck)
+	employee, empErr := s.Employees.List(r.Context(), &filterCheck)
 	if empErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, empErr, "GetEmployeeMoreInfo: failed to get employee list.")
 		return
 	}
 
-	assetHistory, assetErr := dbhelper.GetAssetHistory(employeeID)
+	if len(employee.GetEmployee) == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.EmployeeNotFound, "employee not found.", nil))
+		return
+	}
+
+	assetHistory, assetErr := s.Employees.AssetHistory(r.Context(), employeeID)
 	if assetErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, assetErr, "GetEmployeeMoreInfo:failed to get asset history.")
 		return
@@ -65,14 +69,14 @@ func GetEmployeeMoreInfo(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, employee)
 }
 
-func GetEmployeeList(w http.ResponseWriter, r *http.Request) {
-	filterCheck, err := utils.Filters(r)
+func (s *Service) GetEmployeeList(w http.ResponseWriter, r *http.Request) {
+	filterCheck, err := utils.ListFilters(r, models.EmployeeList)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "GetEmployeeList: cannot get filters properly: ")
+		utils.RespondError(w, http.StatusBadRequest, err, "GetEmployeeList: invalid filters.")
 		return
 	}
 
-	employee, empErr := dbhelper.GetEmployee(&filterCheck)
+	employee, empErr := s.Employees.List(r.Context(), &filterCheck)
 	if empErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, empErr, "failed to get employee list.")
 		return
@@ -81,7 +85,7 @@ func GetEmployeeList(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, employee)
 }
 
-func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
+func (s *Service) UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 	body := models.EmployeeDetails{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
@@ -95,18 +99,30 @@ func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if body.Status == utils.NotAnEmployee {
-		count, err := dbhelper.GetAssignedAsset(body.ID)
+		count, err := s.Employees.AssignedAssetCount(r.Context(), body.ID)
 		switch {
 		case err != nil && count < 0:
 			utils.RespondError(w, http.StatusInternalServerError, err, "Cannot check if some asset is assigned to employee.")
 			return
 		case count > 0:
-			utils.RespondError(w, http.StatusBadRequest, err, "Cannot Update to -> Not an employee: Asset is assigned to this employee.")
+			utils.RespondAppError(w, apperr.New(apperr.InUse, "Cannot Update to -> Not an employee: Asset is assigned to this employee.", err))
 			return
 		}
 	}
 
-	updateErr := dbhelper.UpdateEmployee(&body)
+	if body.ManagerID.Valid {
+		isCycle, err := s.Employees.IsManagerCycle(r.Context(), body.ID, body.ManagerID.String)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "UpdateEmployee: cannot check manager.")
+			return
+		}
+		if isCycle {
+			utils.RespondError(w, http.StatusBadRequest, nil, "employee cannot report to themselves or to one of their reports.")
+			return
+		}
+	}
+
+	updateErr := s.Employees.Update(r.Context(), &body)
 	if updateErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, updateErr, "failed to update user details.")
 		return
@@ -117,7 +133,7 @@ func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func DeleteEmployee(w http.ResponseWriter, r *http.Request) {
+func (s *Service) DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 	employeeID := chi.URLParam(r, "employeeID")
 
 	var body models.Employee
@@ -138,17 +154,17 @@ func DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	count, err := dbhelper.GetAssignedAsset(employeeID)
+	count, err := s.Employees.AssignedAssetCount(r.Context(), employeeID)
 	switch {
 	case err != nil && count < 0:
 		utils.RespondError(w, http.StatusInternalServerError, err, "Cannot check if some asset is assigned to employee.")
 		return
 	case count > 0:
-		utils.RespondError(w, http.StatusBadRequest, err, "Cannot delete: Asset is assigned to this employee.")
+		utils.RespondAppError(w, apperr.New(apperr.InUse, "Cannot delete: Asset is assigned to this employee.", err))
 		return
 	}
 
-	err = dbhelper.DeleteEmployee(employeeID, userID, body)
+	err = s.Employees.Delete(r.Context(), employeeID, userID, body)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "Failed to delete employee.")
 		return
@@ -159,7 +175,7 @@ func DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func CreateEmployeeAssetRelation(w http.ResponseWriter, r *http.Request) {
+func (s *Service) CreateEmployeeAssetRelation(w http.ResponseWriter, r *http.Request) {
 	var employeeAssetRelation models.EmployeeAssetRelation
 
 	userID, userErr := utils.UserContext(r)
@@ -173,20 +189,40 @@ func CreateEmployeeAssetRelation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	txErr := database.Tx(func(tx *sqlx.Tx) error {
-		err := dbhelper.CreateEmployeeAssetRelation(employeeAssetRelation, userID, tx)
-		if err != nil {
-			return err
-		}
+	validationErr := validate.Struct(employeeAssetRelation)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
 
-		err = dbhelper.UpdateAvailableAsset(employeeAssetRelation.AssetID, false, utils.Assigned, tx)
-		if err != nil {
-			return err
+	if loanErr := checkAssignmentType(&employeeAssetRelation.AssignmentType, &employeeAssetRelation.DueDate); loanErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, loanErr, loanErr.Error())
+		return
+	}
+
+	err := s.Assets.Assign(r.Context(), &employeeAssetRelation, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRequestNotApproved):
+			utils.RespondAppError(w, apperr.New(apperr.InvalidState, "equipment request is not approved for this employee.", err))
+			return
+		case errors.Is(err, repository.ErrRequestTypeMismatch):
+			utils.RespondAppError(w, apperr.New(apperr.InvalidState, "asset is not of the type the equipment request asks for.", err))
+			return
+		case errors.Is(err, repository.ErrAssetNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", err))
+			return
+		case errors.Is(err, repository.ErrEmployeeNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.EmployeeNotFound, "employee not found.", err))
+			return
+		case errors.Is(err, repository.ErrAssetAssigned):
+			utils.RespondAppError(w, apperr.New(apperr.AssetAlreadyAssigned, "asset is already assigned.", err))
+			return
+		case errors.Is(err, repository.ErrAssetReserved):
+			utils.RespondAppError(w, apperr.New(apperr.AssetReserved, "asset is reserved for part of this period.", err))
+			return
 		}
-		return nil
-	})
-	if txErr != nil {
-		utils.RespondError(w, http.StatusInternalServerError, txErr, "CreateEmployeeAssetRelation: cannot create employee asset relation.")
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateEmployeeAssetRelation: cannot create employee asset relation.")
 		return
 	}
 
@@ -195,10 +231,10 @@ func CreateEmployeeAssetRelation(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func GetAssetHistory(w http.ResponseWriter, r *http.Request) {
+func (s *Service) GetAssetHistory(w http.ResponseWriter, r *http.Request) {
 	employeeID := r.URL.Query().Get("employeeId")
 
-	assetHistory, assetErr := dbhelper.GetAssetHistory(employeeID)
+	assetHistory, assetErr := s.Employees.AssetHistory(r.Context(), employeeID)
 	if assetErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, assetErr, "failed to get asset history.")
 		return
@@ -210,3 +246,46 @@ func GetAssetHistory(w http.ResponseWriter, r *http.Request) {
 
 	utils.RespondJSON(w, http.StatusOK, assetHistory)
 }
+
+// RestoreEmployee brings back a deleted employee as active, unless an employee who is not deleted has taken their
+// email since
+func (s *Service) RestoreEmployee(w http.ResponseWriter, r *http.Request) {
+	employeeID := chi.URLParam(r, "employeeID")
+
+	var body models.RestoreEmployee
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user id.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error.")
+		return
+	}
+
+	err := s.Employees.Restore(r.Context(), employeeID, body, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrEmployeeNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.EmployeeNotFound, "employee not found.", err))
+		case errors.Is(err, repository.ErrNotDeleted):
+			utils.RespondAppError(w, apperr.New(apperr.InvalidState, "employee is not deleted.", err))
+		case errors.Is(err, repository.ErrRestoreConflict):
+			utils.RespondAppError(w, apperr.New(apperr.AlreadyExists, err.Error()+".", err))
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, err, "Failed to restore employee.")
+		}
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Employee restored successfully.",
+	})
+}
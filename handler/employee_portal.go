@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func CreateEmployeeAccount(w http.ResponseWriter, r *http.Request) {
+	employeeID := chi.URLParam(r, "employeeID")
+
+	body := models.EmployeeAccount{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user id.")
+		return
+	}
+
+	hashedPassword, hashErr := utils.HashPassword(body.Password)
+	if hashErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, hashErr, "failed to secure password.")
+		return
+	}
+
+	err := dbhelper.CreateEmployeeAccount(r.Context(), employeeID, hashedPassword, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateEmployeeAccount: cannot create employee account.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Employee account created.",
+	})
+}
+
+func EmployeeLogin(w http.ResponseWriter, r *http.Request) {
+	var loginDetails models.UsersLoginDetails
+	if parseErr := utils.ParseBody(r.Body, &loginDetails); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "EmployeeLogin: decoder error.")
+		return
+	}
+
+	credentials, err := dbhelper.FetchEmployeeCredentials(r.Context(), loginDetails.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.InvalidCredentials, "wrong email or password.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeLogin: cannot fetch credentials.")
+		return
+	}
+
+	if passwordErr := utils.CheckPassword(loginDetails.Password, credentials.Password); passwordErr != nil {
+		utils.RespondAppError(w, apperr.New(apperr.InvalidCredentials, "wrong email or password.", passwordErr))
+		return
+	}
+
+	tokenString, err := signToken(&models.Claims{
+		ID:          credentials.EmployeeID,
+		Role:        utils.RoleEmployee,
+		Permissions: utils.EmployeePermissions,
+	})
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeLogin: cannot create tokenString.")
+		return
+	}
+
+	err = dbhelper.CreateEmployeeSession(r.Context(), credentials.EmployeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeLogin: cannot create session.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"token": tokenString,
+	})
+}
+
+func EmployeeLogout(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	err := dbhelper.EmployeeLogout(r.Context(), employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeLogout: unable to logout.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Logged out.",
+	})
+}
+
+func UpdateEmployeePassword(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	body := models.EmployeePasswordChange{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	currentPassword, err := dbhelper.GetEmployeePassword(r.Context(), employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateEmployeePassword: cannot get password.")
+		return
+	}
+	if passwordErr := utils.CheckPassword(body.CurrentPassword, currentPassword); passwordErr != nil {
+		utils.RespondAppError(w, apperr.New(apperr.InvalidCredentials, "wrong current password.", passwordErr))
+		return
+	}
+
+	hashedPassword, hashErr := utils.HashPassword(body.Password)
+	if hashErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, hashErr, "failed to secure password.")
+		return
+	}
+
+	err = dbhelper.UpdateEmployeePassword(r.Context(), employeeID, hashedPassword)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateEmployeePassword: cannot update password.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Password updated.",
+	})
+}
+
+func GetOwnAssets(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	currentOnly, err := utils.ParamStrToBool(r.URL.Query().Get("current"))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "current must be a boolean.")
+		return
+	}
+
+	assetHistory, err := dbhelper.GetAssetHistory(r.Context(), employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to get asset history.")
+		return
+	}
+
+	assets := make([]models.AssetHistory, 0, len(assetHistory))
+	for i := range assetHistory {
+		if currentOnly && assetHistory[i].RetrievedDate != nil {
+			continue
+		}
+		assets = append(assets, assetHistory[i])
+	}
+
+	utils.RespondJSON(w, http.StatusOK, assets)
+}
+
+func AcknowledgeAsset(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "assetID")
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	rows, err := dbhelper.AcknowledgeAsset(r.Context(), employeeID, assetID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "AcknowledgeAsset: cannot acknowledge asset.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.AssetNotAssigned, "asset is not assigned to you or is already acknowledged.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Asset receipt acknowledged.",
+	})
+}
+
+func ReportAsset(w http.ResponseWriter, r *http.Request) {
+	assetID := chi.URLParam(r, "assetID")
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	body := models.AssetReport{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	held, err := dbhelper.IsAssetHeldBy(r.Context(), employeeID, assetID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "ReportAsset: cannot check asset holder.")
+		return
+	}
+	if !held {
+		utils.RespondAppError(w, apperr.New(apperr.AssetNotAssigned, "asset is not assigned to you.", nil))
+		return
+	}
+
+	reportID, err := dbhelper.CreateAssetReport(r.Context(), employeeID, assetID, &body)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "ReportAsset: cannot create report.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Report submitted.",
+		ID:  reportID,
+	})
+}
+
+func GetAssetReports(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	employeeID := r.URL.Query().Get("employeeId")
+
+	reports, err := dbhelper.GetAssetReports(r.Context(), status, employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetReports: cannot get asset reports.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, reports)
+}
+
+func ResolveAssetReport(w http.ResponseWriter, r *http.Request) {
+	body := models.ResolveAssetReport{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user id.")
+		return
+	}
+
+	rows, err := dbhelper.ResolveAssetReport(r.Context(), &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "ResolveAssetReport: cannot resolve report.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondError(w, http.StatusBadRequest, nil, "report is not open.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Report resolved.",
+	})
+}
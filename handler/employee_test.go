@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/null"
+)
+
+func TestEmployeeLifecycle(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	managerID := createEmployee(t, s, "Meera", "meera@remotestate.com")
+
+	expect(t, serve(t, s.UpdateEmployee, request{method: http.MethodPut, target: "/", body: models.EmployeeDetails{
+		ID:        employeeID,
+		Name:      "Asha Rao",
+		Type:      "employee",
+		Email:     "asha@remotestate.com",
+		PhoneNo:   "9876543210",
+		Status:    utils.Active,
+		ManagerID: null.StringFrom(managerID),
+	}}), http.StatusOK, nil)
+
+	var info models.TotalGetEmployee
+	expect(t, serve(t, s.GetEmployeeMoreInfo, request{method: http.MethodGet, target: "/", params: map[string]string{"employeeID": employeeID}}),
+		http.StatusOK, &info)
+	if len(info.GetEmployee) != 1 || info.GetEmployee[0].Name != "Asha Rao" || info.GetEmployee[0].ManagerName.String != "Meera" {
+		t.Fatalf("got employee %+v", info.GetEmployee)
+	}
+
+	// Meera reporting to Asha would make Meera her own manager's manager
+	w := serve(t, s.UpdateEmployee, request{method: http.MethodPut, target: "/", body: models.EmployeeDetails{
+		ID:        managerID,
+		Name:      "Meera",
+		Type:      "employee",
+		Email:     "meera@remotestate.com",
+		PhoneNo:   "9876543210",
+		Status:    utils.Active,
+		ManagerID: null.StringFrom(employeeID),
+	}})
+	expectError(t, w, http.StatusBadRequest, string(apperr.BadRequest))
+
+	expect(t, serve(t, s.DeleteEmployee, request{method: http.MethodDelete, target: "/", body: models.Employee{ArchiveReason: "left"},
+		params: map[string]string{"employeeID": employeeID}}), http.StatusOK, nil)
+
+	var employees models.TotalGetEmployee
+	expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/"}), http.StatusOK, &employees)
+	if employees.TotalCount != 1 || employees.GetEmployee[0].ID != managerID {
+		t.Errorf("got employees %+v after deleting one", employees.GetEmployee)
+	}
+	expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/?deleted=true"}), http.StatusOK, &employees)
+	if employees.TotalCount != 1 || employees.GetEmployee[0].ArchiveReason.String != "left" {
+		t.Errorf("got deleted employees %+v", employees.GetEmployee)
+	}
+}
+
+func TestEmployeeWithAssetsCannotBeRemoved(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	assetID, _ := createLaptop(t, s, "Dell")
+	expect(t, serve(t, s.CreateEmployeeAssetRelation, request{method: http.MethodPost, target: "/", body: models.EmployeeAssetRelation{
+		EmployeeID: employeeID, AssetID: assetID, AssignedDate: assignedOn,
+	}}), http.StatusOK, nil)
+
+	w := serve(t, s.DeleteEmployee, request{method: http.MethodDelete, target: "/", body: models.Employee{ArchiveReason: "left"},
+		params: map[string]string{"employeeID": employeeID}})
+	expectError(t, w, http.StatusConflict, string(apperr.InUse))
+
+	w = serve(t, s.UpdateEmployee, request{method: http.MethodPut, target: "/", body: models.EmployeeDetails{
+		ID:      employeeID,
+		Name:    "Asha",
+		Type:    "employee",
+		Email:   "asha@remotestate.com",
+		PhoneNo: "9876543210",
+		Status:  utils.NotAnEmployee,
+	}})
+	expectError(t, w, http.StatusConflict, string(apperr.InUse))
+}
+
+func TestCreateEmployeeValidation(t *testing.T) {
+	s := newService()
+	w := serve(t, s.CreateEmployee, request{method: http.MethodPost, target: "/", body: models.EmployeeDetails{
+		Name:    "Asha",
+		Type:    "employee",
+		Email:   "not an email",
+		PhoneNo: "12345",
+	}})
+	var clientErr utils.ClientError
+	expect(t, w, http.StatusBadRequest, &clientErr)
+	fields := make(map[string]string)
+	for _, field := range clientErr.Fields {
+		fields[field.Field] = field.Rule
+	}
+	if fields["email"] != "email" || fields["phoneNo"] != "min" {
+		t.Errorf("got field errors %+v", clientErr.Fields)
+	}
+}
+
+func TestGetEmployeeMoreInfoNotFound(t *testing.T) {
+	s := newService()
+	w := serve(t, s.GetEmployeeMoreInfo, request{method: http.MethodGet, target: "/", params: map[string]string{"employeeID": uuid.NewString()}})
+	expectError(t, w, http.StatusNotFound, string(apperr.EmployeeNotFound))
+}
+
+func TestEmployeeListSortAndCursor(t *testing.T) {
+	s := newService()
+	for _, name := range []string{"Asha", "Ravi", "Dev"} {
+		createEmployee(t, s, name, strings.ToLower(name)+"@remotestate.com")
+	}
+
+	var names []string
+	target := "/?sort=-name&limit=2"
+	for target != "" {
+		var employees models.TotalGetEmployee
+		expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: target}), http.StatusOK, &employees)
+		for _, employee := range employees.GetEmployee {
+			names = append(names, employee.Name)
+		}
+		target = ""
+		if employees.NextCursor != "" {
+			target = "/?limit=2&cursor=" + employees.NextCursor
+		}
+	}
+	if got := strings.Join(names, ","); got != "Ravi,Dev,Asha" {
+		t.Errorf("got %s, want Ravi,Dev,Asha", got)
+	}
+
+	var employees models.TotalGetEmployee
+	expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/?filter=email:eq:dev@remotestate.com"}), http.StatusOK, &employees)
+	if len(employees.GetEmployee) != 1 || employees.GetEmployee[0].Name != "Dev" {
+		t.Errorf("filtered by email got %+v", employees.GetEmployee)
+	}
+
+	// a cursor of the asset list is refused by the employee list
+	createLaptop(t, s, "Dell")
+	createLaptop(t, s, "HP")
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?limit=1"}), http.StatusOK, &assets)
+	expectError(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/?cursor=" + assets.NextCursor}),
+		http.StatusBadRequest, string(apperr.InvalidInput))
+	expectError(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/?sort=brand"}),
+		http.StatusBadRequest, string(apperr.InvalidInput))
+}
+
+func TestRestoreEmployee(t *testing.T) {
+	s := newService()
+	employeeID := createEmployee(t, s, "Asha", "asha@remotestate.com")
+	params := map[string]string{"employeeID": employeeID}
+	restore := models.RestoreEmployee{RestoreReason: "deleted by mistake"}
+	expectError(t, serve(t, s.RestoreEmployee, request{method: http.MethodPut, target: "/", body: restore, params: params}),
+		http.StatusConflict, string(apperr.InvalidState))
+
+	expect(t, serve(t, s.DeleteEmployee, request{method: http.MethodDelete, target: "/", body: models.Employee{ArchiveReason: "left"},
+		params: params}), http.StatusOK, nil)
+	expect(t, serve(t, s.RestoreEmployee, request{method: http.MethodPut, target: "/", body: restore, params: params}), http.StatusOK, nil)
+
+	var employees models.TotalGetEmployee
+	expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/"}), http.StatusOK, &employees)
+	if employees.TotalCount != 1 || employees.GetEmployee[0].Status != utils.Active || employees.GetEmployee[0].ArchiveReason.Valid {
+		t.Fatalf("got employees %+v after the restore", employees.GetEmployee)
+	}
+
+	// someone rejoined under the same email while Asha was deleted
+	expect(t, serve(t, s.DeleteEmployee, request{method: http.MethodDelete, target: "/", body: models.Employee{ArchiveReason: "left"},
+		params: params}), http.StatusOK, nil)
+	createEmployee(t, s, "Ashwini", "Asha@remotestate.com")
+	expectError(t, serve(t, s.RestoreEmployee, request{method: http.MethodPut, target: "/", body: restore, params: params}),
+		http.StatusConflict, string(apperr.AlreadyExists))
+
+	expectError(t, serve(t, s.RestoreEmployee, request{method: http.MethodPut, target: "/", body: models.RestoreEmployee{},
+		params: params}), http.StatusBadRequest, string(apperr.ValidationFailed))
+}
@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+var errNotApprover = errors.New("user is not the approver of the current step")
+
+func CreateEquipmentRequest(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	body := models.EquipmentRequest{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+	body.EmployeeID = employeeID
+
+	createEquipmentRequest(w, r, &body, null.String{})
+}
+
+// CreateEquipmentRequestForEmployee lets an admin file a request on behalf of an employee, e.g. one their manager asked for
+func CreateEquipmentRequestForEmployee(w http.ResponseWriter, r *http.Request) {
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	body := models.EquipmentRequest{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+	if body.EmployeeID == "" {
+		utils.RespondError(w, http.StatusBadRequest, nil, "employeeId is required.")
+		return
+	}
+
+	createEquipmentRequest(w, r, &body, null.StringFrom(userID))
+}
+
+func createEquipmentRequest(w http.ResponseWriter, r *http.Request, body *models.EquipmentRequest, requestedBy null.String) {
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+	if body.NeededBy.Valid && body.NeededBy.Time.Before(time.Now().Truncate(24*time.Hour)) {
+		utils.RespondError(w, http.StatusBadRequest, nil, "neededBy cannot be in the past.")
+		return
+	}
+
+	var requestID string
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		var err error
+		requestID, err = dbhelper.CreateEquipmentRequest(r.Context(), tx, body, requestedBy)
+		if err != nil {
+			return err
+		}
+		return enqueueEquipmentRequestNotifications(r.Context(), tx, requestID, utils.RequestPending)
+	})
+	if txErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, txErr, "CreateEquipmentRequest: cannot create equipment request.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Equipment requested.",
+		ID:  requestID,
+	})
+}
+
+func GetOwnEquipmentRequests(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	requests, err := dbhelper.GetEquipmentRequests(r.Context(), employeeID, r.URL.Query().Get("status"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetOwnEquipmentRequests: cannot get equipment requests.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, requests)
+}
+
+func GetOwnEquipmentRequest(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	respondEquipmentRequest(w, r, chi.URLParam(r, "requestID"), employeeID)
+}
+
+func CancelOwnEquipmentRequest(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	rows, err := dbhelper.CancelEquipmentRequest(r.Context(), chi.URLParam(r, "requestID"), employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CancelEquipmentRequest: cannot cancel equipment request.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.EquipmentRequestNotFound, "request not found or can no longer be cancelled.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Equipment request cancelled.",
+	})
+}
+
+func GetEquipmentRequests(w http.ResponseWriter, r *http.Request) {
+	employeeID := r.URL.Query().Get("employeeId")
+	status := r.URL.Query().Get("status")
+
+	requests, err := dbhelper.GetEquipmentRequests(r.Context(), employeeID, status)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetEquipmentRequests: cannot get equipment requests.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, requests)
+}
+
+func GetEquipmentRequest(w http.ResponseWriter, r *http.Request) {
+	respondEquipmentRequest(w, r, chi.URLParam(r, "requestID"), "")
+}
+
+func respondEquipmentRequest(w http.ResponseWriter, r *http.Request, requestID, employeeID string) {
+	request, err := dbhelper.GetEquipmentRequest(r.Context(), requestID, employeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.EquipmentRequestNotFound, "equipment request not found.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetEquipmentRequest: cannot get equipment request.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, request)
+}
+
+// DecideEquipmentRequest approves or rejects the current step of a request, the last approval approves the request
+func DecideEquipmentRequest(w http.ResponseWriter, r *http.Request) {
+	requestID := chi.URLParam(r, "requestID")
+
+	body := models.EquipmentRequestDecision{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	var status string
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		approval, err := dbhelper.GetCurrentApproval(r.Context(), tx, requestID)
+		if err != nil {
+			return err
+		}
+		if approval.ApproverID.Valid && approval.ApproverID.String != userID {
+			return errNotApprover
+		}
+
+		status, err = dbhelper.DecideApproval(r.Context(), tx, &approval, &body, userID)
+		if err != nil {
+			return err
+		}
+		return enqueueEquipmentRequestNotifications(r.Context(), tx, requestID, status)
+	})
+	if txErr != nil {
+		switch {
+		case errors.Is(txErr, sql.ErrNoRows):
+			utils.RespondAppError(w, apperr.New(apperr.EquipmentRequestNotFound, "request not found or not awaiting approval.", txErr))
+		case errors.Is(txErr, errNotApprover):
+			utils.RespondAppError(w, apperr.New(apperr.NotApprover, "you are not the approver of the current step.", txErr))
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, txErr, "DecideEquipmentRequest: cannot decide equipment request.")
+		}
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg    string `json:"msg"`
+		Status string `json:"status"`
+	}{
+		Msg:    "Decision recorded.",
+		Status: status,
+	})
+}
+
+func CreateApprovalStep(w http.ResponseWriter, r *http.Request) {
+	body := models.ApprovalStep{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	stepID, err := dbhelper.CreateApprovalStep(r.Context(), &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateApprovalStep: cannot create approval step.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Approval step created.",
+		ID:  stepID,
+	})
+}
+
+func GetApprovalSteps(w http.ResponseWriter, r *http.Request) {
+	steps, err := dbhelper.GetApprovalSteps(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetApprovalSteps: cannot get approval steps.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, steps)
+}
+
+func DeleteApprovalStep(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.DeleteApprovalStep(r.Context(), chi.URLParam(r, "stepID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "DeleteApprovalStep: cannot delete approval step.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.NotFound, "approval step not found.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Approval step deleted.",
+	})
+}
+
+// enqueueEquipmentRequestNotifications queues the email telling the employee the status of a request and, while it
+// is pending, the one asking the approvers of its current step for a decision. They are sent once tx commits
+func enqueueEquipmentRequestNotifications(ctx context.Context, tx *sqlx.Tx, requestID, status string) error {
+	payload := models.EquipmentRequestNotification{RequestID: requestID, Status: status}
+	if err := dbhelper.EnqueueNotification(ctx, tx, models.NotificationRequestStatus, payload); err != nil {
+		return err
+	}
+	if status != utils.RequestPending {
+		return nil
+	}
+	return dbhelper.EnqueueNotification(ctx, tx, models.NotificationRequestApproval, payload)
+}
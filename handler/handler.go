@@ -1,15 +1,17 @@
 package handler
 
 import (
+	"InternalAssetManagement/apperr"
 	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
 	"InternalAssetManagement/utils"
 	"database/sql"
 	"errors"
-	"fmt"
 	"github.com/go-chi/chi/v5"
 	"net/http"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
@@ -17,11 +19,26 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/sendgrid/sendgrid-go"
 	"github.com/sendgrid/sendgrid-go/helpers/mail"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var validate = validator.New()
+var validate = newValidator()
+
+// newValidator reports failed fields by their JSON names so field errors match the request body
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
 
 func AddProfileImage(w http.ResponseWriter, r *http.Request) {
 	userID, userErr := utils.UserContext(r)
@@ -35,8 +52,7 @@ func AddProfileImage(w http.ResponseWriter, r *http.Request) {
 		utils.RespondError(w, http.StatusInternalServerError, err, "UploadImage: cannot upload image url.")
 		return
 	}
-	fmt.Println(url)
-	err = dbhelper.AddProfileImage(userID, url)
+	err = dbhelper.AddProfileImage(r.Context(), userID, url)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "AddProfileImage: cannot add profile image.")
 		return
@@ -51,43 +67,60 @@ func AddProfileImage(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// NextCursorHeader carries the cursor of the next page of the accessed by list, whose body is a bare array
+const NextCursorHeader = "X-Next-Cursor"
+
 func AccessedByDetails(w http.ResponseWriter, r *http.Request) {
 	userType := r.URL.Query().Get("userType")
-	filterCheck, err := utils.Filters(r)
+	filterCheck, err := utils.ListFilters(r, models.AccessedByList)
 	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "AccessedByDetails: cannot get filters properly: ")
+		utils.RespondError(w, http.StatusBadRequest, err, "AccessedByDetails: invalid filters.")
 		return
 	}
 
-	accessedByDetails, err := dbhelper.AccessedByDetails(userType, &filterCheck)
+	accessedByDetails, err := dbhelper.AccessedByDetails(r.Context(), userType, &filterCheck)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "AccessedByDetails: cannot get Accessed By Details.")
 		return
 	}
-	if accessedByDetails == nil {
-		utils.RespondJSON(w, http.StatusOK, []models.AccessedByDetails{})
-		return
+	if accessedByDetails.NextCursor != "" {
+		w.Header().Set(NextCursorHeader, accessedByDetails.NextCursor)
 	}
-	utils.RespondJSON(w, http.StatusOK, accessedByDetails)
+	utils.RespondJSON(w, http.StatusOK, accessedByDetails.AccessedBy)
 }
 
-func Logout(w http.ResponseWriter, r *http.Request) {
+func (s *Service) Logout(w http.ResponseWriter, r *http.Request) {
 	userID, userErr := utils.UserContext(r)
 	if userErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, userErr, "Cannot get user id.")
 		return
 	}
 
-	err := dbhelper.Logout(userID)
+	err := s.Sessions.End(r.Context(), userID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "Logout: unable to logout.")
 		return
 	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Logged out.",
+	})
 }
 
 var JwtKey = []byte("secret_key")
 
-func RegisterUser(w http.ResponseWriter, r *http.Request) {
+const tokenValidity = 24 * time.Hour
+
+// signToken sets the expiry on the claims and signs them into a JWT
+func signToken(claims *models.Claims) (string, error) {
+	claims.StandardClaims = jwt.StandardClaims{
+		ExpiresAt: time.Now().Add(tokenValidity).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(JwtKey)
+}
+
+func (s *Service) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	body := models.RegisterUser{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "Failed to parse request body.")
@@ -100,13 +133,13 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	exists, existsErr := dbhelper.IsUserExist(body.Email, body.PhoneNo)
+	exists, existsErr := s.Users.Exists(r.Context(), body.Email, body.PhoneNo)
 	if existsErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, existsErr, "failed to check users' existence.")
 		return
 	}
 	if exists {
-		utils.RespondError(w, http.StatusBadRequest, nil, "user already exists.")
+		utils.RespondAppError(w, apperr.New(apperr.AlreadyExists, "user already exists.", nil))
 		return
 	}
 
@@ -116,7 +149,7 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := dbhelper.CreateUser(body.Name, body.Email, hashedPassword, body.PhoneNo)
+	err := s.Users.Create(r.Context(), body.Name, body.Email, hashedPassword, body.PhoneNo)
 	if err != nil {
 		utils.RespondError(w, http.StatusBadRequest, err, "failed to create user.")
 		return
@@ -127,7 +160,12 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func LoginUser(w http.ResponseWriter, r *http.Request) {
+func (s *Service) LoginUser(w http.ResponseWriter, r *http.Request) {
+	if passwordLoginDisabled() {
+		utils.RespondAppError(w, apperr.New(apperr.PasswordLoginDisabled, "password login is disabled, please sign in with single sign-on.", nil))
+		return
+	}
+
 	var userDetails models.UsersLoginDetails
 	decoderErr := utils.ParseBody(r.Body, &userDetails)
 	if decoderErr != nil {
@@ -135,40 +173,27 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	companyEmail := strings.Split(userDetails.Email, "@")[1]
-
-	if companyEmail != "remotestate.com" {
-		utils.RespondJSON(w, http.StatusBadRequest, utils.ResponseMsg{
-			Msg: "non-authorized email.",
-		})
-		utils.RespondError(w, http.StatusBadRequest, errors.New("non-authorized email"), "LoginUser: non-authorized email")
+	if !strings.HasSuffix(userDetails.Email, "@remotestate.com") {
+		utils.RespondAppError(w, apperr.New(apperr.EmailNotAuthorized, "non-authorized email.", nil))
 		return
 	}
 
-	userCredentials, fetchErr := dbhelper.FetchPasswordAndID(userDetails.Email)
+	userCredentials, fetchErr := s.Users.Credentials(r.Context(), userDetails.Email)
 	if fetchErr != nil {
-		if fetchErr == sql.ErrNoRows {
-			utils.RespondJSON(w, http.StatusBadRequest, utils.ResponseMsg{
-				Msg: "wrong email.",
-			})
-
-			logrus.Printf("Login:FetchPasswordAndId: wrong details:%v", fetchErr)
+		if errors.Is(fetchErr, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.InvalidCredentials, "wrong email or password.", fetchErr))
 			return
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		utils.RespondError(w, http.StatusInternalServerError, fetchErr, "LoginUser: cannot fetch credentials.")
 		return
 	}
 
 	if PasswordErr := bcrypt.CompareHashAndPassword([]byte(userCredentials.Password), []byte(userDetails.Password)); PasswordErr != nil {
-		_, err := w.Write([]byte("ERROR: Wrong password"))
-		if err != nil {
-			return
-		}
-		utils.RespondError(w, http.StatusUnauthorized, PasswordErr, "Login: Password misMatch")
+		utils.RespondAppError(w, apperr.New(apperr.InvalidCredentials, "wrong email or password.", PasswordErr))
 		return
 	}
 
-	statusDetails, err := dbhelper.GetStatusDetails(userDetails.Email)
+	statusDetails, err := s.Users.Status(r.Context(), userDetails.Email)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "LoginUser: unable to fetch user status.")
 		return
@@ -186,86 +211,61 @@ func LoginUser(w http.ResponseWriter, r *http.Request) {
 		client1 := sendgrid.NewSendClient(os.Getenv("sendgrid_api_key"))
 		response1, RErr := client1.Send(message1)
 		if RErr != nil {
-			logrus.Printf("SendFriendRequest: cannot send mail to user:%v", RErr)
-			return
+			logging.FromContext(r.Context()).WithError(RErr).Error("LoginUser: cannot send unauthorized login warning.")
+		} else {
+			logging.FromContext(r.Context()).WithField("status", response1.StatusCode).Debug("LoginUser: sent unauthorized login warning.")
 		}
-
-		fmt.Println(response1.StatusCode)
-		fmt.Println(response1.Body)
-		fmt.Println(response1.Headers)
-
 		// send email over
-		utils.RespondJSON(w, http.StatusBadRequest, utils.ResponseMsg{
-			Msg: "unauthorized email.",
-		})
 
-		err = dbhelper.AlterStatusDetails(utils.UnAuthorized, utils.Warned, statusDetails.AuthenticationTimes, userCredentials.ID)
+		err = s.Users.AlterStatus(r.Context(), utils.UnAuthorized, utils.Warned, statusDetails.AuthenticationTimes, userCredentials.ID)
 		if err != nil {
 			utils.RespondError(w, http.StatusInternalServerError, err, "AlterNoOfTime: unable to change no of login time.")
 			return
 		}
+		utils.RespondAppError(w, apperr.New(apperr.EmailNotAuthorized, "unauthorized email.", nil))
 		return
 
 	case statusDetails.Type == utils.UnAuthorized && statusDetails.AuthenticationTimes > 0:
 
-		utils.RespondJSON(w, http.StatusBadRequest, utils.ResponseMsg{
-			Msg: "unauthorized email.",
-		})
-		DBErr := dbhelper.AlterStatusDetails(utils.Blocked, utils.Blocklisted, statusDetails.AuthenticationTimes, userCredentials.ID)
+		DBErr := s.Users.AlterStatus(r.Context(), utils.Blocked, utils.Blocklisted, statusDetails.AuthenticationTimes, userCredentials.ID)
 		if DBErr != nil {
 			utils.RespondError(w, http.StatusInternalServerError, DBErr, "AlterUserStatus: unable to change user status.")
 			return
 		}
+		utils.RespondAppError(w, apperr.New(apperr.EmailNotAuthorized, "unauthorized email.", nil))
 		return
 
 	case statusDetails.Type == utils.Blocked:
 
-		utils.RespondJSON(w, http.StatusBadRequest, utils.ResponseMsg{
-			Msg: "Blocked email.",
-		})
+		utils.RespondAppError(w, apperr.New(apperr.AccountBlocked, "Blocked email.", nil))
 		return
 	}
 
-	hours := 24
-
-	expiresAt := time.Now().Add(time.Duration(hours) * time.Hour)
-
 	claims := &models.Claims{
-		ID: userCredentials.ID,
-		StandardClaims: jwt.StandardClaims{
-
-			ExpiresAt: expiresAt.Unix(),
-			// Issuer:    userCredentials.Role,
-		},
+		ID:   userCredentials.ID,
+		Role: userCredentials.Role,
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(JwtKey)
+	tokenString, err := signToken(claims)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "LoginUser: cannot create tokenString.")
 		return
 	}
 
-	err = dbhelper.CreateSession(claims)
+	err = s.Sessions.Create(r.Context(), claims.ID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "LoginUser: cannot create session.")
 		return
 	}
 
-	userOutboundData := make(map[string]interface{})
-
-	userOutboundData["token"] = tokenString
-
-	err = utils.EncodeJSONBody(w, userOutboundData)
-	if err != nil {
-		utils.RespondError(w, http.StatusInternalServerError, err, "LoginUser: not able to login.")
-		return
-	}
+	utils.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"token": tokenString,
+	})
 }
 
-func GetUserInfo(w http.ResponseWriter, r *http.Request) {
+func (s *Service) GetUserInfo(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 
-	user, err := dbhelper.GetUserDetails(userID)
+	user, err := s.Users.Details(r.Context(), userID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "cannot get user details.")
 		return
@@ -274,14 +274,14 @@ func GetUserInfo(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, user)
 }
 
-func GetUserDetails(w http.ResponseWriter, r *http.Request) {
+func (s *Service) GetUserDetails(w http.ResponseWriter, r *http.Request) {
 	userID, userErr := utils.UserContext(r)
 	if userErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user id.")
 		return
 	}
 
-	user, err := dbhelper.GetUserDetails(userID)
+	user, err := s.Users.Details(r.Context(), userID)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "cannot get user details.")
 		return
@@ -296,12 +296,12 @@ func GetDashboard(w http.ResponseWriter, r *http.Request) {
 		dashBoardFilter = "total"
 	}
 
-	quantities, err := dbhelper.GetAssetQuantities(dashBoardFilter)
+	quantities, err := dbhelper.GetAssetQuantities(r.Context(), dashBoardFilter)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to get asset quantities.")
 		return
 	}
-	assetQuantities, err := dbhelper.GetTotalAssetQuantities(quantities)
+	assetQuantities, err := dbhelper.GetTotalAssetQuantities(r.Context(), quantities)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "failed to get total asset quantities.")
 		return
@@ -310,7 +310,7 @@ func GetDashboard(w http.ResponseWriter, r *http.Request) {
 	utils.RespondJSON(w, http.StatusOK, assetQuantities)
 }
 
-func UpdateUser(w http.ResponseWriter, r *http.Request) {
+func (s *Service) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	body := models.RegisterUser{}
 	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
 		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
@@ -335,7 +335,7 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	updateErr := dbhelper.UpdateUser(body, hashedPassword, userID)
+	updateErr := s.Users.Update(r.Context(), body, hashedPassword, userID)
 	if updateErr != nil {
 		utils.RespondError(w, http.StatusInternalServerError, updateErr, "failed to update user details.")
 		return
@@ -350,7 +350,7 @@ func UpdateAccessedBy(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("userId")
 	userType := r.URL.Query().Get("userType")
 
-	err := dbhelper.UpdateAccessedBy(userID, userType)
+	err := dbhelper.UpdateAccessedBy(r.Context(), userID, userType)
 	if err != nil {
 		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateAccessedBy: cannot update accessedBy")
 	}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	s := newService()
+	user := models.RegisterUser{Name: "Admin", Email: "admin@remotestate.com", PhoneNo: "9876543210", Password: "s3cret!"}
+
+	expect(t, serve(t, s.RegisterUser, request{method: http.MethodPost, target: "/", body: user}), http.StatusOK, nil)
+	expectError(t, serve(t, s.RegisterUser, request{method: http.MethodPost, target: "/", body: user}),
+		http.StatusConflict, string(apperr.AlreadyExists))
+
+	var login struct {
+		Token string `json:"token"`
+	}
+	expect(t, serve(t, s.LoginUser, request{method: http.MethodPost, target: "/", body: models.UsersLoginDetails{
+		Email: user.Email, Password: user.Password,
+	}}), http.StatusOK, &login)
+
+	claims := &models.Claims{}
+	if _, err := jwt.ParseWithClaims(login.Token, claims, func(*jwt.Token) (interface{}, error) { return JwtKey, nil }); err != nil {
+		t.Fatalf("login returned an invalid token: %v", err)
+	}
+	credentials, err := s.Users.Credentials(context.Background(), user.Email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != credentials.ID || claims.Role != credentials.Role {
+		t.Errorf("got claims %+v for user %s", claims, credentials.ID)
+	}
+
+	expect(t, serve(t, s.Logout, request{method: http.MethodPut, target: "/"}), http.StatusOK, nil)
+}
+
+func TestLoginErrors(t *testing.T) {
+	s := newService()
+	expect(t, serve(t, s.RegisterUser, request{method: http.MethodPost, target: "/", body: models.RegisterUser{
+		Name: "Admin", Email: "admin@remotestate.com", PhoneNo: "9876543210", Password: "s3cret!",
+	}}), http.StatusOK, nil)
+
+	cases := []struct {
+		name   string
+		login  models.UsersLoginDetails
+		status int
+		code   apperr.Code
+	}{
+		{"wrong password", models.UsersLoginDetails{Email: "admin@remotestate.com", Password: "guess"}, http.StatusUnauthorized, apperr.InvalidCredentials},
+		{"unknown email", models.UsersLoginDetails{Email: "nobody@remotestate.com", Password: "s3cret!"}, http.StatusUnauthorized, apperr.InvalidCredentials},
+		{"outside domain", models.UsersLoginDetails{Email: "admin@example.com", Password: "s3cret!"}, http.StatusForbidden, apperr.EmailNotAuthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expectError(t, serve(t, s.LoginUser, request{method: http.MethodPost, target: "/", body: c.login}), c.status, string(c.code))
+		})
+	}
+}
+
+func TestUserDetails(t *testing.T) {
+	s := newService()
+	expect(t, serve(t, s.RegisterUser, request{method: http.MethodPost, target: "/", body: models.RegisterUser{
+		Name: "Admin", Email: "admin@remotestate.com", PhoneNo: "9876543210", Password: "s3cret!",
+	}}), http.StatusOK, nil)
+	credentials, err := s.Users.Credentials(context.Background(), "admin@remotestate.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, serve(t, s.UpdateUser, request{method: http.MethodPut, target: "/", user: credentials.ID, body: models.RegisterUser{
+		Name: "Head Admin", Email: "head@remotestate.com", PhoneNo: "9123456780", Password: "n3w-secret",
+	}}), http.StatusOK, nil)
+
+	var details models.UserDetails
+	expect(t, serve(t, s.GetUserDetails, request{method: http.MethodGet, target: "/", user: credentials.ID}), http.StatusOK, &details)
+	if details.Name != "Head Admin" || details.Email != "head@remotestate.com" || details.PhoneNo != "9123456780" {
+		t.Errorf("got user details %+v after the update", details)
+	}
+	updated, err := s.Users.Credentials(context.Background(), "head@remotestate.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if utils.CheckPassword("n3w-secret", updated.Password) != nil {
+		t.Error("the password was not changed")
+	}
+
+	expectError(t, serve(t, s.GetUserDetails, request{method: http.MethodGet, target: "/"}), http.StatusNotFound, string(apperr.NotFound))
+}
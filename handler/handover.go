@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+var errAlreadyAcknowledged = errors.New("handover is already acknowledged")
+
+func CreateHandover(w http.ResponseWriter, r *http.Request) {
+	body := models.CreateHandover{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	relationID, err := dbhelper.GetRelationForHandover(r.Context(), body.AssetID, body.EmployeeID, body.Kind)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotAssigned, "asset was never assigned to this employee.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateHandover: cannot get assignment.")
+		return
+	}
+
+	handoverID, err := dbhelper.CreateHandover(r.Context(), relationID, &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateHandover: cannot create handover record.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Handover recorded.",
+		ID:  handoverID,
+	})
+}
+
+// AddHandoverPhoto uploads a photo only once the handover is locked and known to take photos, so no image is stored
+// for a handover that does not exist or is already acknowledged
+func AddHandoverPhoto(w http.ResponseWriter, r *http.Request) {
+	handoverID := chi.URLParam(r, "handoverID")
+
+	var url string
+	var uploadErr error
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		acknowledged, err := dbhelper.LockHandover(r.Context(), tx, handoverID)
+		if err != nil {
+			return err
+		}
+		if acknowledged {
+			return errAlreadyAcknowledged
+		}
+
+		url, uploadErr = utils.UploadImage(r)
+		if uploadErr != nil {
+			return uploadErr
+		}
+		_, err = dbhelper.AddHandoverPhoto(r.Context(), tx, handoverID, url)
+		return err
+	})
+	if txErr != nil {
+		switch {
+		case errors.Is(txErr, sql.ErrNoRows):
+			utils.RespondAppError(w, apperr.New(apperr.HandoverNotFound, "handover not found.", txErr))
+		case errors.Is(txErr, errAlreadyAcknowledged):
+			utils.RespondAppError(w, apperr.New(apperr.AlreadyAcknowledged, "handover is already acknowledged.", txErr))
+		case uploadErr != nil:
+			utils.RespondError(w, http.StatusInternalServerError, txErr, "UploadImage: cannot upload image url.")
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, txErr, "AddHandoverPhoto: cannot add handover photo.")
+		}
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		URL string `json:"url"`
+	}{
+		Msg: "Added photo successfully.",
+		URL: url,
+	})
+}
+
+func GetHandovers(w http.ResponseWriter, r *http.Request) {
+	assetID := r.URL.Query().Get("assetId")
+	employeeID := r.URL.Query().Get("employeeId")
+
+	handovers, err := dbhelper.GetHandovers(r.Context(), assetID, employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetHandovers: cannot get handover records.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, handovers)
+}
+
+// AcknowledgeHandover lets an admin capture the acknowledgement in person e.g. signed on a tablet
+func AcknowledgeHandover(w http.ResponseWriter, r *http.Request) {
+	acknowledgeHandover(w, r, chi.URLParam(r, "handoverID"), "")
+}
+
+func GetHandoverReceipt(w http.ResponseWriter, r *http.Request) {
+	respondHandoverReceipt(w, r, chi.URLParam(r, "handoverID"), "")
+}
+
+func GetOwnHandovers(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	handovers, err := dbhelper.GetHandovers(r.Context(), "", employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetOwnHandovers: cannot get handover records.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, handovers)
+}
+
+func AcknowledgeOwnHandover(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	acknowledgeHandover(w, r, chi.URLParam(r, "handoverID"), employeeID)
+}
+
+func GetOwnHandoverReceipt(w http.ResponseWriter, r *http.Request) {
+	employeeID, employeeErr := utils.EmployeeContext(r)
+	if employeeErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, employeeErr, "cannot get employee id.")
+		return
+	}
+
+	respondHandoverReceipt(w, r, chi.URLParam(r, "handoverID"), employeeID)
+}
+
+// acknowledgeHandover records the typed name, time, IP and optional drawn signature and stores the rendered receipt
+func acknowledgeHandover(w http.ResponseWriter, r *http.Request, handoverID, employeeID string) {
+	body := models.HandoverAcknowledgement{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	// drawn signatures usually arrive as a data URL from the browser canvas
+	if idx := strings.Index(body.SignatureImage, ";base64,"); idx != -1 {
+		body.SignatureImage = body.SignatureImage[idx+len(";base64,"):]
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	handover, err := dbhelper.GetHandover(r.Context(), handoverID, employeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.HandoverNotFound, "handover not found.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "AcknowledgeHandover: cannot get handover record.")
+		return
+	}
+	if handover.AcknowledgedAt.Valid {
+		utils.RespondAppError(w, apperr.New(apperr.AlreadyAcknowledged, "handover is already acknowledged.", errAlreadyAcknowledged))
+		return
+	}
+
+	if body.SignatureImage != "" {
+		signature, decodeErr := base64.StdEncoding.DecodeString(body.SignatureImage)
+		if decodeErr != nil {
+			utils.RespondAppError(w, apperr.New(apperr.InvalidInput, "invalid signature image.", decodeErr))
+			return
+		}
+		if _, typeErr := utils.SignatureImageType(signature); typeErr != nil {
+			utils.RespondError(w, http.StatusBadRequest, typeErr, typeErr.Error())
+			return
+		}
+		handover.SignatureImage = signature
+	}
+	handover.AcknowledgedName = null.StringFrom(body.TypedName)
+	handover.AcknowledgedAt = null.TimeFrom(time.Now())
+	handover.AcknowledgedIP = null.StringFrom(utils.ClientIP(r))
+
+	receipt, err := utils.GenerateHandoverReceipt(&handover)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "AcknowledgeHandover: cannot generate receipt.")
+		return
+	}
+
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		rows, ackErr := dbhelper.AcknowledgeHandover(r.Context(), tx, &handover, receipt)
+		if ackErr != nil {
+			return ackErr
+		}
+		if rows == 0 {
+			return errAlreadyAcknowledged
+		}
+		return nil
+	})
+	if txErr != nil {
+		if errors.Is(txErr, errAlreadyAcknowledged) {
+			utils.RespondAppError(w, apperr.New(apperr.AlreadyAcknowledged, "handover is already acknowledged.", txErr))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, txErr, "AcknowledgeHandover: cannot acknowledge handover.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Handover acknowledged.",
+	})
+}
+
+func respondHandoverReceipt(w http.ResponseWriter, r *http.Request, handoverID, employeeID string) {
+	receipt, err := dbhelper.GetHandoverReceipt(r.Context(), handoverID, employeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.HandoverNotFound, "receipt not found, the handover may not be acknowledged yet.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetHandoverReceipt: cannot get receipt.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", "attachment; filename=handover-"+handoverID+".pdf")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(receipt); err != nil {
+		logging.FromContext(r.Context()).Errorf("GetHandoverReceipt: failed to write receipt with error: %+v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// readinessTimeout bounds the checks of a readiness probe, probes usually give up after a few seconds
+const readinessTimeout = 2 * time.Second
+
+func Health(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{Status: "server is running!"})
+}
+
+// Liveness answers as long as the server serves requests, it does not depend on the database so that an outage of the
+// database does not get the server restarted
+func Liveness(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{Status: "alive"})
+}
+
+// Readiness answers 503 unless the database answers and is migrated at least to the last migration the server knows,
+// so that no traffic is sent to a server that cannot serve it. A database migrated further by a newer server during a
+// rollout keeps older servers ready
+func Readiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	if message, err := checkReadiness(ctx); err != nil {
+		utils.RespondError(w, http.StatusServiceUnavailable, err, "not ready: "+message+".")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+	}{Status: "ready"})
+}
+
+// checkReadiness returns what keeps the server from being ready, the message is sent to the caller and the error is
+// only logged as it can tell where the database is
+func checkReadiness(ctx context.Context) (string, error) {
+	if database.AssetManagement == nil {
+		return "database is not connected", errors.New("no database connection")
+	}
+	if err := database.AssetManagement.PingContext(ctx); err != nil {
+		return "database is not reachable", err
+	}
+
+	version, dirty, err := database.MigrationVersion(ctx, database.AssetManagement)
+	if err != nil {
+		return "cannot read the migration version", err
+	}
+	latest, err := database.LatestMigration()
+	if err != nil {
+		return "cannot read the migrations", err
+	}
+	switch {
+	case dirty:
+		message := fmt.Sprintf("migration %d failed half way", version)
+		return message, errors.New(message)
+	case version < latest:
+		message := fmt.Sprintf("database is at migration %d, want %d", version, latest)
+		return message, errors.New(message)
+	}
+	return "", nil
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/hrsync"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/volatiletech/null"
+)
+
+const (
+	provisioningTokenBytes = 32
+	maxCSVUploadSize       = 10 << 20
+)
+
+// CreateProvisioningToken issues a token for an identity provider, the token is only shown in this response
+func CreateProvisioningToken(w http.ResponseWriter, r *http.Request) {
+	body := models.ProvisioningToken{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	token, err := utils.GenerateToken(provisioningTokenBytes)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateProvisioningToken: cannot generate token.")
+		return
+	}
+
+	tokenID, err := dbhelper.CreateProvisioningToken(r.Context(), body.Name, utils.HashString(token), userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateProvisioningToken: cannot create provisioning token.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg   string `json:"msg"`
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}{
+		Msg:   "Provisioning token created, it will not be shown again.",
+		ID:    tokenID,
+		Token: token,
+	})
+}
+
+func GetProvisioningTokens(w http.ResponseWriter, r *http.Request) {
+	tokens, err := dbhelper.GetProvisioningTokens(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetProvisioningTokens: cannot get provisioning tokens.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, tokens)
+}
+
+func RevokeProvisioningToken(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.RevokeProvisioningToken(r.Context(), chi.URLParam(r, "tokenID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RevokeProvisioningToken: cannot revoke provisioning token.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.TokenNotFound, "token not found or already revoked.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Provisioning token revoked.",
+	})
+}
+
+// PreviewCSVSync shows what an uploaded HR export would change without applying it, the preview is kept as a dry run
+func PreviewCSVSync(w http.ResponseWriter, r *http.Request) {
+	syncUploadedCSV(w, r, true)
+}
+
+func ApplyCSVSync(w http.ResponseWriter, r *http.Request) {
+	syncUploadedCSV(w, r, false)
+}
+
+// syncUploadedCSV runs the multipart "file" upload through the HR sync. Employees missing from the file are
+// deactivated unless deactivateMissing=false is passed for partial exports
+func syncUploadedCSV(w http.ResponseWriter, r *http.Request, dryRun bool) {
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxCSVUploadSize); err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "failed to parse upload.")
+		return
+	}
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "a CSV file is required.")
+		return
+	}
+	defer file.Close()
+
+	records, rejected, err := hrsync.ParseCSV(file)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "cannot read CSV: "+err.Error())
+		return
+	}
+
+	run, err := hrsync.Run(r.Context(), records, rejected, hrsync.Options{
+		Source:            hrsync.SourceCSV,
+		FileName:          null.StringFrom(fileHeader.Filename),
+		DryRun:            dryRun,
+		DeactivateMissing: r.URL.Query().Get("deactivateMissing") != "false",
+		TriggeredBy:       null.StringFrom(userID),
+	})
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "SyncCSV: cannot sync employees.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, run)
+}
+
+func GetSyncRuns(w http.ResponseWriter, r *http.Request) {
+	filters, err := utils.Filters(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid filters.")
+		return
+	}
+
+	runs, err := dbhelper.GetSyncRuns(r.Context(), r.URL.Query().Get("source"), filters.Limit, filters.Page)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetSyncRuns: cannot get sync runs.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, runs)
+}
+
+// GetSyncRun returns a sync run with every change it made or, for a preview, would have made
+func GetSyncRun(w http.ResponseWriter, r *http.Request) {
+	run, err := dbhelper.GetSyncRun(r.Context(), chi.URLParam(r, "runID"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.SyncRunNotFound, "sync run not found.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetSyncRun: cannot get sync run.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, run)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+func GetOverdueLoans(w http.ResponseWriter, r *http.Request) {
+	loans, err := dbhelper.GetOverdueLoans(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetOverdueLoans: cannot get overdue loans.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, loans)
+}
+
+func RequestLoanExtension(w http.ResponseWriter, r *http.Request) {
+	body := models.LoanExtensionRequest{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	relationID, dueDate, err := dbhelper.GetOpenLoanByAssetID(r.Context(), body.AssetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusBadRequest, err, "asset is not currently on loan.")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "RequestLoanExtension: cannot get loan.")
+		return
+	}
+	if !body.RequestedDueDate.After(dueDate) {
+		utils.RespondError(w, http.StatusBadRequest, nil, "requested due date must be after the current due date.")
+		return
+	}
+
+	extensionID, err := dbhelper.CreateLoanExtensionRequest(r.Context(), relationID, &body, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.RespondAppError(w, apperr.New(apperr.AlreadyExists, "an extension of this loan is already pending.", err))
+		return
+	}
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RequestLoanExtension: cannot create extension request.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Extension requested.",
+		ID:  extensionID,
+	})
+}
+
+func GetLoanExtensions(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+
+	extensions, err := dbhelper.GetLoanExtensions(r.Context(), status)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetLoanExtensions: cannot get loan extensions.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, extensions)
+}
+
+func DecideLoanExtension(w http.ResponseWriter, r *http.Request) {
+	body := models.LoanExtensionDecision{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		return dbhelper.DecideLoanExtension(r.Context(), tx, &body, userID)
+	})
+	if txErr != nil {
+		if errors.Is(txErr, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusBadRequest, txErr, "extension request is not pending.")
+			return
+		}
+		if errors.Is(txErr, dbhelper.ErrLoanClosed) {
+			utils.RespondAppError(w, apperr.New(apperr.InvalidState, "the loan was already returned or archived.", txErr))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, txErr, "DecideLoanExtension: cannot update extension request.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Extension " + body.Status + ".",
+	})
+}
+
+func GetLoanStats(w http.ResponseWriter, r *http.Request) {
+	employeeID := r.URL.Query().Get("employeeId")
+
+	stats, err := dbhelper.GetLoanStats(r.Context(), employeeID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetLoanStats: cannot get loan stats.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, stats)
+}
+
+// checkAssignmentType defaults the assignment to permanent and makes sure loans carry a due date which is not in the past
+func checkAssignmentType(assignmentType *string, dueDate *null.Time) error {
+	if *assignmentType == "" {
+		*assignmentType = utils.AssignmentPermanent
+	}
+	if *assignmentType == utils.AssignmentPermanent {
+		*dueDate = null.Time{}
+		return nil
+	}
+
+	if !dueDate.Valid {
+		return errors.New("due date is required for a loan")
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+	if dueDate.Time.Before(today) {
+		return errors.New("due date cannot be in the past")
+	}
+	return nil
+}
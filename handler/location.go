@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+)
+
+func CreateLocation(w http.ResponseWriter, r *http.Request) {
+	body := models.LocationDetails{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	locationID, err := dbhelper.CreateLocation(r.Context(), &body, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateLocation: cannot create location.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Location created.",
+		ID:  locationID,
+	})
+}
+
+func GetLocations(w http.ResponseWriter, r *http.Request) {
+	locationType := r.URL.Query().Get("type")
+
+	locations, err := dbhelper.GetLocations(r.Context(), locationType)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetLocations: cannot get locations.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, locations)
+}
+
+func UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	body := models.LocationDetails{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+	if body.ID == "" {
+		utils.RespondAppError(w, apperr.New(apperr.InvalidInput, "id is required.", nil))
+		return
+	}
+
+	if body.ParentID.Valid {
+		isCycle, err := dbhelper.IsLocationInSubtree(r.Context(), body.ID, body.ParentID.String)
+		if err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "UpdateLocation: cannot check parent location.")
+			return
+		}
+		if isCycle {
+			utils.RespondError(w, http.StatusBadRequest, nil, "location cannot be placed inside itself.")
+			return
+		}
+	}
+
+	err := dbhelper.UpdateLocation(r.Context(), &body)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.RespondAppError(w, apperr.New(apperr.LocationNotFound, "location not found.", err))
+		return
+	}
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateLocation: cannot update location.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Location updated.",
+	})
+}
+
+func DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	locationID := chi.URLParam(r, "locationID")
+
+	count, err := dbhelper.GetLocationUsage(r.Context(), locationID)
+	switch {
+	case err != nil && count < 0:
+		utils.RespondError(w, http.StatusInternalServerError, err, "cannot check if location is in use.")
+		return
+	case count > 0:
+		utils.RespondAppError(w, apperr.New(apperr.InUse, "Cannot delete: location still holds assets or other locations.", err))
+		return
+	}
+
+	err = dbhelper.DeleteLocation(r.Context(), locationID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "Failed to delete location.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Location deleted successfully.",
+	})
+}
+
+func TransferAsset(w http.ResponseWriter, r *http.Request) {
+	body := models.AssetTransfer{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		transferable, err := dbhelper.IsAssetTransferable(r.Context(), tx, body.AssetID)
+		if err != nil {
+			return err
+		}
+		if !transferable {
+			return apperr.New(apperr.AssetAlreadyAssigned, "asset is assigned or reserved, retrieve it or cancel the reservation first.", nil)
+		}
+		if body.ToLocationID.Valid {
+			exists, err := dbhelper.IsStorageLocation(r.Context(), tx, body.ToLocationID.String)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return apperr.New(apperr.LocationNotFound, "location not found.", nil)
+			}
+		}
+		return dbhelper.TransferAsset(r.Context(), tx, &body, userID)
+	})
+	if txErr != nil {
+		if errors.Is(txErr, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", txErr))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, txErr, "TransferAsset: cannot transfer asset.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Asset transferred successfully.",
+	})
+}
+
+func AssetTransfers(w http.ResponseWriter, r *http.Request) {
+	assetID := r.URL.Query().Get("assetId")
+
+	transfers, err := dbhelper.AssetTransfers(r.Context(), assetID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "AssetTransfers: cannot get asset transfers.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, transfers)
+}
+
+func GetLocationDashboard(w http.ResponseWriter, r *http.Request) {
+	quantities, err := dbhelper.GetLocationQuantities(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "failed to get location quantities.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, quantities)
+}
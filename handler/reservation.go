@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+const (
+	dateFormat = "2006-01-02"
+	// calendarHistoryDays keeps recently ended reservations in the calendar feed
+	calendarHistoryDays = 30
+)
+
+var (
+	errReservationConflict = errors.New("reservation conflicts with existing reservations or assignments")
+	errReservationMismatch = errors.New("asset does not match the reservation")
+)
+
+func CreateReservation(w http.ResponseWriter, r *http.Request) {
+	body := models.Reservation{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+	if !body.AssetID.Valid && (!body.Brand.Valid || !body.Model.Valid || body.AssetType == "") {
+		utils.RespondError(w, http.StatusBadRequest, nil, "either assetId or assetType, brand and model are required.")
+		return
+	}
+	if body.StartDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		utils.RespondError(w, http.StatusBadRequest, nil, "startDate cannot be in the past.")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	var reservationID string
+	var conflicts []models.ReservationConflict
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		if body.AssetID.Valid {
+			if err := dbhelper.GetReservationAsset(r.Context(), tx, &body); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		conflicts, err = dbhelper.GetReservationConflicts(r.Context(), tx, &body, "")
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return errReservationConflict
+		}
+
+		reservationID, err = dbhelper.CreateReservation(r.Context(), tx, &body, userID)
+		return err
+	})
+	if txErr != nil {
+		switch {
+		case errors.Is(txErr, sql.ErrNoRows):
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", txErr))
+		case errors.Is(txErr, errReservationConflict):
+			conflictErr := apperr.New(apperr.AssetReserved, "Reservation conflicts with existing reservations or assignments.", txErr)
+			conflictErr.Details = struct {
+				Conflicts []models.ReservationConflict `json:"conflicts"`
+			}{Conflicts: conflicts}
+			utils.RespondAppError(w, conflictErr)
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, txErr, "CreateReservation: cannot create reservation.")
+		}
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg string `json:"msg"`
+		ID  string `json:"id"`
+	}{
+		Msg: "Reservation created.",
+		ID:  reservationID,
+	})
+}
+
+func GetReservations(w http.ResponseWriter, r *http.Request) {
+	assetID := r.URL.Query().Get("assetId")
+	status := r.URL.Query().Get("status")
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	reservations, err := dbhelper.GetReservations(r.Context(), assetID, status, from, to)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetReservations: cannot get reservations.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, reservations)
+}
+
+func CancelReservation(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.CancelReservation(r.Context(), chi.URLParam(r, "reservationID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CancelReservation: cannot cancel reservation.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.ReservationNotFound, "reservation not found or no longer active.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Reservation cancelled.",
+	})
+}
+
+// ConvertReservation assigns the reserved asset, or the chosen unit of a model reservation, to the employee
+func ConvertReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID := chi.URLParam(r, "reservationID")
+
+	body := models.ConvertReservation{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	if loanErr := checkAssignmentType(&body.AssignmentType, &body.DueDate); loanErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, loanErr, loanErr.Error())
+		return
+	}
+	if body.AssignedDate.IsZero() {
+		body.AssignedDate = time.Now()
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	txErr := database.Tx(r.Context(), func(tx *sqlx.Tx) error {
+		reservation, err := dbhelper.GetActiveReservation(r.Context(), tx, reservationID)
+		if err != nil {
+			return err
+		}
+
+		relation := models.EmployeeAssetRelation{
+			EmployeeID:     reservation.EmployeeID.String,
+			AssetID:        reservation.AssetID.String,
+			AssignedDate:   body.AssignedDate,
+			AssignmentType: body.AssignmentType,
+			DueDate:        body.DueDate,
+		}
+		if body.EmployeeID != "" {
+			relation.EmployeeID = body.EmployeeID
+		}
+		if body.AssetID != "" {
+			if reservation.AssetID.Valid && reservation.AssetID.String != body.AssetID {
+				return errReservationMismatch
+			}
+			relation.AssetID = body.AssetID
+		}
+		if relation.EmployeeID == "" || relation.AssetID == "" {
+			return errReservationMismatch
+		}
+		if !reservation.AssetID.Valid {
+			matches, matchErr := dbhelper.IsAssetOfModel(r.Context(), tx, relation.AssetID, reservation.Brand.String, reservation.Model.String)
+			if matchErr != nil {
+				return matchErr
+			}
+			if !matches {
+				return errReservationMismatch
+			}
+		}
+
+		relationID, err := repository.AssignAsset(r.Context(), tx, &relation, userID, reservationID)
+		if err != nil {
+			return err
+		}
+
+		return dbhelper.ConvertReservation(r.Context(), tx, reservationID, relationID)
+	})
+	if txErr != nil {
+		switch {
+		case errors.Is(txErr, sql.ErrNoRows):
+			utils.RespondAppError(w, apperr.New(apperr.ReservationNotFound, "reservation not found or no longer active.", txErr))
+		case errors.Is(txErr, errReservationMismatch):
+			utils.RespondError(w, http.StatusBadRequest, txErr, "an employee and an asset matching the reservation are required.")
+		case errors.Is(txErr, repository.ErrAssetNotFound):
+			utils.RespondAppError(w, apperr.New(apperr.AssetNotFound, "asset not found.", txErr))
+		case errors.Is(txErr, repository.ErrAssetAssigned):
+			utils.RespondAppError(w, apperr.New(apperr.AssetAlreadyAssigned, "asset is already assigned.", txErr))
+		case errors.Is(txErr, repository.ErrAssetReserved):
+			utils.RespondAppError(w, apperr.New(apperr.AssetReserved, "asset is reserved by another reservation for part of this period.", txErr))
+		default:
+			utils.RespondError(w, http.StatusInternalServerError, txErr, "ConvertReservation: cannot convert reservation.")
+		}
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Reservation converted to assignment.",
+	})
+}
+
+// GetReservationCalendar serves reservations and upcoming loan returns as an iCalendar feed
+func GetReservationCalendar(w http.ResponseWriter, r *http.Request) {
+	events, err := dbhelper.GetCalendarEvents(r.Context(), time.Now().AddDate(0, 0, -calendarHistoryDays))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetReservationCalendar: cannot get calendar events.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", "inline; filename=reservations.ics")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(utils.GenerateICS("Asset reservations", events)); err != nil {
+		logging.FromContext(r.Context()).Errorf("GetReservationCalendar: failed to write calendar with error: %+v", err)
+	}
+}
+
+// parseAvailabilityPeriod reads the optional from and to dates of an availability query, from defaults to today
+// and an empty to means the asset is needed indefinitely
+func parseAvailabilityPeriod(r *http.Request) (time.Time, null.Time, error) {
+	from := time.Now().Truncate(24 * time.Hour)
+	var to null.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, err := time.Parse(dateFormat, fromStr)
+		if err != nil {
+			return from, to, err
+		}
+		from = parsed
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		parsed, err := time.Parse(dateFormat, toStr)
+		if err != nil {
+			return from, to, err
+		}
+		to = null.TimeFrom(parsed)
+	}
+	return from, to, nil
+}
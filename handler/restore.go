@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"net/http"
+)
+
+// GetAssetRestores lists the restores of deleted assets, newest first, optionally of one asset
+func GetAssetRestores(w http.ResponseWriter, r *http.Request) {
+	filters, err := utils.Filters(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid filters.")
+		return
+	}
+
+	records, err := dbhelper.GetRestoreHistory(r.Context(), models.RestoreAssetEntity, r.URL.Query().Get("assetId"), filters.Limit, filters.Page)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetAssetRestores: cannot get restore history.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, records)
+}
+
+// GetEmployeeRestores lists the restores of deleted employees, newest first, optionally of one employee
+func GetEmployeeRestores(w http.ResponseWriter, r *http.Request) {
+	filters, err := utils.Filters(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid filters.")
+		return
+	}
+
+	records, err := dbhelper.GetRestoreHistory(r.Context(), models.RestoreEmployeeEntity, r.URL.Query().Get("employeeId"), filters.Limit, filters.Page)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetEmployeeRestores: cannot get restore history.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, records)
+}
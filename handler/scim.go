@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/hrsync"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/volatiletech/null"
+)
+
+const (
+	defaultScimCount = 100
+	maxScimCount     = 200
+)
+
+func GetScimUsers(w http.ResponseWriter, r *http.Request) {
+	var email, externalID string
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		attribute, value, err := hrsync.ParseFilter(filter)
+		if err != nil {
+			utils.RespondScimError(w, http.StatusBadRequest, err, "invalidFilter", err.Error())
+			return
+		}
+		if attribute == "username" {
+			email = value
+		} else {
+			externalID = value
+		}
+	}
+
+	startIndex, err := scimQueryInt(r, "startIndex", 1)
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := scimQueryInt(r, "count", defaultScimCount)
+	if err != nil || count < 0 {
+		count = defaultScimCount
+	}
+	if count > maxScimCount {
+		count = maxScimCount
+	}
+
+	employees, total, err := dbhelper.GetScimEmployees(r.Context(), email, externalID, startIndex, count)
+	if err != nil {
+		utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot get users.")
+		return
+	}
+
+	users := make([]models.ScimUser, 0, len(employees))
+	for i := range employees {
+		users = append(users, hrsync.ToScim(&employees[i], scimLocation(r, employees[i].ID)))
+	}
+	respondScim(w, http.StatusOK, models.ScimListResponse{
+		Schemas:      []string{hrsync.ScimListSchema},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(users),
+		Resources:    users,
+	})
+}
+
+func GetScimUser(w http.ResponseWriter, r *http.Request) {
+	employee, ok := scimEmployee(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+
+	respondScim(w, http.StatusOK, hrsync.ToScim(&employee, scimLocation(r, employee.ID)))
+}
+
+func CreateScimUser(w http.ResponseWriter, r *http.Request) {
+	body := models.ScimUser{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondScimError(w, http.StatusBadRequest, parseErr, "invalidSyntax", "failed to parse request body.")
+		return
+	}
+	body.ID = ""
+	if body.UserName == "" {
+		utils.RespondScimError(w, http.StatusBadRequest, nil, "invalidValue", "userName is required.")
+		return
+	}
+
+	record := hrsync.FromScim(&body, scimManagerEmail(r.Context()))
+	existing, _, err := dbhelper.GetScimEmployees(r.Context(), record.Email, "", 1, 1)
+	if err == nil && len(existing) == 0 && body.ExternalID != "" {
+		existing, _, err = dbhelper.GetScimEmployees(r.Context(), "", body.ExternalID, 1, 1)
+	}
+	if err != nil {
+		utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot check existing users.")
+		return
+	}
+	if len(existing) > 0 {
+		utils.RespondScimError(w, http.StatusConflict, nil, "uniqueness", "a user with this userName or externalId already exists.")
+		return
+	}
+
+	syncScimUser(w, r, record, http.StatusCreated)
+}
+
+// ReplaceScimUser applies a full SCIM user, attributes left out keep their current value
+func ReplaceScimUser(w http.ResponseWriter, r *http.Request) {
+	employee, ok := scimEmployee(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+
+	body := models.ScimUser{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondScimError(w, http.StatusBadRequest, parseErr, "invalidSyntax", "failed to parse request body.")
+		return
+	}
+	body.ID = employee.ID
+	if body.UserName == "" {
+		utils.RespondScimError(w, http.StatusBadRequest, nil, "invalidValue", "userName is required.")
+		return
+	}
+	// a replacement without active keeps the status, it must not bring back a deactivated employee
+	if body.Active == nil {
+		active := employee.Status == utils.Active
+		body.Active = &active
+	}
+
+	syncScimUser(w, r, hrsync.FromScim(&body, scimManagerEmail(r.Context())), http.StatusOK)
+}
+
+func PatchScimUser(w http.ResponseWriter, r *http.Request) {
+	employee, ok := scimEmployee(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+
+	body := models.ScimPatchOp{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondScimError(w, http.StatusBadRequest, parseErr, "invalidSyntax", "failed to parse request body.")
+		return
+	}
+
+	user := hrsync.ToScim(&employee, "")
+	if err := hrsync.ApplyPatch(&user, body.Operations); err != nil {
+		utils.RespondScimError(w, http.StatusBadRequest, err, "invalidValue", err.Error())
+		return
+	}
+
+	syncScimUser(w, r, hrsync.FromScim(&user, scimManagerEmail(r.Context())), http.StatusOK)
+}
+
+// DeleteScimUser deactivates the employee to not_an_employee, the record and its asset history are kept
+func DeleteScimUser(w http.ResponseWriter, r *http.Request) {
+	employee, ok := scimEmployee(w, r, chi.URLParam(r, "userID"))
+	if !ok {
+		return
+	}
+
+	employee.Status = utils.NotAnEmployee
+	if _, err := runScimSync(r, employee); err != nil {
+		utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot deactivate user.")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func GetScimServiceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	unsupported := map[string]bool{"supported": false}
+	respondScim(w, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{hrsync.ScimConfigSchema},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": maxScimCount},
+		"changePassword": unsupported,
+		"sort":           unsupported,
+		"etag":           unsupported,
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "Provisioning token",
+			"description": "Bearer token issued by an asset management admin.",
+			"primary":     true,
+		}},
+	})
+}
+
+// syncScimUser runs a single user through the HR sync so that SCIM changes are logged like CSV imports
+func syncScimUser(w http.ResponseWriter, r *http.Request, record models.SyncEmployee, status int) {
+	run, err := runScimSync(r, record)
+	if err != nil {
+		utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot sync user.")
+		return
+	}
+
+	employeeID := record.ID
+	for i := range run.Changes {
+		if run.Changes[i].Action == hrsync.ActionError {
+			utils.RespondScimError(w, http.StatusBadRequest, nil, "invalidValue", run.Changes[i].Message)
+			return
+		}
+		if run.Changes[i].EmployeeID.Valid {
+			employeeID = run.Changes[i].EmployeeID.String
+		}
+	}
+	if employeeID == "" {
+		utils.RespondScimError(w, http.StatusBadRequest, nil, "invalidValue", "inactive users are not provisioned.")
+		return
+	}
+
+	employee, ok := scimEmployee(w, r, employeeID)
+	if !ok {
+		return
+	}
+	respondScim(w, status, hrsync.ToScim(&employee, scimLocation(r, employee.ID)))
+}
+
+func runScimSync(r *http.Request, record models.SyncEmployee) (models.SyncRun, error) {
+	tokenID, err := utils.TokenContext(r)
+	if err != nil {
+		return models.SyncRun{}, err
+	}
+	return hrsync.Run(r.Context(), []models.SyncEmployee{record}, nil, hrsync.Options{
+		Source:  hrsync.SourceScim,
+		TokenID: null.StringFrom(tokenID),
+	})
+}
+
+func scimEmployee(w http.ResponseWriter, r *http.Request, employeeID string) (models.SyncEmployee, bool) {
+	var employee models.SyncEmployee
+	// SCIM ids are employee UUIDs, anything else cannot name a user
+	if _, err := uuid.Parse(employeeID); err != nil {
+		utils.RespondScimError(w, http.StatusNotFound, err, "", "user not found.")
+		return employee, false
+	}
+
+	employee, err := dbhelper.GetSyncEmployee(r.Context(), employeeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondScimError(w, http.StatusNotFound, err, "", "user not found.")
+			return employee, false
+		}
+		utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot get user.")
+		return employee, false
+	}
+	return employee, true
+}
+
+// scimManagerEmail resolves the SCIM id of a manager to their email, unknown managers are left unlinked
+func scimManagerEmail(ctx context.Context) func(managerID string) string {
+	return func(managerID string) string {
+		manager, err := dbhelper.GetSyncEmployee(ctx, managerID)
+		if err != nil {
+			return ""
+		}
+		return manager.Email
+	}
+}
+
+// scimLocation builds the absolute URL of a user from the request, behind trusted proxies X-Forwarded-Proto decides the
+// scheme
+func scimLocation(r *http.Request, employeeID string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if utils.TrustProxyHeaders() {
+		switch proto := strings.ToLower(r.Header.Get("X-Forwarded-Proto")); proto {
+		case "http", "https":
+			scheme = proto
+		}
+	}
+	base := r.URL.Path
+	if i := strings.Index(base, "/Users"); i >= 0 {
+		base = base[:i]
+	}
+	return fmt.Sprintf("%s://%s%s/Users/%s", scheme, r.Host, base, employeeID)
+}
+
+func scimQueryInt(r *http.Request, key string, fallback int) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func respondScim(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", utils.ScimContentType)
+	utils.RespondJSON(w, statusCode, body)
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"InternalAssetManagement/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScimUserIDMustBeUUID(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		http.MethodGet:    GetScimUser,
+		http.MethodDelete: DeleteScimUser,
+	}
+	for method, h := range handlers {
+		w := serve(t, h, request{method: method, target: "/", params: map[string]string{"userID": "not-a-uuid"}})
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("%s got status %d, want %d: %s", method, w.Code, http.StatusNotFound, w.Body.String())
+		}
+		var scimErr models.ScimError
+		if err := json.NewDecoder(w.Body).Decode(&scimErr); err != nil {
+			t.Fatal(err)
+		}
+		if scimErr.Status != "404" {
+			t.Fatalf("%s got SCIM status %s, want 404", method, scimErr.Status)
+		}
+	}
+}
+
+func TestScimLocation(t *testing.T) {
+	cases := []struct {
+		name  string
+		trust string
+		proto string
+		want  string
+	}{
+		{"proxy headers not trusted", "", "https", "http://example.com/scim/v2/Users/e1"},
+		{"trusted proxy", "true", "https", "https://example.com/scim/v2/Users/e1"},
+		{"unknown scheme", "true", "javascript", "http://example.com/scim/v2/Users/e1"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("TRUST_PROXY_HEADERS", c.trust)
+			r := httptest.NewRequest(http.MethodGet, "http://example.com/scim/v2/Users", nil)
+			r.Header.Set("X-Forwarded-Proto", c.proto)
+			if got := scimLocation(r, "e1"); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"InternalAssetManagement/repository"
+)
+
+// Service serves the asset, employee and account routes from the repositories it is given, Postgres in the application
+// and memory in tests
+type Service struct {
+	Assets    repository.AssetRepository
+	Employees repository.EmployeeRepository
+	Users     repository.UserRepository
+	Sessions  repository.SessionRepository
+}
+
+func NewService(store *repository.Store) *Service {
+	return &Service{
+		Assets:    store.Assets,
+		Employees: store.Employees,
+		Users:     store.Users,
+		Sessions:  store.Sessions,
+	}
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const adminID = "7d4f6c1e-2a3b-4c5d-8e9f-0a1b2c3d4e5f"
+
+var (
+	assignedOn = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	purchased  = time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func newService() *Service {
+	return NewService(repository.NewMemory())
+}
+
+// request is one call of a handler as the signed in user, adminID unless user is set, params are the URL parameters
+// chi would have routed
+type request struct {
+	method string
+	target string
+	body   interface{}
+	params map[string]string
+	user   string
+}
+
+func serve(t *testing.T, h http.HandlerFunc, req request) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	if req.body != nil {
+		if err := json.NewEncoder(&body).Encode(req.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := httptest.NewRequest(req.method, req.target, &body)
+
+	routeCtx := chi.NewRouteContext()
+	for key, value := range req.params {
+		routeCtx.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, routeCtx)
+	user := adminID
+	if req.user != "" {
+		user = req.user
+	}
+	ctx = context.WithValue(ctx, utils.UserContextKey, user)
+
+	w := httptest.NewRecorder()
+	h(w, r.WithContext(ctx))
+	return w
+}
+
+// expect checks the status of a response and decodes its body into out when out is not nil
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int, out interface{}) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("got status %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("cannot decode %s: %v", w.Body.String(), err)
+		}
+	}
+}
+
+// expectError checks the status and code of an error response
+func expectError(t *testing.T, w *httptest.ResponseRecorder, status int, code string) {
+	t.Helper()
+	var clientErr utils.ClientError
+	expect(t, w, status, &clientErr)
+	if string(clientErr.Code) != code {
+		t.Fatalf("got error code %s, want %s: %s", clientErr.Code, code, w.Body.String())
+	}
+}
+
+func createEmployee(t *testing.T, s *Service, name, email string) string {
+	t.Helper()
+	expect(t, serve(t, s.CreateEmployee, request{method: http.MethodPost, target: "/", body: models.EmployeeDetails{
+		Name:    name,
+		Type:    "employee",
+		Email:   email,
+		PhoneNo: "9876543210",
+	}}), http.StatusOK, nil)
+
+	var employees models.TotalGetEmployee
+	expect(t, serve(t, s.GetEmployeeList, request{method: http.MethodGet, target: "/?name=" + name}), http.StatusOK, &employees)
+	if len(employees.GetEmployee) != 1 {
+		t.Fatalf("got %d employees named %s, want 1", len(employees.GetEmployee), name)
+	}
+	return employees.GetEmployee[0].ID
+}
+
+// createLaptop creates a laptop and returns its id and asset tag
+func createLaptop(t *testing.T, s *Service, brand string) (string, string) {
+	t.Helper()
+	var created struct {
+		AssetTag string `json:"assetTag"`
+	}
+	expect(t, serve(t, s.CreateAsset, request{method: http.MethodPost, target: "/", body: models.CreateAsset{
+		Brand:              brand,
+		Model:              "XPS 13",
+		SerialNo:           "SN-" + brand,
+		AssetType:          models.Laptop,
+		PurchasedDate:      purchased,
+		WarrantyStartDate:  purchased,
+		WarrantyExpiryDate: purchased.AddDate(3, 0, 0),
+		RAM:                "16GB",
+		OwnedBy:            utils.RemoteState,
+	}}), http.StatusOK, &created)
+
+	var assets models.TotalGetAsset
+	expect(t, serve(t, s.GetAssetList, request{method: http.MethodGet, target: "/?name=" + brand}), http.StatusOK, &assets)
+	if len(assets.GetAsset) != 1 {
+		t.Fatalf("got %d assets of brand %s, want 1", len(assets.GetAsset), brand)
+	}
+	return assets.GetAsset[0].ID, created.AssetTag
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/oidc"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+// loginStateMaxAge is how long a user has at the identity provider to complete a sign in
+const loginStateMaxAge = 10 * time.Minute
+
+var (
+	ssoProvider = oidc.NewFromEnv()
+	ssoPolicy   = oidc.PolicyFromEnv(utils.RoleViewer)
+	// ssoRoles orders the admin roles from most to least privileged
+	ssoRoles = []string{utils.RoleAdmin, utils.RoleViewer}
+)
+
+// passwordLoginDisabled reports if PASSWORD_LOGIN_DISABLED turns off the password login in favour of single sign-on
+func passwordLoginDisabled() bool {
+	return ssoProvider != nil && strings.EqualFold(os.Getenv("PASSWORD_LOGIN_DISABLED"), "true")
+}
+
+func GetLoginOptions(w http.ResponseWriter, r *http.Request) {
+	utils.RespondJSON(w, http.StatusOK, struct {
+		SSO      bool `json:"sso"`
+		Password bool `json:"password"`
+	}{
+		SSO:      ssoProvider != nil,
+		Password: !passwordLoginDisabled(),
+	})
+}
+
+// SSOLogin starts an authorization code login with PKCE by redirecting to the identity provider
+func SSOLogin(w http.ResponseWriter, r *http.Request) {
+	if ssoProvider == nil {
+		utils.RespondAppError(w, apperr.New(apperr.SSONotConfigured, "single sign-on is not configured.", nil))
+		return
+	}
+
+	state := models.LoginState{}
+	var err error
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		if *value, err = oidc.RandomString(); err != nil {
+			utils.RespondError(w, http.StatusInternalServerError, err, "SSOLogin: cannot generate login state.")
+			return
+		}
+	}
+
+	if err = dbhelper.CreateLoginState(r.Context(), &state, loginStateMaxAge); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "SSOLogin: cannot save login state.")
+		return
+	}
+
+	authURL, err := ssoProvider.AuthCodeURL(r.Context(), state.State, state.Nonce, oidc.CodeChallenge(state.CodeVerifier))
+	if err != nil {
+		utils.RespondError(w, http.StatusBadGateway, err, "SSOLogin: cannot reach identity provider.")
+		return
+	}
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// SSOCallback completes the login, provisioning the user on their first sign in. The session token is passed to
+// OIDC_POST_LOGIN_REDIRECT in the URL fragment when set, otherwise it is returned like the password login
+func SSOCallback(w http.ResponseWriter, r *http.Request) {
+	if ssoProvider == nil {
+		utils.RespondAppError(w, apperr.New(apperr.SSONotConfigured, "single sign-on is not configured.", nil))
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		utils.RespondError(w, http.StatusUnauthorized, errors.New(providerErr), "sign in was cancelled or refused: "+query.Get("error_description"))
+		return
+	}
+
+	state, err := dbhelper.ConsumeLoginState(r.Context(), query.Get("state"), loginStateMaxAge)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondError(w, http.StatusBadRequest, err, "sign in expired or was already used, please try again.")
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "SSOCallback: cannot get login state.")
+		return
+	}
+
+	rawIDToken, err := ssoProvider.Exchange(r.Context(), query.Get("code"), state.CodeVerifier)
+	if err != nil {
+		utils.RespondError(w, http.StatusUnauthorized, err, "SSOCallback: cannot redeem authorization code.")
+		return
+	}
+	identity, err := ssoProvider.VerifyIDToken(r.Context(), rawIDToken, state.Nonce, ssoPolicy.GroupsClaim)
+	if err != nil {
+		utils.RespondAppError(w, apperr.New(apperr.TokenInvalid, "SSOCallback: invalid id token.", err))
+		return
+	}
+
+	// users are matched by email until they are linked, an email the provider has not verified could be anybody's
+	if !ssoPolicy.AllowsIdentity(&identity) {
+		utils.RespondAppError(w, apperr.New(apperr.EmailNotAuthorized, "non-authorized email.", nil))
+		return
+	}
+	role, ok := ssoPolicy.Role(identity.Groups, ssoRoles)
+	if !ok {
+		utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "you are not in a group that may use asset management.", nil))
+		return
+	}
+
+	userID, role, err := provisionSSOUser(r.Context(), &identity, role)
+	if err != nil {
+		if errors.Is(err, errBlockedUser) {
+			utils.RespondAppError(w, apperr.New(apperr.AccountBlocked, "Blocked email.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "SSOCallback: cannot provision user.")
+		return
+	}
+
+	claims := &models.Claims{
+		ID:   userID,
+		Role: role,
+	}
+	tokenString, err := signToken(claims)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "SSOCallback: cannot create tokenString.")
+		return
+	}
+	if err = dbhelper.CreateSession(r.Context(), claims); err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "SSOCallback: cannot create session.")
+		return
+	}
+
+	if redirect := os.Getenv("OIDC_POST_LOGIN_REDIRECT"); redirect != "" {
+		http.Redirect(w, r, redirect+"#token="+tokenString, http.StatusFound)
+		return
+	}
+	utils.RespondJSON(w, http.StatusOK, map[string]interface{}{
+		"token": tokenString,
+	})
+}
+
+var errBlockedUser = errors.New("user is blocked")
+
+// provisionSSOUser returns the local user of an identity, creating it just in time. With a role mapping configured the
+// identity provider decides the role on every sign in, otherwise existing users keep the role given to them locally
+func provisionSSOUser(ctx context.Context, identity *oidc.Identity, role string) (string, string, error) {
+	user, err := dbhelper.GetSSOUser(ctx, identity.Subject, identity.Email)
+	if errors.Is(err, sql.ErrNoRows) {
+		name := identity.Name
+		if name == "" {
+			name = identity.Email
+		}
+		userID, createErr := dbhelper.CreateSSOUser(ctx, name, identity.Email, identity.Subject, role)
+		return userID, role, createErr
+	}
+	if err != nil {
+		return "", "", err
+	}
+	if user.Type == utils.Blocked {
+		return "", "", errBlockedUser
+	}
+
+	var mappedRole null.String
+	if len(ssoPolicy.RoleMapping) > 0 {
+		mappedRole = null.StringFrom(role)
+	} else {
+		role = user.Role
+	}
+	return user.ID, role, dbhelper.LinkSSOUser(ctx, user.ID, identity.Subject, mappedRole)
+}
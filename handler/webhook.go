@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const webhookSecretBytes = 32
+
+// CreateWebhookSubscription subscribes a URL to event types, the signing secret is only shown in this response
+func CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	body := models.CreateWebhookSubscription{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	userID, userErr := utils.UserContext(r)
+	if userErr != nil {
+		utils.RespondError(w, http.StatusInternalServerError, userErr, "cannot get user details.")
+		return
+	}
+
+	secret, err := utils.GenerateToken(webhookSecretBytes)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateWebhookSubscription: cannot generate secret.")
+		return
+	}
+
+	subscriptionID, err := dbhelper.CreateWebhookSubscription(r.Context(), &body, secret, userID)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "CreateWebhookSubscription: cannot create webhook subscription.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, struct {
+		Msg    string `json:"msg"`
+		ID     string `json:"id"`
+		Secret string `json:"secret"`
+	}{
+		Msg:    "Webhook subscription created, the secret will not be shown again.",
+		ID:     subscriptionID,
+		Secret: secret,
+	})
+}
+
+func GetWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := dbhelper.GetWebhookSubscriptions(r.Context())
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetWebhookSubscriptions: cannot get webhook subscriptions.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, subscriptions)
+}
+
+func UpdateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	body := models.UpdateWebhookSubscription{}
+	if parseErr := utils.ParseBody(r.Body, &body); parseErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, parseErr, "failed to parse request body.")
+		return
+	}
+
+	validationErr := validate.Struct(body)
+	if validationErr != nil {
+		utils.RespondError(w, http.StatusBadRequest, validationErr, "validation error")
+		return
+	}
+
+	rows, err := dbhelper.UpdateWebhookSubscription(r.Context(), chi.URLParam(r, "subscriptionID"), &body)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "UpdateWebhookSubscription: cannot update webhook subscription.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.WebhookNotFound, "webhook subscription not found.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Webhook subscription updated successfully.",
+	})
+}
+
+func DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.ArchiveWebhookSubscription(r.Context(), chi.URLParam(r, "subscriptionID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "DeleteWebhookSubscription: cannot delete webhook subscription.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.WebhookNotFound, "webhook subscription not found.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Webhook subscription deleted successfully.",
+	})
+}
+
+// GetWebhookDeliveries lists deliveries newest first, filtered by subscriptionId, status and eventType
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	filters, err := utils.Filters(r)
+	if err != nil {
+		utils.RespondError(w, http.StatusBadRequest, err, "invalid filters.")
+		return
+	}
+
+	query := r.URL.Query()
+	deliveries, err := dbhelper.GetWebhookDeliveries(r.Context(), query.Get("subscriptionId"), query.Get("status"), query.Get("eventType"), filters.Limit, filters.Page)
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetWebhookDeliveries: cannot get webhook deliveries.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, deliveries)
+}
+
+// GetWebhookDelivery returns a delivery with its payload and attempt log
+func GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	delivery, err := dbhelper.GetWebhookDelivery(r.Context(), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.WebhookNotFound, "webhook delivery not found.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "GetWebhookDelivery: cannot get webhook delivery.")
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, delivery)
+}
+
+// RedeliverWebhook sends a delivery again on the next worker run, including dead-lettered and delivered ones
+func RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbhelper.RedeliverWebhook(r.Context(), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		utils.RespondError(w, http.StatusInternalServerError, err, "RedeliverWebhook: cannot queue redelivery.")
+		return
+	}
+	if rows == 0 {
+		utils.RespondAppError(w, apperr.New(apperr.WebhookNotFound, "webhook delivery not found or its subscription was deleted.", nil))
+		return
+	}
+
+	utils.RespondJSON(w, http.StatusOK, utils.ResponseMsg{
+		Msg: "Webhook redelivery queued.",
+	})
+}
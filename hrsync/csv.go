@@ -0,0 +1,110 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/volatiletech/null"
+)
+
+// csvColumns maps the accepted header names of an HR export onto the synced fields
+var csvColumns = map[string]string{
+	"external_id":   "external_id",
+	"employee_id":   "external_id",
+	"name":          "name",
+	"email":         "email",
+	"phone_no":      "phone_no",
+	"phone":         "phone_no",
+	"type":          "type",
+	"status":        "status",
+	"active":        "active",
+	"department":    "department",
+	"manager_email": "manager_email",
+}
+
+// ParseCSV reads an HR export with a header row. Rows that cannot be read are returned as error changes so that they
+// show up in the run log, a missing email column fails the whole file
+func ParseCSV(r io.Reader) ([]models.SyncEmployee, []models.SyncChange, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, nil, errors.New("the email column is required")
+	}
+
+	records := make([]models.SyncEmployee, 0)
+	rejected := make([]models.SyncChange, 0)
+	for line := 2; ; line++ {
+		row, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			rejected = append(rejected, models.SyncChange{
+				Action:  ActionError,
+				Message: readErr.Error(),
+			})
+			continue
+		}
+
+		value := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		if strings.Join(row, "") == "" {
+			continue
+		}
+
+		record := models.SyncEmployee{
+			Name:         value("name"),
+			Email:        value("email"),
+			PhoneNo:      value("phone_no"),
+			Type:         value("type"),
+			Status:       value("status"),
+			Department:   value("department"),
+			ManagerEmail: value("manager_email"),
+		}
+		if externalID := value("external_id"); externalID != "" {
+			record.ExternalID = null.StringFrom(externalID)
+		}
+		if active := value("active"); active != "" {
+			isActive, parseErr := strconv.ParseBool(active)
+			if parseErr != nil {
+				rejected = append(rejected, models.SyncChange{
+					ExternalID: record.ExternalID,
+					Email:      record.Email,
+					Action:     ActionError,
+					Message:    fmt.Sprintf("line %d: active must be true or false", line),
+				})
+				continue
+			}
+			record.Status = utils.Active
+			if !isActive {
+				record.Status = utils.NotAnEmployee
+			}
+		}
+		records = append(records, record)
+	}
+	return records, rejected, nil
+}
@@ -0,0 +1,246 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/volatiletech/null"
+)
+
+const (
+	ActionCreate     = "create"
+	ActionUpdate     = "update"
+	ActionDeactivate = "deactivate"
+	ActionError      = "error"
+
+	SourceScim = "scim"
+	SourceCSV  = "csv"
+)
+
+var employeeTypes = map[string]bool{
+	"employee":   true,
+	"intern":     true,
+	"freelancer": true,
+}
+
+// Plan compares the incoming HR records with the current employees and returns the changes needed to match them.
+// Records are matched on id, then external id, then email. Empty incoming fields leave the current value untouched.
+// When deactivateMissing is set, HR managed employees (those with an external id) missing from the batch are deactivated
+func Plan(current, incoming []models.SyncEmployee, deactivateMissing bool) []models.SyncChange {
+	byID := make(map[string]*models.SyncEmployee, len(current))
+	byExternalID := make(map[string]*models.SyncEmployee, len(current))
+	byEmail := make(map[string]*models.SyncEmployee, len(current))
+	for i := range current {
+		employee := &current[i]
+		byID[employee.ID] = employee
+		if employee.ExternalID.Valid {
+			byExternalID[employee.ExternalID.String] = employee
+		}
+		byEmail[strings.ToLower(employee.Email)] = employee
+	}
+
+	changes := make([]models.SyncChange, 0)
+	seen := make(map[string]bool, len(incoming))
+	batchEmails := make(map[string]bool, len(incoming))
+	for i := range incoming {
+		record := normalize(incoming[i])
+		if msg := check(&record); msg != "" {
+			changes = append(changes, errorChange(&record, msg))
+			continue
+		}
+		if batchEmails[record.Email] {
+			changes = append(changes, errorChange(&record, "email appears more than once in this batch"))
+			continue
+		}
+		batchEmails[record.Email] = true
+
+		var existing *models.SyncEmployee
+		switch {
+		case record.ID != "":
+			existing = byID[record.ID]
+			if existing == nil {
+				changes = append(changes, errorChange(&record, "employee not found"))
+				continue
+			}
+		case record.ExternalID.Valid && byExternalID[record.ExternalID.String] != nil:
+			existing = byExternalID[record.ExternalID.String]
+		default:
+			existing = byEmail[record.Email]
+		}
+
+		if existing == nil {
+			if record.Status == utils.NotAnEmployee {
+				continue
+			}
+			if record.Name == "" {
+				changes = append(changes, errorChange(&record, "name is required for a new employee"))
+				continue
+			}
+			changes = append(changes, createChange(&record))
+			continue
+		}
+		seen[existing.ID] = true
+
+		if existing.Status == utils.Deleted {
+			changes = append(changes, errorChange(&record, "employee was deleted in asset management"))
+			continue
+		}
+		if other := byEmail[record.Email]; other != nil && other.ID != existing.ID {
+			changes = append(changes, errorChange(&record, "email belongs to another employee"))
+			continue
+		}
+		if change, ok := updateChange(existing, &record); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	if !deactivateMissing || len(incoming) == 0 {
+		return changes
+	}
+	for i := range current {
+		employee := current[i]
+		if !employee.ExternalID.Valid || seen[employee.ID] || employee.Status != utils.Active {
+			continue
+		}
+		record := employee
+		record.Status = utils.NotAnEmployee
+		if change, ok := updateChange(&employee, &record); ok {
+			change.Message = strings.TrimSpace("missing from the HR roster. " + change.Message)
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// normalize trims an incoming record and fills in the defaults of a new employee
+func normalize(record models.SyncEmployee) models.SyncEmployee {
+	record.Name = strings.TrimSpace(record.Name)
+	record.Email = strings.ToLower(strings.TrimSpace(record.Email))
+	record.PhoneNo = strings.TrimSpace(record.PhoneNo)
+	record.Type = strings.ToLower(strings.TrimSpace(record.Type))
+	record.Status = strings.ToLower(strings.TrimSpace(record.Status))
+	record.Department = strings.TrimSpace(record.Department)
+	record.ManagerEmail = strings.ToLower(strings.TrimSpace(record.ManagerEmail))
+	if record.ExternalID.Valid {
+		record.ExternalID.String = strings.TrimSpace(record.ExternalID.String)
+		record.ExternalID.Valid = record.ExternalID.String != ""
+	}
+	if record.Status == "" {
+		record.Status = utils.Active
+	}
+	return record
+}
+
+func check(record *models.SyncEmployee) string {
+	switch {
+	case record.Email == "" || !strings.Contains(record.Email, "@"):
+		return "a valid email is required"
+	case record.Status != utils.Active && record.Status != utils.NotAnEmployee:
+		return fmt.Sprintf("unknown status %q", record.Status)
+	case record.Type != "" && !employeeTypes[record.Type]:
+		return fmt.Sprintf("unknown type %q", record.Type)
+	case record.ManagerEmail != "" && record.ManagerEmail == record.Email:
+		return "employee cannot be their own manager"
+	}
+	return ""
+}
+
+func createChange(record *models.SyncEmployee) models.SyncChange {
+	target := *record
+	if target.Type == "" {
+		target.Type = "employee"
+	}
+	diff := make(map[string]models.FieldChange)
+	addDiff(diff, "name", "", target.Name)
+	addDiff(diff, "email", "", target.Email)
+	addDiff(diff, "phoneNo", "", target.PhoneNo)
+	addDiff(diff, "type", "", target.Type)
+	addDiff(diff, "status", "", target.Status)
+	addDiff(diff, "externalId", "", target.ExternalID.String)
+	addDiff(diff, "department", "", target.Department)
+	addDiff(diff, "managerEmail", "", target.ManagerEmail)
+	return newChange(ActionCreate, null.String{}, &target, diff, "")
+}
+
+// updateChange merges the incoming record into the current employee and reports what differs
+func updateChange(existing, record *models.SyncEmployee) (models.SyncChange, bool) {
+	target := *existing
+	if record.ExternalID.Valid {
+		target.ExternalID = record.ExternalID
+	}
+	if record.Name != "" {
+		target.Name = record.Name
+	}
+	target.Email = record.Email
+	if record.PhoneNo != "" {
+		target.PhoneNo = record.PhoneNo
+	}
+	if record.Type != "" {
+		target.Type = record.Type
+	}
+	target.Status = record.Status
+	if record.Department != "" {
+		target.Department = record.Department
+	}
+	if record.ManagerEmail != "" {
+		target.ManagerEmail = record.ManagerEmail
+	}
+
+	diff := make(map[string]models.FieldChange)
+	addDiff(diff, "externalId", existing.ExternalID.String, target.ExternalID.String)
+	addDiff(diff, "name", existing.Name, target.Name)
+	addDiff(diff, "email", strings.ToLower(existing.Email), target.Email)
+	addDiff(diff, "phoneNo", existing.PhoneNo, target.PhoneNo)
+	addDiff(diff, "type", existing.Type, target.Type)
+	addDiff(diff, "status", existing.Status, target.Status)
+	if !strings.EqualFold(existing.Department, target.Department) {
+		addDiff(diff, "department", existing.Department, target.Department)
+	}
+	addDiff(diff, "managerEmail", strings.ToLower(existing.ManagerEmail), target.ManagerEmail)
+	if len(diff) == 0 {
+		return models.SyncChange{}, false
+	}
+
+	action := ActionUpdate
+	message := ""
+	if existing.Status == utils.Active && target.Status == utils.NotAnEmployee {
+		action = ActionDeactivate
+		if existing.HeldAssets > 0 {
+			message = fmt.Sprintf("still holds %d assets that need to be retrieved.", existing.HeldAssets)
+		}
+	}
+	return newChange(action, null.StringFrom(existing.ID), &target, diff, message), true
+}
+
+func errorChange(record *models.SyncEmployee, message string) models.SyncChange {
+	var employeeID null.String
+	if record.ID != "" {
+		employeeID = null.StringFrom(record.ID)
+	}
+	return newChange(ActionError, employeeID, record, nil, message)
+}
+
+func newChange(action string, employeeID null.String, target *models.SyncEmployee, diff map[string]models.FieldChange, message string) models.SyncChange {
+	change := models.SyncChange{
+		EmployeeID: employeeID,
+		ExternalID: target.ExternalID,
+		Email:      target.Email,
+		Action:     action,
+		Message:    message,
+		Employee:   *target,
+	}
+	if len(diff) > 0 {
+		// a map of strings always marshals
+		change.Changes, _ = json.Marshal(diff)
+	}
+	return change
+}
+
+func addDiff(diff map[string]models.FieldChange, field, from, to string) {
+	if from != to {
+		diff[field] = models.FieldChange{From: from, To: to}
+	}
+}
@@ -0,0 +1,75 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"reflect"
+	"testing"
+
+	"github.com/volatiletech/null"
+)
+
+func TestPlan(t *testing.T) {
+	asha := models.SyncEmployee{ID: "e1", ExternalID: null.StringFrom("hr-1"), Name: "Asha", Email: "asha@example.com", Type: "employee", Status: utils.Active}
+	ravi := models.SyncEmployee{ID: "e2", Name: "Ravi", Email: "ravi@example.com", Type: "employee", Status: utils.Active}
+	meera := models.SyncEmployee{ID: "e3", ExternalID: null.StringFrom("hr-3"), Name: "Meera", Email: "meera@example.com", Type: "intern", Status: utils.Active, HeldAssets: 2}
+	gone := models.SyncEmployee{ID: "e4", Name: "Gone", Email: "gone@example.com", Type: "employee", Status: utils.Deleted}
+	current := []models.SyncEmployee{asha, ravi, meera, gone}
+
+	cases := []struct {
+		name              string
+		incoming          []models.SyncEmployee
+		deactivateMissing bool
+		want              []string
+		wantStatus        []string
+	}{
+		{"unchanged record", []models.SyncEmployee{{ExternalID: null.StringFrom("hr-1"), Email: "ASHA@example.com "}}, false, []string{}, nil},
+		{"new employee", []models.SyncEmployee{{Name: "Kiran", Email: "kiran@example.com"}}, false, []string{ActionCreate}, []string{utils.Active}},
+		{"new employee without name", []models.SyncEmployee{{Email: "kiran@example.com"}}, false, []string{ActionError}, nil},
+		{"new employee already gone", []models.SyncEmployee{{Name: "Kiran", Email: "kiran@example.com", Status: utils.NotAnEmployee}}, false, []string{}, nil},
+		{"matched on external id", []models.SyncEmployee{{ExternalID: null.StringFrom("hr-1"), Name: "Asha Rao", Email: "asha.rao@example.com"}}, false, []string{ActionUpdate}, []string{utils.Active}},
+		{"matched on email", []models.SyncEmployee{{Email: "ravi@example.com", PhoneNo: "9876543210"}}, false, []string{ActionUpdate}, []string{utils.Active}},
+		{"unknown id", []models.SyncEmployee{{ID: "e9", Email: "someone@example.com"}}, false, []string{ActionError}, nil},
+		{"deactivated", []models.SyncEmployee{{ID: "e3", Email: "meera@example.com", Status: utils.NotAnEmployee}}, false, []string{ActionDeactivate}, []string{utils.NotAnEmployee}},
+		{"deleted in asset management", []models.SyncEmployee{{Email: "gone@example.com", Name: "Gone"}}, false, []string{ActionError}, nil},
+		{"email of another employee", []models.SyncEmployee{{ExternalID: null.StringFrom("hr-1"), Email: "ravi@example.com"}}, false, []string{ActionError}, nil},
+		{"email twice in batch", []models.SyncEmployee{{Name: "Kiran", Email: "kiran@example.com"}, {Name: "Kiran", Email: "kiran@example.com"}}, false, []string{ActionCreate, ActionError}, []string{utils.Active}},
+		{"invalid email", []models.SyncEmployee{{Name: "Kiran", Email: "kiran"}}, false, []string{ActionError}, nil},
+		{"unknown status", []models.SyncEmployee{{Email: "ravi@example.com", Status: "retired"}}, false, []string{ActionError}, nil},
+		{"unknown type", []models.SyncEmployee{{Email: "ravi@example.com", Type: "contractor"}}, false, []string{ActionError}, nil},
+		{"own manager", []models.SyncEmployee{{Email: "ravi@example.com", ManagerEmail: "ravi@example.com"}}, false, []string{ActionError}, nil},
+		{"missing kept", []models.SyncEmployee{{ExternalID: null.StringFrom("hr-1"), Email: "asha@example.com"}}, false, []string{}, nil},
+		{"missing deactivated", []models.SyncEmployee{{ExternalID: null.StringFrom("hr-1"), Email: "asha@example.com"}}, true, []string{ActionDeactivate}, []string{utils.NotAnEmployee}},
+		{"empty batch deactivates nobody", nil, true, []string{}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			changes := Plan(current, c.incoming, c.deactivateMissing)
+			actions := make([]string, 0, len(changes))
+			statuses := make([]string, 0)
+			for i := range changes {
+				actions = append(actions, changes[i].Action)
+				if changes[i].Action != ActionError {
+					statuses = append(statuses, changes[i].Employee.Status)
+				}
+			}
+			if !reflect.DeepEqual(actions, c.want) {
+				t.Errorf("got actions %v, want %v", actions, c.want)
+			}
+			if c.wantStatus != nil && !reflect.DeepEqual(statuses, c.wantStatus) {
+				t.Errorf("got statuses %v, want %v", statuses, c.wantStatus)
+			}
+		})
+	}
+}
+
+func TestPlanDeactivateWithAssets(t *testing.T) {
+	current := []models.SyncEmployee{{ID: "e1", ExternalID: null.StringFrom("hr-1"), Name: "Meera", Email: "meera@example.com", Status: utils.Active, HeldAssets: 2}}
+	changes := Plan(current, []models.SyncEmployee{{ID: "e1", Email: "meera@example.com", Status: utils.NotAnEmployee}}, false)
+	if len(changes) != 1 {
+		t.Fatalf("got %d changes, want 1", len(changes))
+	}
+	if want := "still holds 2 assets that need to be retrieved."; changes[0].Message != want {
+		t.Errorf("got message %q, want %q", changes[0].Message, want)
+	}
+}
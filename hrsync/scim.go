@@ -0,0 +1,250 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/volatiletech/null"
+)
+
+const (
+	ScimUserSchema       = "urn:ietf:params:scim:schemas:core:2.0:User"
+	ScimEnterpriseSchema = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	ScimListSchema       = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	ScimPatchSchema      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ScimErrorSchema      = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ScimConfigSchema     = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// ErrInvalidFilter is returned for filters other than userName or externalId equality
+var ErrInvalidFilter = errors.New("only userName eq and externalId eq filters are supported")
+
+var filterPattern = regexp.MustCompile(`(?i)^\s*(userName|externalId)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// ParseFilter reads a SCIM filter of the form userName eq "value" and returns the attribute and value
+func ParseFilter(filter string) (string, string, error) {
+	matches := filterPattern.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", ErrInvalidFilter
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return "", "", ErrInvalidFilter
+	}
+	return strings.ToLower(matches[1]), value, nil
+}
+
+// ToScim maps an employee onto a SCIM user, the SCIM id is the employee id
+func ToScim(employee *models.SyncEmployee, location string) models.ScimUser {
+	active := employee.Status == utils.Active
+	user := models.ScimUser{
+		Schemas:     []string{ScimUserSchema, ScimEnterpriseSchema},
+		ID:          employee.ID,
+		ExternalID:  employee.ExternalID.String,
+		UserName:    employee.Email,
+		Name:        &models.ScimName{Formatted: employee.Name},
+		DisplayName: employee.Name,
+		UserType:    employee.Type,
+		Active:      &active,
+		Emails:      []models.ScimMultiValue{{Value: employee.Email, Type: "work", Primary: true}},
+		Enterprise:  &models.ScimEnterpriseUser{Department: employee.Department},
+		Meta: &models.ScimMeta{
+			ResourceType: "User",
+			Created:      employee.CreatedAt,
+			Location:     location,
+		},
+	}
+	if employee.PhoneNo != "" {
+		user.PhoneNumbers = []models.ScimMultiValue{{Value: employee.PhoneNo, Type: "work", Primary: true}}
+	}
+	if employee.ManagerID.Valid {
+		user.Enterprise.Manager = &models.ScimManager{Value: employee.ManagerID.String}
+	}
+	if employee.UpdatedAt.Valid {
+		user.Meta.LastModified = &employee.UpdatedAt.Time
+	}
+	return user
+}
+
+// FromScim maps a SCIM user onto an incoming HR record. The manager is referenced by SCIM id, managerEmail resolves
+// it to the email the sync links managers by
+func FromScim(user *models.ScimUser, managerEmail func(id string) string) models.SyncEmployee {
+	record := models.SyncEmployee{
+		ID:     user.ID,
+		Email:  user.UserName,
+		Type:   user.UserType,
+		Status: utils.Active,
+	}
+	if user.ExternalID != "" {
+		record.ExternalID = null.StringFrom(user.ExternalID)
+	}
+	if !strings.Contains(user.UserName, "@") {
+		record.Email = primaryValue(user.Emails)
+	}
+
+	switch {
+	case user.DisplayName != "":
+		record.Name = user.DisplayName
+	case user.Name != nil && user.Name.Formatted != "":
+		record.Name = user.Name.Formatted
+	case user.Name != nil:
+		record.Name = strings.TrimSpace(user.Name.GivenName + " " + user.Name.FamilyName)
+	}
+	record.PhoneNo = primaryValue(user.PhoneNumbers)
+	if user.Active != nil && !*user.Active {
+		record.Status = utils.NotAnEmployee
+	}
+	if user.Enterprise != nil {
+		record.Department = user.Enterprise.Department
+		if user.Enterprise.Manager != nil && user.Enterprise.Manager.Value != "" {
+			record.ManagerEmail = managerEmail(user.Enterprise.Manager.Value)
+		}
+	}
+	return record
+}
+
+func primaryValue(values []models.ScimMultiValue) string {
+	for i := range values {
+		if values[i].Primary {
+			return values[i].Value
+		}
+	}
+	if len(values) > 0 {
+		return values[0].Value
+	}
+	return ""
+}
+
+// ApplyPatch applies add and replace operations to a SCIM user. Remove operations and attributes the employee table
+// has no column for are ignored, as empty HR fields leave employees untouched
+func ApplyPatch(user *models.ScimUser, operations []models.ScimPatchOperation) error {
+	for i := range operations {
+		operation := operations[i]
+		switch strings.ToLower(operation.Op) {
+		case "add", "replace":
+		case "remove":
+			continue
+		default:
+			return fmt.Errorf("unsupported operation %q", operation.Op)
+		}
+
+		if operation.Path != "" {
+			if err := setAttribute(user, operation.Path, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		attributes := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+			return fmt.Errorf("operation without a path needs an object value: %w", err)
+		}
+		for path, value := range attributes {
+			if err := setAttribute(user, path, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setAttribute(user *models.ScimUser, path string, value json.RawMessage) error {
+	if user.Enterprise == nil {
+		user.Enterprise = &models.ScimEnterpriseUser{}
+	}
+	if user.Name == nil {
+		user.Name = &models.ScimName{}
+	}
+
+	lowerPath := strings.ToLower(path)
+	enterprisePrefix := strings.ToLower(ScimEnterpriseSchema)
+	switch {
+	case lowerPath == enterprisePrefix:
+		return unmarshalAttribute(path, value, user.Enterprise)
+	case strings.HasPrefix(lowerPath, enterprisePrefix+":"):
+		return setEnterpriseAttribute(user.Enterprise, strings.TrimPrefix(lowerPath, enterprisePrefix+":"), value)
+	case lowerPath == "active":
+		active, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("active: %w", err)
+		}
+		user.Active = &active
+	case lowerPath == "username":
+		return unmarshalAttribute(path, value, &user.UserName)
+	case lowerPath == "externalid":
+		return unmarshalAttribute(path, value, &user.ExternalID)
+	case lowerPath == "displayname":
+		return unmarshalAttribute(path, value, &user.DisplayName)
+	case lowerPath == "usertype":
+		return unmarshalAttribute(path, value, &user.UserType)
+	case lowerPath == "name":
+		return unmarshalAttribute(path, value, user.Name)
+	case lowerPath == "name.formatted":
+		return unmarshalAttribute(path, value, &user.Name.Formatted)
+	case lowerPath == "name.givenname":
+		user.DisplayName, user.Name.Formatted = "", ""
+		return unmarshalAttribute(path, value, &user.Name.GivenName)
+	case lowerPath == "name.familyname":
+		user.DisplayName, user.Name.Formatted = "", ""
+		return unmarshalAttribute(path, value, &user.Name.FamilyName)
+	case lowerPath == "emails":
+		return unmarshalAttribute(path, value, &user.Emails)
+	case strings.HasPrefix(lowerPath, "emails[") && strings.HasSuffix(lowerPath, ".value"):
+		return setPrimaryValue(path, value, &user.Emails)
+	case lowerPath == "phonenumbers":
+		return unmarshalAttribute(path, value, &user.PhoneNumbers)
+	case strings.HasPrefix(lowerPath, "phonenumbers[") && strings.HasSuffix(lowerPath, ".value"):
+		return setPrimaryValue(path, value, &user.PhoneNumbers)
+	}
+	return nil
+}
+
+func setEnterpriseAttribute(enterprise *models.ScimEnterpriseUser, attribute string, value json.RawMessage) error {
+	switch attribute {
+	case "department":
+		return unmarshalAttribute(attribute, value, &enterprise.Department)
+	case "manager", "manager.value":
+		manager := models.ScimManager{}
+		if err := json.Unmarshal(value, &manager.Value); err != nil {
+			if err := unmarshalAttribute(attribute, value, &manager); err != nil {
+				return err
+			}
+		}
+		enterprise.Manager = &manager
+	}
+	return nil
+}
+
+func setPrimaryValue(path string, value json.RawMessage, values *[]models.ScimMultiValue) error {
+	var v string
+	if err := unmarshalAttribute(path, value, &v); err != nil {
+		return err
+	}
+	*values = []models.ScimMultiValue{{Value: v, Type: "work", Primary: true}}
+	return nil
+}
+
+func unmarshalAttribute(path string, value json.RawMessage, out interface{}) error {
+	if err := json.Unmarshal(value, out); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// parseBool accepts JSON booleans and the "True"/"False" strings some identity providers send
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(s)
+}
@@ -0,0 +1,104 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/models"
+	"encoding/json"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	cases := []struct {
+		filter    string
+		attribute string
+		value     string
+		wantErr   bool
+	}{
+		{`userName eq "asha@example.com"`, "username", "asha@example.com", false},
+		{`  externalId EQ "hr-1"  `, "externalid", "hr-1", false},
+		{`userName eq "a \"quoted\" name"`, "username", `a "quoted" name`, false},
+		{`userName eq ""`, "username", "", false},
+		{`userName ne "asha@example.com"`, "", "", true},
+		{`displayName eq "Asha"`, "", "", true},
+		{`userName eq asha@example.com`, "", "", true},
+		{`userName eq "a" and externalId eq "b"`, "", "", true},
+		{``, "", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.filter, func(t *testing.T) {
+			attribute, value, err := ParseFilter(c.filter)
+			if c.wantErr {
+				if err != ErrInvalidFilter {
+					t.Errorf("got error %v, want %v", err, ErrInvalidFilter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if attribute != c.attribute || value != c.value {
+				t.Errorf("got %s %q, want %s %q", attribute, value, c.attribute, c.value)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	cases := []struct {
+		name       string
+		operations string
+		wantErr    bool
+		check      func(user *models.ScimUser) bool
+	}{
+		{"deactivate", `[{"op":"replace","path":"active","value":false}]`, false,
+			func(user *models.ScimUser) bool { return user.Active != nil && !*user.Active }},
+		{"active as string", `[{"op":"Replace","path":"active","value":"False"}]`, false,
+			func(user *models.ScimUser) bool { return user.Active != nil && !*user.Active }},
+		{"object value", `[{"op":"replace","value":{"displayName":"Asha Rao","userName":"asha.rao@example.com"}}]`, false,
+			func(user *models.ScimUser) bool {
+				return user.DisplayName == "Asha Rao" && user.UserName == "asha.rao@example.com"
+			}},
+		{"given name clears display name", `[{"op":"replace","path":"name.givenName","value":"Asha"}]`, false,
+			func(user *models.ScimUser) bool { return user.DisplayName == "" && user.Name.GivenName == "Asha" }},
+		{"primary email", `[{"op":"replace","path":"emails[type eq \"work\"].value","value":"new@example.com"}]`, false,
+			func(user *models.ScimUser) bool {
+				return len(user.Emails) == 1 && user.Emails[0].Value == "new@example.com"
+			}},
+		{"enterprise department", `[{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department","value":"Finance"}]`, false,
+			func(user *models.ScimUser) bool { return user.Enterprise.Department == "Finance" }},
+		{"enterprise manager", `[{"op":"add","path":"urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:manager","value":{"value":"e2"}}]`, false,
+			func(user *models.ScimUser) bool {
+				return user.Enterprise.Manager != nil && user.Enterprise.Manager.Value == "e2"
+			}},
+		{"remove ignored", `[{"op":"remove","path":"displayName"}]`, false,
+			func(user *models.ScimUser) bool { return user.DisplayName == "Asha" }},
+		{"unknown attribute ignored", `[{"op":"replace","path":"nickName","value":"A"}]`, false,
+			func(user *models.ScimUser) bool { return user.DisplayName == "Asha" }},
+		{"unsupported operation", `[{"op":"move","path":"displayName","value":"A"}]`, true, nil},
+		{"invalid active", `[{"op":"replace","path":"active","value":"maybe"}]`, true, nil},
+		{"no path and no object", `[{"op":"replace","value":"Asha"}]`, true, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var operations []models.ScimPatchOperation
+			if err := json.Unmarshal([]byte(c.operations), &operations); err != nil {
+				t.Fatalf("cannot parse operations: %v", err)
+			}
+			active := true
+			user := models.ScimUser{UserName: "asha@example.com", DisplayName: "Asha", Active: &active}
+
+			err := ApplyPatch(&user, operations)
+			if c.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !c.check(&user) {
+				t.Errorf("unexpected user %+v", user)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package hrsync
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"context"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+// Options describe where a batch of HR records came from and how it is applied
+type Options struct {
+	Source   string
+	FileName null.String
+	// DryRun plans and logs the changes without touching employees, it backs the diff preview
+	DryRun bool
+	// DeactivateMissing is only set for full rosters such as a CSV drop
+	DeactivateMissing bool
+	TriggeredBy       null.String
+	TokenID           null.String
+}
+
+// Run plans the incoming records against the current employees, applies the changes unless it is a dry run and
+// logs the run. Rows that could not be parsed are passed as rejected and logged as errors. Each change is applied
+// in its own savepoint so one bad row is recorded as an error instead of failing the whole run.
+// SCIM runs without changes are not logged since identity providers resend unchanged users routinely
+func Run(ctx context.Context, incoming []models.SyncEmployee, rejected []models.SyncChange, opts Options) (models.SyncRun, error) {
+	run := models.SyncRun{
+		Source:      opts.Source,
+		DryRun:      opts.DryRun,
+		FileName:    opts.FileName,
+		TriggeredBy: opts.TriggeredBy,
+		TokenID:     opts.TokenID,
+	}
+
+	current, err := dbhelper.GetSyncEmployees(ctx)
+	if err != nil {
+		return run, err
+	}
+	run.Changes = append(rejected, Plan(current, incoming, opts.DeactivateMissing)...)
+	if len(run.Changes) == 0 && opts.Source == SourceScim {
+		return run, nil
+	}
+
+	txErr := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		run.ID, err = dbhelper.CreateSyncRun(ctx, tx, &run)
+		if err != nil {
+			return err
+		}
+		if !opts.DryRun {
+			if err := apply(ctx, tx, run.Changes, opts.TriggeredBy); err != nil {
+				return err
+			}
+		}
+
+		for i := range run.Changes {
+			run.Changes[i].RunID = run.ID
+			switch run.Changes[i].Action {
+			case ActionCreate:
+				run.CreatedCount++
+			case ActionUpdate:
+				run.UpdatedCount++
+			case ActionDeactivate:
+				run.DeactivatedCount++
+			case ActionError:
+				run.ErrorCount++
+			}
+			if err := dbhelper.CreateSyncChange(ctx, tx, run.ID, &run.Changes[i]); err != nil {
+				return err
+			}
+		}
+		return dbhelper.FinishSyncRun(ctx, tx, &run)
+	})
+	return run, txErr
+}
+
+// apply writes the planned changes, managers are linked in a second pass so they can be created in the same batch
+func apply(ctx context.Context, tx *sqlx.Tx, changes []models.SyncChange, triggeredBy null.String) error {
+	for i := range changes {
+		change := &changes[i]
+		if change.Action == ActionError {
+			continue
+		}
+		err := database.Savepoint(ctx, tx, func() error {
+			var departmentID null.String
+			if change.Employee.Department != "" {
+				id, err := dbhelper.GetOrCreateDepartment(ctx, tx, change.Employee.Department, triggeredBy)
+				if err != nil {
+					return err
+				}
+				departmentID = null.StringFrom(id)
+			}
+
+			if change.Action == ActionCreate {
+				id, err := dbhelper.CreateSyncedEmployee(ctx, tx, &change.Employee, departmentID)
+				if err != nil {
+					return err
+				}
+				change.Employee.ID = id
+				change.EmployeeID = null.StringFrom(id)
+				return nil
+			}
+			return dbhelper.UpdateSyncedEmployee(ctx, tx, &change.Employee, departmentID)
+		})
+		if err != nil {
+			markFailed(change, err)
+		}
+	}
+
+	for i := range changes {
+		change := &changes[i]
+		if change.Action == ActionError || change.Employee.ManagerEmail == "" || !managerChanged(change) {
+			continue
+		}
+		err := database.Savepoint(ctx, tx, func() error {
+			return dbhelper.SetSyncedManager(ctx, tx, change.Employee.ID, change.Employee.ManagerEmail)
+		})
+		if err != nil {
+			// the employee itself was saved, only the manager link is reported
+			change.Message = "manager " + change.Employee.ManagerEmail + " not linked: " + err.Error()
+		}
+	}
+	return nil
+}
+
+func managerChanged(change *models.SyncChange) bool {
+	return change.Action == ActionCreate || containsField(change.Changes, "managerEmail")
+}
+
+func markFailed(change *models.SyncChange, err error) {
+	change.Action = ActionError
+	change.Message = err.Error()
+}
+
+func containsField(changes []byte, field string) bool {
+	diff := make(map[string]models.FieldChange)
+	if err := json.Unmarshal(changes, &diff); err != nil {
+		return false
+	}
+	_, ok := diff[field]
+	return ok
+}
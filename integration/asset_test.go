@@ -0,0 +1,556 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+func TestAssetTags(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	seedAsset(t, user, newAsset(models.Laptop, "Dell", purchased.AddDate(3, 0, 0)))
+	seedAsset(t, user, newAsset(models.Mouse, "Logitech", purchased.AddDate(3, 0, 0)))
+	seedAsset(t, user, newAsset(models.Laptop, "Lenovo", purchased.AddDate(3, 0, 0)))
+
+	labels, err := dbhelper.GetAssetLabels(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"RS-LAP-00001", "RS-LAP-00002", "RS-MOU-00001"}
+	if len(labels) != len(want) {
+		t.Fatalf("got labels %+v", labels)
+	}
+	for i, label := range labels {
+		if label.AssetTag != want[i] {
+			t.Errorf("label %d has tag %s, want %s", i, label.AssetTag, want[i])
+		}
+	}
+
+	one, err := dbhelper.GetAssetLabels(ctx, []string{labels[2].ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(one) != 1 || one[0].Brand != "Logitech" {
+		t.Errorf("got labels %+v for one asset", one)
+	}
+}
+
+func TestGetAssetSpec(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	expiry := purchased.AddDate(3, 0, 0)
+
+	laptop := newAsset(models.Laptop, "Dell", expiry)
+	laptop.RAM, laptop.Processor, laptop.Charger = "16GB", "i7", true
+	penDrive := newAsset(models.Pendrive, "SanDisk", expiry)
+	penDrive.Storage = "64GB"
+	hardDisk := newAsset(models.Harddisk, "Seagate", expiry)
+	hardDisk.Storage = "1TB"
+	mobile := newAsset(models.Mobile, "Samsung", expiry)
+	mobile.OsType, mobile.Imei1, mobile.Imei2, mobile.RAM = "android", "356938035643809", "356938035643817", "8GB"
+	sim := newAsset(models.Sim, "Jio", expiry)
+	sim.SimNo, sim.PhoneNo = "8991101200003204510", "9876500000"
+	mouse := newAsset(models.Mouse, "Logitech", expiry)
+
+	cases := []struct {
+		asset models.CreateAsset
+		check func(models.CreateAsset) bool
+	}{
+		{laptop, func(a models.CreateAsset) bool { return a.RAM == "16GB" && a.Processor == "i7" && a.Charger }},
+		{penDrive, func(a models.CreateAsset) bool { return a.Storage == "64GB" }},
+		{hardDisk, func(a models.CreateAsset) bool { return a.Storage == "1TB" }},
+		{mobile, func(a models.CreateAsset) bool {
+			return a.Imei1 == "356938035643809" && a.Imei2 == "356938035643817" && a.RAM == "8GB"
+		}},
+		{sim, func(a models.CreateAsset) bool { return a.SimNo == "8991101200003204510" && a.PhoneNo == "9876500000" }},
+		{mouse, func(a models.CreateAsset) bool { return true }},
+	}
+	for _, c := range cases {
+		t.Run(string(c.asset.AssetType), func(t *testing.T) {
+			id := seedAsset(t, user, c.asset)
+			spec, err := dbhelper.GetAssetSpec(ctx, id, string(c.asset.AssetType))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(spec) != 1 {
+				t.Fatalf("got %d specifications", len(spec))
+			}
+			got := spec[0]
+			if got.Brand != c.asset.Brand || got.SerialNo != c.asset.SerialNo || got.Status != utils.Available ||
+				got.AssetTag == "" || !got.WarrantyExpiryDate.Equal(expiry) || !c.check(got) {
+				t.Errorf("got specification %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdateAsset(t *testing.T) {
+	inv := seedInventory(t)
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		update := &models.UpdateAssetSpecification{
+			ID:                 inv.dell,
+			Brand:              "Dell",
+			Model:              "XPS 15",
+			SerialNo:           "SN-DELL-XPS15",
+			PurchasedDate:      purchased,
+			WarrantyStartDate:  purchased,
+			WarrantyExpiryDate: purchased.AddDate(4, 0, 0),
+			RAM:                "32GB",
+		}
+		if err := dbhelper.UpdateAsset(ctx, update, tx); err != nil {
+			return err
+		}
+		return dbhelper.UpdateLaptopSpecifications(ctx, update, tx)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := dbhelper.GetAssetSpec(ctx, inv.dell, utils.Laptop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec) != 1 || spec[0].Model != "XPS 15" || spec[0].RAM != "32GB" {
+		t.Errorf("got specification %+v after updating", spec)
+	}
+
+	for _, c := range []struct {
+		assetID string
+		rows    int64
+	}{{inv.dell, 1}, {inv.broken, 0}} {
+		var rows int64
+		err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+			var err error
+			rows, err = dbhelper.UpdateWarranty(ctx, tx, models.WarrantyDetails{
+				AssetID:            c.assetID,
+				WarrantyStartDate:  purchased,
+				WarrantyExpiryDate: purchased.AddDate(5, 0, 0),
+			})
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rows != c.rows {
+			t.Errorf("updating the warranty of %s changed %d rows, want %d", c.assetID, rows, c.rows)
+		}
+	}
+}
+
+func TestLookupAsset(t *testing.T) {
+	inv := seedInventory(t)
+
+	mobile := newAsset(models.Mobile, "Samsung", purchased.AddDate(3, 0, 0))
+	mobile.Imei1, mobile.Imei2 = "356938035643809", "356938035643817"
+	phone := seedAsset(t, inv.user, mobile)
+
+	cases := []struct {
+		code string
+		id   string
+	}{
+		{"rs-lap-00001", inv.dell},
+		{"SN-DELL-laptop", inv.dell},
+		{"356938035643809", phone},
+		{"356938035643817", phone},
+		{"RS-LAP-00004", ""},
+		{"RS-LAP-99999", ""},
+	}
+	for _, c := range cases {
+		t.Run(c.code, func(t *testing.T) {
+			found, err := dbhelper.LookupAsset(ctx, c.code)
+			if c.id == "" {
+				if !errors.Is(err, sql.ErrNoRows) {
+					t.Errorf("got %+v, %v for a code of no asset in use", found, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found.ID != c.id {
+				t.Errorf("found %s, want %s", found.ID, c.id)
+			}
+		})
+	}
+}
+
+func TestGetAssetsWithFilters(t *testing.T) {
+	inv := seedInventory(t)
+
+	cases := []struct {
+		name    string
+		filters models.FiltersCheck
+		want    []string
+	}{
+		{"available", models.FiltersCheck{Available: true}, inv.available},
+		{"assigned", models.FiltersCheck{Assigned: true}, inv.assigned},
+		{"deleted", models.FiltersCheck{Deleted: true}, inv.deleted},
+		{"available or assigned", models.FiltersCheck{Available: true, Assigned: true}, join(inv.available, inv.assigned)},
+		{"available or deleted", models.FiltersCheck{Available: true, Deleted: true}, join(inv.available, inv.deleted)},
+		{"assigned or deleted", models.FiltersCheck{Assigned: true, Deleted: true}, join(inv.assigned, inv.deleted)},
+		{"any status", models.FiltersCheck{Available: true, Assigned: true, Deleted: true}, join(inv.available, inv.assigned, inv.deleted)},
+		{"available or assigned mice", models.FiltersCheck{Available: true, Assigned: true, AssetTypes: pq.StringArray{utils.Mouse}},
+			[]string{inv.mouse}},
+		{"assigned or deleted laptops", models.FiltersCheck{Assigned: true, Deleted: true, AssetTypes: pq.StringArray{utils.Laptop}},
+			[]string{inv.lenovo, inv.broken}},
+		{"available or deleted dell", models.FiltersCheck{Available: true, Deleted: true, SearchedName: "dell"},
+			[]string{inv.dell, inv.broken}},
+		{"in warranty for 3 more months", models.FiltersCheck{Available: true, Assigned: true, Warranty: 3}, []string{inv.dell}},
+		{"expired", models.FiltersCheck{Available: true, Assigned: true, IsExpired: true}, []string{inv.mouse}},
+		{"at the office", models.FiltersCheck{Available: true, Assigned: true, LocationID: inv.office}, []string{inv.dell}},
+		{"held by engineering", models.FiltersCheck{Available: true, Assigned: true, DepartmentID: inv.engineering}, inv.assigned},
+	}
+	for _, c := range cases {
+		for _, pagination := range []bool{true, false} {
+			filters := c.filters
+			filters.Pagination = pagination
+			filters.Limit = utils.DefaultLimit
+			assets, err := dbhelper.GetAssetsWithFilters(ctx, &filters)
+			if err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+			sameIDs(t, assetIDs(assets.GetAsset), c.want)
+			if assets.TotalCount != len(c.want) {
+				t.Errorf("%s with pagination %t: got total %d, want %d", c.name, pagination, assets.TotalCount, len(c.want))
+			}
+		}
+	}
+}
+
+func TestGetAssetsWithFiltersHolders(t *testing.T) {
+	inv := seedInventory(t)
+	assets, err := dbhelper.GetAssetsWithFilters(ctx, &models.FiltersCheck{
+		Available: true, Assigned: true, Deleted: true, Pagination: true, Limit: utils.DefaultLimit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	holders := map[string]string{inv.lenovo: "Asha", inv.mouse: "Asha", inv.broken: "Ravi", inv.dell: "", inv.hp: ""}
+	for _, asset := range assets.GetAsset {
+		if asset.AssignedTo.String != holders[asset.ID] {
+			t.Errorf("asset %s (%s) is listed as held by %q, want %q", asset.ID, asset.Status, asset.AssignedTo.String, holders[asset.ID])
+		}
+		if asset.Status == utils.Assigned && asset.AssignedToID.String != inv.asha {
+			t.Errorf("assigned asset %s is listed with holder id %s", asset.ID, asset.AssignedToID.String)
+		}
+		if asset.ID == inv.dell && asset.Location.String != "Store room" {
+			t.Errorf("got location %q for the dell", asset.Location.String)
+		}
+	}
+}
+
+func TestGetAssetsWithFiltersPages(t *testing.T) {
+	inv := seedInventory(t)
+	all := join(inv.available, inv.assigned, inv.deleted)
+
+	seen := make([]string, 0)
+	for page, size := range []int{2, 2, 1, 0} {
+		assets, err := dbhelper.GetAssetsWithFilters(ctx, &models.FiltersCheck{
+			Available: true, Assigned: true, Deleted: true, Pagination: true, Limit: 2, Page: page,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(assets.GetAsset) != size {
+			t.Errorf("page %d has %d assets, want %d", page, len(assets.GetAsset), size)
+		}
+		if size > 0 && assets.TotalCount != len(all) {
+			t.Errorf("page %d has total %d, want %d", page, assets.TotalCount, len(all))
+		}
+		seen = append(seen, assetIDs(assets.GetAsset)...)
+	}
+	sameIDs(t, seen, all)
+}
+
+func TestGetAssets(t *testing.T) {
+	inv := seedInventory(t)
+	inUse := join(inv.available, inv.assigned)
+
+	cases := []struct {
+		name    string
+		filters models.FiltersCheck
+		want    []string
+	}{
+		{"all in use", models.FiltersCheck{}, inUse},
+		{"mice", models.FiltersCheck{AssetTypes: pq.StringArray{utils.Mouse}}, []string{inv.mouse}},
+		{"laptops and mice", models.FiltersCheck{AssetTypes: pq.StringArray{utils.Laptop, utils.Mouse}}, inUse},
+		{"searched", models.FiltersCheck{SearchedName: "LEN"}, []string{inv.lenovo}},
+		{"warranty ending in 3 months", models.FiltersCheck{Warranty: 3}, []string{inv.dell}},
+		{"warranty expired", models.FiltersCheck{IsExpired: true}, []string{inv.mouse}},
+		{"at the office", models.FiltersCheck{LocationID: inv.office}, []string{inv.dell}},
+		{"in the room", models.FiltersCheck{LocationID: inv.room}, []string{inv.dell}},
+		{"held by engineering", models.FiltersCheck{DepartmentID: inv.engineering}, inv.assigned},
+		{"held by platform laptops", models.FiltersCheck{DepartmentID: inv.platform, AssetTypes: pq.StringArray{utils.Laptop}},
+			[]string{inv.lenovo}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := c.filters
+			filters.Limit = utils.DefaultLimit
+			assets, err := dbhelper.GetAssets(ctx, &filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameIDs(t, assetIDs(assets.GetAsset), c.want)
+			if assets.TotalCount != len(c.want) {
+				t.Errorf("got total %d, want %d", assets.TotalCount, len(c.want))
+			}
+		})
+	}
+
+	assets, err := dbhelper.GetAssets(ctx, &models.FiltersCheck{Limit: utils.DefaultLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, asset := range assets.GetAsset {
+		holder := ""
+		if asset.ID == inv.lenovo || asset.ID == inv.mouse {
+			holder = "Asha"
+		}
+		if asset.AssignedTo.String != holder {
+			t.Errorf("asset %s is listed as held by %q, want %q", asset.ID, asset.AssignedTo.String, holder)
+		}
+	}
+}
+
+func TestGetAssetsPages(t *testing.T) {
+	inv := seedInventory(t)
+	inUse := join(inv.available, inv.assigned)
+
+	seen := make([]string, 0)
+	for page, size := range []int{3, 1, 0} {
+		assets, err := dbhelper.GetAssets(ctx, &models.FiltersCheck{Limit: 3, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(assets.GetAsset) != size {
+			t.Errorf("page %d has %d assets, want %d", page, len(assets.GetAsset), size)
+		}
+		if size > 0 && assets.TotalCount != len(inUse) {
+			t.Errorf("page %d has total %d, want %d", page, assets.TotalCount, len(inUse))
+		}
+		seen = append(seen, assetIDs(assets.GetAsset)...)
+	}
+	sameIDs(t, seen, inUse)
+}
+
+// assetPages follows the cursors of list from the first page to the last and returns the ids in the order listed
+func assetPages(t *testing.T, list func(context.Context, *models.FiltersCheck) (models.TotalGetAsset, error), filters models.FiltersCheck) []string {
+	t.Helper()
+	ids := make([]string, 0)
+	for page := 0; ; page++ {
+		assets, err := list(ctx, &filters)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, assetIDs(assets.GetAsset)...)
+		if assets.NextCursor == "" {
+			return ids
+		}
+		if page > 10 {
+			t.Fatal("the cursors do not end")
+		}
+		cursor, err := utils.DecodeCursor(assets.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filters.Cursor = &cursor
+	}
+}
+
+func TestGetAssetsCursor(t *testing.T) {
+	inv := seedInventory(t)
+	byBrand := []string{inv.dell, inv.hp, inv.lenovo, inv.mouse}
+
+	for _, limit := range []int{1, 3, utils.DefaultLimit} {
+		got := assetPages(t, dbhelper.GetAssets, models.FiltersCheck{Limit: limit, Sort: models.ListSort{Field: "brand"}})
+		if strings.Join(got, ",") != strings.Join(byBrand, ",") {
+			t.Errorf("pages of %d by brand got %v, want %v", limit, got, byBrand)
+		}
+		got = assetPages(t, dbhelper.GetAssets, models.FiltersCheck{Limit: limit, Sort: models.ListSort{Field: "brand", Desc: true}})
+		for i := range got {
+			if got[i] != byBrand[len(byBrand)-1-i] {
+				t.Errorf("pages of %d by brand descending got %v", limit, got)
+				break
+			}
+		}
+	}
+
+	// the lenovo and hp laptops have the same warranty, the id keeps every asset on exactly one page
+	all := join(inv.available, inv.assigned, inv.deleted)
+	for _, sort := range []models.ListSort{{Field: "warrantyExpiry"}, {Field: "purchaseDate", Desc: true}, {Field: "assignee"}} {
+		got := assetPages(t, dbhelper.GetAssetsWithFilters, models.FiltersCheck{Available: true, Assigned: true, Deleted: true,
+			Pagination: true, Limit: 1, Sort: sort})
+		if len(got) != len(all) {
+			t.Errorf("pages by %s got %v", sort, got)
+		}
+		sameIDs(t, got, all)
+	}
+}
+
+func TestGetAssetsConditions(t *testing.T) {
+	inv := seedInventory(t)
+	today := time.Now().Truncate(24 * time.Hour)
+
+	cases := []struct {
+		name       string
+		conditions []models.Condition
+		want       []string
+	}{
+		{"warranty ended", []models.Condition{{Field: "warrantyExpiry", Op: models.OpLt, Value: today}}, []string{inv.mouse}},
+		{"held by Asha", []models.Condition{{Field: "assignee", Op: models.OpEq, Value: "Asha"}}, inv.assigned},
+		{"available or assigned", []models.Condition{{Field: "status", Op: models.OpIn, Value: []string{utils.Available, utils.Assigned}}},
+			join(inv.available, inv.assigned)},
+		{"dell laptops", []models.Condition{{Field: "brand", Op: models.OpContains, Value: "ELL"},
+			{Field: "assetType", Op: models.OpEq, Value: string(models.Laptop)}}, []string{inv.dell, inv.broken}},
+		{"bought then", []models.Condition{{Field: "purchaseDate", Op: models.OpEq, Value: purchased},
+			{Field: "status", Op: models.OpNe, Value: utils.Deleted}}, join(inv.available, inv.assigned)},
+		{"in the store room", []models.Condition{{Field: "location", Op: models.OpEq, Value: "Store room"}}, []string{inv.dell}},
+		{"wildcards taken as typed", []models.Condition{{Field: "brand", Op: models.OpContains, Value: "%"}}, []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assets, err := dbhelper.GetAssetsWithFilters(ctx, &models.FiltersCheck{Available: true, Assigned: true, Deleted: true,
+				Pagination: true, Limit: utils.DefaultLimit, Conditions: c.conditions})
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameIDs(t, assetIDs(assets.GetAsset), c.want)
+			if assets.TotalCount != len(c.want) {
+				t.Errorf("got total %d, want %d", assets.TotalCount, len(c.want))
+			}
+		})
+	}
+}
+
+func TestGetAssetsKeepsAssetsOfFormerEmployees(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	employee := seedEmployee(t, "Asha")
+	asset := seedAsset(t, user, newAsset(models.Laptop, "Dell", purchased.AddDate(3, 0, 0)))
+	assign(t, user, employee, asset, assignedOn)
+	retrieve(t, user, employee, asset, assignedOn.AddDate(0, 1, 0))
+	if err := dbhelper.DeleteEmployee(ctx, employee, user, models.Employee{ArchiveReason: "left"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assets, err := dbhelper.GetAssets(ctx, &models.FiltersCheck{Limit: utils.DefaultLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameIDs(t, assetIDs(assets.GetAsset), []string{asset})
+}
+
+func TestAssignmentHistory(t *testing.T) {
+	inv := seedInventory(t)
+
+	history, err := dbhelper.EmployeeHistory(ctx, inv.hp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	holders := make([]string, 0)
+	for _, holder := range history {
+		if !holder.RetrievedDate.Valid || holder.RetrievalReason != "returned" {
+			t.Errorf("got open assignment %+v of a returned asset", holder)
+		}
+		holders = append(holders, holder.ID)
+	}
+	sameIDs(t, holders, []string{inv.ravi, inv.asha})
+
+	held, err := dbhelper.GetAssetHistory(ctx, inv.asha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heldIDs := make([]string, 0)
+	for _, asset := range held {
+		heldIDs = append(heldIDs, asset.ID)
+	}
+	sameIDs(t, heldIDs, []string{inv.lenovo, inv.hp, inv.mouse})
+
+	everyone, err := dbhelper.GetAssetHistory(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(everyone) != 4 {
+		t.Errorf("got %d assignments of assets in use, want 4", len(everyone))
+	}
+
+	counts := []struct {
+		name  string
+		count func() (int, error)
+		want  int
+	}{
+		{"holders of the lenovo", func() (int, error) { return dbhelper.GetAssignedEmployee(ctx, models.Asset{ID: inv.lenovo}) }, 1},
+		{"holders of the hp", func() (int, error) { return dbhelper.GetAssignedEmployee(ctx, models.Asset{ID: inv.hp}) }, 0},
+		{"assets held by asha", func() (int, error) { return dbhelper.GetAssignedAsset(ctx, inv.asha) }, 2},
+		{"assets held by ravi", func() (int, error) { return dbhelper.GetAssignedAsset(ctx, inv.ravi) }, 0},
+	}
+	for _, c := range counts {
+		got, err := c.count()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != c.want {
+			t.Errorf("got %d %s, want %d", got, c.name, c.want)
+		}
+	}
+}
+
+func TestAssetQuantities(t *testing.T) {
+	seedInventory(t)
+
+	totals, err := dbhelper.GetTotalAssetQuantities(ctx, models.GetAssetQuantity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totals.TotalAssets != 4 || totals.DistributedAssets != 2 || totals.AvailableAssets != 2 {
+		t.Errorf("got totals %+v", totals)
+	}
+
+	cases := []struct {
+		filter         string
+		laptops, mouse int
+	}{
+		{"total", 3, 1},
+		{"available", 2, 0},
+		{"distributed", 1, 1},
+	}
+	for _, c := range cases {
+		quantities, err := dbhelper.GetAssetQuantities(ctx, c.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quantities.LaptopQuantity != c.laptops || quantities.MouseQuantity != c.mouse {
+			t.Errorf("%s: got %+v", c.filter, quantities)
+		}
+	}
+}
+
+func TestRetrieveIsDatedOnce(t *testing.T) {
+	inv := seedInventory(t)
+	retrieve(t, inv.user, inv.asha, inv.lenovo, time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	// retrieving again must not overwrite the date of the first retrieval
+	retrieve(t, inv.user, inv.asha, inv.lenovo, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC))
+	history, err := dbhelper.EmployeeHistory(ctx, inv.lenovo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || !history[0].RetrievedDate.Valid || history[0].RetrievedDate.Time.Month() != time.May {
+		t.Errorf("got history %+v", history)
+	}
+}
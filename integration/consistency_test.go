@@ -0,0 +1,134 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"testing"
+)
+
+// seedDrift seeds an asset breaking each consistency check and returns the ids each check should report
+func seedDrift(t *testing.T) map[string][]string {
+	t.Helper()
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	asha := seedEmployee(t, "Asha")
+	ravi := seedEmployee(t, "Ravi")
+	expiry := purchased.AddDate(3, 0, 0)
+
+	flagged := seedAsset(t, user, newAsset(models.Mouse, "Logitech", expiry))
+	orphaned := seedAsset(t, user, newAsset(models.Mouse, "HP", expiry))
+	unmarked := seedAsset(t, user, newAsset(models.Mouse, "Dell", expiry))
+	shared := seedAsset(t, user, newAsset(models.Mouse, "Lenovo", expiry))
+	bare := seedAsset(t, user, newAsset(models.Laptop, "Acer", expiry))
+	reassigned := seedAsset(t, user, newAsset(models.Mouse, "Zebronics", expiry))
+
+	assign(t, user, asha, unmarked, assignedOn)
+	assign(t, user, asha, shared, assignedOn)
+	assign(t, user, ravi, reassigned, assignedOn)
+	drift := []string{
+		`UPDATE assets SET is_available = false WHERE id = '` + flagged + `'`,
+		`UPDATE assets SET status = 'assigned', is_available = false WHERE id = '` + orphaned + `'`,
+		`UPDATE assets SET status = 'available', is_available = true WHERE id = '` + unmarked + `'`,
+		`INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_date) VALUES ('` + ravi + `', '` + shared + `', '2024-04-01')`,
+		`DELETE FROM laptop_specifications WHERE asset_id = '` + bare + `'`,
+		`UPDATE employee_asset_relation SET archived_at = '2024-05-01' WHERE asset_id = '` + reassigned + `'`,
+		`INSERT INTO employee_asset_relation(employee_id, asset_id, assigned_date) VALUES ('` + asha + `', '` + reassigned + `', '2024-05-01')`,
+	}
+	for _, SQL := range drift {
+		if _, err := database.AssetManagement.Exec(SQL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var archived string
+	err := database.AssetManagement.Get(&archived, `SELECT id FROM employee_asset_relation WHERE asset_id = $1 AND archived_at IS NOT NULL`, reassigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string][]string{
+		dbhelper.CheckArchivedWithoutRetrieval: {archived},
+		dbhelper.CheckMultipleOpenAssignments:  {shared},
+		dbhelper.CheckAssignedWithoutHolder:    {orphaned},
+		dbhelper.CheckHeldButNotAssigned:       {unmarked},
+		dbhelper.CheckAssetAvailability:        {flagged},
+		dbhelper.CheckMissingLaptopSpec:        {bare},
+	}
+}
+
+func foundByCheck(t *testing.T) map[string][]string {
+	t.Helper()
+	inconsistencies, err := dbhelper.CheckConsistency(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string][]string)
+	for _, inconsistency := range inconsistencies {
+		found[inconsistency.Check] = append(found[inconsistency.Check], inconsistency.ID)
+	}
+	return found
+}
+
+func TestCheckConsistency(t *testing.T) {
+	want := seedDrift(t)
+	found := foundByCheck(t)
+	for check, ids := range want {
+		t.Run(check, func(t *testing.T) {
+			sameIDs(t, found[check], ids)
+		})
+	}
+	if len(found) != len(want) {
+		t.Errorf("got inconsistencies %v", found)
+	}
+}
+
+func TestRepairConsistency(t *testing.T) {
+	want := seedDrift(t)
+
+	preview, err := dbhelper.RepairConsistency(ctx, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, repair := range preview {
+		sameIDs(t, repair.IDs, want[repair.Check])
+	}
+	if len(preview) != len(want) {
+		t.Errorf("got preview %+v", preview)
+	}
+	if found := foundByCheck(t); len(found) != len(want) {
+		t.Errorf("the dry run changed the data, found %v", found)
+	}
+
+	only, err := dbhelper.RepairConsistency(ctx, []string{dbhelper.CheckMissingLaptopSpec}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(only) != 1 || only[0].Check != dbhelper.CheckMissingLaptopSpec {
+		t.Errorf("got repairs %+v when repairing one check", only)
+	}
+	if found := foundByCheck(t); len(found) != len(want)-1 || found[dbhelper.CheckMissingLaptopSpec] != nil {
+		t.Errorf("got inconsistencies %v after repairing one check", found)
+	}
+
+	if _, err := dbhelper.RepairConsistency(ctx, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if found := foundByCheck(t); len(found) != 0 {
+		t.Errorf("got inconsistencies %v after the repair", found)
+	}
+
+	var open int
+	err = database.AssetManagement.Get(&open, `SELECT COUNT(*)
+                                                FROM   employee_asset_relation
+                                                WHERE  asset_id = $1
+                                                AND    retrieved_date IS NULL
+                                                AND    archived_at IS NULL`, want[dbhelper.CheckMultipleOpenAssignments][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open != 1 {
+		t.Errorf("the shared asset has %d open assignments after the repair", open)
+	}
+}
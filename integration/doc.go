@@ -0,0 +1,6 @@
+// Package integration tests the database helpers against a real Postgres. TestMain starts a throwaway Postgres 13,
+// the version the service runs on, and applies the embedded migrations to it. The tests are behind the integration build
+// tag because the first run downloads the Postgres binaries:
+//
+//	go test -tags integration ./integration/...
+package integration
@@ -0,0 +1,247 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+// staff is the data the employee tests list: Asha in the Platform team reporting to Meera, holding a mouse, Ravi
+// holding a laptop, Kiran who is no longer an employee and Dev who was deleted
+type staff struct {
+	user                           string
+	asha, ravi, meera, kiran, dev  string
+	engineering, platform          string
+	active, notAnEmployee, deleted []string
+}
+
+func seedStaff(t *testing.T) staff {
+	t.Helper()
+	reset(t)
+	var s staff
+	s.user = seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	s.asha = seedEmployee(t, "Asha")
+	s.ravi = seedEmployee(t, "Ravi")
+	s.meera = seedEmployee(t, "Meera")
+	s.kiran = seedEmployee(t, "Kiran")
+	s.dev = seedEmployee(t, "Dev")
+
+	var err error
+	s.engineering, err = dbhelper.CreateDepartment(ctx, &models.Department{Name: "Engineering"}, s.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.platform, err = dbhelper.CreateDepartment(ctx, &models.Department{Name: "Platform", ParentID: null.StringFrom(s.engineering)}, s.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateEmployee(t, s.asha, func(e *models.EmployeeDetails) {
+		e.DepartmentID = null.StringFrom(s.platform)
+		e.ManagerID = null.StringFrom(s.meera)
+	})
+	updateEmployee(t, s.kiran, func(e *models.EmployeeDetails) { e.Status = utils.NotAnEmployee })
+	if err := dbhelper.DeleteEmployee(ctx, s.dev, s.user, models.Employee{ArchiveReason: "left"}); err != nil {
+		t.Fatal(err)
+	}
+
+	assign(t, s.user, s.asha, seedAsset(t, s.user, newAsset(models.Mouse, "Logitech", purchased.AddDate(3, 0, 0))), assignedOn)
+	assign(t, s.user, s.ravi, seedAsset(t, s.user, newAsset(models.Laptop, "Dell", purchased.AddDate(3, 0, 0))), assignedOn)
+
+	s.active = []string{s.asha, s.ravi, s.meera}
+	s.notAnEmployee = []string{s.kiran}
+	s.deleted = []string{s.dev}
+	return s
+}
+
+func TestGetEmployee(t *testing.T) {
+	s := seedStaff(t)
+
+	cases := []struct {
+		name    string
+		filters models.FiltersCheck
+		want    []string
+	}{
+		{"active", models.FiltersCheck{}, s.active},
+		{"deleted", models.FiltersCheck{Deleted: true}, s.deleted},
+		{"not an employee", models.FiltersCheck{NotAnEmployee: true}, s.notAnEmployee},
+		{"deleted or not an employee", models.FiltersCheck{Deleted: true, NotAnEmployee: true}, join(s.deleted, s.notAnEmployee)},
+		{"deleted or not an employee named dev", models.FiltersCheck{Deleted: true, NotAnEmployee: true, SearchedName: "dev"},
+			s.deleted},
+		{"searched", models.FiltersCheck{SearchedName: "RA"}, []string{s.ravi}},
+		{"by id", models.FiltersCheck{EmployeeID: s.asha}, []string{s.asha}},
+		{"by id and name", models.FiltersCheck{EmployeeID: s.asha, SearchedName: "asha"}, []string{s.asha}},
+		{"by id and another name", models.FiltersCheck{EmployeeID: s.asha, SearchedName: "ravi"}, []string{}},
+		{"deleted by id", models.FiltersCheck{EmployeeID: s.dev, Deleted: true}, s.deleted},
+		{"holding a mouse", models.FiltersCheck{AssetTypes: pq.StringArray{utils.Mouse}}, []string{s.asha}},
+		{"holding a laptop or mouse", models.FiltersCheck{AssetTypes: pq.StringArray{utils.Laptop, utils.Mouse}}, []string{s.asha, s.ravi}},
+		{"in engineering", models.FiltersCheck{DepartmentID: s.engineering}, []string{s.asha}},
+		{"in platform", models.FiltersCheck{DepartmentID: s.platform}, []string{s.asha}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := c.filters
+			filters.Limit = utils.DefaultLimit
+			employees, err := dbhelper.GetEmployee(ctx, &filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameIDs(t, employeeIDs(employees.GetEmployee), c.want)
+			if employees.TotalCount != len(c.want) {
+				t.Errorf("got total %d, want %d", employees.TotalCount, len(c.want))
+			}
+		})
+	}
+}
+
+func TestGetEmployeeDetails(t *testing.T) {
+	s := seedStaff(t)
+	employees, err := dbhelper.GetEmployee(ctx, &models.FiltersCheck{EmployeeID: s.asha, Limit: utils.DefaultLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employees.GetEmployee) != 1 {
+		t.Fatalf("got %d employees", len(employees.GetEmployee))
+	}
+	asha := employees.GetEmployee[0]
+	if asha.AssetQuantity != 1 || asha.Department.String != "Platform" || asha.ManagerName.String != "Meera" || asha.Type != "employee" {
+		t.Errorf("got employee %+v", asha)
+	}
+
+	employees, err = dbhelper.GetEmployee(ctx, &models.FiltersCheck{Deleted: true, Limit: utils.DefaultLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employees.GetEmployee) != 1 {
+		t.Fatalf("got %d deleted employees", len(employees.GetEmployee))
+	}
+	dev := employees.GetEmployee[0]
+	if dev.Status != utils.Deleted || !dev.ArchivedAt.Valid || dev.ArchiveReason.String != "left" || dev.DeletedBy.String != s.user {
+		t.Errorf("got deleted employee %+v", dev)
+	}
+}
+
+func TestGetEmployeePages(t *testing.T) {
+	s := seedStaff(t)
+
+	// pages follow the order the employees were created in
+	for page, want := range [][]string{{s.asha, s.ravi}, {s.meera}, {}} {
+		employees, err := dbhelper.GetEmployee(ctx, &models.FiltersCheck{Limit: 2, Page: page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := employeeIDs(employees.GetEmployee)
+		if len(got) != len(want) {
+			t.Fatalf("page %d has %v, want %v", page, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("page %d has %v, want %v", page, got, want)
+				break
+			}
+		}
+		if len(want) > 0 && employees.TotalCount != len(s.active) {
+			t.Errorf("page %d has total %d, want %d", page, employees.TotalCount, len(s.active))
+		}
+	}
+}
+
+func TestGetEmployeeCursor(t *testing.T) {
+	s := seedStaff(t)
+
+	cases := []struct {
+		name    string
+		filters models.FiltersCheck
+		want    []string
+	}{
+		{"by name", models.FiltersCheck{Sort: models.ListSort{Field: "name"}}, []string{s.asha, s.meera, s.ravi}},
+		{"by name descending", models.FiltersCheck{Sort: models.ListSort{Field: "name", Desc: true}}, []string{s.ravi, s.meera, s.asha}},
+		{"holding assets", models.FiltersCheck{Sort: models.ListSort{Field: "email"},
+			Conditions: []models.Condition{{Field: "assetQuantity", Op: models.OpGt, Value: int64(0)}}}, []string{s.asha, s.ravi}},
+		{"in platform under meera", models.FiltersCheck{Conditions: []models.Condition{
+			{Field: "department", Op: models.OpEq, Value: "Platform"}, {Field: "manager", Op: models.OpContains, Value: "meer"}}},
+			[]string{s.asha}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := c.filters
+			filters.Limit = 1
+			got := make([]string, 0)
+			for page := 0; page <= len(c.want); page++ {
+				employees, err := dbhelper.GetEmployee(ctx, &filters)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(employees.GetEmployee) > 0 && employees.TotalCount != len(c.want) {
+					t.Errorf("got total %d, want %d", employees.TotalCount, len(c.want))
+				}
+				got = append(got, employeeIDs(employees.GetEmployee)...)
+				if employees.NextCursor == "" {
+					break
+				}
+				cursor, err := utils.DecodeCursor(employees.NextCursor)
+				if err != nil {
+					t.Fatal(err)
+				}
+				filters.Cursor = &cursor
+			}
+			if strings.Join(got, ",") != strings.Join(c.want, ",") {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestCreateEmployeeKeepsExistingEmail(t *testing.T) {
+	s := seedStaff(t)
+	err := dbhelper.CreateEmployee(ctx, &models.EmployeeDetails{Name: "Asha Rao", Email: "asha@remotestate.com", PhoneNo: "9876500000", Type: "intern"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	employees, err := dbhelper.GetEmployee(ctx, &models.FiltersCheck{SearchedName: "asha", Limit: utils.DefaultLimit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(employees.GetEmployee) != 1 || employees.GetEmployee[0].ID != s.asha || employees.GetEmployee[0].Name != "Asha" {
+		t.Errorf("got employees %+v after creating an existing email again", employees.GetEmployee)
+	}
+}
+
+func TestManagers(t *testing.T) {
+	s := seedStaff(t)
+
+	cycle, err := dbhelper.IsManagerCycle(ctx, database.AssetManagement, s.meera, s.asha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cycle {
+		t.Error("Meera reporting to Asha, who reports to Meera, is not a cycle")
+	}
+	cycle, err = dbhelper.IsManagerCycle(ctx, database.AssetManagement, s.ravi, s.asha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cycle {
+		t.Error("Ravi reporting to Asha is a cycle")
+	}
+
+	held, err := dbhelper.GetAssetHistory(ctx, s.asha)
+	if err != nil || len(held) != 1 {
+		t.Fatalf("got asset history %+v, %v", held, err)
+	}
+	notice, err := dbhelper.GetManagerNotice(ctx, s.asha, held[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notice.ManagerName != "Meera" || notice.EmployeeName != "Asha" || notice.Brand != "Logitech" {
+		t.Errorf("got notice %+v", notice)
+	}
+}
@@ -0,0 +1,268 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+var (
+	// ctx is the context the helpers run with, the tests do not cancel their queries
+	ctx = context.Background()
+
+	purchased  = time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	assignedOn = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// reset empties every table so that a test only sees the rows it seeds
+func reset(t *testing.T) {
+	t.Helper()
+	var tables []string
+	err := database.AssetManagement.Select(&tables, `SELECT tablename
+														FROM   pg_tables
+														WHERE  schemaname = 'public'
+														AND    tablename != 'schema_migrations'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range tables {
+		tables[i] = pq.QuoteIdentifier(tables[i])
+	}
+	if _, err := database.AssetManagement.Exec(`TRUNCATE ` + strings.Join(tables, ", ") + ` CASCADE`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func seedUser(t *testing.T, name, email, phoneNo string) string {
+	t.Helper()
+	if err := dbhelper.CreateUser(ctx, name, email, "hashed-"+name, phoneNo); err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := dbhelper.FetchPasswordAndID(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return credentials.ID
+}
+
+func seedEmployee(t *testing.T, name string) string {
+	t.Helper()
+	email := strings.ToLower(name) + "@remotestate.com"
+	err := dbhelper.CreateEmployee(ctx, &models.EmployeeDetails{Name: name, Email: email, PhoneNo: "9876543210", Type: "employee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	if err := database.AssetManagement.Get(&id, `SELECT id FROM employee WHERE email = $1`, email); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// updateEmployee changes an employee the way the update form does, sending every field back
+func updateEmployee(t *testing.T, employeeID string, change func(*models.EmployeeDetails)) {
+	t.Helper()
+	list, err := dbhelper.GetEmployee(ctx, &models.FiltersCheck{EmployeeID: employeeID, Limit: 1})
+	if err != nil || len(list.GetEmployee) != 1 {
+		t.Fatalf("cannot find employee %s: %v", employeeID, err)
+	}
+	current := list.GetEmployee[0]
+	employee := models.EmployeeDetails{
+		ID:           current.ID,
+		Name:         current.Name,
+		Type:         current.Type,
+		Email:        current.Email,
+		PhoneNo:      current.PhoneNo,
+		Status:       current.Status,
+		DepartmentID: current.DepartmentID,
+		ManagerID:    current.ManagerID,
+	}
+	change(&employee)
+	if err := dbhelper.UpdateEmployee(ctx, &employee); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newAsset(assetType models.AssetType, brand string, warrantyExpiry time.Time) models.CreateAsset {
+	return models.CreateAsset{
+		Brand:              brand,
+		Model:              brand + " " + string(assetType),
+		SerialNo:           "SN-" + strings.ToUpper(brand) + "-" + strings.ReplaceAll(string(assetType), " ", ""),
+		AssetType:          assetType,
+		PurchasedDate:      purchased,
+		WarrantyStartDate:  purchased,
+		WarrantyExpiryDate: warrantyExpiry,
+		OwnedBy:            utils.RemoteState,
+	}
+}
+
+// seedAsset creates an asset with its specification and returns its id
+func seedAsset(t *testing.T, userID string, asset models.CreateAsset) string {
+	t.Helper()
+	tag, err := repository.PostgresAssets{}.Create(ctx, &asset, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id string
+	if err := database.AssetManagement.Get(&id, `SELECT id FROM assets WHERE asset_tag = $1`, tag); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func assign(t *testing.T, userID, employeeID, assetID string, on time.Time) {
+	t.Helper()
+	err := repository.PostgresAssets{}.Assign(ctx, &models.EmployeeAssetRelation{
+		EmployeeID:     employeeID,
+		AssetID:        assetID,
+		AssignedDate:   on,
+		AssignmentType: utils.AssignmentPermanent,
+	}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func retrieve(t *testing.T, userID, employeeID, assetID string, on time.Time) {
+	t.Helper()
+	err := repository.PostgresAssets{}.Retrieve(ctx, models.AssetRetrievalDetails{
+		RetrievedDate:   on,
+		RetrievalReason: "returned",
+		EmployeeID:      employeeID,
+		AssetID:         assetID,
+	}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func deleteAsset(t *testing.T, userID, assetID string, assetType models.AssetType) {
+	t.Helper()
+	err := repository.PostgresAssets{}.Delete(ctx, models.Asset{ID: assetID, AssetType: assetType, DeleteReason: "broken"}, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// inventory is the data most asset tests list and filter:
+//
+//	dell    laptop  available, warranty ending in two months, in a room of the Pune office
+//	lenovo  laptop  assigned to Asha
+//	hp      laptop  available, held by Ravi and then by Asha
+//	mouse   mouse   assigned to Asha, warranty expired
+//	broken  laptop  deleted after Ravi returned it
+type inventory struct {
+	user                                string
+	asha, ravi                          string
+	dell, lenovo, hp, mouse, broken     string
+	office, room, engineering, platform string
+	available, assigned, deleted        []string
+}
+
+func seedInventory(t *testing.T) inventory {
+	t.Helper()
+	reset(t)
+	var inv inventory
+	inv.user = seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	inv.asha = seedEmployee(t, "Asha")
+	inv.ravi = seedEmployee(t, "Ravi")
+
+	later := time.Now().AddDate(3, 0, 0)
+	inv.dell = seedAsset(t, inv.user, newAsset(models.Laptop, "Dell", time.Now().AddDate(0, 2, 0)))
+	inv.lenovo = seedAsset(t, inv.user, newAsset(models.Laptop, "Lenovo", later))
+	inv.hp = seedAsset(t, inv.user, newAsset(models.Laptop, "HP", later))
+	inv.mouse = seedAsset(t, inv.user, newAsset(models.Mouse, "Logitech", purchased.AddDate(1, 0, 0)))
+	inv.broken = seedAsset(t, inv.user, newAsset(models.Laptop, "Dell", later))
+
+	assign(t, inv.user, inv.asha, inv.lenovo, assignedOn)
+	assign(t, inv.user, inv.ravi, inv.hp, assignedOn)
+	retrieve(t, inv.user, inv.ravi, inv.hp, assignedOn.AddDate(0, 1, 0))
+	assign(t, inv.user, inv.asha, inv.hp, assignedOn.AddDate(0, 2, 0))
+	retrieve(t, inv.user, inv.asha, inv.hp, assignedOn.AddDate(0, 3, 0))
+	assign(t, inv.user, inv.asha, inv.mouse, assignedOn)
+	assign(t, inv.user, inv.ravi, inv.broken, assignedOn)
+	retrieve(t, inv.user, inv.ravi, inv.broken, assignedOn.AddDate(0, 1, 0))
+	deleteAsset(t, inv.user, inv.broken, models.Laptop)
+
+	var err error
+	inv.office, err = dbhelper.CreateLocation(ctx, &models.LocationDetails{Name: "Pune office", Type: utils.LocationSite}, inv.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv.room, err = dbhelper.CreateLocation(ctx, &models.LocationDetails{Name: "Store room", Type: utils.LocationRoom,
+		ParentID: null.StringFrom(inv.office)}, inv.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.Tx(ctx, func(tx *sqlx.Tx) error {
+		return dbhelper.TransferAsset(ctx, tx, &models.AssetTransfer{AssetID: inv.dell, ToLocationID: null.StringFrom(inv.room)}, inv.user)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv.engineering, err = dbhelper.CreateDepartment(ctx, &models.Department{Name: "Engineering"}, inv.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inv.platform, err = dbhelper.CreateDepartment(ctx, &models.Department{Name: "Platform", ParentID: null.StringFrom(inv.engineering)}, inv.user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updateEmployee(t, inv.asha, func(e *models.EmployeeDetails) { e.DepartmentID = null.StringFrom(inv.platform) })
+
+	inv.available = []string{inv.dell, inv.hp}
+	inv.assigned = []string{inv.lenovo, inv.mouse}
+	inv.deleted = []string{inv.broken}
+	return inv
+}
+
+func assetIDs(assets []models.GetAsset) []string {
+	ids := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		ids = append(ids, asset.ID)
+	}
+	return ids
+}
+
+func employeeIDs(employees []models.GetEmployee) []string {
+	ids := make([]string, 0, len(employees))
+	for _, employee := range employees {
+		ids = append(ids, employee.ID)
+	}
+	return ids
+}
+
+// join concatenates id lists into a new slice
+func join(lists ...[]string) []string {
+	joined := make([]string, 0)
+	for _, list := range lists {
+		joined = append(joined, list...)
+	}
+	return joined
+}
+
+// sameIDs checks got and want hold the same ids in any order, each once
+func sameIDs(t *testing.T, got, want []string) {
+	t.Helper()
+	got = append([]string(nil), got...)
+	want = append([]string(nil), want...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got ids %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+const (
+	testDatabase = "asset_management"
+	testUser     = "postgres"
+	testPassword = "postgres"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	port, err := freePort()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot find a free port: %v\n", err)
+		return 1
+	}
+	runtimePath, err := os.MkdirTemp("", "asset-management-postgres")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot create the postgres runtime directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(runtimePath)
+
+	var postgresLog bytes.Buffer
+	postgres := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Version(embeddedpostgres.V13).
+		Port(port).
+		Database(testDatabase).
+		Username(testUser).
+		Password(testPassword).
+		RuntimePath(runtimePath).
+		Logger(&postgresLog))
+	if err := postgres.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot start postgres: %v\n%s", err, postgresLog.String())
+		return 1
+	}
+	defer func() {
+		if err := postgres.Stop(); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot stop postgres: %v\n", err)
+		}
+	}()
+
+	err = database.ConnectAndMigrate("localhost", strconv.Itoa(int(port)), testDatabase, testUser, testPassword, database.SSLModeDisable)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot migrate the database: %v\n", err)
+		return 1
+	}
+	defer database.ShutdownDatabase()
+
+	return m.Run()
+}
+
+func freePort() (uint32, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return uint32(listener.Addr().(*net.TCPAddr).Port), nil
+}
@@ -0,0 +1,72 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/metrics"
+	"InternalAssetManagement/models"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestInventoryMetrics(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	asha := seedEmployee(t, "Asha")
+	soon := time.Now().AddDate(0, 0, 10)
+	later := time.Now().AddDate(1, 0, 0)
+
+	dell := seedAsset(t, user, newAsset(models.Laptop, "Dell", soon))
+	seedAsset(t, user, newAsset(models.Laptop, "HP", later))
+	logitech := seedAsset(t, user, newAsset(models.Mouse, "Logitech", soon))
+	deleted := seedAsset(t, user, newAsset(models.Mouse, "Zebronics", soon))
+	assign(t, user, asha, dell, assignedOn)
+	assign(t, user, asha, logitech, assignedOn)
+	retrieve(t, user, asha, logitech, assignedOn.AddDate(0, 1, 0))
+	deleteAsset(t, user, deleted, models.Mouse)
+
+	inventory, err := dbhelper.GetInventoryMetrics(ctx, metrics.WarrantyWindow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(inventory.Assets, func(i, j int) bool {
+		if inventory.Assets[i].AssetType != inventory.Assets[j].AssetType {
+			return inventory.Assets[i].AssetType < inventory.Assets[j].AssetType
+		}
+		return inventory.Assets[i].Status < inventory.Assets[j].Status
+	})
+	want := []models.AssetCount{
+		{AssetType: models.Laptop, Status: "assigned", Count: 1},
+		{AssetType: models.Laptop, Status: "available", Count: 1},
+		{AssetType: models.Mouse, Status: "available", Count: 1},
+		{AssetType: models.Mouse, Status: "deleted", Count: 1},
+	}
+	if !reflect.DeepEqual(inventory.Assets, want) {
+		t.Errorf("assets: got %+v, want %+v", inventory.Assets, want)
+	}
+	if inventory.OpenAssignments != 1 {
+		t.Errorf("open assignments: got %d, want 1", inventory.OpenAssignments)
+	}
+	// the deleted mouse does not count, the laptop held by Asha and the mouse back in store do
+	if inventory.ExpiringWarranties != 2 {
+		t.Errorf("expiring warranties: got %d, want 2", inventory.ExpiringWarranties)
+	}
+}
+
+func TestMigrationVersion(t *testing.T) {
+	version, dirty, err := database.MigrationVersion(ctx, database.AssetManagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := database.LatestMigration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest || dirty {
+		t.Errorf("database is at version %d, dirty %t, want %d", version, dirty, latest)
+	}
+}
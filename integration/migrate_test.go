@@ -0,0 +1,46 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"testing"
+)
+
+func TestMigrationsRollBackAndReapply(t *testing.T) {
+	reset(t)
+	m, err := database.NewMigrator(database.AssetManagement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses, latest, _, err := database.MigrationsStatus(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) == 0 || statuses[len(statuses)-1].Version != latest {
+		t.Fatalf("database is at version %d, want every migration of %+v applied", latest, statuses)
+	}
+
+	// every down migration runs, so each of them has to undo its up migration for the next one to succeed
+	if err := m.Steps(-len(statuses)); err != nil {
+		t.Fatalf("cannot roll back every migration: %v", err)
+	}
+	var tables int
+	if err := database.AssetManagement.Get(&tables, `SELECT count(*) FROM pg_tables WHERE schemaname = 'public' AND tablename != 'schema_migrations'`); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("%d tables are left after rolling back every migration", tables)
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("cannot apply the migrations again: %v", err)
+	}
+	version, dirty, err := m.Version()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest || dirty {
+		t.Errorf("database is at version %d, dirty %t after migrating up again, want %d", version, dirty, latest)
+	}
+}
@@ -0,0 +1,144 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRestoreAsset(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	dell := seedAsset(t, user, newAsset(models.Laptop, "Dell", purchased.AddDate(3, 0, 0)))
+	restore := models.RestoreAsset{ID: dell, RestoreReason: "deleted by mistake"}
+	if err := (repository.PostgresAssets{}).Restore(ctx, restore, user); !errors.Is(err, repository.ErrNotDeleted) {
+		t.Fatalf("restoring an asset that is not deleted returned %v", err)
+	}
+
+	deleteAsset(t, user, dell, models.Laptop)
+	if err := (repository.PostgresAssets{}).Restore(ctx, restore, user); err != nil {
+		t.Fatal(err)
+	}
+	var restored struct {
+		Status      string `db:"status"`
+		IsAvailable bool   `db:"is_available"`
+		Archived    bool   `db:"archived"`
+		SpecAlive   bool   `db:"spec_alive"`
+	}
+	err := database.AssetManagement.Get(&restored, `SELECT a.status,
+                                                           a.is_available,
+                                                           a.archived_at IS NOT NULL AS archived,
+                                                           EXISTS(SELECT 1 FROM laptop_specifications WHERE asset_id = a.id AND archived_at IS NULL) AS spec_alive
+                                                    FROM   assets a
+                                                    WHERE  a.id = $1`, dell)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Status != utils.Available || !restored.IsAvailable || restored.Archived || !restored.SpecAlive {
+		t.Errorf("got %+v after the restore", restored)
+	}
+
+	history, err := dbhelper.GetRestoreHistory(ctx, models.RestoreAssetEntity, dell, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].ArchiveReason.String != "broken" || history[0].DeletedByName.String != "Admin" ||
+		history[0].RestoreReason != restore.RestoreReason || history[0].RestoredByName != "Admin" {
+		t.Errorf("got restore history %+v", history)
+	}
+
+	// a replacement with the same serial number was bought while the asset was deleted
+	deleteAsset(t, user, dell, models.Laptop)
+	seedAsset(t, user, newAsset(models.Laptop, "Dell", purchased.AddDate(3, 0, 0)))
+	if err := (repository.PostgresAssets{}).Restore(ctx, restore, user); !errors.Is(err, repository.ErrRestoreConflict) {
+		t.Errorf("restoring over a taken serial number returned %v", err)
+	}
+}
+
+func TestRestoreEmployee(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	asha := seedEmployee(t, "Asha")
+	restore := models.RestoreEmployee{RestoreReason: "deleted by mistake"}
+
+	if err := dbhelper.DeleteEmployee(ctx, asha, user, models.Employee{ArchiveReason: "left"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := (repository.PostgresEmployees{}).Restore(ctx, asha, restore, user); err != nil {
+		t.Fatal(err)
+	}
+	list, err := dbhelper.GetEmployee(ctx, &models.FiltersCheck{EmployeeID: asha, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.GetEmployee) != 1 || list.GetEmployee[0].Status != utils.Active {
+		t.Errorf("got %+v after the restore", list.GetEmployee)
+	}
+
+	// someone rejoined under the same email in other case while Asha was deleted
+	if err := dbhelper.DeleteEmployee(ctx, asha, user, models.Employee{ArchiveReason: "left"}); err != nil {
+		t.Fatal(err)
+	}
+	err = dbhelper.CreateEmployee(ctx, &models.EmployeeDetails{Name: "Asha Rao", Email: "Asha@remotestate.com", PhoneNo: "9876543210", Type: "employee"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (repository.PostgresEmployees{}).Restore(ctx, asha, restore, user); !errors.Is(err, repository.ErrRestoreConflict) {
+		t.Errorf("restoring over a taken email returned %v", err)
+	}
+}
+
+func TestPurgeArchived(t *testing.T) {
+	reset(t)
+	user := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	asha := seedEmployee(t, "Asha")
+	ravi := seedEmployee(t, "Ravi")
+	expiry := purchased.AddDate(3, 0, 0)
+	old := seedAsset(t, user, newAsset(models.Laptop, "HP", expiry))
+	recent := seedAsset(t, user, newAsset(models.Laptop, "Dell", expiry))
+
+	assign(t, user, ravi, old, assignedOn)
+	retrieve(t, user, ravi, old, assignedOn.AddDate(0, 1, 0))
+	deleteAsset(t, user, old, models.Laptop)
+	deleteAsset(t, user, recent, models.Laptop)
+	for _, id := range []string{ravi, asha} {
+		if err := dbhelper.DeleteEmployee(ctx, id, user, models.Employee{ArchiveReason: "left"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	longAgo := []string{
+		`UPDATE assets SET archived_at = '2020-01-01' WHERE id = '` + old + `'`,
+		`UPDATE laptop_specifications SET archived_at = '2020-01-01' WHERE asset_id = '` + old + `'`,
+		`UPDATE employee SET archived_at = '2020-01-01' WHERE id = '` + ravi + `'`,
+	}
+	for _, SQL := range longAgo {
+		if _, err := database.AssetManagement.Exec(SQL); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purged, err := dbhelper.PurgeArchived(ctx, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if purged.Assets != 1 || purged.Employees != 1 {
+		t.Errorf("got %+v purged", purged)
+	}
+
+	var assets, employees []string
+	if err := database.AssetManagement.Select(&assets, `SELECT id FROM assets`); err != nil {
+		t.Fatal(err)
+	}
+	sameIDs(t, assets, []string{recent})
+	if err := database.AssetManagement.Select(&employees, `SELECT id FROM employee`); err != nil {
+		t.Fatal(err)
+	}
+	sameIDs(t, employees, []string{asha})
+}
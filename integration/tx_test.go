@@ -0,0 +1,39 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestTxReportsCommitFailure(t *testing.T) {
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		// a deferred unique constraint is only checked on commit
+		if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE deferred_check (id INT UNIQUE DEFERRABLE INITIALLY DEFERRED) ON COMMIT DROP`); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO deferred_check VALUES (1), (1)`)
+		return err
+	})
+	if err == nil {
+		t.Fatal("a transaction failing on commit reported success")
+	}
+}
+
+func TestTxStopsAtDeadline(t *testing.T) {
+	deadline, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := database.Tx(deadline, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(deadline, `SELECT pg_sleep(5)`)
+		return err
+	})
+	if err == nil || time.Since(start) > 2*time.Second {
+		t.Fatalf("got %v after %s, want the statement cancelled at the deadline", err, time.Since(start))
+	}
+}
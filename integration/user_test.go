@@ -0,0 +1,197 @@
+//go:build integration
+
+package integration
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestUsers(t *testing.T) {
+	reset(t)
+	id := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+
+	for _, c := range []struct {
+		email, phoneNo string
+		exists         bool
+	}{
+		{"admin@remotestate.com", "9876543210", true},
+		{"admin@remotestate.com", "9876500000", false},
+		{"other@remotestate.com", "9876543210", false},
+	} {
+		exists, err := dbhelper.IsUserExist(ctx, c.email, c.phoneNo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != c.exists {
+			t.Errorf("user %s with phone %s exists: %t, want %t", c.email, c.phoneNo, exists, c.exists)
+		}
+	}
+
+	credentials, err := dbhelper.FetchPasswordAndID(ctx, "admin@remotestate.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.Password != "hashed-Admin" || credentials.Role != "admin" {
+		t.Errorf("got credentials %+v", credentials)
+	}
+	if _, err := dbhelper.FetchPasswordAndID(ctx, "nobody@remotestate.com"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v for an unknown email", err)
+	}
+
+	status, err := dbhelper.GetStatusDetails(ctx, "admin@remotestate.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Type != "authorized" || status.Status != "white_listed" || status.AuthenticationTimes != 0 {
+		t.Errorf("got status %+v of a new user", status)
+	}
+	if err := dbhelper.AlterStatusDetails(ctx, "authorized", "warned", status.AuthenticationTimes, id); err != nil {
+		t.Fatal(err)
+	}
+	status, err = dbhelper.GetStatusDetails(ctx, "admin@remotestate.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Status != "warned" || status.AuthenticationTimes != 1 {
+		t.Errorf("got status %+v after one authentication", status)
+	}
+
+	if err := dbhelper.UpdateUser(ctx, models.RegisterUser{Name: "Head Admin", Email: "head@remotestate.com", PhoneNo: "9876500000"}, "hashed-new", id); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbhelper.AddProfileImage(ctx, id, "https://images.remotestate.com/head.png"); err != nil {
+		t.Fatal(err)
+	}
+	details, err := dbhelper.GetUserDetails(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.UserDetails{Name: "Head Admin", Email: "head@remotestate.com", PhoneNo: "9876500000", Image: "https://images.remotestate.com/head.png"}
+	if *details != want {
+		t.Errorf("got details %+v, want %+v", *details, want)
+	}
+	if _, err := dbhelper.GetUserDetails(ctx, uuid.NewString()); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v for an unknown user", err)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	reset(t)
+	id := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+
+	if _, err := dbhelper.CheckSession(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v before signing in", err)
+	}
+	if err := dbhelper.CreateSession(ctx, &models.Claims{ID: id}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbhelper.CheckSession(ctx, id); err != nil {
+		t.Errorf("got %v after signing in", err)
+	}
+	if err := dbhelper.Logout(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbhelper.CheckSession(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got %v after signing out", err)
+	}
+}
+
+func TestAccessedByDetails(t *testing.T) {
+	reset(t)
+	admin := seedUser(t, "Admin", "admin@remotestate.com", "9876543210")
+	guest := seedUser(t, "Guest", "guest@example.com", "9876500000")
+	if err := dbhelper.UpdateAccessedBy(ctx, guest, "unauthorized"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbhelper.CreateSession(ctx, &models.Claims{ID: admin}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		userType string
+		filters  models.FiltersCheck
+		want     []string
+	}{
+		{"authorized", "authorized", models.FiltersCheck{}, []string{admin}},
+		{"not authorized", "unauthorized", models.FiltersCheck{}, []string{guest}},
+		{"searched", "authorized", models.FiltersCheck{IsSearched: true, SearchedName: "adm"}, []string{admin}},
+		{"searched another name", "authorized", models.FiltersCheck{IsSearched: true, SearchedName: "guest"}, []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			users, err := dbhelper.AccessedByDetails(ctx, c.userType, &c.filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]string, 0, len(users.AccessedBy))
+			for _, user := range users.AccessedBy {
+				ids = append(ids, user.ID)
+				if user.ID == admin && !user.LastLoginTime.Valid {
+					t.Error("the admin has no last sign in time")
+				}
+			}
+			sameIDs(t, ids, c.want)
+		})
+	}
+}
+
+func TestAccessedByDetailsPages(t *testing.T) {
+	reset(t)
+	ids := make(map[string]string)
+	for _, name := range []string{"Meera", "Asha", "Ravi"} {
+		ids[name] = seedUser(t, name, strings.ToLower(name)+"@remotestate.com", "9876543210")
+	}
+	if err := dbhelper.CreateSession(ctx, &models.Claims{ID: ids["Ravi"]}); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		filters models.FiltersCheck
+		want    []string
+	}{
+		{"by name", models.FiltersCheck{}, []string{"Asha", "Meera", "Ravi"}},
+		{"signed in", models.FiltersCheck{Conditions: []models.Condition{
+			{Field: "lastLogin", Op: models.OpGt, Value: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)}}}, []string{"Ravi"}},
+		{"by email without ravi", models.FiltersCheck{Sort: models.ListSort{Field: "email", Desc: true},
+			Conditions: []models.Condition{{Field: "name", Op: models.OpNe, Value: "Ravi"}}}, []string{"Meera", "Asha"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			filters := c.filters
+			filters.Pagination = true
+			filters.Limit = 1
+			names := make([]string, 0)
+			for page := 0; page <= len(c.want); page++ {
+				users, err := dbhelper.AccessedByDetails(ctx, "authorized", &filters)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, user := range users.AccessedBy {
+					names = append(names, user.Name)
+				}
+				if users.NextCursor == "" {
+					break
+				}
+				cursor, err := utils.DecodeCursor(users.NextCursor)
+				if err != nil {
+					t.Fatal(err)
+				}
+				filters.Cursor = &cursor
+			}
+			if strings.Join(names, ",") != strings.Join(c.want, ",") {
+				t.Errorf("got %v, want %v", names, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StartConsistencyCheck periodically logs the rows breaking each consistency check and, when repair is set, repairs
+// them so that drift left by failed writes does not pile up
+func StartConsistencyCheck(ctx context.Context, interval time.Duration, repair bool) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "consistency_check"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := traced(ctx, "consistency_check", func(ctx context.Context) error {
+			return checkConsistency(ctx, repair)
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartConsistencyCheck: failed to check consistency.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkConsistency(ctx context.Context, repair bool) error {
+	inconsistencies, err := dbhelper.CheckConsistency(ctx)
+	if err != nil {
+		return err
+	}
+	if len(inconsistencies) == 0 {
+		return nil
+	}
+	found := make(map[string]int)
+	for _, inconsistency := range inconsistencies {
+		found[inconsistency.Check]++
+	}
+	for check, count := range found {
+		logging.FromContext(ctx).WithField("check", check).Warnf("checkConsistency: %d rows break the check.", count)
+	}
+	if !repair {
+		return nil
+	}
+
+	repairs, err := dbhelper.RepairConsistency(ctx, nil, false)
+	if err != nil {
+		return err
+	}
+	for _, repaired := range repairs {
+		logging.FromContext(ctx).WithField("check", repaired.Check).Infof("checkConsistency: repaired %d rows, %s.", len(repaired.IDs), repaired.Action)
+	}
+	return nil
+}
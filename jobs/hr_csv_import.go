@@ -0,0 +1,97 @@
+package jobs
+
+import (
+	"InternalAssetManagement/hrsync"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+)
+
+const (
+	processedDir = "processed"
+	failedDir    = "failed"
+)
+
+// StartHRCSVImport periodically imports the HR exports dropped into dir. Each file is a full roster, so HR managed
+// employees missing from it are deactivated. Imported files are moved to dir/processed, unreadable ones to dir/failed
+func StartHRCSVImport(ctx context.Context, dir string, interval time.Duration) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "hr_csv_import"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := traced(ctx, "hr_csv_import", func(ctx context.Context) error {
+			return importHRCSVFiles(ctx, dir)
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartHRCSVImport: failed to import HR files.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func importHRCSVFiles(ctx context.Context, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return err
+	}
+	// exports are usually timestamped, importing oldest first keeps the latest roster last
+	sort.Strings(files)
+
+	for _, path := range files {
+		run, importErr := importHRCSVFile(ctx, path)
+		target := processedDir
+		if importErr != nil {
+			logging.FromContext(ctx).WithError(importErr).Errorf("importHRCSVFiles: cannot import %s.", path)
+			target = failedDir
+		} else {
+			logging.FromContext(ctx).Infof("importHRCSVFiles: %s created %d, updated %d, deactivated %d employees with %d errors.",
+				filepath.Base(path), run.CreatedCount, run.UpdatedCount, run.DeactivatedCount, run.ErrorCount)
+		}
+		if err := moveFile(path, filepath.Join(dir, target)); err != nil {
+			// stop rather than import the same file again on the next tick
+			return err
+		}
+	}
+	return nil
+}
+
+func importHRCSVFile(ctx context.Context, path string) (models.SyncRun, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return models.SyncRun{}, err
+	}
+	defer file.Close()
+
+	records, rejected, err := hrsync.ParseCSV(file)
+	if err != nil {
+		return models.SyncRun{}, err
+	}
+
+	return hrsync.Run(ctx, records, rejected, hrsync.Options{
+		Source:            hrsync.SourceCSV,
+		FileName:          null.StringFrom(filepath.Base(path)),
+		DeactivateMissing: true,
+	})
+}
+
+// moveFile moves a file into dir, prefixing it with the time so repeated drops of the same name are kept
+func moveFile(path, dir string) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s", time.Now().Format("20060102T150405"), strings.TrimSpace(filepath.Base(path)))
+	return os.Rename(path, filepath.Join(dir, name))
+}
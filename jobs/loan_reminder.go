@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reminderCoolDown is the minimum gap between two reminders for the same loan
+const reminderCoolDown = 24 * time.Hour
+
+// StartLoanReminders periodically emails borrowers about loans that are due soon or overdue and sends admins an overdue digest
+func StartLoanReminders(ctx context.Context, interval time.Duration, dueWithinDays int) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "loan_reminders"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := traced(ctx, "loan_reminders", func(ctx context.Context) error {
+			return sendLoanReminders(ctx, dueWithinDays)
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartLoanReminders: failed to send loan reminders.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sendLoanReminders(ctx context.Context, dueWithinDays int) error {
+	loans, err := dbhelper.ClaimLoansToRemind(ctx, dueWithinDays, time.Now().Add(-reminderCoolDown))
+	if err != nil {
+		return err
+	}
+	if len(loans) == 0 {
+		return nil
+	}
+
+	overdue := make([]models.Loan, 0)
+	for i := range loans {
+		loan := loans[i]
+		subject := fmt.Sprintf("Reminder: %s %s is due on %s", loan.Brand, loan.Model, loan.DueDate.Format("02 Jan 2006"))
+		body := fmt.Sprintf("Hi %s, the %s (%s) lent to you on %s is due on %s. Please return it or request an extension.",
+			loan.EmployeeName, loan.AssetType, loan.AssetTag, loan.AssignedDate.Format("02 Jan 2006"), loan.DueDate.Format("02 Jan 2006"))
+		if loan.OverdueDays > 0 {
+			overdue = append(overdue, loan)
+			subject = fmt.Sprintf("Overdue: %s %s was due on %s", loan.Brand, loan.Model, loan.DueDate.Format("02 Jan 2006"))
+		}
+
+		if err := utils.SendEmail(loan.EmployeeName, loan.Email, subject, body, "<p>"+html.EscapeString(body)+"</p>"); err != nil {
+			logging.FromContext(ctx).WithError(err).Errorf("sendLoanReminders: cannot send reminder for loan %s.", loan.RelationID)
+			if err := dbhelper.ReleaseLoanReminder(ctx, loan.RelationID, loan.LastReminder); err != nil {
+				return err
+			}
+			continue
+		}
+	}
+
+	if len(overdue) == 0 {
+		return nil
+	}
+	return sendOverdueDigest(ctx, overdue)
+}
+
+func sendOverdueDigest(ctx context.Context, overdue []models.Loan) error {
+	admins, err := dbhelper.GetAdminContacts(ctx)
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(overdue))
+	htmlLines := make([]string, 0, len(overdue))
+	for i := range overdue {
+		line := fmt.Sprintf("%s %s %s (%s) with %s, %d days overdue",
+			overdue[i].AssetTag, overdue[i].Brand, overdue[i].Model, overdue[i].AssetType, overdue[i].EmployeeName, overdue[i].OverdueDays)
+		lines = append(lines, line)
+		htmlLines = append(htmlLines, html.EscapeString(line))
+	}
+	subject := fmt.Sprintf("%d loaned assets are overdue", len(overdue))
+	plainText := strings.Join(lines, "\n")
+	htmlContent := "<ul><li>" + strings.Join(htmlLines, "</li><li>") + "</li></ul>"
+
+	for i := range admins {
+		if err := utils.SendEmail(admins[i].Name, admins[i].Email, subject, plainText, htmlContent); err != nil {
+			logging.FromContext(ctx).WithError(err).Errorf("sendOverdueDigest: cannot send overdue digest to %s.", admins[i].Email)
+		}
+	}
+	return nil
+}
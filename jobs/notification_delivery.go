@@ -0,0 +1,181 @@
+package jobs
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+)
+
+const (
+	// notificationLease is how long a claimed notification waits before another run may try it, it covers the few
+	// emails a notification sends even when the mail provider is slow to answer
+	notificationLease = time.Minute
+	// an email that still cannot be sent after maxNotificationAttempts attempts is given up, it is retried after
+	// notificationRetryDelay, so the attempts span about a quarter of an hour of mail provider trouble
+	maxNotificationAttempts = 5
+	notificationBaseDelay   = time.Minute
+	notificationMaxDelay    = 30 * time.Minute
+)
+
+// StartNotificationDelivery periodically sends the emails queued in the notification outbox once the changes they
+// tell about are committed, retrying those that fail
+func StartNotificationDelivery(ctx context.Context, interval time.Duration) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "notification_delivery"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := traced(ctx, "notification_delivery", func(ctx context.Context) error {
+			return deliverNotifications(ctx)
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartNotificationDelivery: failed to deliver notifications.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func deliverNotifications(ctx context.Context) error {
+	for ctx.Err() == nil {
+		notifications, err := dbhelper.ClaimNotifications(ctx, 1, maxNotificationAttempts, notificationLease)
+		if err != nil {
+			return err
+		}
+		if len(notifications) == 0 {
+			return nil
+		}
+		if err := sendNotification(ctx, &notifications[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendNotification sends a notification and records the outcome, only a failure to record it is returned
+func sendNotification(ctx context.Context, notification *models.DueNotification) error {
+	var sendErr error
+	switch notification.Kind {
+	case models.NotificationRequestStatus, models.NotificationRequestApproval:
+		var payload models.EquipmentRequestNotification
+		if sendErr = json.Unmarshal(notification.Payload, &payload); sendErr != nil {
+			break
+		}
+		if notification.Kind == models.NotificationRequestStatus {
+			sendErr = sendRequestStatus(ctx, &payload)
+		} else {
+			sendErr = sendRequestApproval(ctx, &payload)
+		}
+	case models.NotificationManager:
+		var payload models.ManagerNotification
+		if sendErr = json.Unmarshal(notification.Payload, &payload); sendErr == nil {
+			sendErr = sendManagerNotice(ctx, &payload)
+		}
+	default:
+		sendErr = fmt.Errorf("unknown notification kind %s", notification.Kind)
+	}
+
+	if sendErr == nil {
+		return dbhelper.RecordNotificationAttempt(ctx, notification.ID, null.String{}, null.Time{})
+	}
+	entry := logging.FromContext(ctx).WithError(sendErr)
+	if notification.Attempts+1 >= maxNotificationAttempts {
+		entry.Errorf("sendNotification: giving up on %s notification %s after %d attempts.", notification.Kind, notification.ID, notification.Attempts+1)
+	} else {
+		entry.Warnf("sendNotification: cannot send %s notification %s, it will be retried.", notification.Kind, notification.ID)
+	}
+	return dbhelper.RecordNotificationAttempt(ctx, notification.ID, null.StringFrom(sendErr.Error()),
+		null.TimeFrom(time.Now().Add(notificationRetryDelay(notification.Attempts))))
+}
+
+// notificationRetryDelay is the wait after the given number of earlier failed attempts, 1m, 2m, 4m, 8m and at most
+// notificationMaxDelay
+func notificationRetryDelay(attempts int) time.Duration {
+	delay := notificationBaseDelay
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= notificationMaxDelay {
+			return notificationMaxDelay
+		}
+	}
+	return delay
+}
+
+// sendRequestStatus emails the employee the status their equipment request had when the notification was queued
+func sendRequestStatus(ctx context.Context, payload *models.EquipmentRequestNotification) error {
+	request, err := dbhelper.GetEquipmentRequest(ctx, payload.RequestID, "")
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Your %s request is %s", request.AssetType, payload.Status)
+	body := fmt.Sprintf("Hi %s, your request for a %s is now %s.", request.EmployeeName, request.AssetType, payload.Status)
+	if payload.Status == utils.RequestPending && request.CurrentStep.Valid {
+		body += fmt.Sprintf(" It is awaiting %s.", request.CurrentStep.String)
+	}
+	return utils.SendEmail(request.EmployeeName, request.EmployeeEmail, subject, body, "<p>"+html.EscapeString(body)+"</p>")
+}
+
+// sendRequestApproval emails the approvers of the current step of an equipment request that is still pending
+func sendRequestApproval(ctx context.Context, payload *models.EquipmentRequestNotification) error {
+	request, err := dbhelper.GetEquipmentRequest(ctx, payload.RequestID, "")
+	if err != nil {
+		return err
+	}
+	if request.Status != utils.RequestPending {
+		return nil
+	}
+
+	for i := range request.Approvals {
+		if request.Approvals[i].Status != utils.RequestPending {
+			continue
+		}
+		approvers, err := dbhelper.GetApproverContacts(ctx, request.Approvals[i].ApproverID)
+		if err != nil {
+			return err
+		}
+		subject := fmt.Sprintf("Approval needed: %s for %s", request.AssetType, request.EmployeeName)
+		body := fmt.Sprintf("%s requested a %s: %s. The request is awaiting %s.",
+			request.EmployeeName, request.AssetType, request.Justification, request.Approvals[i].StepName)
+		if request.NeededBy.Valid {
+			body += fmt.Sprintf(" Needed by %s.", request.NeededBy.Time.Format("02 Jan 2006"))
+		}
+		for j := range approvers {
+			if err := utils.SendEmail(approvers[j].Name, approvers[j].Email, subject, body, "<p>"+html.EscapeString(body)+"</p>"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// sendManagerNotice emails the manager of an employee that the employee received or returned an asset, nothing is sent
+// when the employee has no manager
+func sendManagerNotice(ctx context.Context, payload *models.ManagerNotification) error {
+	notice, err := dbhelper.GetManagerNotice(ctx, payload.EmployeeID, payload.AssetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	asset := fmt.Sprintf("%s %s %s (%s)", notice.AssetTag, notice.Brand, notice.Model, notice.AssetType)
+	subject := fmt.Sprintf("%s %s %s", notice.EmployeeName, payload.Event, asset)
+	body := fmt.Sprintf("Hi %s, %s, who reports to you, %s the %s.", notice.ManagerName, notice.EmployeeName, payload.Event, asset)
+	return utils.SendEmail(notice.ManagerName, notice.ManagerEmail, subject, body, "<p>"+html.EscapeString(body)+"</p>")
+}
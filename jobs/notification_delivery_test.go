@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotificationRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{4, 16 * time.Minute},
+		{5, notificationMaxDelay},
+		{40, notificationMaxDelay},
+	}
+	for _, c := range cases {
+		if got := notificationRetryDelay(c.attempts); got != c.want {
+			t.Errorf("after %d attempts got %s, want %s", c.attempts, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StartRetentionPurge periodically hard deletes the assets and employees deleted more than retention ago, after which
+// they can no longer be restored
+func StartRetentionPurge(ctx context.Context, interval, retention time.Duration) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "retention_purge"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var purged models.PurgeCount
+		err := traced(ctx, "retention_purge", func(ctx context.Context) (err error) {
+			purged, err = dbhelper.PurgeArchived(ctx, time.Now().Add(-retention))
+			return err
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartRetentionPurge: failed to purge deleted records.")
+		} else if purged.Assets > 0 || purged.Employees > 0 {
+			logging.FromContext(ctx).Infof("StartRetentionPurge: purged %d assets and %d employees.", purged.Assets, purged.Employees)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
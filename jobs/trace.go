@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"InternalAssetManagement/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+)
+
+// traced runs one round of a job in a span of its own so that the statements of the round make up one trace
+func traced(ctx context.Context, job string, round func(ctx context.Context) error) error {
+	ctx, span := tracing.Tracer().Start(ctx, "job "+job)
+	defer span.End()
+	err := round(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
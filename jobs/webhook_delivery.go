@@ -0,0 +1,173 @@
+package jobs
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/models"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/volatiletech/null"
+)
+
+const (
+	webhookBatchSize = 50
+	webhookTimeout   = 10 * time.Second
+	// webhookLease keeps a claimed delivery from being picked up again while it is being sent. Deliveries are claimed
+	// one at a time so the lease only has to outlast a single request and the recording of its outcome
+	webhookLease = time.Minute
+	// a delivery is dead-lettered after maxWebhookAttempts failed attempts, retries back off exponentially from
+	// webhookBaseDelay up to webhookMaxDelay, i.e. 30s, 1m, 2m ... 6h
+	maxWebhookAttempts = 10
+	webhookBaseDelay   = 30 * time.Second
+	webhookMaxDelay    = 6 * time.Hour
+	// maxWebhookErrorSize bounds how much of a failed response is kept in the delivery log
+	maxWebhookErrorSize = 512
+)
+
+// recordWebhookAttempt stores the outcome of a delivery attempt, tests replace it to run without a database
+var recordWebhookAttempt = dbhelper.RecordWebhookAttempt
+
+var webhookClient = &http.Client{
+	Timeout: webhookTimeout,
+	// a redirect is reported as a failure rather than followed with the signed payload
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// StartWebhookDelivery periodically fans outbox events out to the subscriptions of their type and sends the deliveries
+// that are due. Every request is signed, receivers verify X-Webhook-Signature, which is "sha256=" followed by the hex
+// HMAC-SHA256 of X-Webhook-Timestamp, a dot and the raw body, keyed with the subscription secret
+func StartWebhookDelivery(ctx context.Context, interval time.Duration) {
+	ctx = logging.WithFields(ctx, logrus.Fields{"job": "webhook_delivery"})
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := traced(ctx, "webhook_delivery", func(ctx context.Context) error {
+			return deliverWebhooks(ctx)
+		})
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).Error("StartWebhookDelivery: failed to deliver webhooks.")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func deliverWebhooks(ctx context.Context) error {
+	for {
+		dispatched, err := dbhelper.DispatchWebhookEvents(ctx, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+		if dispatched < webhookBatchSize {
+			break
+		}
+	}
+
+	for ctx.Err() == nil {
+		deliveries, err := dbhelper.ClaimWebhookDeliveries(ctx, 1, webhookLease)
+		if err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		if err := sendWebhook(ctx, &deliveries[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendWebhook posts a delivery and records the outcome, only a failure to record it is returned
+func sendWebhook(ctx context.Context, delivery *models.DueWebhookDelivery) error {
+	body, err := json.Marshal(models.WebhookEvent{
+		ID:         delivery.OutboxID,
+		Type:       delivery.EventType,
+		OccurredAt: delivery.OccurredAt,
+		Data:       delivery.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	attempt := models.WebhookAttempt{DeliveryID: delivery.ID}
+	started := time.Now()
+	statusCode, sendErr := postWebhook(ctx, delivery, body)
+	attempt.DurationMS = int(time.Since(started).Milliseconds())
+	if statusCode != 0 {
+		attempt.StatusCode = null.IntFrom(statusCode)
+	}
+	if sendErr != nil {
+		attempt.Error = null.StringFrom(sendErr.Error())
+	}
+
+	if sendErr == nil {
+		return recordWebhookAttempt(ctx, &attempt, models.WebhookDelivered, null.Time{})
+	}
+	if delivery.Attempts+1 >= maxWebhookAttempts {
+		logging.FromContext(ctx).WithError(sendErr).Warnf("sendWebhook: delivery %s dead-lettered after %d attempts.", delivery.ID, delivery.Attempts+1)
+		return recordWebhookAttempt(ctx, &attempt, models.WebhookDead, null.Time{})
+	}
+	return recordWebhookAttempt(ctx, &attempt, models.WebhookPending, null.TimeFrom(time.Now().Add(webhookRetryDelay(delivery.Attempts))))
+}
+
+func postWebhook(ctx context.Context, delivery *models.DueWebhookDelivery, body []byte) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "asset-management-webhooks")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", delivery.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+signWebhook(delivery.Secret, timestamp, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return resp.StatusCode, nil
+	}
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorSize))
+	return resp.StatusCode, fmt.Errorf("receiver responded %d: %s", resp.StatusCode, snippet)
+}
+
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay is the wait after the given number of earlier failed attempts
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookBaseDelay
+	for i := 0; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxDelay {
+			return webhookMaxDelay
+		}
+	}
+	return delay
+}
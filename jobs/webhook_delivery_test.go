@@ -0,0 +1,122 @@
+package jobs
+
+import (
+	"InternalAssetManagement/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+type recordedAttempt struct {
+	attempt       models.WebhookAttempt
+	status        string
+	nextAttemptAt null.Time
+}
+
+// recordAttempts replaces the recording of delivery attempts for the duration of the test
+func recordAttempts(t *testing.T) *[]recordedAttempt {
+	t.Helper()
+	recorded := make([]recordedAttempt, 0)
+	original := recordWebhookAttempt
+	recordWebhookAttempt = func(ctx context.Context, attempt *models.WebhookAttempt, status string, nextAttemptAt null.Time) error {
+		recorded = append(recorded, recordedAttempt{attempt: *attempt, status: status, nextAttemptAt: nextAttemptAt})
+		return nil
+	}
+	t.Cleanup(func() { recordWebhookAttempt = original })
+	return &recorded
+}
+
+func TestSignWebhook(t *testing.T) {
+	var got, timestamp string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("X-Webhook-Signature")
+		timestamp = r.Header.Get("X-Webhook-Timestamp")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	recorded := recordAttempts(t)
+
+	delivery := models.DueWebhookDelivery{ID: "d1", OutboxID: "o1", URL: server.URL, Secret: "s3cret", EventType: models.EventAssetAssigned, Payload: []byte(`{"assetId":"a1"}`)}
+	if err := sendWebhook(context.Background(), &delivery); err != nil {
+		t.Fatal(err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+	if len(*recorded) != 1 || (*recorded)[0].status != models.WebhookDelivered {
+		t.Errorf("got recorded attempts %+v, want one delivered", *recorded)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	cases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{9, 4*time.Hour + 16*time.Minute},
+		{10, webhookMaxDelay},
+		{50, webhookMaxDelay},
+	}
+	for _, c := range cases {
+		if got := webhookRetryDelay(c.attempts); got != c.want {
+			t.Errorf("after %d attempts got %s, want %s", c.attempts, got, c.want)
+		}
+	}
+}
+
+func TestSendWebhookFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "receiver is down", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name     string
+		attempts int
+		status   string
+	}{
+		{"retried", 0, models.WebhookPending},
+		{"retried before the last attempt", maxWebhookAttempts - 2, models.WebhookPending},
+		{"dead-lettered", maxWebhookAttempts - 1, models.WebhookDead},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recorded := recordAttempts(t)
+			delivery := models.DueWebhookDelivery{ID: "d1", URL: server.URL, Secret: "s3cret", EventType: models.EventAssetAssigned, Attempts: c.attempts}
+			if err := sendWebhook(context.Background(), &delivery); err != nil {
+				t.Fatal(err)
+			}
+			if len(*recorded) != 1 {
+				t.Fatalf("got %d recorded attempts, want 1", len(*recorded))
+			}
+			record := (*recorded)[0]
+			if record.status != c.status {
+				t.Errorf("got status %s, want %s", record.status, c.status)
+			}
+			if record.nextAttemptAt.Valid != (c.status == models.WebhookPending) {
+				t.Errorf("got next attempt %v for status %s", record.nextAttemptAt, record.status)
+			}
+			if record.attempt.StatusCode.Int != http.StatusServiceUnavailable || !strings.Contains(record.attempt.Error.String, "receiver is down") {
+				t.Errorf("got attempt %+v", record.attempt)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package logging configures logrus and carries request scoped log fields, such as the request and user ids, through
+// a context.Context so that log lines written anywhere while serving a request can be tied back to it
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	FieldRequestID = "request_id"
+	FieldUserID    = "user_id"
+	FieldTraceID   = "trace_id"
+)
+
+type scopeKey struct{}
+
+// scope holds the fields of a context. It is shared by pointer so that middlewares deeper in the chain, such as
+// authentication, can add the user to lines written by middlewares above them, such as the access log
+type scope struct {
+	mu     sync.Mutex
+	fields logrus.Fields
+}
+
+// Configure sets the minimum level, e.g. debug, info or warn, and the format, json or text, of log output and hides
+// secrets and personal data in it. Empty values keep info and json
+func Configure(level, format string) error {
+	logLevel := logrus.InfoLevel
+	if level != "" {
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			return err
+		}
+		logLevel = parsed
+	}
+
+	var formatter logrus.Formatter
+	switch strings.ToLower(format) {
+	case "", FormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	case FormatText:
+		formatter = &logrus.TextFormatter{FullTimestamp: true}
+	default:
+		return fmt.Errorf("unknown log format %q, use %s or %s", format, FormatJSON, FormatText)
+	}
+
+	logrus.SetLevel(logLevel)
+	logrus.SetFormatter(formatter)
+	logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	logrus.AddHook(redactHook{})
+	return nil
+}
+
+// WithFields returns a context whose log lines carry the fields on top of those of ctx
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	merged := make(logrus.Fields)
+	if parent, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		parent.mu.Lock()
+		for key, value := range parent.fields {
+			merged[key] = value
+		}
+		parent.mu.Unlock()
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, scopeKey{}, &scope{fields: merged})
+}
+
+// AddFields adds the fields to the scope ctx was given by WithFields, so that they also show on lines logged with
+// the contexts it was derived from. It does nothing for a context without fields
+func AddFields(ctx context.Context, fields logrus.Fields) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, value := range fields {
+		s.fields[key] = value
+	}
+}
+
+// SetUserID records the user a request acts as once it is authenticated
+func SetUserID(ctx context.Context, userID string) {
+	AddFields(ctx, logrus.Fields{FieldUserID: userID})
+}
+
+// RequestID returns the id of the request ctx belongs to, empty outside a request
+func RequestID(ctx context.Context) string {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, _ := s.fields[FieldRequestID].(string)
+	return id
+}
+
+// FromContext returns a logger with the fields of ctx
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if ctx == nil {
+		return entry
+	}
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return entry
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return entry.WithFields(s.fields)
+}
@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+var (
+	// sensitiveKeys are field names whose values are never logged
+	sensitiveKeys = []string{"password", "secret", "token", "authorization", "cookie", "apikey", "api_key", "api-key"}
+
+	jwtPattern    = regexp.MustCompile(`eyJ[\w-]+\.[\w-]+\.[\w-]+`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[\w.~+/=-]+`)
+	secretPattern = regexp.MustCompile(`(?i)(password|secret|token|api[_-]?key|authorization)("?\s*[:=]\s*"?)(?:bearer\s+)?[^\s"&,;]+`)
+	emailPattern  = regexp.MustCompile(`([\w.%+-])[\w.%+-]*@([\w-]+\.[\w.-]+)`)
+)
+
+// redactHook hides secrets and personal data in every line: fields named like a secret are dropped and tokens,
+// passwords in key value pairs and email addresses are masked in messages and text fields
+type redactHook struct{}
+
+func (redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = Redact(entry.Message)
+	for key, value := range entry.Data {
+		if isSensitiveKey(key) {
+			entry.Data[key] = redacted
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			entry.Data[key] = Redact(v)
+		case error:
+			entry.Data[key] = Redact(v.Error())
+		}
+	}
+	return nil
+}
+
+// isSensitiveKey matches fields such as apiKey or password, but not the ids of tokens and keys such as api_key_id
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "id") {
+		return false
+	}
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact masks tokens, secrets in key value pairs and email addresses in s, leaving the first letter and the domain
+// of an email so that lines about the same address can still be told apart
+func Redact(s string) string {
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = secretPattern.ReplaceAllString(s, "${1}${2}"+redacted)
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	return emailPattern.ReplaceAllString(s, "${1}***@${2}")
+}
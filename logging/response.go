@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ResponseWriter records the status and size of a response for the access log and lets code that only has the
+// writer, such as the error responders in utils, log with the fields of the request
+type ResponseWriter struct {
+	http.ResponseWriter
+	ctx    context.Context
+	status int
+	bytes  int
+}
+
+func NewResponseWriter(w http.ResponseWriter, ctx context.Context) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w, ctx: ctx}
+}
+
+func (w *ResponseWriter) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *ResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// Status is the status sent, 200 when the handler wrote nothing
+func (w *ResponseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *ResponseWriter) Bytes() int {
+	return w.bytes
+}
+
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// FromWriter returns a logger with the fields of the request w responds to, found through writers that wrap it and
+// have an Unwrap method
+func FromWriter(w http.ResponseWriter) *logrus.Entry {
+	for {
+		switch writer := w.(type) {
+		case *ResponseWriter:
+			return FromContext(writer.ctx)
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = writer.Unwrap()
+		default:
+			return logrus.NewEntry(logrus.StandardLogger())
+		}
+	}
+}
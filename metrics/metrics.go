@@ -0,0 +1,102 @@
+// Package metrics exports the metrics of the service in the Prometheus format: HTTP requests per route, the statistics
+// of the database pool and gauges of the inventory read from the database when they are scraped
+package metrics
+
+import (
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/logging"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "asset_management"
+	// WarrantyWindow is how far ahead warranties count as expiring
+	WarrantyWindow = 30 * 24 * time.Hour
+	// inventoryTimeout bounds the queries of a scrape so that a slow database does not pile scrapes up
+	inventoryTimeout = 5 * time.Second
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests served by method, chi route pattern and status.",
+	}, []string{"method", "route", "status"})
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests by method and chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requests,
+		requestDuration,
+	)
+}
+
+// Handler serves the metrics for Prometheus to scrape
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest records a served request under its chi route pattern, such as /asset-management/asset/{assetID}, so
+// that ids in paths do not make a series each
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// RegisterDatabase exports the pool statistics of db and the inventory gauges, it is called once connected
+func RegisterDatabase(db *sqlx.DB) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db.DB, namespace), inventoryCollector{})
+}
+
+var (
+	assetsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "assets"),
+		"Assets by type and status, deleted assets included.", []string{"type", "status"}, nil)
+	openAssignmentsDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "open_assignments"),
+		"Assets assigned to employees and not retrieved yet.", nil, nil)
+	expiringWarrantiesDesc = prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "expiring_warranties"),
+		"Assets that are not deleted whose warranty expires within 30 days.", nil, nil)
+)
+
+// inventoryCollector reads the inventory gauges on every scrape, a scrape that cannot read them leaves them out
+type inventoryCollector struct{}
+
+func (inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- assetsDesc
+	ch <- openAssignmentsDesc
+	ch <- expiringWarrantiesDesc
+}
+
+func (inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), inventoryTimeout)
+	defer cancel()
+	inventory, err := dbhelper.GetInventoryMetrics(ctx, WarrantyWindow)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).Warn("metrics: cannot read the inventory gauges.")
+		return
+	}
+	for _, assets := range inventory.Assets {
+		ch <- prometheus.MustNewConstMetric(assetsDesc, prometheus.GaugeValue, float64(assets.Count), string(assets.AssetType), assets.Status)
+	}
+	ch <- prometheus.MustNewConstMetric(openAssignmentsDesc, prometheus.GaugeValue, float64(inventory.OpenAssignments))
+	ch <- prometheus.MustNewConstMetric(expiringWarrantiesDesc, prometheus.GaugeValue, float64(inventory.ExpiringWarranties))
+}
@@ -1,15 +1,21 @@
 package middlewares
 
 import (
+	"InternalAssetManagement/apperr"
 	"InternalAssetManagement/database/dbhelper"
 	"InternalAssetManagement/handler"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
 	"InternalAssetManagement/utils"
 	"context"
-	"encoding/json"
+	"database/sql"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"runtime/debug"
+	"strings"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/go-chi/chi/v5"
@@ -19,41 +25,252 @@ import (
 
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("Authorization")
+		if apiKey := r.Header.Get("x-api-key"); apiKey != "" {
+			apiKeyAuth(w, r, next, apiKey)
+			return
+		}
 
-		claims := models.Claims{}
+		claims, ok := parseClaims(w, r)
+		if !ok {
+			return
+		}
 
-		tkn, err1 := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
-			return handler.JwtKey, nil
-		})
-		if err1 != nil {
-			if err1 == jwt.ErrSignatureInvalid {
-				utils.RespondError(w, http.StatusUnauthorized, err1, "AuthMiddleware: Signature invalid.")
-				return
-			}
-			utils.RespondError(w, http.StatusUnauthorized, err1, "AuthMiddleware: ParseErr.")
+		if claims.Role == utils.RoleEmployee {
+			utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "AuthMiddleware: employee accounts cannot access admin routes.", nil))
 			return
 		}
 
-		if !tkn.Valid {
-			w.WriteHeader(http.StatusUnauthorized)
-			logrus.Printf("token is invalid")
+		if claims.Role == utils.RoleViewer && !isReadOnlyRequest(r) {
+			utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "AuthMiddleware: viewers have read only access.", nil))
 			return
 		}
 
-		_, err := dbhelper.CheckSession(claims.ID)
+		_, err := dbhelper.CheckSession(r.Context(), claims.ID)
 		if err != nil {
-			logrus.Printf("session expired:%v", err)
+			if errors.Is(err, sql.ErrNoRows) {
+				utils.RespondAppError(w, apperr.New(apperr.SessionExpired, "session expired, please sign in again.", err))
+				return
+			}
+			utils.RespondError(w, http.StatusInternalServerError, err, "AuthMiddleware: cannot check session.")
 			return
 		}
 		userID := claims.ID
 
 		// value := models.ContextValues{ID: userID}
+		logging.SetUserID(r.Context(), userID)
 		ctx := context.WithValue(r.Context(), utils.UserContextKey, userID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// apiKeyAuth authenticates an integration by its API key. The request acts as the admin who created the key, limited
+// to the key's scopes which RequireScope checks per route group. Keys of a creator who is no longer an admin only keep
+// read access, as viewers have
+func apiKeyAuth(w http.ResponseWriter, r *http.Request, next http.Handler, key string) {
+	apiKey, err := dbhelper.GetAPIKey(r.Context(), utils.HashString(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			utils.RespondAppError(w, apperr.New(apperr.APIKeyInvalid, "AuthMiddleware: invalid or revoked API key.", err))
+			return
+		}
+		utils.RespondError(w, http.StatusInternalServerError, err, "AuthMiddleware: cannot check API key.")
+		return
+	}
+	if apiKey.ExpiresAt.Valid && apiKey.ExpiresAt.Time.Before(time.Now()) {
+		utils.RespondAppError(w, apperr.New(apperr.APIKeyExpired, "AuthMiddleware: API key expired.", nil))
+		return
+	}
+
+	ip := utils.ClientIP(r)
+	if !isIPAllowed(apiKey.AllowedIPs, ip) {
+		utils.RespondAppError(w, apperr.New(apperr.IPNotAllowed, "AuthMiddleware: API key is not allowed from "+ip+".", nil))
+		return
+	}
+	// failing to record the use must not fail the request
+	_ = dbhelper.TouchAPIKey(r.Context(), apiKey.ID, ip)
+
+	logging.SetUserID(r.Context(), apiKey.CreatedBy)
+	logging.AddFields(r.Context(), logrus.Fields{"api_key_id": apiKey.ID})
+	ctx := context.WithValue(r.Context(), utils.UserContextKey, apiKey.CreatedBy)
+	ctx = context.WithValue(ctx, utils.APIKeyContextKey, apiKey.ID)
+	scopes := []string(apiKey.Scopes)
+	if apiKey.CreatorRole != utils.RoleAdmin {
+		scopes = readScopes(scopes)
+	}
+	ctx = context.WithValue(ctx, utils.PermissionsContextKey, scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// readScopes narrows scopes to read access, a write scope becomes the read scope of its resource
+func readScopes(scopes []string) []string {
+	read := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if strings.HasSuffix(scope, utils.ScopeWrite) {
+			scope = strings.TrimSuffix(scope, utils.ScopeWrite) + utils.ScopeRead
+		}
+		read = append(read, scope)
+	}
+	return read
+}
+
+// isIPAllowed checks an address against an allowlist of addresses and CIDR ranges, an empty allowlist allows all
+func isIPAllowed(allowed []string, ip string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+			continue
+		}
+		if allowedAddr := net.ParseIP(entry); allowedAddr != nil && allowedAddr.Equal(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope limits API keys to the routes of a resource they hold a scope for, reads need resource:read or
+// resource:write and anything else needs resource:write. User sessions pass through
+func RequireScope(resource string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !utils.IsAPIKeyRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			allowed := utils.HasPermission(r, resource+utils.ScopeWrite)
+			if isReadOnlyRequest(r) {
+				allowed = allowed || utils.HasPermission(r, resource+utils.ScopeRead)
+			}
+			if !allowed {
+				utils.RespondAppError(w, apperr.New(apperr.ScopeMissing, "API key is missing a "+resource+" scope for this request.", nil))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyAPIKeys keeps account routes, such as managing API keys, to user sessions
+func DenyAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if utils.IsAPIKeyRequest(r) {
+			utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "API keys cannot access account routes.", nil))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isReadOnlyRequest reports if a request only reads data, logging out is allowed to every role
+func isReadOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return strings.HasSuffix(r.URL.Path, "/log-out")
+}
+
+// EmployeeAuthMiddleware authenticates employee self-service accounts and attaches their permission set
+func EmployeeAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := parseClaims(w, r)
+		if !ok {
+			return
+		}
+
+		if claims.Role != utils.RoleEmployee {
+			utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "EmployeeAuthMiddleware: not an employee account.", nil))
+			return
+		}
+
+		_, err := dbhelper.CheckEmployeeSession(r.Context(), claims.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				utils.RespondAppError(w, apperr.New(apperr.SessionExpired, "session expired, please sign in again.", err))
+				return
+			}
+			utils.RespondError(w, http.StatusInternalServerError, err, "EmployeeAuthMiddleware: cannot check session.")
+			return
+		}
+
+		logging.AddFields(r.Context(), logrus.Fields{"employee_id": claims.ID})
+		ctx := context.WithValue(r.Context(), utils.EmployeeContextKey, claims.ID)
+		ctx = context.WithValue(ctx, utils.PermissionsContextKey, claims.Permissions)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ProvisioningAuthMiddleware authenticates SCIM clients by the bearer provisioning token issued to them
+func ProvisioningAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		if token == "" {
+			utils.RespondScimError(w, http.StatusUnauthorized, nil, "", "provisioning token is required.")
+			return
+		}
+
+		tokenID, err := dbhelper.CheckProvisioningToken(r.Context(), utils.HashString(token))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				utils.RespondScimError(w, http.StatusUnauthorized, err, "", "invalid or revoked provisioning token.")
+				return
+			}
+			utils.RespondScimError(w, http.StatusInternalServerError, err, "", "cannot check provisioning token.")
+			return
+		}
+
+		logging.AddFields(r.Context(), logrus.Fields{"provisioning_token_id": tokenID})
+		ctx := context.WithValue(r.Context(), utils.TokenContextKey, tokenID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequirePermission rejects requests whose permission set does not include the given permission
+func RequirePermission(permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !utils.HasPermission(r, permission) {
+				utils.RespondAppError(w, apperr.New(apperr.PermissionDenied, "missing permission: "+permission, nil))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// parseClaims validates the JWT from the Authorization header and responds on failure
+func parseClaims(w http.ResponseWriter, r *http.Request) (models.Claims, bool) {
+	token := r.Header.Get("Authorization")
+
+	claims := models.Claims{}
+
+	tkn, err1 := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return handler.JwtKey, nil
+	})
+	if err1 != nil {
+		if err1 == jwt.ErrSignatureInvalid {
+			utils.RespondAppError(w, apperr.New(apperr.TokenInvalid, "AuthMiddleware: Signature invalid.", err1))
+			return claims, false
+		}
+		utils.RespondAppError(w, apperr.New(apperr.TokenInvalid, "AuthMiddleware: ParseErr.", err1))
+		return claims, false
+	}
+
+	if !tkn.Valid {
+		utils.RespondAppError(w, apperr.New(apperr.TokenInvalid, "AuthMiddleware: token is invalid.", nil))
+		return claims, false
+	}
+	return claims, true
+}
+
 var MaxAge = 300
 
 // corsOptions setting up routes for cors
@@ -61,8 +278,8 @@ func corsOptions() *cors.Cors {
 	return cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Access-Token", "importDate", "X-Client-Version", "Cache-Control", "Pragma", "x-started-at", "x-api-key", "token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Access-Token", "importDate", "X-Client-Version", "Cache-Control", "Pragma", "x-started-at", "x-api-key", "token", RequestIDHeader},
+		ExposedHeaders:   []string{"Link", RequestIDHeader, handler.NextCursorHeader},
 		AllowCredentials: true,
 		MaxAge:           MaxAge,
 	})
@@ -83,18 +300,9 @@ func CommonMiddlewares() chi.Middlewares {
 				defer func() {
 					err := recover()
 					if err != nil {
-						logrus.Errorf("Request Panic err: %v", err)
-						jsonBody, _ := json.Marshal(map[string]string{
-							"error": "There was an internal server error",
-							"trace": fmt.Sprintf("%+v", err),
-							"stack": string(debug.Stack()),
-						})
+						logging.FromContext(r.Context()).Errorf("Request Panic err: %v\n%s", err, debug.Stack())
 						w.Header().Set("Content-Type", "application/json")
-						w.WriteHeader(http.StatusInternalServerError)
-						_, err := w.Write(jsonBody)
-						if err != nil {
-							logrus.Errorf("Failed to send response from middleware with error: %+v", err)
-						}
+						utils.RespondError(w, http.StatusInternalServerError, fmt.Errorf("panic: %v", err), "There was an internal server error")
 					}
 				}()
 				next.ServeHTTP(w, r)
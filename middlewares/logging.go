@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"InternalAssetManagement/logging"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern limits the ids taken from callers so that they cannot inject anything into the logs
+var requestIDPattern = regexp.MustCompile(`^[\w.-]{1,64}$`)
+
+// RequestID tags every log line of a request with its id, taken from X-Request-ID when a proxy or the caller set a
+// sensible one and generated otherwise, and sends it back in the same header so callers can quote it
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		ctx := logging.WithFields(r.Context(), logrus.Fields{logging.FieldRequestID: requestID})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// AccessLog writes a line per request with its route, status, size and latency once it is served. It goes after
+// RequestID, the user is added by the authentication middlewares
+func AccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		writer := logging.NewResponseWriter(w, r.Context())
+		next.ServeHTTP(writer, r)
+
+		route := routePattern(r)
+		if route == "" {
+			route = r.URL.Path
+		}
+		entry := logging.FromContext(r.Context()).WithFields(logrus.Fields{
+			"method":     r.Method,
+			"route":      route,
+			"status":     writer.Status(),
+			"bytes":      writer.Bytes(),
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+		})
+		switch {
+		case writer.Status() >= http.StatusInternalServerError:
+			entry.Error("request failed")
+		case writer.Status() >= http.StatusBadRequest:
+			entry.Warn("request rejected")
+		default:
+			entry.Info("request served")
+		}
+	})
+}
+
+// routePattern returns the chi route pattern that served r, such as /asset-management/asset/{assetID}, empty when no
+// route matched. It is only known once r is served
+func routePattern(r *http.Request) string {
+	if routeCtx := chi.RouteContext(r.Context()); routeCtx != nil {
+		return routeCtx.RoutePattern()
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"InternalAssetManagement/metrics"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute is the route label of requests no route matched, their paths are not used so that scanners cannot
+// make a series per path
+const unmatchedRoute = "unmatched"
+
+// Metrics counts and times requests per chi route pattern once they are served
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		writer := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(writer, r)
+
+		route := routePattern(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := writer.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.ObserveRequest(r.Method, route, status, time.Since(start))
+	})
+}
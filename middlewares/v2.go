@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/utils"
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// V2Naming serves the shared handlers with the consistent field naming of the v2 API. JSON responses are rewritten to
+// utils.V2JSONName keys and query parameters ending in Id are also passed under the ID spelling some handlers read.
+// Request bodies need no rewriting as field names are matched case-insensitively when decoded
+func V2Naming(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		for key, values := range query {
+			if !strings.HasSuffix(key, "Id") {
+				continue
+			}
+			if legacy := strings.TrimSuffix(key, "Id") + "ID"; query[legacy] == nil {
+				query[legacy] = values
+			}
+		}
+		r.URL.RawQuery = query.Encode()
+
+		writer := &v2ResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(writer, r)
+		writer.flush()
+	})
+}
+
+// v2ResponseWriter holds back JSON bodies until the handler is done so their keys can be renamed, anything else such as
+// PDFs and calendars is passed straight through
+type v2ResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	started bool
+	buffer  *bytes.Buffer
+}
+
+func (w *v2ResponseWriter) WriteHeader(statusCode int) {
+	if w.started {
+		if w.buffer == nil {
+			w.ResponseWriter.WriteHeader(statusCode)
+		}
+		return
+	}
+	w.started = true
+	w.status = statusCode
+	if strings.Contains(w.Header().Get("Content-Type"), "json") {
+		w.buffer = &bytes.Buffer{}
+		return
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *v2ResponseWriter) Write(b []byte) (int, error) {
+	if !w.started {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffer != nil {
+		return w.buffer.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *v2ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *v2ResponseWriter) flush() {
+	if w.buffer == nil {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(utils.RenameJSONKeys(w.buffer.Bytes())); err != nil {
+		logging.FromWriter(w).Errorf("V2Naming: failed to write response with error: %+v", err)
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+type CreateAPIKey struct {
+	Name       string    `json:"name" validate:"required"`
+	Scopes     []string  `json:"scopes" validate:"required,min=1"`
+	AllowedIPs []string  `json:"allowedIps"`
+	ExpiresAt  null.Time `json:"expiresAt"`
+}
+
+type APIKey struct {
+	ID         string         `json:"id" db:"id"`
+	Name       string         `json:"name" db:"name"`
+	Prefix     string         `json:"prefix" db:"key_prefix"`
+	Scopes     pq.StringArray `json:"scopes" db:"scopes"`
+	AllowedIPs pq.StringArray `json:"allowedIps" db:"allowed_ips"`
+	ExpiresAt  null.Time      `json:"expiresAt" db:"expires_at"`
+	CreatedBy  string         `json:"createdBy" db:"created_by"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+	LastUsedAt null.Time      `json:"lastUsedAt" db:"last_used_at"`
+	LastUsedIP null.String    `json:"lastUsedIp" db:"last_used_ip"`
+	RevokedAt  null.Time      `json:"revokedAt" db:"revoked_at"`
+	// CreatorRole is the current role of the user who created the key, read when the key authenticates a request
+	CreatorRole string `json:"-" db:"creator_role"`
+}
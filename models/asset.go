@@ -32,17 +32,21 @@ type CreateAsset struct {
 	ArchivedAt         null.Time   `json:"archivedAt" db:"archived_at"`
 	ArchiveReason      null.String `json:"archiveReason" db:"archive_reason"`
 	DeletedBy          null.String `json:"deletedBy" db:"deleted_by"`
+	AssetTag           string      `json:"assetTag" db:"asset_tag"`
+	LocationID         null.String `json:"locationId" db:"location_id"`
 	AssetHistory       []EmployeeHistory
 }
 
 type TotalGetAsset struct {
 	GetAsset   []GetAsset
-	TotalCount int `json:"totalCount" db:"total_count"`
+	TotalCount int    `json:"totalCount" db:"total_count"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type GetAsset struct {
 	TotalCount         int         `json:"-" db:"total_count"`
 	ID                 string      `json:"id" db:"id"`
+	AssetTag           string      `json:"assetTag" db:"asset_tag"`
 	Brand              string      `json:"brand" db:"brand"`
 	Model              string      `json:"model" db:"model"`
 	SerialNo           string      `json:"serialNo" db:"serial_no"`
@@ -53,6 +57,8 @@ type GetAsset struct {
 	AssignedToID       null.String `json:"assignedToID" db:"assigned_to_id"`
 	AssignedTo         null.String `json:"assignedTo" db:"name"`
 	Status             string      `json:"status" db:"status"`
+	Location           null.String `json:"location" db:"location_name"`
+	SortKey            string      `json:"-" db:"sort_key"`
 }
 
 type UpdateAssetSpecification struct {
@@ -84,6 +90,8 @@ type ReassignAsset struct {
 	RetrievedDate   time.Time `json:"retrievedDate" db:"retrieved_date" validate:"required"`
 	RetrievalReason string    `json:"retrievalReason" db:"retrieval_reason" validate:"required"`
 	AssignedDate    string    `json:"assignedDate" db:"assigned_date" validate:"required"`
+	AssignmentType  string    `json:"assignmentType" db:"assignment_type" validate:"omitempty,oneof=permanent loan"`
+	DueDate         null.Time `json:"dueDate" db:"due_date"`
 }
 
 type AssignAssetDetails struct {
@@ -114,10 +122,11 @@ type WarrantyDetails struct {
 }
 
 type AssetRetrievalDetails struct {
-	RetrievedDate   time.Time `json:"retrievedDate" db:"retrieved_date"`
-	RetrievalReason string    `json:"retrievalReason" db:"retrieval_reason"`
-	EmployeeID      string    `json:"employeeId" db:"employee_id"`
-	AssetID         string    `json:"assetId" db:"asset_id"`
+	RetrievedDate   time.Time   `json:"retrievedDate" db:"retrieved_date"`
+	RetrievalReason string      `json:"retrievalReason" db:"retrieval_reason"`
+	EmployeeID      string      `json:"employeeId" db:"employee_id"`
+	AssetID         string      `json:"assetId" db:"asset_id"`
+	LocationID      null.String `json:"locationId" db:"location_id"`
 }
 
 type Asset struct {
@@ -125,3 +134,17 @@ type Asset struct {
 	AssetType    AssetType `json:"assetType" db:"asset_type" validate:"required"`
 	DeleteReason string    `json:"deleteReason" db:"archive_reason"`
 }
+
+type AssetLabel struct {
+	ID        string    `json:"id" db:"id"`
+	AssetTag  string    `json:"assetTag" db:"asset_tag"`
+	Brand     string    `json:"brand" db:"brand"`
+	Model     string    `json:"model" db:"model"`
+	SerialNo  string    `json:"serialNo" db:"serial_no"`
+	AssetType AssetType `json:"assetType" db:"asset_type"`
+}
+
+type AssetLookup struct {
+	ID        string `json:"id" db:"id"`
+	AssetType string `json:"assetType" db:"asset_type"`
+}
@@ -0,0 +1,25 @@
+package models
+
+// Inconsistency is a row breaking a rule the data is expected to keep
+type Inconsistency struct {
+	Check string `json:"check" db:"check"`
+	// ID is the id of the row, Label a name people know it by such as the asset tag
+	ID     string `json:"id" db:"id"`
+	Label  string `json:"label" db:"label"`
+	Detail string `json:"detail" db:"detail"`
+}
+
+// ConsistencyRepair lists the rows a repair changed to fix the inconsistencies of one check, the ids are those the
+// check reported
+type ConsistencyRepair struct {
+	Check  string   `json:"check"`
+	Action string   `json:"action"`
+	IDs    []string `json:"ids"`
+}
+
+// ConsistencyReport is the outcome of a repair run, on a dry run the repairs are what a real run would change
+type ConsistencyReport struct {
+	DryRun          bool                `json:"dryRun"`
+	Inconsistencies []Inconsistency     `json:"inconsistencies"`
+	Repairs         []ConsistencyRepair `json:"repairs"`
+}
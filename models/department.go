@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+// Department is a department or, when it has a parent, a team inside a department
+type Department struct {
+	ID       string      `json:"id" db:"id"`
+	Name     string      `json:"name" db:"name" validate:"required"`
+	ParentID null.String `json:"parentId" db:"parent_id"`
+}
+
+type DepartmentDetails struct {
+	Department
+	ParentName    null.String `json:"parentName" db:"parent_name"`
+	EmployeeCount int         `json:"employeeCount" db:"employee_count"`
+	CreatedAt     time.Time   `json:"createdAt" db:"created_at"`
+}
+
+// DepartmentQuantity counts the assets held by the employees of a department including its teams
+type DepartmentQuantity struct {
+	ID               string      `json:"id" db:"id"`
+	Name             string      `json:"name" db:"name"`
+	ParentID         null.String `json:"parentId" db:"parent_id"`
+	Employees        int         `json:"employees" db:"employees"`
+	HeldAssets       int         `json:"heldAssets" db:"held_assets"`
+	LaptopQuantity   int         `json:"laptopQuantity" db:"laptop_quantity"`
+	MouseQuantity    int         `json:"mouseQuantity" db:"mouse_quantity"`
+	PenDriveQuantity int         `json:"penDriveQuantity" db:"pen_drive_quantity"`
+	HardDiskQuantity int         `json:"hardDiskQuantity" db:"hard_disk_quantity"`
+	MobileQuantity   int         `json:"mobileQuantity" db:"mobile_quantity"`
+	SimQuantity      int         `json:"simQuantity" db:"sim_quantity"`
+}
+
+// ManagerNotice holds what a manager is told when one of their reports receives or returns an asset
+type ManagerNotice struct {
+	ManagerName  string `db:"manager_name"`
+	ManagerEmail string `db:"manager_email"`
+	EmployeeName string `db:"employee_name"`
+	AssetTag     string `db:"asset_tag"`
+	Brand        string `db:"brand"`
+	Model        string `db:"model"`
+	AssetType    string `db:"asset_type"`
+}
@@ -7,17 +7,20 @@ import (
 )
 
 type EmployeeDetails struct {
-	ID      string `json:"id" db:"id"`
-	Name    string `json:"name" db:"name" validate:"required"`
-	Type    string `json:"type" db:"type" validate:"required"`
-	Email   string `json:"email" db:"email" validate:"required,email"`
-	PhoneNo string `json:"phoneNo" db:"phone_no" validate:"required,min=10,max=10,numeric"`
-	Status  string `json:"status" db:"status"`
+	ID           string      `json:"id" db:"id"`
+	Name         string      `json:"name" db:"name" validate:"required"`
+	Type         string      `json:"type" db:"type" validate:"required"`
+	Email        string      `json:"email" db:"email" validate:"required,email"`
+	PhoneNo      string      `json:"phoneNo" db:"phone_no" validate:"required,min=10,max=10,numeric"`
+	Status       string      `json:"status" db:"status"`
+	DepartmentID null.String `json:"departmentId" db:"department_id"`
+	ManagerID    null.String `json:"managerId" db:"manager_id"`
 }
 
 type TotalGetEmployee struct {
 	GetEmployee []GetEmployee
-	TotalCount  int `json:"totalCount" db:"total_count"`
+	TotalCount  int    `json:"totalCount" db:"total_count"`
+	NextCursor  string `json:"nextCursor,omitempty"`
 }
 
 type GetEmployee struct {
@@ -32,13 +35,22 @@ type GetEmployee struct {
 	ArchiveReason null.String    `json:"archiveReason" db:"archive_reason"`
 	DeletedBy     null.String    `json:"deletedBy" db:"deleted_by"`
 	AssetQuantity int            `json:"assetQuantity" db:"asset_quantity"`
+	DepartmentID  null.String    `json:"departmentId" db:"department_id"`
+	Department    null.String    `json:"department" db:"department_name"`
+	ManagerID     null.String    `json:"managerId" db:"manager_id"`
+	ManagerName   null.String    `json:"managerName" db:"manager_name"`
 	AssetHistory  []AssetHistory `json:"assetHistory"`
+	SortKey       string         `json:"-" db:"sort_key"`
 }
 
 type EmployeeAssetRelation struct {
-	EmployeeID   string    `json:"employeeID" db:"employee_id"`
-	AssetID      string    `json:"assetId" db:"asset_id"`
-	AssignedDate time.Time `json:"assignedDate" db:"assigned_date"`
+	EmployeeID     string    `json:"employeeID" db:"employee_id"`
+	AssetID        string    `json:"assetId" db:"asset_id"`
+	AssignedDate   time.Time `json:"assignedDate" db:"assigned_date"`
+	AssignmentType string    `json:"assignmentType" db:"assignment_type" validate:"omitempty,oneof=permanent loan"`
+	DueDate        null.Time `json:"dueDate" db:"due_date"`
+	// EquipmentRequestID links the assignment to the approved request it fulfils
+	EquipmentRequestID null.String `json:"equipmentRequestId" db:"equipment_request_id"`
 }
 
 type AssetHistory struct {
@@ -50,6 +62,8 @@ type AssetHistory struct {
 	AssignedDate    time.Time  `json:"assignedDate" db:"assigned_date"`
 	RetrievedDate   *time.Time `json:"retrievedDate" db:"retrieved_date"`
 	RetrievalReason string     `json:"retrievalReason" db:"retrieval_reason"`
+	DueDate         *time.Time `json:"dueDate" db:"due_date"`
+	AcknowledgedAt  *time.Time `json:"acknowledgedAt" db:"acknowledged_at"`
 }
 
 type GetEmployeeList struct {
@@ -74,3 +88,44 @@ type AssetHistoryDetails struct {
 type Employee struct {
 	ArchiveReason string `json:"archiveReason" db:"archive_reason"`
 }
+
+type EmployeeAccount struct {
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+type EmployeePasswordChange struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	Password        string `json:"password" validate:"required,min=6"`
+}
+
+type EmployeeCredentials struct {
+	EmployeeID string `db:"employee_id"`
+	Password   string `db:"password"`
+}
+
+type AssetReport struct {
+	Type        string `json:"type" db:"type" validate:"required,oneof=problem loss"`
+	Description string `json:"description" db:"description" validate:"required"`
+}
+
+type AssetReportDetails struct {
+	ID           string      `json:"id" db:"id"`
+	AssetID      string      `json:"assetId" db:"asset_id"`
+	AssetTag     string      `json:"assetTag" db:"asset_tag"`
+	Brand        string      `json:"brand" db:"brand"`
+	Model        string      `json:"model" db:"model"`
+	EmployeeID   string      `json:"employeeId" db:"employee_id"`
+	EmployeeName string      `json:"employeeName" db:"employee_name"`
+	Type         string      `json:"type" db:"type"`
+	Description  string      `json:"description" db:"description"`
+	Status       string      `json:"status" db:"status"`
+	Resolution   null.String `json:"resolution" db:"resolution"`
+	ResolvedBy   null.String `json:"resolvedBy" db:"resolved_by"`
+	ResolvedAt   null.Time   `json:"resolvedAt" db:"resolved_at"`
+	CreatedAt    time.Time   `json:"createdAt" db:"created_at"`
+}
+
+type ResolveAssetReport struct {
+	ID         string `json:"id" validate:"required"`
+	Resolution string `json:"resolution" validate:"required"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+type EquipmentRequest struct {
+	EmployeeID    string    `json:"employeeId" db:"employee_id"`
+	AssetType     AssetType `json:"assetType" db:"asset_type" validate:"required"`
+	Justification string    `json:"justification" db:"justification" validate:"required"`
+	NeededBy      null.Time `json:"neededBy" db:"needed_by"`
+}
+
+type EquipmentRequestDetails struct {
+	ID            string      `json:"id" db:"id"`
+	EmployeeID    string      `json:"employeeId" db:"employee_id"`
+	EmployeeName  string      `json:"employeeName" db:"employee_name"`
+	EmployeeEmail string      `json:"-" db:"employee_email"`
+	AssetType     AssetType   `json:"assetType" db:"asset_type"`
+	Justification string      `json:"justification" db:"justification"`
+	NeededBy      null.Time   `json:"neededBy" db:"needed_by"`
+	Status        string      `json:"status" db:"status"`
+	CurrentStep   null.String `json:"currentStep" db:"current_step"`
+	RequestedBy   null.String `json:"requestedBy" db:"requested_by"`
+	RelationID    null.String `json:"relationId" db:"relation_id"`
+	FulfilledBy   null.String `json:"fulfilledBy" db:"fulfilled_by"`
+	FulfilledAt   null.Time   `json:"fulfilledAt" db:"fulfilled_at"`
+	CreatedAt     time.Time   `json:"createdAt" db:"created_at"`
+	UpdatedAt     null.Time   `json:"updatedAt" db:"updated_at"`
+
+	Approvals []EquipmentRequestApproval `json:"approvals,omitempty"`
+}
+
+type EquipmentRequestApproval struct {
+	ID         string      `json:"id" db:"id"`
+	RequestID  string      `json:"requestId" db:"request_id"`
+	StepOrder  int         `json:"stepOrder" db:"step_order"`
+	StepName   string      `json:"stepName" db:"step_name"`
+	ApproverID null.String `json:"approverId" db:"approver_id"`
+	Status     string      `json:"status" db:"status"`
+	Comment    null.String `json:"comment" db:"comment"`
+	DecidedBy  null.String `json:"decidedBy" db:"decided_by"`
+	DecidedAt  null.Time   `json:"decidedAt" db:"decided_at"`
+}
+
+type EquipmentRequestDecision struct {
+	Status  string `json:"status" validate:"required,oneof=approved rejected"`
+	Comment string `json:"comment"`
+}
+
+type ApprovalStep struct {
+	ID         string      `json:"id" db:"id"`
+	StepOrder  int         `json:"stepOrder" db:"step_order" validate:"required,min=1"`
+	Name       string      `json:"name" db:"name" validate:"required"`
+	AssetType  null.String `json:"assetType" db:"asset_type"`
+	ApproverID null.String `json:"approverId" db:"approver_id"`
+}
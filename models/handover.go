@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+type CreateHandover struct {
+	AssetID        string         `json:"assetId" validate:"required"`
+	EmployeeID     string         `json:"employeeId" validate:"required"`
+	Kind           string         `json:"kind" validate:"required,oneof=assignment retrieval"`
+	ConditionNotes string         `json:"conditionNotes"`
+	Accessories    pq.StringArray `json:"accessories"`
+}
+
+type HandoverAcknowledgement struct {
+	TypedName      string `json:"typedName" validate:"required"`
+	SignatureImage string `json:"signatureImage" validate:"omitempty,base64"`
+}
+
+type Handover struct {
+	ID               string         `json:"id" db:"id"`
+	RelationID       string         `json:"relationId" db:"relation_id"`
+	Kind             string         `json:"kind" db:"kind"`
+	AssetID          string         `json:"assetId" db:"asset_id"`
+	AssetTag         string         `json:"assetTag" db:"asset_tag"`
+	Brand            string         `json:"brand" db:"brand"`
+	Model            string         `json:"model" db:"model"`
+	SerialNo         string         `json:"serialNo" db:"serial_no"`
+	AssetType        AssetType      `json:"assetType" db:"asset_type"`
+	EmployeeID       string         `json:"employeeId" db:"employee_id"`
+	EmployeeName     string         `json:"employeeName" db:"employee_name"`
+	EmployeeEmail    string         `json:"employeeEmail" db:"employee_email"`
+	AssignedDate     time.Time      `json:"assignedDate" db:"assigned_date"`
+	RetrievedDate    null.Time      `json:"retrievedDate" db:"retrieved_date"`
+	ConditionNotes   string         `json:"conditionNotes" db:"condition_notes"`
+	Accessories      pq.StringArray `json:"accessories" db:"accessories"`
+	Photos           pq.StringArray `json:"photos" db:"photos"`
+	AcknowledgedName null.String    `json:"acknowledgedName" db:"acknowledged_name"`
+	AcknowledgedAt   null.Time      `json:"acknowledgedAt" db:"acknowledged_at"`
+	AcknowledgedIP   null.String    `json:"acknowledgedIp" db:"acknowledged_ip"`
+	HasSignature     bool           `json:"hasSignature" db:"has_signature"`
+	HasReceipt       bool           `json:"hasReceipt" db:"has_receipt"`
+	CreatedBy        null.String    `json:"createdBy" db:"created_by"`
+	CreatedAt        time.Time      `json:"createdAt" db:"created_at"`
+	SignatureImage   []byte         `json:"-" db:"signature_image"`
+}
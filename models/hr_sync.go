@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/volatiletech/null"
+)
+
+// SyncEmployee is an employee as known to the HR system, departments and managers are matched by name and email
+type SyncEmployee struct {
+	ID           string      `json:"id" db:"id"`
+	ExternalID   null.String `json:"externalId" db:"external_id"`
+	Name         string      `json:"name" db:"name"`
+	Email        string      `json:"email" db:"email"`
+	PhoneNo      string      `json:"phoneNo" db:"phone_no"`
+	Type         string      `json:"type" db:"type"`
+	Status       string      `json:"status" db:"status"`
+	Department   string      `json:"department" db:"department_name"`
+	ManagerID    null.String `json:"-" db:"manager_id"`
+	ManagerEmail string      `json:"managerEmail" db:"manager_email"`
+	HeldAssets   int         `json:"-" db:"held_assets"`
+	CreatedAt    time.Time   `json:"-" db:"created_at"`
+	UpdatedAt    null.Time   `json:"-" db:"updated_at"`
+}
+
+type FieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type SyncChange struct {
+	ID         string         `json:"id" db:"id"`
+	RunID      string         `json:"runId" db:"run_id"`
+	EmployeeID null.String    `json:"employeeId" db:"employee_id"`
+	ExternalID null.String    `json:"externalId" db:"external_id"`
+	Email      string         `json:"email" db:"email"`
+	Action     string         `json:"action" db:"action"`
+	Changes    types.JSONText `json:"changes" db:"changes"`
+	Message    string         `json:"message" db:"message"`
+
+	Employee SyncEmployee `json:"-" db:"-"`
+}
+
+type SyncRun struct {
+	ID               string       `json:"id" db:"id"`
+	Source           string       `json:"source" db:"source"`
+	DryRun           bool         `json:"dryRun" db:"dry_run"`
+	FileName         null.String  `json:"fileName" db:"file_name"`
+	CreatedCount     int          `json:"createdCount" db:"created_count"`
+	UpdatedCount     int          `json:"updatedCount" db:"updated_count"`
+	DeactivatedCount int          `json:"deactivatedCount" db:"deactivated_count"`
+	ErrorCount       int          `json:"errorCount" db:"error_count"`
+	TriggeredBy      null.String  `json:"triggeredBy" db:"triggered_by"`
+	TokenID          null.String  `json:"tokenId" db:"token_id"`
+	StartedAt        time.Time    `json:"startedAt" db:"started_at"`
+	FinishedAt       null.Time    `json:"finishedAt" db:"finished_at"`
+	Changes          []SyncChange `json:"changes,omitempty"`
+}
+
+type ProvisioningToken struct {
+	ID         string    `json:"id" db:"id"`
+	Name       string    `json:"name" db:"name" validate:"required"`
+	CreatedBy  string    `json:"createdBy" db:"created_by"`
+	CreatedAt  time.Time `json:"createdAt" db:"created_at"`
+	LastUsedAt null.Time `json:"lastUsedAt" db:"last_used_at"`
+	RevokedAt  null.Time `json:"revokedAt" db:"revoked_at"`
+}
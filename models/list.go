@@ -0,0 +1,90 @@
+package models
+
+// FieldKind is the type of the values a list field is compared with
+type FieldKind string
+
+const (
+	FieldText   FieldKind = "text"
+	FieldNumber FieldKind = "number"
+	FieldDate   FieldKind = "date"
+	FieldTime   FieldKind = "time"
+)
+
+// operators of the conditions of a list filter, in takes | separated values
+const (
+	OpEq       = "eq"
+	OpNe       = "ne"
+	OpLt       = "lt"
+	OpLte      = "lte"
+	OpGt       = "gt"
+	OpGte      = "gte"
+	OpContains = "contains"
+	OpIn       = "in"
+)
+
+// ListSpec is a list that can be sorted and filtered: the fields it allows and the sort it has when none is asked for
+type ListSpec struct {
+	Name        string
+	DefaultSort string
+	Fields      map[string]FieldKind
+}
+
+var (
+	AssetList = ListSpec{Name: "assets", DefaultSort: "purchaseDate", Fields: map[string]FieldKind{
+		"purchaseDate":   FieldDate,
+		"warrantyExpiry": FieldDate,
+		"brand":          FieldText,
+		"model":          FieldText,
+		"assetType":      FieldText,
+		"status":         FieldText,
+		"assignee":       FieldText,
+		"location":       FieldText,
+	}}
+	EmployeeList = ListSpec{Name: "employees", DefaultSort: "createdAt", Fields: map[string]FieldKind{
+		"name":          FieldText,
+		"email":         FieldText,
+		"type":          FieldText,
+		"status":        FieldText,
+		"department":    FieldText,
+		"manager":       FieldText,
+		"assetQuantity": FieldNumber,
+		"createdAt":     FieldTime,
+	}}
+	AccessedByList = ListSpec{Name: "accessedBy", DefaultSort: "name", Fields: map[string]FieldKind{
+		"name":                FieldText,
+		"email":               FieldText,
+		"status":              FieldText,
+		"authenticationTimes": FieldNumber,
+		"lastLogin":           FieldTime,
+	}}
+)
+
+// ListSort orders a list by Field, descending when Desc is set, and then by id in the same direction
+type ListSort struct {
+	Field string
+	Desc  bool
+}
+
+// String is the sort as the sort query parameter spells it, e.g. -purchaseDate
+func (s ListSort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// Condition keeps the rows whose Field compares to Value with Op. Value is a string, int64 or time.Time as the kind
+// of the field asks for, or a []string for OpIn
+type Condition struct {
+	Field string
+	Op    string
+	Value interface{}
+}
+
+// ListCursor is where the next page of a list starts: after the row with ID whose sort field is Key
+type ListCursor struct {
+	List string `json:"l"`
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+type Loan struct {
+	RelationID   string      `json:"relationId" db:"relation_id"`
+	AssetID      string      `json:"assetId" db:"asset_id"`
+	AssetTag     string      `json:"assetTag" db:"asset_tag"`
+	Brand        string      `json:"brand" db:"brand"`
+	Model        string      `json:"model" db:"model"`
+	AssetType    AssetType   `json:"assetType" db:"asset_type"`
+	EmployeeID   string      `json:"employeeId" db:"employee_id"`
+	EmployeeName string      `json:"employeeName" db:"employee_name"`
+	Email        string      `json:"email" db:"email"`
+	AssignedDate time.Time   `json:"assignedDate" db:"assigned_date"`
+	DueDate      time.Time   `json:"dueDate" db:"due_date"`
+	OverdueDays  int         `json:"overdueDays" db:"overdue_days"`
+	LastReminder null.Time   `json:"lastReminderAt" db:"last_reminder_at"`
+	ExtensionID  null.String `json:"pendingExtensionId" db:"pending_extension_id"`
+}
+
+type LoanExtensionRequest struct {
+	AssetID          string    `json:"assetId" validate:"required"`
+	RequestedDueDate time.Time `json:"requestedDueDate" validate:"required"`
+	Reason           string    `json:"reason"`
+}
+
+type LoanExtensionDecision struct {
+	ID     string `json:"id" validate:"required"`
+	Status string `json:"status" validate:"required,oneof=approved rejected"`
+}
+
+type LoanExtension struct {
+	ID               string      `json:"id" db:"id"`
+	RelationID       string      `json:"relationId" db:"relation_id"`
+	AssetID          string      `json:"assetId" db:"asset_id"`
+	EmployeeID       string      `json:"employeeId" db:"employee_id"`
+	EmployeeName     string      `json:"employeeName" db:"employee_name"`
+	CurrentDueDate   time.Time   `json:"currentDueDate" db:"current_due_date"`
+	RequestedDueDate time.Time   `json:"requestedDueDate" db:"requested_due_date"`
+	Reason           string      `json:"reason" db:"reason"`
+	Status           string      `json:"status" db:"status"`
+	DecidedBy        null.String `json:"decidedBy" db:"decided_by"`
+	DecidedAt        null.Time   `json:"decidedAt" db:"decided_at"`
+	CreatedAt        time.Time   `json:"createdAt" db:"created_at"`
+}
+
+type LoanStats struct {
+	EmployeeID      string  `json:"employeeId" db:"employee_id"`
+	EmployeeName    string  `json:"employeeName" db:"employee_name"`
+	TotalLoans      int     `json:"totalLoans" db:"total_loans"`
+	ActiveLoans     int     `json:"activeLoans" db:"active_loans"`
+	OverdueLoans    int     `json:"overdueLoans" db:"overdue_loans"`
+	ReturnedLate    int     `json:"returnedLate" db:"returned_late"`
+	Extensions      int     `json:"extensions" db:"extensions"`
+	AverageLoanDays float64 `json:"averageLoanDays" db:"average_loan_days"`
+}
+
+type AdminContact struct {
+	Name  string `json:"name" db:"name"`
+	Email string `json:"email" db:"email"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+type LocationDetails struct {
+	ID       string      `json:"id" db:"id"`
+	Name     string      `json:"name" db:"name" validate:"required"`
+	Type     string      `json:"type" db:"type" validate:"required,oneof=site room shelf"`
+	ParentID null.String `json:"parentId" db:"parent_id"`
+}
+
+type Location struct {
+	ID           string      `json:"id" db:"id"`
+	Name         string      `json:"name" db:"name"`
+	Type         string      `json:"type" db:"type"`
+	ParentID     null.String `json:"parentId" db:"parent_id"`
+	ParentName   null.String `json:"parentName" db:"parent_name"`
+	EmployeeID   null.String `json:"employeeId" db:"employee_id"`
+	EmployeeName null.String `json:"employeeName" db:"employee_name"`
+	AssetCount   int         `json:"assetCount" db:"asset_count"`
+	CreatedAt    time.Time   `json:"createdAt" db:"created_at"`
+}
+
+type AssetTransfer struct {
+	AssetID      string      `json:"assetId" db:"asset_id" validate:"required"`
+	ToLocationID null.String `json:"toLocationId" db:"to_location_id"`
+	Note         string      `json:"note" db:"note"`
+}
+
+type AssetTransferHistory struct {
+	ID               string      `json:"id" db:"id"`
+	AssetID          string      `json:"assetId" db:"asset_id"`
+	FromLocationID   null.String `json:"fromLocationId" db:"from_location_id"`
+	FromLocationName null.String `json:"fromLocationName" db:"from_location_name"`
+	ToLocationID     null.String `json:"toLocationId" db:"to_location_id"`
+	ToLocationName   null.String `json:"toLocationName" db:"to_location_name"`
+	TransferredBy    null.String `json:"transferredBy" db:"transferred_by"`
+	TransferredAt    time.Time   `json:"transferredAt" db:"transferred_at"`
+	Note             string      `json:"note" db:"note"`
+}
+
+type LocationQuantity struct {
+	ID         string      `json:"id" db:"id"`
+	Name       string      `json:"name" db:"name"`
+	Type       string      `json:"type" db:"type"`
+	ParentID   null.String `json:"parentId" db:"parent_id"`
+	AssetCount int         `json:"assetCount" db:"asset_count"`
+}
+
+type LocationDashboard struct {
+	Locations       []LocationQuantity `json:"locations"`
+	WithEmployees   int                `json:"withEmployees" db:"with_employees"`
+	UnknownLocation int                `json:"unknownLocation" db:"unknown_location"`
+}
@@ -0,0 +1,15 @@
+package models
+
+// AssetCount is the number of assets of a type in a status
+type AssetCount struct {
+	AssetType AssetType `db:"asset_type"`
+	Status    string    `db:"status"`
+	Count     int       `db:"count"`
+}
+
+// InventoryMetrics is the state of the inventory exported as gauges
+type InventoryMetrics struct {
+	Assets             []AssetCount
+	OpenAssignments    int `db:"open_assignments"`
+	ExpiringWarranties int `db:"expiring_warranties"`
+}
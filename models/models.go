@@ -21,6 +21,14 @@ type AccessedByDetails struct {
 	AuthenticationTimes int       `json:"authenticationTimes" db:"authentication_times"`
 	LastLoginTime       null.Time `json:"lastLoginTime" db:"start_time"`
 	Status              string    `json:"status" db:"status"`
+	TotalCount          int       `json:"-" db:"total_count"`
+	SortKey             string    `json:"-" db:"sort_key"`
+}
+
+type TotalAccessedBy struct {
+	AccessedBy []AccessedByDetails
+	TotalCount int
+	NextCursor string
 }
 
 type FiltersCheck struct {
@@ -37,6 +45,11 @@ type FiltersCheck struct {
 	Deleted       bool
 	Assigned      bool
 	Warranty      int
+	LocationID    string
+	DepartmentID  string
+	Sort          ListSort
+	Conditions    []Condition
+	Cursor        *ListCursor
 }
 
 type AssetType string
@@ -71,10 +84,13 @@ type StatusDetails struct {
 type UserCredentials struct {
 	ID       string `json:"id" db:"id"`
 	Password string `json:"password" db:"password"`
+	Role     string `json:"role" db:"role"`
 }
 
 type Claims struct {
-	ID string `json:"id"`
+	ID          string   `json:"id"`
+	Role        string   `json:"role,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
 	jwt.StandardClaims
 }
 
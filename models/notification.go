@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/jmoiron/sqlx/types"
+)
+
+// kinds of the emails queued in the notification outbox
+const (
+	// NotificationRequestStatus tells an employee the status of their equipment request
+	NotificationRequestStatus = "equipment_request.status"
+	// NotificationRequestApproval asks the approvers of the current step of an equipment request for a decision
+	NotificationRequestApproval = "equipment_request.approval"
+	// NotificationManager tells the manager of an employee that their report received or returned an asset
+	NotificationManager = "employee.manager"
+)
+
+// events of a NotificationManager
+const (
+	ManagerAssetReceived = "received"
+	ManagerAssetReturned = "returned"
+)
+
+// DueNotification is a claimed notification outbox entry
+type DueNotification struct {
+	ID       string         `db:"id"`
+	Kind     string         `db:"kind"`
+	Payload  types.JSONText `db:"payload"`
+	Attempts int            `db:"attempts"`
+}
+
+// EquipmentRequestNotification is the payload of the equipment request notifications, Status is the status the
+// request had when the notification was queued
+type EquipmentRequestNotification struct {
+	RequestID string `json:"requestId"`
+	Status    string `json:"status"`
+}
+
+// ManagerNotification is the payload of a NotificationManager, the manager is looked up when it is sent
+type ManagerNotification struct {
+	EmployeeID string `json:"employeeId"`
+	AssetID    string `json:"assetId"`
+	Event      string `json:"event"`
+}
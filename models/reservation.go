@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+// Reservation holds either a specific asset or, when AssetID is empty, any asset of the given brand and model
+type Reservation struct {
+	ID         string      `json:"id" db:"id"`
+	AssetID    null.String `json:"assetId" db:"asset_id"`
+	AssetType  AssetType   `json:"assetType" db:"asset_type"`
+	Brand      null.String `json:"brand" db:"brand"`
+	Model      null.String `json:"model" db:"model"`
+	EmployeeID null.String `json:"employeeId" db:"employee_id"`
+	HolderName null.String `json:"holderName" db:"holder_name"`
+	Purpose    string      `json:"purpose" db:"purpose" validate:"required"`
+	StartDate  time.Time   `json:"startDate" db:"start_date" validate:"required"`
+	EndDate    time.Time   `json:"endDate" db:"end_date" validate:"required,gtefield=StartDate"`
+}
+
+type ReservationDetails struct {
+	Reservation
+	AssetTag     null.String `json:"assetTag" db:"asset_tag"`
+	EmployeeName null.String `json:"employeeName" db:"employee_name"`
+	Status       string      `json:"status" db:"status"`
+	RelationID   null.String `json:"relationId" db:"relation_id"`
+	CreatedBy    string      `json:"createdBy" db:"created_by"`
+	CreatedAt    time.Time   `json:"createdAt" db:"created_at"`
+}
+
+type ReservationConflict struct {
+	ReservationID string    `json:"reservationId" db:"reservation_id"`
+	AssetID       string    `json:"assetId" db:"asset_id"`
+	Reason        string    `json:"reason" db:"reason"`
+	StartDate     time.Time `json:"startDate" db:"start_date"`
+	EndDate       time.Time `json:"endDate" db:"end_date"`
+}
+
+type ConvertReservation struct {
+	AssetID        string    `json:"assetId"`
+	EmployeeID     string    `json:"employeeId"`
+	AssignedDate   time.Time `json:"assignedDate"`
+	AssignmentType string    `json:"assignmentType" validate:"omitempty,oneof=permanent loan"`
+	DueDate        null.Time `json:"dueDate"`
+}
+
+// CalendarEvent is a single all-day entry of the reservations calendar feed
+type CalendarEvent struct {
+	UID         string    `db:"uid"`
+	Summary     string    `db:"summary"`
+	Description string    `db:"description"`
+	StartDate   time.Time `db:"start_date"`
+	EndDate     time.Time `db:"end_date"`
+}
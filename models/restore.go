@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/volatiletech/null"
+)
+
+const (
+	RestoreAssetEntity    = "asset"
+	RestoreEmployeeEntity = "employee"
+)
+
+type RestoreAsset struct {
+	ID            string `json:"id" validate:"required"`
+	RestoreReason string `json:"restoreReason" validate:"required"`
+}
+
+type RestoreEmployee struct {
+	RestoreReason string `json:"restoreReason" validate:"required"`
+}
+
+// Archived is the deletion a restore reverses, it is kept in the restore history as the row itself forgets it
+type Archived struct {
+	ArchivedAt    time.Time   `json:"archivedAt" db:"archived_at"`
+	ArchiveReason null.String `json:"archiveReason" db:"archive_reason"`
+	DeletedBy     null.String `json:"deletedBy" db:"deleted_by"`
+}
+
+type RestoreRecord struct {
+	ID         string `json:"id" db:"id"`
+	EntityType string `json:"entityType" db:"entity_type"`
+	EntityID   string `json:"entityId" db:"entity_id"`
+	Archived
+	DeletedByName  null.String `json:"deletedByName" db:"deleted_by_name"`
+	RestoreReason  string      `json:"restoreReason" db:"restore_reason"`
+	RestoredBy     string      `json:"restoredBy" db:"restored_by"`
+	RestoredByName string      `json:"restoredByName" db:"restored_by_name"`
+	RestoredAt     time.Time   `json:"restoredAt" db:"restored_at"`
+}
+
+// PurgeCount is how many rows archived beyond the retention period a purge removed
+type PurgeCount struct {
+	Assets    int `json:"assets"`
+	Employees int `json:"employees"`
+}
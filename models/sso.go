@@ -0,0 +1,13 @@
+package models
+
+type LoginState struct {
+	State        string `db:"state"`
+	Nonce        string `db:"nonce"`
+	CodeVerifier string `db:"code_verifier"`
+}
+
+type SSOUser struct {
+	ID   string `db:"id"`
+	Role string `db:"role"`
+	Type string `db:"type"`
+}
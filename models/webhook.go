@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+const (
+	EventAssetAssigned        = "asset.assigned"
+	EventAssetRetrieved       = "asset.retrieved"
+	EventAssetDeleted         = "asset.deleted"
+	EventAssetWarrantyUpdated = "asset.warranty_updated"
+)
+
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	WebhookDead      = "dead"
+)
+
+type CreateWebhookSubscription struct {
+	Name       string   `json:"name" validate:"required"`
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=asset.assigned asset.retrieved asset.deleted asset.warranty_updated"`
+}
+
+type UpdateWebhookSubscription struct {
+	Name       string   `json:"name" validate:"required"`
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,dive,oneof=asset.assigned asset.retrieved asset.deleted asset.warranty_updated"`
+	Active     bool     `json:"active"`
+}
+
+type WebhookSubscription struct {
+	ID         string         `json:"id" db:"id"`
+	Name       string         `json:"name" db:"name"`
+	URL        string         `json:"url" db:"url"`
+	EventTypes pq.StringArray `json:"eventTypes" db:"event_types"`
+	Active     bool           `json:"active" db:"active"`
+	CreatedBy  string         `json:"createdBy" db:"created_by"`
+	CreatedAt  time.Time      `json:"createdAt" db:"created_at"`
+	UpdatedAt  null.Time      `json:"updatedAt" db:"updated_at"`
+}
+
+// WebhookEvent is the JSON body posted to subscribers, ID stays the same across retries and redeliveries
+type WebhookEvent struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	OccurredAt time.Time      `json:"occurredAt"`
+	Data       types.JSONText `json:"data"`
+}
+
+// WebhookDelivery is one event queued for one subscription
+type WebhookDelivery struct {
+	ID             string           `json:"id" db:"id"`
+	OutboxID       string           `json:"eventId" db:"outbox_id"`
+	SubscriptionID string           `json:"subscriptionId" db:"subscription_id"`
+	EventType      string           `json:"eventType" db:"event_type"`
+	Status         string           `json:"status" db:"status"`
+	Attempts       int              `json:"attempts" db:"attempts"`
+	NextAttemptAt  null.Time        `json:"nextAttemptAt" db:"next_attempt_at"`
+	LastStatusCode null.Int         `json:"lastStatusCode" db:"last_status_code"`
+	LastError      null.String      `json:"lastError" db:"last_error"`
+	DeliveredAt    null.Time        `json:"deliveredAt" db:"delivered_at"`
+	CreatedAt      time.Time        `json:"createdAt" db:"created_at"`
+	Payload        types.JSONText   `json:"payload,omitempty" db:"payload"`
+	AttemptLog     []WebhookAttempt `json:"attemptLog,omitempty"`
+}
+
+type WebhookAttempt struct {
+	DeliveryID  string      `json:"-" db:"delivery_id"`
+	StatusCode  null.Int    `json:"statusCode" db:"status_code"`
+	Error       null.String `json:"error" db:"error"`
+	DurationMS  int         `json:"durationMs" db:"duration_ms"`
+	AttemptedAt time.Time   `json:"attemptedAt" db:"attempted_at"`
+}
+
+// DueWebhookDelivery is a claimed delivery with everything needed to send it
+type DueWebhookDelivery struct {
+	ID         string         `db:"id"`
+	OutboxID   string         `db:"outbox_id"`
+	Attempts   int            `db:"attempts"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	EventType  string         `db:"event_type"`
+	Payload    types.JSONText `db:"payload"`
+	OccurredAt time.Time      `db:"occurred_at"`
+}
+
+type AssetAssignedEvent struct {
+	AssetID            string      `json:"assetId"`
+	EmployeeID         string      `json:"employeeId"`
+	PreviousEmployeeID null.String `json:"previousEmployeeId"`
+	AssignedDate       string      `json:"assignedDate"`
+	AssignmentType     string      `json:"assignmentType"`
+	DueDate            null.Time   `json:"dueDate"`
+	AssignedBy         string      `json:"assignedBy"`
+}
+
+type AssetRetrievedEvent struct {
+	AssetID         string    `json:"assetId"`
+	EmployeeID      string    `json:"employeeId"`
+	RetrievedDate   time.Time `json:"retrievedDate"`
+	RetrievalReason string    `json:"retrievalReason"`
+	RetrievedBy     string    `json:"retrievedBy"`
+}
+
+type AssetDeletedEvent struct {
+	AssetID   string    `json:"assetId"`
+	AssetType AssetType `json:"assetType"`
+	DeletedBy string    `json:"deletedBy"`
+}
+
+type AssetWarrantyUpdatedEvent struct {
+	AssetID            string    `json:"assetId"`
+	WarrantyStartDate  time.Time `json:"warrantyStartDate"`
+	WarrantyExpiryDate time.Time `json:"warrantyExpiryDate"`
+	UpdatedBy          string    `json:"updatedBy"`
+}
@@ -0,0 +1,89 @@
+package oidc
+
+import (
+	"os"
+	"strings"
+)
+
+const defaultGroupsClaim = "groups"
+
+// Policy decides who may sign in through the provider and with which local role
+type Policy struct {
+	AllowedDomains []string
+	GroupsClaim    string
+	// RoleMapping maps identity provider groups onto local roles, when it is empty every allowed user gets DefaultRole
+	RoleMapping map[string]string
+	DefaultRole string
+	// TrustUnverifiedEmail accepts emails without email_verified, for providers that leave the claim out and do not let
+	// users choose their address. Users are matched by email on their first sign in, so it must stay off otherwise
+	TrustUnverifiedEmail bool
+}
+
+// PolicyFromEnv reads OIDC_ALLOWED_DOMAINS (comma separated), OIDC_GROUPS_CLAIM, OIDC_ROLE_MAPPING
+// (comma separated group=role pairs), OIDC_DEFAULT_ROLE and OIDC_TRUST_UNVERIFIED_EMAIL
+func PolicyFromEnv(defaultRole string) Policy {
+	policy := Policy{
+		GroupsClaim:          os.Getenv("OIDC_GROUPS_CLAIM"),
+		RoleMapping:          make(map[string]string),
+		DefaultRole:          os.Getenv("OIDC_DEFAULT_ROLE"),
+		TrustUnverifiedEmail: strings.EqualFold(os.Getenv("OIDC_TRUST_UNVERIFIED_EMAIL"), "true"),
+	}
+	if policy.GroupsClaim == "" {
+		policy.GroupsClaim = defaultGroupsClaim
+	}
+	if policy.DefaultRole == "" {
+		policy.DefaultRole = defaultRole
+	}
+	for _, domain := range strings.Split(os.Getenv("OIDC_ALLOWED_DOMAINS"), ",") {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			policy.AllowedDomains = append(policy.AllowedDomains, strings.TrimPrefix(domain, "@"))
+		}
+	}
+	for _, pair := range strings.Split(os.Getenv("OIDC_ROLE_MAPPING"), ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		if ok && strings.TrimSpace(group) != "" {
+			policy.RoleMapping[strings.TrimSpace(group)] = strings.TrimSpace(role)
+		}
+	}
+	return policy
+}
+
+// AllowsEmail checks the email domain against the allowed domains, no allowed domains means nobody is allowed
+func (p *Policy) AllowsEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for i := range p.AllowedDomains {
+		if p.AllowedDomains[i] == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// Role returns the most privileged role granted by the groups, roles are ordered from most to least privileged.
+// It reports false when a role mapping is configured and none of the groups is mapped
+func (p *Policy) Role(groups, roles []string) (string, bool) {
+	if len(p.RoleMapping) == 0 {
+		return p.DefaultRole, true
+	}
+	granted := make(map[string]bool, len(groups))
+	for i := range groups {
+		if role, ok := p.RoleMapping[groups[i]]; ok {
+			granted[role] = true
+		}
+	}
+	for i := range roles {
+		if granted[roles[i]] {
+			return roles[i], true
+		}
+	}
+	return "", false
+}
+
+// AllowsIdentity checks the email of an identity can be relied on and is in an allowed domain
+func (p *Policy) AllowsIdentity(identity *Identity) bool {
+	return (identity.EmailVerified || p.TrustUnverifiedEmail) && p.AllowsEmail(identity.Email)
+}
@@ -0,0 +1,24 @@
+package oidc
+
+import "testing"
+
+func TestAllowsIdentity(t *testing.T) {
+	policy := Policy{AllowedDomains: []string{"remotestate.com"}}
+	cases := []struct {
+		name     string
+		identity Identity
+		trust    bool
+		want     bool
+	}{
+		{"verified", Identity{Email: "asha@remotestate.com", EmailVerified: true}, false, true},
+		{"unverified", Identity{Email: "asha@remotestate.com"}, false, false},
+		{"unverified from a trusted provider", Identity{Email: "asha@remotestate.com"}, true, true},
+		{"verified in another domain", Identity{Email: "asha@example.com", EmailVerified: true}, false, false},
+	}
+	for _, c := range cases {
+		policy.TrustUnverifiedEmail = c.trust
+		if got := policy.AllowsIdentity(&c.identity); got != c.want {
+			t.Errorf("%s: got %t, want %t", c.name, got, c.want)
+		}
+	}
+}
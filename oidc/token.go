@@ -0,0 +1,113 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const randomBytes = 32
+
+// Identity is what the application uses from a verified ID token
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of an ID token and returns its identity.
+// groupsClaim names the claim holding the user's groups, e.g. groups or roles
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce, groupsClaim string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	if issuer, _ := claims["iss"].(string); strings.TrimSuffix(issuer, "/") != p.config.Issuer {
+		return Identity{}, fmt.Errorf("unexpected issuer %q", issuer)
+	}
+	if !hasAudience(claims["aud"], p.config.ClientID) {
+		return Identity{}, errors.New("id token was not issued for this client")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return Identity{}, errors.New("id token has no expiry")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+		return Identity{}, errors.New("nonce mismatch")
+	}
+
+	identity := Identity{
+		Groups: stringList(claims[groupsClaim]),
+	}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	// an email is only verified when the provider says so, Policy.TrustUnverifiedEmail covers providers that never do
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = verified
+	case string:
+		identity.EmailVerified = verified == "true"
+	}
+	if identity.Subject == "" {
+		return Identity{}, errors.New("id token has no subject")
+	}
+	return identity, nil
+}
+
+// RandomString returns a URL safe random string for state, nonce and PKCE verifiers
+func RandomString() (string, error) {
+	b := make([]byte, randomBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge derives the S256 PKCE challenge of a verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	for _, audience := range stringList(aud) {
+		if audience == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList reads a claim that is either a single string or a list of strings
+func stringList(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for i := range value {
+			if s, ok := value[i].(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Package openapi describes the v2 API. The document is built from the route table and the models the handlers use,
+// openapi.json is the committed copy served at /asset-management/v2/openapi.json
+package openapi
+
+import (
+	"InternalAssetManagement/utils"
+	_ "embed" // openapi.json is embedded
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ../cmd/openapi-gen -spec openapi.json -client ../client/client.gen.go
+
+const (
+	Version  = "2.0.0"
+	BasePath = "/asset-management/v2"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// Spec returns the committed document
+func Spec() []byte {
+	return spec
+}
+
+var pathParam = regexp.MustCompile(`{([^}]+)}`)
+
+// Build generates the document from Routes
+func Build() *Document {
+	builder := newSchemaBuilder()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   "Internal Asset Management",
+			Version: Version,
+			Description: "Field names are lower camel case and identifiers are spelled Id. Admin routes take the session " +
+				"token from /login in the Authorization header, or an API key in the x-api-key header when the route " +
+				"allows it. Employee portal routes take the token from /employee/login.",
+		},
+		Servers: []Server{{URL: BasePath}},
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				"adminToken": {Type: "apiKey", In: "header", Name: "Authorization",
+					Description: "session token from /login, sent without a Bearer prefix"},
+				"apiKey": {Type: "apiKey", In: "header", Name: "x-api-key",
+					Description: "API key, read routes accept a :read or :write scope and other routes need :write"},
+				"employeeToken": {Type: "apiKey", In: "header", Name: "Authorization",
+					Description: "session token from /employee/login, sent without a Bearer prefix"},
+			},
+		},
+	}
+	clientError := builder.schema(reflect.TypeOf(utils.ClientError{}))
+
+	seenTags := make(map[string]bool)
+	for i := range Routes {
+		route := &Routes[i]
+		if !seenTags[route.Tag] {
+			seenTags[route.Tag] = true
+			doc.Tags = append(doc.Tags, Tag{Name: route.Tag})
+		}
+
+		path := pathParam.ReplaceAllStringFunc(route.Path, func(param string) string {
+			return "{" + utils.V2JSONName(strings.Trim(param, "{}")) + "}"
+		})
+		item := doc.Paths[path]
+		if item == nil {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(route.Method)
+		if (*item)[method] != nil {
+			panic(fmt.Sprintf("openapi: %s %s is listed twice", route.Method, route.Path))
+		}
+		(*item)[method] = operation(builder, route, path, clientError)
+	}
+
+	doc.Components.Schemas = builder.components
+	return doc
+}
+
+func operation(builder *schemaBuilder, route *Route, path string, clientError *Schema) *Operation {
+	op := &Operation{
+		OperationID: route.Handler,
+		Summary:     route.Summary,
+		Tags:        []string{route.Tag},
+		Responses:   make(map[string]*Response),
+		Security:    security(route.Auth),
+	}
+	if route.Scope != "" {
+		op.Description = "API keys need the " + route.Scope + " scope."
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, param := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        param.Name,
+			In:          "query",
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: param.Type},
+		})
+	}
+
+	switch {
+	case route.Body != nil:
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"application/json": {Schema: builder.schema(reflect.TypeOf(route.Body))},
+		}}
+	case route.Upload != "":
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{
+			"multipart/form-data": {Schema: &Schema{
+				Type:       "object",
+				Properties: map[string]*Schema{route.Upload: {Type: "string", Format: "binary"}},
+				Required:   []string{route.Upload},
+			}},
+		}}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case route.Response != nil:
+		success.Content = map[string]MediaType{"application/json": {Schema: builder.schema(reflect.TypeOf(route.Response))}}
+	case len(route.Produces) > 0:
+		success.Content = make(map[string]MediaType)
+		for _, contentType := range route.Produces {
+			success.Content[contentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	op.Responses["default"] = &Response{
+		Description: "Error",
+		Content:     map[string]MediaType{"application/json": {Schema: clientError}},
+	}
+	return op
+}
+
+func security(auth Auth) []map[string][]string {
+	switch auth {
+	case Admin:
+		return []map[string][]string{{"adminToken": {}}, {"apiKey": {}}}
+	case Account:
+		return []map[string][]string{{"adminToken": {}}}
+	case Portal:
+		return []map[string][]string{{"employeeToken": {}}}
+	}
+	return []map[string][]string{}
+}
+
+// JSON renders the document the way it is committed
+func (doc *Document) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
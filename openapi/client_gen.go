@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// GenerateClient writes the types and methods of package client from the document. The hand written part of the
+// package supplies the Client type and its do, upload and raw helpers
+func GenerateClient(doc *Document) ([]byte, error) {
+	g := &clientGenerator{doc: doc, imports: make(map[string]bool)}
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		g.structType(name, doc.Components.Schemas[name])
+	}
+
+	type method struct {
+		path, verb string
+		op         *Operation
+	}
+	var methods []method
+	for path, item := range doc.Paths {
+		for verb, op := range *item {
+			methods = append(methods, method{path: path, verb: verb, op: op})
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].op.OperationID < methods[j].op.OperationID })
+	for _, m := range methods {
+		g.method(m.path, m.verb, m.op)
+	}
+
+	var file strings.Builder
+	file.WriteString("// Code generated by openapi-gen from the v2 OpenAPI document. DO NOT EDIT.\n\npackage client\n\n")
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	file.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&file, "\t%q\n", path)
+	}
+	file.WriteString(")\n")
+	file.WriteString(g.types.String())
+	file.WriteString(g.methods.String())
+
+	source, err := format.Source([]byte(file.String()))
+	if err != nil {
+		return nil, fmt.Errorf("GenerateClient: cannot format generated client: %w", err)
+	}
+	return source, nil
+}
+
+type clientGenerator struct {
+	doc     *Document
+	imports map[string]bool
+	types   strings.Builder
+	methods strings.Builder
+}
+
+// goName exports a JSON or parameter name, spelling Id as ID the way Go does
+func goName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	out := b.String()
+	for _, suffix := range []string{"Id", "Url"} {
+		if strings.HasSuffix(out, suffix) {
+			out = strings.TrimSuffix(out, suffix) + strings.ToUpper(suffix)
+		}
+	}
+	return out
+}
+
+func (g *clientGenerator) structType(name string, schema *Schema) {
+	fmt.Fprintf(&g.types, "\ntype %s struct {\n", name)
+	properties := make([]string, 0, len(schema.Properties))
+	for property := range schema.Properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	for _, property := range properties {
+		tag := property
+		if !contains(schema.Required, property) {
+			tag += ",omitempty"
+		}
+		fieldType := g.goType(schema.Properties[property], name+goName(property))
+		fmt.Fprintf(&g.types, "\t%s %s `json:%q`\n", goName(property), fieldType, tag)
+	}
+	g.types.WriteString("}\n")
+}
+
+// goType returns the Go type of a schema, inline objects become struct types named after where they appear
+func (g *clientGenerator) goType(schema *Schema, inlineName string) string {
+	if schema.Ref != "" {
+		return schema.RefName()
+	}
+	if len(schema.AllOf) == 1 {
+		inner := g.goType(schema.AllOf[0], inlineName)
+		if schema.Nullable {
+			return "*" + inner
+		}
+		return inner
+	}
+
+	var goType string
+	switch schema.Type {
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			g.imports["time"] = true
+			goType = "time.Time"
+		case "byte":
+			goType = "[]byte"
+		default:
+			goType = "string"
+		}
+	case "integer":
+		goType = "int"
+		if schema.Format == "int64" {
+			goType = "int64"
+		}
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		return "[]" + g.goType(schema.Items, inlineName+"Item")
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return "map[string]" + g.goType(schema.AdditionalProperties, inlineName+"Value")
+		}
+		g.structType(inlineName, schema)
+		goType = inlineName
+	default:
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+	if schema.Nullable {
+		return "*" + goType
+	}
+	return goType
+}
+
+func (g *clientGenerator) method(path, verb string, op *Operation) {
+	name := op.OperationID
+	var args, query []string
+	pathExpr := fmt.Sprintf("%q", path)
+	for _, param := range op.Parameters {
+		if param.In == "path" {
+			g.imports["net/url"] = true
+			arg := goName(param.Name)
+			arg = strings.ToLower(arg[:1]) + arg[1:]
+			args = append(args, arg+" string")
+			pathExpr = fmt.Sprintf("strings.Replace(%s, %q, url.PathEscape(%s), 1)", pathExpr, "{"+param.Name+"}", arg)
+			g.imports["strings"] = true
+		} else {
+			query = append(query, param.Name)
+		}
+	}
+	if len(query) > 0 {
+		g.paramsType(name, op)
+		args = append(args, "params *"+name+"Params")
+	}
+
+	bodyArg := "nil"
+	var upload string
+	if op.RequestBody != nil {
+		if content, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, "body "+g.goType(content.Schema, name+"Request"))
+			bodyArg = "body"
+		} else {
+			g.imports["io"] = true
+			upload = op.RequestBody.Content["multipart/form-data"].Schema.Required[0]
+			args = append(args, "filename string", "file io.Reader")
+		}
+	}
+
+	var success *Response
+	for status, response := range op.Responses {
+		if strings.HasPrefix(status, "2") || strings.HasPrefix(status, "3") {
+			success = response
+		}
+	}
+	queryArg := "nil"
+	if len(query) > 0 {
+		queryArg = "params.values()"
+	}
+	ctxArgs := strings.Join(append([]string{"ctx context.Context"}, args...), ", ")
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+	method := "http.Method" + strings.ToUpper(verb[:1]) + verb[1:]
+
+	fmt.Fprintf(&g.methods, "\n// %s sends %s %s: %s\n", name, strings.ToUpper(verb), path, op.Summary)
+	content, isJSON := success.Content["application/json"]
+	switch {
+	case isJSON && content.Schema.Type != "string":
+		result := g.goType(content.Schema, name+"Response")
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) (%s, error) {\n\tvar out %s\n", name, ctxArgs, result, result)
+		if upload != "" {
+			fmt.Fprintf(&g.methods, "\terr := c.upload(ctx, %s, %s, %s, %q, filename, file, &out)\n", method, pathExpr, queryArg, upload)
+		} else {
+			fmt.Fprintf(&g.methods, "\terr := c.do(ctx, %s, %s, %s, %s, &out)\n", method, pathExpr, queryArg, bodyArg)
+		}
+		g.methods.WriteString("\treturn out, err\n}\n")
+	case len(success.Content) > 0:
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) ([]byte, error) {\n", name, ctxArgs)
+		fmt.Fprintf(&g.methods, "\treturn c.raw(ctx, %s, %s, %s)\n}\n", method, pathExpr, queryArg)
+	default:
+		fmt.Fprintf(&g.methods, "func (c *Client) %s(%s) error {\n", name, ctxArgs)
+		fmt.Fprintf(&g.methods, "\t_, err := c.raw(ctx, %s, %s, %s)\n\treturn err\n}\n", method, pathExpr, queryArg)
+	}
+}
+
+// paramsType writes the query parameters of an operation as a struct, unset fields are left out of the query
+func (g *clientGenerator) paramsType(name string, op *Operation) {
+	g.imports["net/url"] = true
+	var fields, encode strings.Builder
+	for _, param := range op.Parameters {
+		if param.In != "query" {
+			continue
+		}
+		field := goName(param.Name)
+		if param.Description != "" {
+			fmt.Fprintf(&fields, "\t// %s %s\n", field, param.Description)
+		}
+		switch param.Schema.Type {
+		case "integer":
+			g.imports["strconv"] = true
+			fmt.Fprintf(&fields, "\t%s *int\n", field)
+			fmt.Fprintf(&encode, "\tif p.%s != nil {\n\t\tq.Set(%q, strconv.Itoa(*p.%s))\n\t}\n", field, param.Name, field)
+		case "boolean":
+			g.imports["strconv"] = true
+			fmt.Fprintf(&fields, "\t%s *bool\n", field)
+			fmt.Fprintf(&encode, "\tif p.%s != nil {\n\t\tq.Set(%q, strconv.FormatBool(*p.%s))\n\t}\n", field, param.Name, field)
+		default:
+			fmt.Fprintf(&fields, "\t%s string\n", field)
+			fmt.Fprintf(&encode, "\tif p.%s != \"\" {\n\t\tq.Set(%q, p.%s)\n\t}\n", field, param.Name, field)
+		}
+	}
+	fmt.Fprintf(&g.types, "\n// %sParams are the query parameters of %s\ntype %sParams struct {\n%s}\n", name, name, name, fields.String())
+	fmt.Fprintf(&g.types, "\nfunc (p *%sParams) values() url.Values {\n\tq := url.Values{}\n\tif p == nil {\n\t\treturn q\n\t}\n%s\treturn q\n}\n",
+		name, encode.String())
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+const regenerate = "run go generate ./openapi to regenerate it"
+
+func TestSpecIsUpToDate(t *testing.T) {
+	built, err := Build().JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(built, Spec()) {
+		t.Fatalf("openapi.json does not match the route table, %s", regenerate)
+	}
+}
+
+func TestClientIsUpToDate(t *testing.T) {
+	generated, err := GenerateClient(Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	committed, err := os.ReadFile("../client/client.gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, committed) {
+		t.Fatalf("client/client.gen.go does not match the document, %s", regenerate)
+	}
+}
+
+func TestSpecLoads(t *testing.T) {
+	doc, err := Load(Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, item := range doc.Paths {
+		for method, op := range *item {
+			for status, response := range op.Responses {
+				for _, content := range response.Content {
+					if content.Schema.Ref != "" && doc.Components.Schemas[content.Schema.RefName()] == nil {
+						t.Errorf("%s %s %s refers to the missing schema %s", method, path, status, content.Schema.Ref)
+					}
+				}
+			}
+		}
+	}
+	if !doc.Components.Schemas["UsersLoginDetails"].Closed {
+		t.Error("additionalProperties: false is lost when the document is loaded")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc := Build()
+	equipmentRequest := &Schema{Ref: "#/components/schemas/EquipmentRequest"}
+	cases := []struct {
+		name   string
+		schema *Schema
+		value  interface{}
+		err    string
+	}{
+		{"valid", equipmentRequest, map[string]interface{}{"assetType": "laptop", "justification": "new joiner"}, ""},
+		{"nullable", equipmentRequest, map[string]interface{}{"assetType": "laptop", "justification": "x", "neededBy": nil}, ""},
+		{"date-time", equipmentRequest, map[string]interface{}{"assetType": "laptop", "justification": "x", "neededBy": "tomorrow"}, "not a date-time"},
+		{"missing required", equipmentRequest, map[string]interface{}{"assetType": "laptop"}, "justification is required"},
+		{"enum", equipmentRequest, map[string]interface{}{"assetType": "desk", "justification": "x"}, "is not one of"},
+		{"v1 name", equipmentRequest, map[string]interface{}{"assetType": "laptop", "justification": "x", "employeeID": "1"}, "employeeID is not documented"},
+		{"type", &Schema{Type: "integer"}, 1.5, "not an integer"},
+		{"items", &Schema{Type: "array", Items: &Schema{Type: "string"}}, []interface{}{"a", true}, "$[1]"},
+		{"null", &Schema{Type: "array", Items: &Schema{Type: "string"}}, nil, "is null"},
+	}
+	for _, c := range cases {
+		err := doc.Validate(c.schema, c.value)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", c.name, err)
+		case c.err != "" && (err == nil || !strings.Contains(err.Error(), c.err)):
+			t.Errorf("%s: got error %v, want one containing %q", c.name, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package openapi
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Auth says how a route is authenticated
+type Auth int
+
+const (
+	// Public routes need no credentials
+	Public Auth = iota
+	// Admin routes take an admin session token or an API key with the route's scope
+	Admin
+	// Account routes take an admin session token only, API keys are refused
+	Account
+	// Portal routes take an employee session token
+	Portal
+)
+
+type Param struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// Route documents one handler of the v2 API. Body and Response are values of the types the handler decodes and
+// encodes, Produces is set instead of Response for handlers that write something other than JSON
+type Route struct {
+	Method   string
+	Path     string
+	Handler  string
+	Summary  string
+	Tag      string
+	Auth     Auth
+	Scope    string
+	Query    []Param
+	Body     interface{}
+	Upload   string
+	Response interface{}
+	Produces []string
+	Status   int
+}
+
+// IDResponse, URLResponse and TokenResponse are the bodies handlers write as anonymous structs
+type IDResponse struct {
+	Msg string `json:"msg"`
+	ID  string `json:"id"`
+}
+
+type URLResponse struct {
+	Msg string `json:"msg"`
+	URL string `json:"url"`
+}
+
+type TokenResponse struct {
+	Token string `json:"token"`
+}
+
+func query(name, typ, description string) Param {
+	return Param{Name: name, Type: typ, Description: description}
+}
+
+// listing documents the sort, filter and cursor parameters of list
+func listing(list models.ListSpec) []Param {
+	fields := make([]string, 0, len(list.Fields))
+	for field := range list.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return []Param{
+		query("sort", "string", "field to order by, descending with a leading -, "+list.DefaultSort+" when not set: "+strings.Join(fields, ", ")),
+		query("filter", "string", "comma separated field:op:value conditions, op is eq, ne, lt, lte, gt, gte, contains or in with | separated values"),
+		query("cursor", "string", "nextCursor of the previous page, used instead of page"),
+	}
+}
+
+var (
+	paging = []Param{
+		query("limit", "integer", "page size"),
+		query("page", "integer", "zero based page number"),
+		query("pagination", "boolean", "false returns every row"),
+	}
+	assetFilters = append([]Param{
+		query("name", "string", "search by brand, model or serial number"),
+		query("assetType", "string", "comma separated asset types"),
+		query("available", "boolean", "only unassigned assets"),
+		query("assigned", "boolean", "only assigned assets"),
+		query("deleted", "boolean", "only deleted assets"),
+		query("warranty", "integer", "warranty expiring within this many days, 0 for expired"),
+		query("locationId", "string", ""),
+		query("departmentId", "string", ""),
+	}, append(listing(models.AssetList), paging...)...)
+	employeeFilters = append([]Param{
+		query("name", "string", "search by name or email"),
+		query("notAnEmployee", "boolean", "only former employees"),
+		query("locationId", "string", ""),
+		query("departmentId", "string", ""),
+	}, append(listing(models.EmployeeList), paging...)...)
+	consistencyChecks = []Param{
+		query("checks", "string", "comma separated checks to repair, every check when not set"),
+	}
+)
+
+// Routes is every route of the v2 API relative to /asset-management/v2, a test keeps it in step with the router
+var Routes = []Route{
+	{Method: http.MethodGet, Path: "/health", Handler: "Health", Summary: "Check the server is running", Tag: "docs",
+		Response: struct {
+			Status string `json:"status"`
+		}{}},
+	{Method: http.MethodGet, Path: "/health/live", Handler: "Liveness", Summary: "Liveness probe, answers while the server serves", Tag: "docs",
+		Response: struct {
+			Status string `json:"status"`
+		}{}},
+	{Method: http.MethodGet, Path: "/health/ready", Handler: "Readiness",
+		Summary: "Readiness probe, 503 unless the database answers and is migrated", Tag: "docs",
+		Response: struct {
+			Status string `json:"status"`
+		}{}},
+	{Method: http.MethodGet, Path: "/openapi.json", Handler: "GetOpenAPISpec", Summary: "This document", Tag: "docs",
+		Produces: []string{"application/json"}},
+	{Method: http.MethodGet, Path: "/docs", Handler: "GetAPIDocs", Summary: "Browsable API documentation", Tag: "docs",
+		Produces: []string{"text/html"}},
+
+	{Method: http.MethodPost, Path: "/login", Handler: "LoginUser", Summary: "Sign in with email and password", Tag: "auth",
+		Body: models.UsersLoginDetails{}, Response: TokenResponse{}},
+	{Method: http.MethodGet, Path: "/login/options", Handler: "GetLoginOptions", Summary: "Sign in methods that are enabled", Tag: "auth",
+		Response: struct {
+			SSO      bool `json:"sso"`
+			Password bool `json:"password"`
+		}{}},
+	{Method: http.MethodGet, Path: "/sso/login", Handler: "SSOLogin", Summary: "Start a single sign-on login", Tag: "auth",
+		Status: http.StatusFound},
+	{Method: http.MethodGet, Path: "/sso/callback", Handler: "SSOCallback", Summary: "Finish a single sign-on login", Tag: "auth",
+		Query: []Param{query("code", "string", ""), query("state", "string", "")}, Response: TokenResponse{}},
+
+	{Method: http.MethodPost, Path: "/employee/login", Handler: "EmployeeLogin", Summary: "Sign in to the employee portal", Tag: "portal",
+		Body: models.UsersLoginDetails{}, Response: TokenResponse{}},
+	{Method: http.MethodPut, Path: "/employee/password", Handler: "UpdateEmployeePassword", Summary: "Change the portal password", Tag: "portal", Auth: Portal,
+		Body: models.EmployeePasswordChange{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPut, Path: "/employee/log-out", Handler: "EmployeeLogout", Summary: "Sign out of the employee portal", Tag: "portal", Auth: Portal,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/employee/assets", Handler: "GetOwnAssets", Summary: "Assets held by the signed in employee", Tag: "portal", Auth: Portal,
+		Query: []Param{query("current", "boolean", "only assets still held")}, Response: []models.AssetHistory{}},
+	{Method: http.MethodPut, Path: "/employee/assets/{assetID}/acknowledge", Handler: "AcknowledgeAsset", Summary: "Acknowledge receipt of an asset", Tag: "portal", Auth: Portal,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/employee/assets/{assetID}/report", Handler: "ReportAsset", Summary: "Report a problem with an asset", Tag: "portal", Auth: Portal,
+		Body: models.AssetReport{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/employee/handover", Handler: "GetOwnHandovers", Summary: "Handovers of the signed in employee", Tag: "portal", Auth: Portal,
+		Response: []models.Handover{}},
+	{Method: http.MethodGet, Path: "/employee/handover/{handoverID}/receipt", Handler: "GetOwnHandoverReceipt", Summary: "Download a handover receipt", Tag: "portal", Auth: Portal,
+		Produces: []string{"application/pdf"}},
+	{Method: http.MethodPut, Path: "/employee/handover/{handoverID}/acknowledge", Handler: "AcknowledgeOwnHandover", Summary: "Sign a handover", Tag: "portal", Auth: Portal,
+		Body: models.HandoverAcknowledgement{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/employee/equipment-request", Handler: "CreateEquipmentRequest", Summary: "Request equipment", Tag: "portal", Auth: Portal,
+		Body: models.EquipmentRequest{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/employee/equipment-request", Handler: "GetOwnEquipmentRequests", Summary: "Equipment requests of the signed in employee", Tag: "portal", Auth: Portal,
+		Query: []Param{query("status", "string", "")}, Response: []models.EquipmentRequestDetails{}},
+	{Method: http.MethodGet, Path: "/employee/equipment-request/{requestID}", Handler: "GetOwnEquipmentRequest", Summary: "An own equipment request with its approvals", Tag: "portal", Auth: Portal,
+		Response: models.EquipmentRequestDetails{}},
+	{Method: http.MethodPut, Path: "/employee/equipment-request/{requestID}/cancel", Handler: "CancelOwnEquipmentRequest", Summary: "Cancel an own equipment request", Tag: "portal", Auth: Portal,
+		Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/register", Handler: "RegisterUser", Summary: "Register an admin user", Tag: "account", Auth: Account,
+		Body: models.RegisterUser{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/info", Handler: "GetUserDetails", Summary: "The signed in user", Tag: "account", Auth: Account,
+		Response: models.UserDetails{}},
+	{Method: http.MethodGet, Path: "/user/{userID}", Handler: "GetUserInfo", Summary: "A user", Tag: "account", Auth: Account,
+		Response: models.UserDetails{}},
+	{Method: http.MethodPut, Path: "/user/info", Handler: "UpdateUser", Summary: "Update the signed in user", Tag: "account", Auth: Account,
+		Body: models.RegisterUser{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/accessed-by", Handler: "AccessedByDetails", Summary: "Users and their sign in history, the X-Next-Cursor header holds the cursor of the next page",
+		Tag: "account", Auth: Account,
+		Query:    append(append([]Param{query("userType", "string", "")}, listing(models.AccessedByList)...), paging...),
+		Response: []models.AccessedByDetails{}},
+	{Method: http.MethodPut, Path: "/user/accessed-by", Handler: "UpdateAccessedBy", Summary: "Change the type of a user", Tag: "account", Auth: Account,
+		Query: []Param{query("userId", "string", ""), query("userType", "string", "")}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPut, Path: "/user/image", Handler: "AddProfileImage", Summary: "Upload a profile image", Tag: "account", Auth: Account,
+		Upload: "image", Response: URLResponse{}},
+	{Method: http.MethodPut, Path: "/user/log-out", Handler: "Logout", Summary: "Sign out", Tag: "account", Auth: Account,
+		Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/api-key", Handler: "CreateAPIKey", Summary: "Issue an API key, the key is only returned once", Tag: "api-keys", Auth: Account,
+		Body: models.CreateAPIKey{}, Response: struct {
+			Msg string `json:"msg"`
+			ID  string `json:"id"`
+			Key string `json:"key"`
+		}{}},
+	{Method: http.MethodGet, Path: "/user/api-key", Handler: "GetAPIKeys", Summary: "API keys", Tag: "api-keys", Auth: Account,
+		Response: []models.APIKey{}},
+	{Method: http.MethodDelete, Path: "/user/api-key/{keyID}", Handler: "RevokeAPIKey", Summary: "Revoke an API key", Tag: "api-keys", Auth: Account,
+		Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/webhook", Handler: "CreateWebhookSubscription", Summary: "Subscribe to events, the signing secret is only returned once", Tag: "webhooks", Auth: Account,
+		Body: models.CreateWebhookSubscription{}, Response: struct {
+			Msg    string `json:"msg"`
+			ID     string `json:"id"`
+			Secret string `json:"secret"`
+		}{}},
+	{Method: http.MethodGet, Path: "/user/webhook", Handler: "GetWebhookSubscriptions", Summary: "Webhook subscriptions", Tag: "webhooks", Auth: Account,
+		Response: []models.WebhookSubscription{}},
+	{Method: http.MethodPut, Path: "/user/webhook/{subscriptionID}", Handler: "UpdateWebhookSubscription", Summary: "Update a webhook subscription", Tag: "webhooks", Auth: Account,
+		Body: models.UpdateWebhookSubscription{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodDelete, Path: "/user/webhook/{subscriptionID}", Handler: "DeleteWebhookSubscription", Summary: "Delete a webhook subscription", Tag: "webhooks", Auth: Account,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/webhook/deliveries", Handler: "GetWebhookDeliveries", Summary: "Webhook deliveries, newest first", Tag: "webhooks", Auth: Account,
+		Query:    append([]Param{query("subscriptionId", "string", ""), query("status", "string", "pending, delivered or dead"), query("eventType", "string", "")}, paging...),
+		Response: []models.WebhookDelivery{}},
+	{Method: http.MethodGet, Path: "/user/webhook/deliveries/{deliveryID}", Handler: "GetWebhookDelivery", Summary: "A webhook delivery with its payload and attempts", Tag: "webhooks", Auth: Account,
+		Response: models.WebhookDelivery{}},
+	{Method: http.MethodPost, Path: "/user/webhook/deliveries/{deliveryID}/redeliver", Handler: "RedeliverWebhook", Summary: "Send a webhook delivery again", Tag: "webhooks", Auth: Account,
+		Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodGet, Path: "/user/consistency", Handler: "GetInconsistencies", Summary: "Rows breaking the data consistency checks", Tag: "consistency", Auth: Account,
+		Response: []models.Inconsistency{}},
+	{Method: http.MethodPost, Path: "/user/consistency/repair/preview", Handler: "PreviewConsistencyRepair", Summary: "Preview the repair of inconsistencies", Tag: "consistency", Auth: Account,
+		Query: consistencyChecks, Response: models.ConsistencyReport{}},
+	{Method: http.MethodPost, Path: "/user/consistency/repair", Handler: "RepairConsistency", Summary: "Repair inconsistencies in one transaction", Tag: "consistency", Auth: Account,
+		Query: consistencyChecks, Response: models.ConsistencyReport{}},
+
+	{Method: http.MethodGet, Path: "/user/dashboard", Handler: "GetDashboard", Summary: "Asset counts by type", Tag: "dashboard", Auth: Admin, Scope: utils.ScopeDashboard,
+		Query: []Param{query("dashBoardFilter", "string", "total, available or assigned")}, Response: models.GetAssetQuantity{}},
+	{Method: http.MethodGet, Path: "/user/dashboard/locations", Handler: "GetLocationDashboard", Summary: "Asset counts by location", Tag: "dashboard", Auth: Admin, Scope: utils.ScopeDashboard,
+		Response: models.LocationDashboard{}},
+	{Method: http.MethodGet, Path: "/user/dashboard/departments", Handler: "GetDepartmentDashboard", Summary: "Asset counts by department", Tag: "dashboard", Auth: Admin, Scope: utils.ScopeDashboard,
+		Response: []models.DepartmentQuantity{}},
+
+	{Method: http.MethodPost, Path: "/user/employee", Handler: "CreateEmployee", Summary: "Create an employee", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.EmployeeDetails{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/employee", Handler: "GetEmployeeList", Summary: "Employees", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: employeeFilters, Response: models.TotalGetEmployee{}},
+	{Method: http.MethodPut, Path: "/user/employee", Handler: "UpdateEmployee", Summary: "Update an employee", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.EmployeeDetails{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodDelete, Path: "/user/employee/{employeeID}", Handler: "DeleteEmployee", Summary: "Delete an employee", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.Employee{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPut, Path: "/user/employee/{employeeID}/restore", Handler: "RestoreEmployee", Summary: "Restore a deleted employee", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.RestoreEmployee{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/employee/restores", Handler: "GetEmployeeRestores", Summary: "Restores of deleted employees, newest first", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: append([]Param{query("employeeId", "string", "")}, paging...), Response: []models.RestoreRecord{}},
+	{Method: http.MethodGet, Path: "/user/employee/{employeeID}/info", Handler: "GetEmployeeMoreInfo", Summary: "An employee with their asset history", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Response: models.TotalGetEmployee{}},
+	{Method: http.MethodPut, Path: "/user/employee/{employeeID}/account", Handler: "CreateEmployeeAccount", Summary: "Give an employee a portal account", Tag: "employees", Auth: Account,
+		Body: models.EmployeeAccount{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/user/employee/asset", Handler: "CreateEmployeeAssetRelation", Summary: "Assign an asset to an employee", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.EmployeeAssetRelation{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/employee/asset-list", Handler: "GetAssetHistory", Summary: "Assets an employee held", Tag: "employees", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: []Param{query("employeeId", "string", "")}, Response: []models.AssetHistory{}},
+
+	{Method: http.MethodPost, Path: "/user/employee/equipment-request", Handler: "CreateEquipmentRequestForEmployee", Summary: "Request equipment for an employee", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.EquipmentRequest{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/employee/equipment-request", Handler: "GetEquipmentRequests", Summary: "Equipment requests", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: []Param{query("employeeId", "string", ""), query("status", "string", "")}, Response: []models.EquipmentRequestDetails{}},
+	{Method: http.MethodGet, Path: "/user/employee/equipment-request/approval-step", Handler: "GetApprovalSteps", Summary: "Approval chain for equipment requests", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Response: []models.ApprovalStep{}},
+	{Method: http.MethodPost, Path: "/user/employee/equipment-request/approval-step", Handler: "CreateApprovalStep", Summary: "Add an approval step", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.ApprovalStep{}, Response: IDResponse{}},
+	{Method: http.MethodDelete, Path: "/user/employee/equipment-request/approval-step/{stepID}", Handler: "DeleteApprovalStep", Summary: "Remove an approval step", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/employee/equipment-request/{requestID}", Handler: "GetEquipmentRequest", Summary: "An equipment request with its approvals", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Response: models.EquipmentRequestDetails{}},
+	{Method: http.MethodPut, Path: "/user/employee/equipment-request/{requestID}/decision", Handler: "DecideEquipmentRequest", Summary: "Approve or reject the current step of a request", Tag: "equipment-requests", Auth: Admin, Scope: utils.ScopeEmployees,
+		Body: models.EquipmentRequestDecision{}, Response: struct {
+			Msg    string `json:"msg"`
+			Status string `json:"status"`
+		}{}},
+
+	{Method: http.MethodPost, Path: "/user/employee/sync/token", Handler: "CreateProvisioningToken", Summary: "Issue a SCIM provisioning token, the token is only returned once", Tag: "hr-sync", Auth: Account,
+		Body: models.ProvisioningToken{}, Response: struct {
+			Msg   string `json:"msg"`
+			ID    string `json:"id"`
+			Token string `json:"token"`
+		}{}},
+	{Method: http.MethodGet, Path: "/user/employee/sync/token", Handler: "GetProvisioningTokens", Summary: "SCIM provisioning tokens", Tag: "hr-sync", Auth: Account,
+		Response: []models.ProvisioningToken{}},
+	{Method: http.MethodDelete, Path: "/user/employee/sync/token/{tokenID}", Handler: "RevokeProvisioningToken", Summary: "Revoke a SCIM provisioning token", Tag: "hr-sync", Auth: Account,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/user/employee/sync/csv/preview", Handler: "PreviewCSVSync", Summary: "Preview the changes of an HR export", Tag: "hr-sync", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: []Param{query("deactivateMissing", "boolean", "false keeps employees missing from the file")}, Upload: "file", Response: models.SyncRun{}},
+	{Method: http.MethodPost, Path: "/user/employee/sync/csv", Handler: "ApplyCSVSync", Summary: "Apply an HR export", Tag: "hr-sync", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: []Param{query("deactivateMissing", "boolean", "false keeps employees missing from the file")}, Upload: "file", Response: models.SyncRun{}},
+	{Method: http.MethodGet, Path: "/user/employee/sync/runs", Handler: "GetSyncRuns", Summary: "HR sync runs, newest first", Tag: "hr-sync", Auth: Admin, Scope: utils.ScopeEmployees,
+		Query: append([]Param{query("source", "string", "scim or csv")}, paging...), Response: []models.SyncRun{}},
+	{Method: http.MethodGet, Path: "/user/employee/sync/runs/{runID}", Handler: "GetSyncRun", Summary: "An HR sync run with its changes", Tag: "hr-sync", Auth: Admin, Scope: utils.ScopeEmployees,
+		Response: models.SyncRun{}},
+
+	{Method: http.MethodPost, Path: "/user/asset", Handler: "CreateAsset", Summary: "Create an asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.CreateAsset{}, Response: struct {
+			Msg      string `json:"msg"`
+			AssetTag string `json:"assetTag"`
+		}{}},
+	{Method: http.MethodGet, Path: "/user/asset", Handler: "GetAssetList", Summary: "Assets", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: assetFilters, Response: models.TotalGetAsset{}},
+	{Method: http.MethodPut, Path: "/user/asset", Handler: "UpdateAsset", Summary: "Update an asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.UpdateAssetSpecification{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodDelete, Path: "/user/asset", Handler: "DeleteAsset", Summary: "Delete an unassigned asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.Asset{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPut, Path: "/user/asset/restore", Handler: "RestoreAsset", Summary: "Restore a deleted asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.RestoreAsset{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/asset/restores", Handler: "GetAssetRestores", Summary: "Restores of deleted assets, newest first", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: append([]Param{query("assetId", "string", "")}, paging...), Response: []models.RestoreRecord{}},
+	{Method: http.MethodGet, Path: "/user/asset/specifications", Handler: "GetAssetSpec", Summary: "An asset with its specification and history", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("assetId", "string", ""), query("assetType", "string", "")}, Response: []models.CreateAsset{}},
+	{Method: http.MethodGet, Path: "/user/asset/lookup", Handler: "LookupAsset", Summary: "Find an asset by a scanned tag or serial number", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{{Name: "code", Type: "string", Required: true}}, Response: []models.CreateAsset{}},
+	{Method: http.MethodGet, Path: "/user/asset/labels", Handler: "GetAssetLabels", Summary: "Print asset labels", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query:    []Param{query("assetIds", "string", "comma separated, every asset when left out"), query("format", "string", "pdf or zpl")},
+		Produces: []string{"application/pdf", "application/zpl"}},
+	{Method: http.MethodPost, Path: "/user/asset/reassign", Handler: "ReassignAsset", Summary: "Move an asset to another employee", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.ReassignAsset{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/asset/brand", Handler: "AvailableAssets", Summary: "Assets available for assignment", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("assetType", "string", ""), query("brand", "string", ""), query("model", "string", ""),
+			query("from", "string", "YYYY-MM-DD"), query("to", "string", "YYYY-MM-DD")},
+		Response: []models.AssignAssetDetails{}},
+	{Method: http.MethodGet, Path: "/user/asset/employee", Handler: "EmployeeHistory", Summary: "Employees who held an asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("assetId", "string", "")}, Response: []models.EmployeeHistory{}},
+	{Method: http.MethodPut, Path: "/user/asset/warranty", Handler: "UpdateWarranty", Summary: "Update the warranty of an asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.WarrantyDetails{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPut, Path: "/user/asset/retrieve-asset", Handler: "RetrieveAsset", Summary: "Take an asset back from an employee", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.AssetRetrievalDetails{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/user/asset/transfer", Handler: "TransferAsset", Summary: "Move an asset to another location", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.AssetTransfer{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/asset/transfers", Handler: "AssetTransfers", Summary: "Location history of an asset", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("assetId", "string", "")}, Response: []models.AssetTransferHistory{}},
+	{Method: http.MethodGet, Path: "/user/asset/reports", Handler: "GetAssetReports", Summary: "Problems reported by employees", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("status", "string", ""), query("employeeId", "string", "")}, Response: []models.AssetReportDetails{}},
+	{Method: http.MethodPut, Path: "/user/asset/reports", Handler: "ResolveAssetReport", Summary: "Resolve a reported problem", Tag: "assets", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.ResolveAssetReport{}, Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/asset/reservation", Handler: "CreateReservation", Summary: "Reserve an asset for a period", Tag: "reservations", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.Reservation{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/asset/reservation", Handler: "GetReservations", Summary: "Reservations", Tag: "reservations", Auth: Admin, Scope: utils.ScopeAssets,
+		Query:    []Param{query("assetId", "string", ""), query("status", "string", ""), query("from", "string", "YYYY-MM-DD"), query("to", "string", "YYYY-MM-DD")},
+		Response: []models.ReservationDetails{}},
+	{Method: http.MethodGet, Path: "/user/asset/reservation/calendar.ics", Handler: "GetReservationCalendar", Summary: "Reservations as an iCalendar feed", Tag: "reservations", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("from", "string", "YYYY-MM-DD"), query("to", "string", "YYYY-MM-DD")}, Produces: []string{"text/calendar"}},
+	{Method: http.MethodPut, Path: "/user/asset/reservation/{reservationID}/cancel", Handler: "CancelReservation", Summary: "Cancel a reservation", Tag: "reservations", Auth: Admin, Scope: utils.ScopeAssets,
+		Response: utils.ResponseMsg{}},
+	{Method: http.MethodPost, Path: "/user/asset/reservation/{reservationID}/convert", Handler: "ConvertReservation", Summary: "Turn a reservation into an assignment", Tag: "reservations", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.ConvertReservation{}, Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/asset/handover", Handler: "CreateHandover", Summary: "Record the handover of an asset", Tag: "handovers", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.CreateHandover{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/asset/handover", Handler: "GetHandovers", Summary: "Handovers", Tag: "handovers", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("assetId", "string", ""), query("employeeId", "string", "")}, Response: []models.Handover{}},
+	{Method: http.MethodPost, Path: "/user/asset/handover/{handoverID}/photo", Handler: "AddHandoverPhoto", Summary: "Attach a condition photo to a handover", Tag: "handovers", Auth: Admin, Scope: utils.ScopeAssets,
+		Upload: "image", Response: URLResponse{}},
+	{Method: http.MethodPut, Path: "/user/asset/handover/{handoverID}/acknowledge", Handler: "AcknowledgeHandover", Summary: "Sign a handover on behalf of the employee", Tag: "handovers", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.HandoverAcknowledgement{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodGet, Path: "/user/asset/handover/{handoverID}/receipt", Handler: "GetHandoverReceipt", Summary: "Download a handover receipt", Tag: "handovers", Auth: Admin, Scope: utils.ScopeAssets,
+		Produces: []string{"application/pdf"}},
+
+	{Method: http.MethodGet, Path: "/user/asset/loan/overdue", Handler: "GetOverdueLoans", Summary: "Loans past their due date", Tag: "loans", Auth: Admin, Scope: utils.ScopeAssets,
+		Response: []models.Loan{}},
+	{Method: http.MethodGet, Path: "/user/asset/loan/stats", Handler: "GetLoanStats", Summary: "Loan statistics per employee", Tag: "loans", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("employeeId", "string", "")}, Response: []models.LoanStats{}},
+	{Method: http.MethodPost, Path: "/user/asset/loan/extension", Handler: "RequestLoanExtension", Summary: "Ask for a later due date", Tag: "loans", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.LoanExtensionRequest{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/asset/loan/extension", Handler: "GetLoanExtensions", Summary: "Loan extension requests", Tag: "loans", Auth: Admin, Scope: utils.ScopeAssets,
+		Query: []Param{query("status", "string", "")}, Response: []models.LoanExtension{}},
+	{Method: http.MethodPut, Path: "/user/asset/loan/extension", Handler: "DecideLoanExtension", Summary: "Approve or reject a loan extension", Tag: "loans", Auth: Admin, Scope: utils.ScopeAssets,
+		Body: models.LoanExtensionDecision{}, Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/location", Handler: "CreateLocation", Summary: "Create a location", Tag: "locations", Auth: Admin, Scope: utils.ScopeLocations,
+		Body: models.LocationDetails{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/location", Handler: "GetLocations", Summary: "Locations", Tag: "locations", Auth: Admin, Scope: utils.ScopeLocations,
+		Query: []Param{query("type", "string", "")}, Response: []models.Location{}},
+	{Method: http.MethodPut, Path: "/user/location", Handler: "UpdateLocation", Summary: "Update a location", Tag: "locations", Auth: Admin, Scope: utils.ScopeLocations,
+		Body: models.LocationDetails{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodDelete, Path: "/user/location/{locationID}", Handler: "DeleteLocation", Summary: "Delete an empty location", Tag: "locations", Auth: Admin, Scope: utils.ScopeLocations,
+		Response: utils.ResponseMsg{}},
+
+	{Method: http.MethodPost, Path: "/user/department", Handler: "CreateDepartment", Summary: "Create a department", Tag: "departments", Auth: Admin, Scope: utils.ScopeDepartments,
+		Body: models.Department{}, Response: IDResponse{}},
+	{Method: http.MethodGet, Path: "/user/department", Handler: "GetDepartments", Summary: "Departments", Tag: "departments", Auth: Admin, Scope: utils.ScopeDepartments,
+		Response: []models.DepartmentDetails{}},
+	{Method: http.MethodPut, Path: "/user/department", Handler: "UpdateDepartment", Summary: "Update a department", Tag: "departments", Auth: Admin, Scope: utils.ScopeDepartments,
+		Body: models.Department{}, Response: utils.ResponseMsg{}},
+	{Method: http.MethodDelete, Path: "/user/department/{departmentID}", Handler: "DeleteDepartment", Summary: "Delete an empty department", Tag: "departments", Auth: Admin, Scope: utils.ScopeDepartments,
+		Response: utils.ResponseMsg{}},
+}
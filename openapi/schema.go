@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx/types"
+	"github.com/lib/pq"
+	"github.com/volatiletech/null"
+)
+
+// scalars are the library types with their own JSON encoding
+var scalars = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(null.String{}):     {Type: "string", Nullable: true},
+	reflect.TypeOf(null.Time{}):       {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(null.Int{}):        {Type: "integer", Nullable: true},
+	reflect.TypeOf(null.Int64{}):      {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(null.Bool{}):       {Type: "boolean", Nullable: true},
+	reflect.TypeOf(null.Float64{}):    {Type: "number", Nullable: true},
+	reflect.TypeOf(pq.StringArray{}):  {Type: "array", Items: &Schema{Type: "string"}, Nullable: true},
+	reflect.TypeOf(types.JSONText{}):  {},
+	reflect.TypeOf(json.RawMessage{}): {},
+}
+
+// enums lists the values of named string types
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(apperr.Code("")): apperr.Codes(),
+	reflect.TypeOf(models.AssetType("")): {
+		string(models.Laptop), string(models.Pendrive), string(models.Harddisk),
+		string(models.Mouse), string(models.Mobile), string(models.Sim),
+	},
+}
+
+// schemaBuilder turns Go types into schemas the way encoding/json and the v2 naming see them. Named structs become
+// components, two types of the same name in different packages are refused rather than silently merged
+type schemaBuilder struct {
+	components map[string]*Schema
+	types      map[string]reflect.Type
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: make(map[string]*Schema),
+		types:      make(map[string]reflect.Type),
+	}
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	if scalar, ok := scalars[t]; ok {
+		if scalar.Items != nil {
+			items := *scalar.Items
+			scalar.Items = &items
+		}
+		return &scalar
+	}
+	if values, ok := enums[t]; ok {
+		return &Schema{Type: "string", Enum: values}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem := b.schema(t.Elem())
+		if elem.Ref != "" {
+			return &Schema{AllOf: []*Schema{elem}, Nullable: true}
+		}
+		elem.Nullable = true
+		return elem
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		// nil slices, such as the result of a select without rows, are encoded as null
+		return &Schema{Type: "array", Items: b.schema(t.Elem()), Nullable: t.Kind() == reflect.Slice}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		return b.component(t)
+	}
+	panic(fmt.Sprintf("openapi: no schema for %s", t))
+}
+
+func (b *schemaBuilder) component(t reflect.Type) *Schema {
+	name := t.Name()
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if known, ok := b.types[name]; ok {
+		if known != t {
+			panic(fmt.Sprintf("openapi: %s and %s share the schema name %s", known, t, name))
+		}
+		return ref
+	}
+	b.types[name] = t
+	// registered before the fields are built so self references resolve
+	b.components[name] = &Schema{}
+	*b.components[name] = *b.object(t)
+	return ref
+}
+
+func (b *schemaBuilder) object(t reflect.Type) *Schema {
+	object := &Schema{Type: "object", Properties: make(map[string]*Schema), Closed: true}
+	b.fields(t, object)
+	sort.Strings(object.Required)
+	return object
+}
+
+func (b *schemaBuilder) fields(t reflect.Type, object *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.fields(field.Type, object)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = utils.V2JSONName(name)
+
+		property := b.schema(field.Type)
+		if validation := field.Tag.Get("validate"); validation != "" && applyValidation(property, validation) {
+			object.Required = append(object.Required, name)
+		}
+		object.Properties[name] = property
+	}
+}
+
+// applyValidation carries the validator tags the API documents over to the schema and reports whether the field is
+// required. Rules after dive apply to the items of a list
+func applyValidation(property *Schema, validation string) bool {
+	required := false
+	target := property
+	for _, rule := range strings.Split(validation, ",") {
+		switch {
+		case rule == "dive":
+			if property.Items == nil {
+				return required
+			}
+			target = property.Items
+		case rule == "required" && target == property:
+			required = true
+		case strings.HasPrefix(rule, "oneof="):
+			target.Enum = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+		case rule == "email":
+			target.Format = "email"
+		case rule == "url":
+			target.Format = "uri"
+		}
+	}
+	return required
+}
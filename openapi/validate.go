@@ -0,0 +1,247 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Find returns the operation serving a request path, with or without the base path. Like the router it prefers
+// literal segments over parameters, so /user/info is not taken for /user/{userId}
+func (doc *Document) Find(method, path string) (*Operation, error) {
+	path = strings.TrimPrefix(path, BasePath)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var found *Operation
+	best := -1
+	for template, item := range doc.Paths {
+		literals, ok := matchPath(strings.Split(strings.Trim(template, "/"), "/"), segments)
+		if !ok || literals <= best {
+			continue
+		}
+		if op := (*item)[strings.ToLower(method)]; op != nil {
+			found, best = op, literals
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s %s is not documented", method, path)
+	}
+	return found, nil
+}
+
+// matchPath reports whether segments fit a path template and how many of them matched literally
+func matchPath(template, segments []string) (int, bool) {
+	if len(template) != len(segments) {
+		return 0, false
+	}
+	literals := 0
+	for i := range template {
+		switch {
+		case strings.HasPrefix(template[i], "{"):
+		case template[i] == segments[i]:
+			literals++
+		default:
+			return 0, false
+		}
+	}
+	return literals, true
+}
+
+// ValidateRequest checks the query parameters and body of a request against its operation. The body is read and put
+// back so the request can still be served
+func (doc *Document) ValidateRequest(r *http.Request) error {
+	op, err := doc.Find(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+	query := r.URL.Query()
+	for _, param := range op.Parameters {
+		if param.In == "query" && param.Required && query.Get(param.Name) == "" {
+			return fmt.Errorf("%s: query parameter %s is required", op.OperationID, param.Name)
+		}
+	}
+	for key := range query {
+		if !hasQueryParam(op, key) {
+			return fmt.Errorf("%s: query parameter %s is not documented", op.OperationID, key)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("%s: request content type %q is not documented", op.OperationID, mediaType)
+	}
+	if mediaType != "application/json" {
+		return nil
+	}
+	return doc.validateJSON(op.OperationID+" request", content.Schema, body)
+}
+
+func hasQueryParam(op *Operation, name string) bool {
+	for _, param := range op.Parameters {
+		if param.In == "query" && param.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateResponse checks a response of the operation serving method and path against the documented response for its
+// status code
+func (doc *Document) ValidateResponse(method, path string, status int, header http.Header, body []byte) error {
+	op, err := doc.Find(method, path)
+	if err != nil {
+		return err
+	}
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		response = op.Responses["default"]
+	}
+	if len(response.Content) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	content, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("%s: response content type %q is not documented for %d", op.OperationID, mediaType, status)
+	}
+	if mediaType != "application/json" || content.Schema.Type == "string" {
+		return nil
+	}
+	return doc.validateJSON(fmt.Sprintf("%s %d response", op.OperationID, status), content.Schema, body)
+}
+
+func (doc *Document) validateJSON(what string, schema *Schema, body []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	if err := doc.Validate(schema, value); err != nil {
+		return fmt.Errorf("%s: %w", what, err)
+	}
+	return nil
+}
+
+// Validate checks a value decoded by encoding/json against a schema of the document
+func (doc *Document) Validate(schema *Schema, value interface{}) error {
+	return doc.validate(schema, value, "$")
+}
+
+func (doc *Document) validate(schema *Schema, value interface{}, at string) error {
+	if schema.Ref != "" {
+		component, ok := doc.Components.Schemas[schema.RefName()]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", at, schema.Ref)
+		}
+		return doc.validate(component, value, at)
+	}
+	if value == nil {
+		if schema.Nullable || schema.Type == "" && len(schema.AllOf) == 0 {
+			return nil
+		}
+		return fmt.Errorf("%s: is null", at)
+	}
+	for _, part := range schema.AllOf {
+		if err := doc.validate(part, value, at); err != nil {
+			return err
+		}
+	}
+
+	switch schema.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a string", at, value)
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, s)
+			}
+		}
+		if len(schema.Enum) > 0 && !contains(schema.Enum, s) {
+			return fmt.Errorf("%s: %q is not one of %s", at, s, strings.Join(schema.Enum, ", "))
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("%s: %v is not a number", at, value)
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			return fmt.Errorf("%s: %v is not an integer", at, n)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %v is not a boolean", at, value)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an array", at, value)
+		}
+		for i, item := range items {
+			if err := doc.validate(schema.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: %v is not an object", at, value)
+		}
+		return doc.validateObject(schema, object, at)
+	}
+	return nil
+}
+
+func (doc *Document) validateObject(schema *Schema, object map[string]interface{}, at string) error {
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s: %s is required", at, name)
+		}
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		switch {
+		case ok:
+		case schema.AdditionalProperties != nil:
+			property = schema.AdditionalProperties
+		case schema.Closed:
+			return fmt.Errorf("%s: %s is not documented", at, key)
+		default:
+			continue
+		}
+		if err := doc.validate(property, object[key], at+"."+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,915 @@
+package repository
+
+import (
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volatiletech/null"
+)
+
+// NewMemory returns an empty store kept in memory, for tests. It keeps the rules handlers rely on, such as an asset
+// having one holder at a time or reservations blocking assignments, but not the joins with locations, departments or
+// webhooks
+func NewMemory() *Store {
+	m := &memory{
+		tags:      make(map[string]int),
+		sessions:  make(map[string]int),
+		employees: make(map[string]*memoryEmployee),
+	}
+	return &Store{
+		Assets:    memoryAssets{m},
+		Employees: memoryEmployees{m},
+		Users:     memoryUsers{m},
+		Sessions:  memorySessions{m},
+	}
+}
+
+type memory struct {
+	mu        sync.Mutex
+	assets    []*memoryAsset
+	tags      map[string]int
+	employees map[string]*memoryEmployee
+	relations []*memoryRelation
+	// reservations are the active reservations of specific assets, added with ReserveInMemory
+	reservations []memoryReservation
+	users        []*memoryUser
+	// sessions counts the open sessions of each user
+	sessions map[string]int
+}
+
+type memoryAsset struct {
+	models.CreateAsset
+	ID          string
+	IsAvailable bool
+}
+
+type memoryEmployee struct {
+	models.EmployeeDetails
+	ArchivedAt    null.Time
+	ArchiveReason null.String
+	DeletedBy     null.String
+	created       int
+	createdAt     time.Time
+}
+
+type memoryRelation struct {
+	models.EmployeeAssetRelation
+	AssignedBy      string
+	RetrievedDate   null.Time
+	RetrievalReason string
+	Archived        bool
+}
+
+type memoryReservation struct {
+	AssetID   string
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+type memoryUser struct {
+	models.UserCredentials
+	models.StatusDetails
+	models.UserDetails
+	Archived bool
+}
+
+func (m *memory) asset(id string) *memoryAsset {
+	for _, asset := range m.assets {
+		if asset.ID == id {
+			return asset
+		}
+	}
+	return nil
+}
+
+// openRelation returns the assignment of the asset that has not been retrieved yet
+func (m *memory) openRelation(assetID string) *memoryRelation {
+	for _, relation := range m.relations {
+		if relation.AssetID == assetID && !relation.RetrievedDate.Valid && !relation.Archived {
+			return relation
+		}
+	}
+	return nil
+}
+
+// activeEmployee returns the employee unless it does not exist or is deleted
+func (m *memory) activeEmployee(id string) *memoryEmployee {
+	if employee := m.employees[id]; employee != nil && !employee.ArchivedAt.Valid {
+		return employee
+	}
+	return nil
+}
+
+// reserved checks if an active reservation holds the asset on a day from the given date, or today when that is later,
+// until to, or indefinitely when to is not set
+func (m *memory) reserved(assetID string, from time.Time, to null.Time) bool {
+	today := time.Now().Truncate(24 * time.Hour)
+	if from.Before(today) {
+		from = today
+	}
+	for _, reservation := range m.reservations {
+		if reservation.AssetID == assetID && !reservation.EndDate.Before(from) &&
+			(!to.Valid || !reservation.StartDate.After(to.Time)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReserveInMemory holds an asset of the assets of a store made by NewMemory for an active reservation from start to end
+func ReserveInMemory(assets AssetRepository, assetID string, start, end time.Time) {
+	m := assets.(memoryAssets).m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reservations = append(m.reservations, memoryReservation{AssetID: assetID, StartDate: start, EndDate: end})
+}
+
+func (m *memory) user(match func(*memoryUser) bool) *memoryUser {
+	for _, user := range m.users {
+		if match(user) {
+			return user
+		}
+	}
+	return nil
+}
+
+// listPage applies the conditions, sort, cursor and page of filters to the n rows of list, value reads a field of row
+// i and id its id. It returns the rows of the page in order, how many rows meet the conditions and the next cursor
+func listPage(list models.ListSpec, filters *models.FiltersCheck, n int, value func(i int, field string) interface{},
+	id func(i int) string) ([]int, int, string, error) {
+	order := utils.SortOf(list, filters)
+	kind, ok := list.Fields[order.Field]
+	if !ok {
+		return nil, 0, "", fmt.Errorf("cannot sort %s by %s", list.Name, order.Field)
+	}
+	// position is where row i is sorted relative to a row whose sort field is key and id is keyID, negative for before
+	position := func(i int, key interface{}, keyID string) int {
+		c := compareValues(value(i, order.Field), key)
+		if c == 0 {
+			c = strings.Compare(id(i), keyID)
+		}
+		if order.Desc {
+			return -c
+		}
+		return c
+	}
+
+	rows := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if meetsConditions(filters.Conditions, func(field string) interface{} { return value(i, field) }) {
+			rows = append(rows, i)
+		}
+	}
+	sort.Slice(rows, func(a, b int) bool { return position(rows[a], value(rows[b], order.Field), id(rows[b])) < 0 })
+	total := len(rows)
+
+	paging := *filters
+	if filters.Cursor != nil && filters.Limit > 0 {
+		key, err := utils.ListValue(kind, filters.Cursor.Key)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		after := make([]int, 0, len(rows))
+		for _, i := range rows {
+			if position(i, key, filters.Cursor.ID) > 0 {
+				after = append(after, i)
+			}
+		}
+		rows = after
+		paging.Page = 0
+	}
+
+	start, end := page(&paging, len(rows))
+	next := ""
+	if end < len(rows) {
+		last := rows[end-1]
+		next = utils.NextCursor(list, filters, formatValue(value(last, order.Field)), id(last))
+	}
+	return rows[start:end], total, next, nil
+}
+
+// meetsConditions tells whether the row whose fields value reads meets every condition
+func meetsConditions(conditions []models.Condition, value func(field string) interface{}) bool {
+	for _, condition := range conditions {
+		v := value(condition.Field)
+		var met bool
+		switch condition.Op {
+		case models.OpContains:
+			met = strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(fmt.Sprint(condition.Value)))
+		case models.OpIn:
+			values, _ := condition.Value.([]string)
+			for _, in := range values {
+				met = met || v == in
+			}
+		case models.OpEq:
+			met = compareValues(v, condition.Value) == 0
+		case models.OpNe:
+			met = compareValues(v, condition.Value) != 0
+		case models.OpLt:
+			met = compareValues(v, condition.Value) < 0
+		case models.OpLte:
+			met = compareValues(v, condition.Value) <= 0
+		case models.OpGt:
+			met = compareValues(v, condition.Value) > 0
+		case models.OpGte:
+			met = compareValues(v, condition.Value) >= 0
+		}
+		if !met {
+			return false
+		}
+	}
+	return true
+}
+
+// compareValues orders two strings, int64s or times, values of other types are equal
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		b, _ := b.(string)
+		return strings.Compare(a, b)
+	case int64:
+		b, _ := b.(int64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	case time.Time:
+		b, _ := b.(time.Time)
+		switch {
+		case a.Before(b):
+			return -1
+		case a.After(b):
+			return 1
+		}
+	}
+	return 0
+}
+
+// formatValue is the cursor key of a value, read back with utils.ListValue
+func formatValue(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// assetValue is a field of models.AssetList of a listed asset
+func assetValue(asset models.GetAsset, field string) interface{} {
+	switch field {
+	case "purchaseDate":
+		return asset.PurchasedDate
+	case "warrantyExpiry":
+		return asset.WarrantyExpiryDate
+	case "brand":
+		return asset.Brand
+	case "model":
+		return asset.Model
+	case "assetType":
+		return string(asset.AssetType)
+	case "status":
+		return asset.Status
+	case "assignee":
+		return asset.AssignedTo.String
+	case "location":
+		return asset.Location.String
+	}
+	return nil
+}
+
+// employeeValue is a field of models.EmployeeList of a listed employee created at createdAt
+func employeeValue(employee models.GetEmployee, createdAt time.Time, field string) interface{} {
+	switch field {
+	case "name":
+		return employee.Name
+	case "email":
+		return employee.Email
+	case "type":
+		return employee.Type
+	case "status":
+		return employee.Status
+	case "department":
+		return employee.Department.String
+	case "manager":
+		return employee.ManagerName.String
+	case "assetQuantity":
+		return int64(employee.AssetQuantity)
+	case "createdAt":
+		return createdAt
+	}
+	return nil
+}
+
+// page applies the limit and offset of the filters to n items
+func page(filters *models.FiltersCheck, n int) (int, int) {
+	if filters.Limit <= 0 {
+		return 0, n
+	}
+	start := filters.Limit * filters.Page
+	if start > n {
+		start = n
+	}
+	end := start + filters.Limit
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+func hasAssetType(types []string, assetType models.AssetType) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == string(assetType) {
+			return true
+		}
+	}
+	return false
+}
+
+type memoryAssets struct{ m *memory }
+
+func (s memoryAssets) Create(ctx context.Context, asset *models.CreateAsset, userID string) (string, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	prefix := utils.AssetTagPrefix(asset.AssetType)
+	s.m.tags[prefix]++
+	asset.AssetTag = utils.FormatAssetTag(prefix, s.m.tags[prefix])
+
+	stored := &memoryAsset{CreateAsset: *asset, ID: uuid.NewString(), IsAvailable: true}
+	stored.Status = utils.Available
+	stored.AssetHistory = nil
+	s.m.assets = append(s.m.assets, stored)
+	return asset.AssetTag, nil
+}
+
+func (s memoryAssets) Spec(ctx context.Context, assetID, assetType string) ([]models.CreateAsset, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	asset := s.m.asset(assetID)
+	if asset == nil || string(asset.AssetType) != assetType {
+		return []models.CreateAsset{}, nil
+	}
+	return []models.CreateAsset{asset.CreateAsset}, nil
+}
+
+func (s memoryAssets) History(ctx context.Context, assetID string) ([]models.EmployeeHistory, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	history := make([]models.EmployeeHistory, 0)
+	for _, relation := range s.m.relations {
+		employee := s.m.employees[relation.EmployeeID]
+		if relation.AssetID != assetID || employee == nil || employee.ArchivedAt.Valid {
+			continue
+		}
+		history = append(history, models.EmployeeHistory{
+			ID:              employee.ID,
+			Name:            employee.Name,
+			Email:           employee.Email,
+			PhoneNo:         employee.PhoneNo,
+			AssignedDate:    relation.AssignedDate.Format(time.RFC3339),
+			AssignedBy:      relation.AssignedBy,
+			RetrievedDate:   relation.RetrievedDate,
+			RetrievalReason: relation.RetrievalReason,
+		})
+	}
+	return history, nil
+}
+
+func (s memoryAssets) Lookup(ctx context.Context, code string) (models.AssetLookup, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	var found *memoryAsset
+	for _, asset := range s.m.assets {
+		if asset.ArchivedAt.Valid {
+			continue
+		}
+		if strings.EqualFold(asset.AssetTag, code) {
+			found = asset
+			break
+		}
+		if found == nil && (asset.SerialNo == code || asset.Imei1 == code || asset.Imei2 == code) {
+			found = asset
+		}
+	}
+	if found == nil {
+		return models.AssetLookup{}, sql.ErrNoRows
+	}
+	return models.AssetLookup{ID: found.ID, AssetType: string(found.AssetType)}, nil
+}
+
+// List supports the status, asset type, brand and warranty filters, location and department filters are ignored
+func (s memoryAssets) List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetAsset, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	byStatus := filters.Available || filters.Assigned || filters.Deleted
+	assets := make([]models.GetAsset, 0)
+	for _, asset := range s.m.assets {
+		switch {
+		case byStatus && !(filters.Available && asset.Status == utils.Available ||
+			filters.Assigned && asset.Status == utils.Assigned || filters.Deleted && asset.Status == utils.Deleted):
+			continue
+		case !byStatus && asset.ArchivedAt.Valid:
+			continue
+		case !hasAssetType(filters.AssetTypes, asset.AssetType):
+			continue
+		case filters.SearchedName != "" && !strings.Contains(strings.ToLower(asset.Brand), strings.ToLower(filters.SearchedName)):
+			continue
+		case filters.Warranty > 0 && (asset.WarrantyExpiryDate.Before(time.Now()) || asset.WarrantyExpiryDate.After(time.Now().AddDate(0, filters.Warranty, 0))):
+			continue
+		case filters.Warranty == 0 && filters.IsExpired && !asset.WarrantyExpiryDate.Before(time.Now()):
+			continue
+		}
+
+		listed := models.GetAsset{
+			ID:                 asset.ID,
+			AssetTag:           asset.AssetTag,
+			Brand:              asset.Brand,
+			Model:              asset.Model,
+			SerialNo:           asset.SerialNo,
+			AssetType:          asset.AssetType,
+			PurchasedDate:      asset.PurchasedDate,
+			WarrantyStartDate:  asset.WarrantyStartDate,
+			WarrantyExpiryDate: asset.WarrantyExpiryDate,
+			Status:             asset.Status,
+		}
+		if relation := s.m.openRelation(asset.ID); relation != nil && !asset.IsAvailable {
+			listed.AssignedToID = null.StringFrom(relation.EmployeeID)
+			if employee := s.m.employees[relation.EmployeeID]; employee != nil {
+				listed.AssignedTo = null.StringFrom(employee.Name)
+			}
+		}
+		assets = append(assets, listed)
+	}
+
+	rows, total, next, err := listPage(models.AssetList, filters, len(assets),
+		func(i int, field string) interface{} { return assetValue(assets[i], field) },
+		func(i int) string { return assets[i].ID })
+	if err != nil {
+		return models.TotalGetAsset{}, err
+	}
+	paged := make([]models.GetAsset, 0, len(rows))
+	for _, i := range rows {
+		paged = append(paged, assets[i])
+	}
+	return models.TotalGetAsset{GetAsset: paged, TotalCount: total, NextCursor: next}, nil
+}
+
+func (s memoryAssets) Assign(ctx context.Context, relation *models.EmployeeAssetRelation, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	asset := s.m.asset(relation.AssetID)
+	switch {
+	case asset == nil || asset.ArchivedAt.Valid:
+		return ErrAssetNotFound
+	case !asset.IsAvailable:
+		return ErrAssetAssigned
+	case s.m.activeEmployee(relation.EmployeeID) == nil:
+		return ErrEmployeeNotFound
+	case s.m.reserved(relation.AssetID, relation.AssignedDate, relation.DueDate):
+		return ErrAssetReserved
+	case relation.EquipmentRequestID.Valid:
+		// equipment requests are not kept in memory so none of them is approved
+		return ErrRequestNotApproved
+	}
+
+	s.m.relations = append(s.m.relations, &memoryRelation{EmployeeAssetRelation: *relation, AssignedBy: userID})
+	asset.IsAvailable = false
+	asset.Status = utils.Assigned
+	return nil
+}
+
+func (s memoryAssets) Reassign(ctx context.Context, reassign *models.ReassignAsset, userID string) (string, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	asset := s.m.asset(reassign.AssetID)
+	if asset == nil || asset.ArchivedAt.Valid {
+		return "", ErrAssetNotFound
+	}
+	if s.m.activeEmployee(reassign.EmployeeID) == nil {
+		return "", ErrEmployeeNotFound
+	}
+	assignedDate, err := time.Parse(time.RFC3339, reassign.AssignedDate)
+	if err != nil {
+		return "", err
+	}
+	if s.m.reserved(reassign.AssetID, assignedDate, reassign.DueDate) {
+		return "", ErrAssetReserved
+	}
+
+	var previousHolder string
+	if relation := s.m.openRelation(reassign.AssetID); relation != nil {
+		previousHolder = relation.EmployeeID
+		relation.RetrievedDate = null.TimeFrom(reassign.RetrievedDate)
+		relation.RetrievalReason = reassign.RetrievalReason
+		relation.Archived = true
+	}
+	s.m.relations = append(s.m.relations, &memoryRelation{
+		EmployeeAssetRelation: models.EmployeeAssetRelation{
+			EmployeeID:     reassign.EmployeeID,
+			AssetID:        reassign.AssetID,
+			AssignedDate:   assignedDate,
+			AssignmentType: reassign.AssignmentType,
+			DueDate:        reassign.DueDate,
+		},
+		AssignedBy: userID,
+	})
+	asset.IsAvailable = false
+	asset.Status = utils.Assigned
+	return previousHolder, nil
+}
+
+func (s memoryAssets) Retrieve(ctx context.Context, retrieval models.AssetRetrievalDetails, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, relation := range s.m.relations {
+		if relation.AssetID == retrieval.AssetID && relation.EmployeeID == retrieval.EmployeeID && !relation.RetrievedDate.Valid {
+			relation.RetrievedDate = null.TimeFrom(retrieval.RetrievedDate)
+			relation.RetrievalReason = retrieval.RetrievalReason
+		}
+	}
+	if asset := s.m.asset(retrieval.AssetID); asset != nil && !asset.ArchivedAt.Valid {
+		asset.IsAvailable = true
+		asset.Status = utils.Available
+		asset.LocationID = retrieval.LocationID
+	}
+	return nil
+}
+
+func (s memoryAssets) HolderCount(ctx context.Context, assetID string) (int, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if s.m.openRelation(assetID) != nil {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (s memoryAssets) Delete(ctx context.Context, asset models.Asset, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	stored := s.m.asset(asset.ID)
+	if stored == nil || stored.ArchivedAt.Valid {
+		return nil
+	}
+	stored.ArchivedAt = null.TimeFrom(time.Now())
+	stored.ArchiveReason = null.StringFrom(asset.DeleteReason)
+	stored.DeletedBy = null.StringFrom(userID)
+	stored.Status = utils.Deleted
+	return nil
+}
+
+// Restore reports a conflict when an asset of the same type that is not deleted has the serial number
+func (s memoryAssets) Restore(ctx context.Context, restore models.RestoreAsset, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	stored := s.m.asset(restore.ID)
+	if stored == nil {
+		return ErrAssetNotFound
+	}
+	if !stored.ArchivedAt.Valid {
+		return ErrNotDeleted
+	}
+	for _, asset := range s.m.assets {
+		if !asset.ArchivedAt.Valid && asset.AssetType == stored.AssetType && strings.EqualFold(asset.SerialNo, stored.SerialNo) {
+			return fmt.Errorf("%w: an asset that is not deleted has the serial number %s", ErrRestoreConflict, stored.SerialNo)
+		}
+	}
+	stored.ArchivedAt = null.Time{}
+	stored.ArchiveReason = null.String{}
+	stored.DeletedBy = null.String{}
+	stored.Status = utils.Available
+	stored.IsAvailable = true
+	return nil
+}
+
+type memoryEmployees struct{ m *memory }
+
+func (s memoryEmployees) Create(ctx context.Context, employee *models.EmployeeDetails) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	// like the insert, an employee whose email is taken is left as it is
+	for _, existing := range s.m.employees {
+		if existing.Email == employee.Email {
+			return nil
+		}
+	}
+	stored := &memoryEmployee{EmployeeDetails: *employee, created: len(s.m.employees), createdAt: time.Now()}
+	stored.ID = uuid.NewString()
+	stored.Status = utils.Active
+	s.m.employees[stored.ID] = stored
+	return nil
+}
+
+// List supports the status, name, employee and asset type filters, the department filter is ignored
+func (s memoryEmployees) List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetEmployee, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	statuses := make(map[string]bool)
+	if filters.Deleted {
+		statuses[utils.Deleted] = true
+	}
+	if filters.NotAnEmployee {
+		statuses[utils.NotAnEmployee] = true
+	}
+	if len(statuses) == 0 {
+		statuses[utils.Active] = true
+	}
+
+	ordered := make([]*memoryEmployee, len(s.m.employees))
+	for _, employee := range s.m.employees {
+		ordered[employee.created] = employee
+	}
+
+	employees := make([]models.GetEmployee, 0)
+	createdAt := make([]time.Time, 0)
+	for _, employee := range ordered {
+		switch {
+		case !statuses[employee.Status]:
+			continue
+		case employee.ArchivedAt.Valid && filters.EmployeeID == "" && !filters.Deleted:
+			continue
+		case filters.EmployeeID != "" && employee.ID != filters.EmployeeID:
+			continue
+		case filters.SearchedName != "" && !strings.Contains(strings.ToLower(employee.Name), strings.ToLower(filters.SearchedName)):
+			continue
+		}
+
+		quantity := 0
+		matchesType := len(filters.AssetTypes) == 0
+		for _, relation := range s.m.relations {
+			if relation.EmployeeID != employee.ID {
+				continue
+			}
+			quantity++
+			if asset := s.m.asset(relation.AssetID); asset != nil && hasAssetType(filters.AssetTypes, asset.AssetType) {
+				matchesType = true
+			}
+		}
+		if !matchesType {
+			continue
+		}
+
+		listed := models.GetEmployee{
+			ID:            employee.ID,
+			Name:          employee.Name,
+			Email:         employee.Email,
+			PhoneNo:       employee.PhoneNo,
+			Status:        employee.Status,
+			Type:          employee.Type,
+			ArchivedAt:    employee.ArchivedAt,
+			ArchiveReason: employee.ArchiveReason,
+			DeletedBy:     employee.DeletedBy,
+			AssetQuantity: quantity,
+			DepartmentID:  employee.DepartmentID,
+			ManagerID:     employee.ManagerID,
+		}
+		if manager := s.m.employees[employee.ManagerID.String]; employee.ManagerID.Valid && manager != nil {
+			listed.ManagerName = null.StringFrom(manager.Name)
+		}
+		employees = append(employees, listed)
+		createdAt = append(createdAt, employee.createdAt)
+	}
+
+	rows, total, next, err := listPage(models.EmployeeList, filters, len(employees),
+		func(i int, field string) interface{} { return employeeValue(employees[i], createdAt[i], field) },
+		func(i int) string { return employees[i].ID })
+	if err != nil {
+		return models.TotalGetEmployee{}, err
+	}
+	paged := make([]models.GetEmployee, 0, len(rows))
+	for _, i := range rows {
+		paged = append(paged, employees[i])
+	}
+	return models.TotalGetEmployee{GetEmployee: paged, TotalCount: total, NextCursor: next}, nil
+}
+
+func (s memoryEmployees) AssetHistory(ctx context.Context, employeeID string) ([]models.AssetHistory, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	history := make([]models.AssetHistory, 0)
+	for _, relation := range s.m.relations {
+		asset := s.m.asset(relation.AssetID)
+		if employeeID != "" && relation.EmployeeID != employeeID || asset == nil || asset.ArchivedAt.Valid {
+			continue
+		}
+		entry := models.AssetHistory{
+			ID:              asset.ID,
+			Brand:           asset.Brand,
+			Model:           asset.Model,
+			SerialNo:        asset.SerialNo,
+			AssetType:       asset.AssetType,
+			AssignedDate:    relation.AssignedDate,
+			RetrievalReason: relation.RetrievalReason,
+		}
+		if relation.RetrievedDate.Valid {
+			entry.RetrievedDate = &relation.RetrievedDate.Time
+		}
+		if relation.DueDate.Valid {
+			entry.DueDate = &relation.DueDate.Time
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+func (s memoryEmployees) AssignedAssetCount(ctx context.Context, employeeID string) (int, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	count := 0
+	for _, relation := range s.m.relations {
+		if relation.EmployeeID == employeeID && !relation.RetrievedDate.Valid && !relation.Archived {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s memoryEmployees) IsManagerCycle(ctx context.Context, employeeID, managerID string) (bool, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for id := managerID; id != "" && !seen[id]; {
+		if id == employeeID {
+			return true, nil
+		}
+		seen[id] = true
+		manager := s.m.employees[id]
+		if manager == nil || !manager.ManagerID.Valid {
+			break
+		}
+		id = manager.ManagerID.String
+	}
+	return false, nil
+}
+
+func (s memoryEmployees) Update(ctx context.Context, employee *models.EmployeeDetails) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	stored := s.m.employees[employee.ID]
+	if stored == nil || stored.ArchivedAt.Valid {
+		return nil
+	}
+	stored.EmployeeDetails = *employee
+	return nil
+}
+
+func (s memoryEmployees) Delete(ctx context.Context, employeeID, userID string, employee models.Employee) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	stored := s.m.employees[employeeID]
+	if stored == nil || stored.ArchivedAt.Valid {
+		return nil
+	}
+	stored.ArchivedAt = null.TimeFrom(time.Now())
+	stored.ArchiveReason = null.StringFrom(employee.ArchiveReason)
+	stored.DeletedBy = null.StringFrom(userID)
+	stored.Status = utils.Deleted
+	return nil
+}
+
+func (s memoryEmployees) Restore(ctx context.Context, employeeID string, restore models.RestoreEmployee, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	stored := s.m.employees[employeeID]
+	if stored == nil {
+		return ErrEmployeeNotFound
+	}
+	if !stored.ArchivedAt.Valid {
+		return ErrNotDeleted
+	}
+	for _, employee := range s.m.employees {
+		if !employee.ArchivedAt.Valid && strings.EqualFold(employee.Email, stored.Email) {
+			return fmt.Errorf("%w: an employee who is not deleted has the email %s", ErrRestoreConflict, stored.Email)
+		}
+	}
+	stored.ArchivedAt = null.Time{}
+	stored.ArchiveReason = null.String{}
+	stored.DeletedBy = null.String{}
+	stored.Status = utils.Active
+	return nil
+}
+
+type memoryUsers struct{ m *memory }
+
+func (s memoryUsers) Exists(ctx context.Context, email, phoneNo string) (bool, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user := s.m.user(func(u *memoryUser) bool { return u.Email == email && u.PhoneNo == phoneNo && !u.Archived })
+	return user != nil, nil
+}
+
+func (s memoryUsers) Create(ctx context.Context, name, email, hashedPassword, phoneNo string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	s.m.users = append(s.m.users, &memoryUser{
+		UserCredentials: models.UserCredentials{ID: uuid.NewString(), Password: hashedPassword, Role: utils.RoleAdmin},
+		StatusDetails:   models.StatusDetails{Type: utils.Authorized, Status: utils.Whitelisted},
+		UserDetails:     models.UserDetails{Name: name, Email: email, PhoneNo: phoneNo},
+	})
+	return nil
+}
+
+func (s memoryUsers) Credentials(ctx context.Context, email string) (models.UserCredentials, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user := s.m.user(func(u *memoryUser) bool { return u.Email == email })
+	if user == nil {
+		return models.UserCredentials{}, sql.ErrNoRows
+	}
+	return user.UserCredentials, nil
+}
+
+func (s memoryUsers) Status(ctx context.Context, email string) (models.StatusDetails, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user := s.m.user(func(u *memoryUser) bool { return u.Email == email })
+	if user == nil {
+		return models.StatusDetails{}, sql.ErrNoRows
+	}
+	return user.StatusDetails, nil
+}
+
+func (s memoryUsers) AlterStatus(ctx context.Context, userType, status string, authenticationTimes int, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if user := s.m.user(func(u *memoryUser) bool { return u.ID == userID }); user != nil {
+		user.StatusDetails = models.StatusDetails{Type: userType, Status: status, AuthenticationTimes: authenticationTimes + 1}
+	}
+	return nil
+}
+
+func (s memoryUsers) Details(ctx context.Context, userID string) (*models.UserDetails, error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	user := s.m.user(func(u *memoryUser) bool { return u.ID == userID && !u.Archived })
+	if user == nil {
+		return nil, sql.ErrNoRows
+	}
+	details := user.UserDetails
+	return &details, nil
+}
+
+func (s memoryUsers) Update(ctx context.Context, update models.RegisterUser, hashedPassword, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	if user := s.m.user(func(u *memoryUser) bool { return u.ID == userID && !u.Archived }); user != nil {
+		user.Name, user.Email, user.PhoneNo = update.Name, update.Email, update.PhoneNo
+		user.Password = hashedPassword
+	}
+	return nil
+}
+
+type memorySessions struct{ m *memory }
+
+func (s memorySessions) Create(ctx context.Context, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	s.m.sessions[userID]++
+	return nil
+}
+
+func (s memorySessions) End(ctx context.Context, userID string) error {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	delete(s.m.sessions, userID)
+	return nil
+}
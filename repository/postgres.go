@@ -0,0 +1,464 @@
+package repository
+
+import (
+	"InternalAssetManagement/database"
+	"InternalAssetManagement/database/dbhelper"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/volatiletech/null"
+)
+
+// NewPostgres returns the store backed by database.AssetManagement. The connection is looked up on every call so the
+// store can be built before the database is connected
+func NewPostgres() *Store {
+	return &Store{
+		Assets:    PostgresAssets{},
+		Employees: PostgresEmployees{},
+		Users:     PostgresUsers{},
+		Sessions:  PostgresSessions{},
+	}
+}
+
+type PostgresAssets struct{}
+
+func (PostgresAssets) Create(ctx context.Context, asset *models.CreateAsset, userID string) (string, error) {
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		assetTag, err := dbhelper.NextAssetTag(ctx, tx, utils.AssetTagPrefix(asset.AssetType))
+		if err != nil {
+			return err
+		}
+		asset.AssetTag = assetTag
+
+		assetID, err := dbhelper.CreateAsset(ctx, tx, asset, userID)
+		if err != nil {
+			return err
+		}
+
+		switch asset.AssetType {
+		case models.Laptop:
+			return dbhelper.CreateLaptopSpecification(ctx, tx, asset, assetID)
+		case models.Pendrive:
+			return dbhelper.CreatePenDriveSpecification(ctx, tx, asset, assetID)
+		case models.Harddisk:
+			return dbhelper.CreateHardDiskSpecification(ctx, tx, asset, assetID)
+		case models.Mobile:
+			return dbhelper.CreateMobileSpecification(ctx, tx, asset, assetID)
+		case models.Sim:
+			return dbhelper.CreateSimSpecification(ctx, tx, asset, assetID)
+		}
+		return nil
+	})
+	return asset.AssetTag, err
+}
+
+func (PostgresAssets) Spec(ctx context.Context, assetID, assetType string) ([]models.CreateAsset, error) {
+	return dbhelper.GetAssetSpec(ctx, assetID, assetType)
+}
+
+func (PostgresAssets) History(ctx context.Context, assetID string) ([]models.EmployeeHistory, error) {
+	return dbhelper.EmployeeHistory(ctx, assetID)
+}
+
+func (PostgresAssets) Lookup(ctx context.Context, code string) (models.AssetLookup, error) {
+	return dbhelper.LookupAsset(ctx, code)
+}
+
+func (PostgresAssets) List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetAsset, error) {
+	if filters.Available || filters.Assigned || filters.Deleted {
+		return dbhelper.GetAssetsWithFilters(ctx, filters)
+	}
+	return dbhelper.GetAssets(ctx, filters)
+}
+
+func (PostgresAssets) Assign(ctx context.Context, relation *models.EmployeeAssetRelation, userID string) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		relationID, err := AssignAsset(ctx, tx, relation, userID, "")
+		if err != nil {
+			return err
+		}
+
+		if relation.EquipmentRequestID.Valid {
+			rows, fulfilErr := dbhelper.FulfilEquipmentRequest(ctx, tx, relation.EquipmentRequestID.String, relation.EmployeeID, relationID, userID)
+			if fulfilErr != nil {
+				return fulfilErr
+			}
+			if rows == 0 {
+				return ErrRequestNotApproved
+			}
+
+			matches, matchErr := dbhelper.IsAssetOfRequestedType(ctx, tx, relation.AssetID, relation.EquipmentRequestID.String)
+			if matchErr != nil {
+				return matchErr
+			}
+			if !matches {
+				return ErrRequestTypeMismatch
+			}
+
+			return dbhelper.EnqueueNotification(ctx, tx, models.NotificationRequestStatus, models.EquipmentRequestNotification{
+				RequestID: relation.EquipmentRequestID.String,
+				Status:    utils.RequestFulfilled,
+			})
+		}
+		return nil
+	})
+}
+
+func (PostgresAssets) Reassign(ctx context.Context, reassign *models.ReassignAsset, userID string) (string, error) {
+	var previousHolder null.String
+	err := database.Tx(ctx, func(tx *sqlx.Tx) error {
+		if err := dbhelper.LockAsset(ctx, tx, reassign.AssetID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrAssetNotFound
+			}
+			return err
+		}
+		if err := checkEmployee(ctx, tx, reassign.EmployeeID); err != nil {
+			return err
+		}
+
+		reserved, err := dbhelper.IsAssetReserved(ctx, tx, reassign.AssetID, reassign.AssignedDate, reassign.DueDate, "")
+		if err != nil {
+			return err
+		}
+		if reserved {
+			return ErrAssetReserved
+		}
+
+		previousHolder, err = dbhelper.GetAssetHolder(ctx, tx, reassign.AssetID)
+		if err != nil {
+			return err
+		}
+
+		if err := dbhelper.RetrieveAssetByAssetID(ctx, tx, reassign); err != nil {
+			return err
+		}
+		if err := dbhelper.UpdateAvailableAsset(ctx, reassign.AssetID, false, utils.Assigned, tx); err != nil {
+			return err
+		}
+		if err := dbhelper.ReassignAsset(ctx, tx, reassign, userID); err != nil {
+			return err
+		}
+		if err := moveAssetToEmployee(ctx, tx, reassign.AssetID, reassign.EmployeeID, userID); err != nil {
+			return err
+		}
+
+		if err := notifyManager(ctx, tx, reassign.EmployeeID, reassign.AssetID, models.ManagerAssetReceived); err != nil {
+			return err
+		}
+		if previousHolder.Valid {
+			if err := notifyManager(ctx, tx, previousHolder.String, reassign.AssetID, models.ManagerAssetReturned); err != nil {
+				return err
+			}
+			eventErr := dbhelper.EnqueueWebhookEvent(ctx, tx, models.EventAssetRetrieved, models.AssetRetrievedEvent{
+				AssetID:         reassign.AssetID,
+				EmployeeID:      previousHolder.String,
+				RetrievedDate:   reassign.RetrievedDate,
+				RetrievalReason: reassign.RetrievalReason,
+				RetrievedBy:     userID,
+			})
+			if eventErr != nil {
+				return eventErr
+			}
+		}
+		return dbhelper.EnqueueWebhookEvent(ctx, tx, models.EventAssetAssigned, models.AssetAssignedEvent{
+			AssetID:            reassign.AssetID,
+			EmployeeID:         reassign.EmployeeID,
+			PreviousEmployeeID: previousHolder,
+			AssignedDate:       reassign.AssignedDate,
+			AssignmentType:     reassign.AssignmentType,
+			DueDate:            reassign.DueDate,
+			AssignedBy:         userID,
+		})
+	})
+	return previousHolder.String, err
+}
+
+func (PostgresAssets) Retrieve(ctx context.Context, retrieval models.AssetRetrievalDetails, userID string) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		if err := dbhelper.RetrieveAsset(ctx, retrieval, tx); err != nil {
+			return err
+		}
+		if err := dbhelper.UpdateAvailableAsset(ctx, retrieval.AssetID, true, utils.Available, tx); err != nil {
+			return err
+		}
+
+		err := dbhelper.TransferAsset(ctx, tx, &models.AssetTransfer{
+			AssetID:      retrieval.AssetID,
+			ToLocationID: retrieval.LocationID,
+			Note:         "retrieved from employee",
+		}, userID)
+		if err != nil {
+			return err
+		}
+		if err := notifyManager(ctx, tx, retrieval.EmployeeID, retrieval.AssetID, models.ManagerAssetReturned); err != nil {
+			return err
+		}
+
+		return dbhelper.EnqueueWebhookEvent(ctx, tx, models.EventAssetRetrieved, models.AssetRetrievedEvent{
+			AssetID:         retrieval.AssetID,
+			EmployeeID:      retrieval.EmployeeID,
+			RetrievedDate:   retrieval.RetrievedDate,
+			RetrievalReason: retrieval.RetrievalReason,
+			RetrievedBy:     userID,
+		})
+	})
+}
+
+func (PostgresAssets) HolderCount(ctx context.Context, assetID string) (int, error) {
+	return dbhelper.GetAssignedEmployee(ctx, models.Asset{ID: assetID})
+}
+
+func (PostgresAssets) Delete(ctx context.Context, asset models.Asset, userID string) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		var err error
+		switch asset.AssetType {
+		case models.Laptop:
+			err = dbhelper.DeleteLaptopSpec(ctx, tx, asset.ID)
+		case models.Pendrive:
+			err = dbhelper.DeletePenDriveSpec(ctx, tx, asset.ID)
+		case models.Harddisk:
+			err = dbhelper.DeleteHardDiskSpec(ctx, tx, asset.ID)
+		case models.Mobile:
+			err = dbhelper.DeleteMobileSpec(ctx, tx, asset.ID)
+		case models.Sim:
+			err = dbhelper.DeleteSimSpec(ctx, tx, asset.ID)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := dbhelper.DeleteAsset(ctx, tx, asset, userID); err != nil {
+			return err
+		}
+
+		return dbhelper.EnqueueWebhookEvent(ctx, tx, models.EventAssetDeleted, models.AssetDeletedEvent{
+			AssetID:   asset.ID,
+			AssetType: asset.AssetType,
+			DeletedBy: userID,
+		})
+	})
+}
+
+func (PostgresAssets) Restore(ctx context.Context, restore models.RestoreAsset, userID string) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		assetType, archivedAt, err := dbhelper.GetArchivedAsset(ctx, tx, restore.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrAssetNotFound
+			}
+			return err
+		}
+		if !archivedAt.Valid {
+			return ErrNotDeleted
+		}
+		conflict, err := dbhelper.GetAssetRestoreConflict(ctx, tx, restore.ID)
+		if err != nil {
+			return err
+		}
+		if conflict != "" {
+			return fmt.Errorf("%w: an asset that is not deleted has the %s", ErrRestoreConflict, conflict)
+		}
+
+		archived, err := dbhelper.RestoreAsset(ctx, tx, restore.ID, assetType)
+		if err != nil {
+			return err
+		}
+		return dbhelper.CreateRestoreRecord(ctx, tx, models.RestoreAssetEntity, restore.ID, archived, restore.RestoreReason, userID)
+	})
+}
+
+// AssignAsset creates the assignment, marks the asset assigned, moves it to the employee and enqueues the
+// asset.assigned webhook event, refusing assets reserved for the assignment period other than by the reservation
+// being converted
+func AssignAsset(ctx context.Context, tx *sqlx.Tx, relation *models.EmployeeAssetRelation, userID, reservationID string) (string, error) {
+	available, err := dbhelper.IsAssetAvailable(ctx, tx, relation.AssetID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrAssetNotFound
+		}
+		return "", err
+	}
+	if !available {
+		return "", ErrAssetAssigned
+	}
+	if err := checkEmployee(ctx, tx, relation.EmployeeID); err != nil {
+		return "", err
+	}
+
+	reserved, err := dbhelper.IsAssetReserved(ctx, tx, relation.AssetID, relation.AssignedDate.Format(time.RFC3339), relation.DueDate, reservationID)
+	if err != nil {
+		return "", err
+	}
+	if reserved {
+		return "", ErrAssetReserved
+	}
+
+	relationID, err := dbhelper.CreateEmployeeAssetRelation(ctx, *relation, userID, tx)
+	if err != nil {
+		return "", err
+	}
+
+	err = dbhelper.UpdateAvailableAsset(ctx, relation.AssetID, false, utils.Assigned, tx)
+	if err != nil {
+		return "", err
+	}
+
+	err = moveAssetToEmployee(ctx, tx, relation.AssetID, relation.EmployeeID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	err = notifyManager(ctx, tx, relation.EmployeeID, relation.AssetID, models.ManagerAssetReceived)
+	if err != nil {
+		return "", err
+	}
+
+	return relationID, dbhelper.EnqueueWebhookEvent(ctx, tx, models.EventAssetAssigned, models.AssetAssignedEvent{
+		AssetID:        relation.AssetID,
+		EmployeeID:     relation.EmployeeID,
+		AssignedDate:   relation.AssignedDate.Format(time.RFC3339),
+		AssignmentType: relation.AssignmentType,
+		DueDate:        relation.DueDate,
+		AssignedBy:     userID,
+	})
+}
+
+// checkEmployee fails with ErrEmployeeNotFound unless the employee exists and is not deleted
+func checkEmployee(ctx context.Context, tx *sqlx.Tx, employeeID string) error {
+	active, err := dbhelper.IsEmployeeActive(ctx, tx, employeeID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrEmployeeNotFound
+	}
+	return nil
+}
+
+// notifyManager queues the email that tells the manager of the employee about the asset they received or returned
+func notifyManager(ctx context.Context, tx *sqlx.Tx, employeeID, assetID, event string) error {
+	return dbhelper.EnqueueNotification(ctx, tx, models.NotificationManager, models.ManagerNotification{
+		EmployeeID: employeeID,
+		AssetID:    assetID,
+		Event:      event,
+	})
+}
+
+// moveAssetToEmployee records that an assigned asset is now with the employee
+func moveAssetToEmployee(ctx context.Context, tx *sqlx.Tx, assetID, employeeID, userID string) error {
+	locationID, err := dbhelper.EmployeeLocation(ctx, tx, employeeID, userID)
+	if err != nil {
+		return err
+	}
+
+	return dbhelper.TransferAsset(ctx, tx, &models.AssetTransfer{
+		AssetID:      assetID,
+		ToLocationID: null.StringFrom(locationID),
+		Note:         "assigned to employee",
+	}, userID)
+}
+
+type PostgresEmployees struct{}
+
+func (PostgresEmployees) Create(ctx context.Context, employee *models.EmployeeDetails) error {
+	return dbhelper.CreateEmployee(ctx, employee)
+}
+
+func (PostgresEmployees) List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetEmployee, error) {
+	return dbhelper.GetEmployee(ctx, filters)
+}
+
+func (PostgresEmployees) AssetHistory(ctx context.Context, employeeID string) ([]models.AssetHistory, error) {
+	return dbhelper.GetAssetHistory(ctx, employeeID)
+}
+
+func (PostgresEmployees) AssignedAssetCount(ctx context.Context, employeeID string) (int, error) {
+	return dbhelper.GetAssignedAsset(ctx, employeeID)
+}
+
+func (PostgresEmployees) IsManagerCycle(ctx context.Context, employeeID, managerID string) (bool, error) {
+	return dbhelper.IsManagerCycle(ctx, database.AssetManagement, employeeID, managerID)
+}
+
+func (PostgresEmployees) Update(ctx context.Context, employee *models.EmployeeDetails) error {
+	return dbhelper.UpdateEmployee(ctx, employee)
+}
+
+func (PostgresEmployees) Delete(ctx context.Context, employeeID, userID string, employee models.Employee) error {
+	return dbhelper.DeleteEmployee(ctx, employeeID, userID, employee)
+}
+
+func (PostgresEmployees) Restore(ctx context.Context, employeeID string, restore models.RestoreEmployee, userID string) error {
+	return database.Tx(ctx, func(tx *sqlx.Tx) error {
+		email, archivedAt, err := dbhelper.GetArchivedEmployee(ctx, tx, employeeID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrEmployeeNotFound
+			}
+			return err
+		}
+		if !archivedAt.Valid {
+			return ErrNotDeleted
+		}
+		taken, err := dbhelper.IsEmployeeEmailTaken(ctx, tx, email, employeeID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: an employee who is not deleted has the email %s", ErrRestoreConflict, email)
+		}
+
+		archived, err := dbhelper.RestoreEmployee(ctx, tx, employeeID)
+		if err != nil {
+			return err
+		}
+		return dbhelper.CreateRestoreRecord(ctx, tx, models.RestoreEmployeeEntity, employeeID, archived, restore.RestoreReason, userID)
+	})
+}
+
+type PostgresUsers struct{}
+
+func (PostgresUsers) Exists(ctx context.Context, email, phoneNo string) (bool, error) {
+	return dbhelper.IsUserExist(ctx, email, phoneNo)
+}
+
+func (PostgresUsers) Create(ctx context.Context, name, email, hashedPassword, phoneNo string) error {
+	return dbhelper.CreateUser(ctx, name, email, hashedPassword, phoneNo)
+}
+
+func (PostgresUsers) Credentials(ctx context.Context, email string) (models.UserCredentials, error) {
+	return dbhelper.FetchPasswordAndID(ctx, email)
+}
+
+func (PostgresUsers) Status(ctx context.Context, email string) (models.StatusDetails, error) {
+	return dbhelper.GetStatusDetails(ctx, email)
+}
+
+func (PostgresUsers) AlterStatus(ctx context.Context, userType, status string, authenticationTimes int, userID string) error {
+	return dbhelper.AlterStatusDetails(ctx, userType, status, authenticationTimes, userID)
+}
+
+func (PostgresUsers) Details(ctx context.Context, userID string) (*models.UserDetails, error) {
+	return dbhelper.GetUserDetails(ctx, userID)
+}
+
+func (PostgresUsers) Update(ctx context.Context, user models.RegisterUser, hashedPassword, userID string) error {
+	return dbhelper.UpdateUser(ctx, user, hashedPassword, userID)
+}
+
+type PostgresSessions struct{}
+
+func (PostgresSessions) Create(ctx context.Context, userID string) error {
+	return dbhelper.CreateSession(ctx, &models.Claims{ID: userID})
+}
+
+func (PostgresSessions) End(ctx context.Context, userID string) error {
+	return dbhelper.Logout(ctx, userID)
+}
@@ -0,0 +1,90 @@
+// Package repository is the storage handlers read and write through. Postgres serves the application, Memory serves
+// handler tests without a database; both return the errors below for the same conditions.
+package repository
+
+import (
+	"InternalAssetManagement/models"
+	"context"
+	"errors"
+)
+
+var (
+	ErrAssetNotFound       = errors.New("asset not found")
+	ErrAssetAssigned       = errors.New("asset is already assigned")
+	ErrAssetReserved       = errors.New("asset is reserved")
+	ErrEmployeeNotFound    = errors.New("employee not found")
+	ErrRequestNotApproved  = errors.New("equipment request is not approved")
+	ErrRequestTypeMismatch = errors.New("asset is not of the requested type")
+	ErrNotDeleted          = errors.New("not deleted")
+	// ErrRestoreConflict is wrapped with the identifier a record that is not deleted has taken since the deletion
+	ErrRestoreConflict = errors.New("cannot restore")
+)
+
+// Store groups the repositories a handler service needs
+type Store struct {
+	Assets    AssetRepository
+	Employees EmployeeRepository
+	Users     UserRepository
+	Sessions  SessionRepository
+}
+
+type AssetRepository interface {
+	// Create stores the asset with the specification of its type and returns the asset tag allocated to it
+	Create(ctx context.Context, asset *models.CreateAsset, userID string) (string, error)
+	// Spec returns the asset with the specification of assetType, empty when there is no such asset
+	Spec(ctx context.Context, assetID, assetType string) ([]models.CreateAsset, error)
+	// History lists the employees who held the asset
+	History(ctx context.Context, assetID string) ([]models.EmployeeHistory, error)
+	// Lookup finds an asset by tag, serial number or IMEI, sql.ErrNoRows when nothing matches
+	Lookup(ctx context.Context, code string) (models.AssetLookup, error)
+	List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetAsset, error)
+	// Assign gives an available asset to an employee and fulfils the equipment request the assignment is linked to.
+	// It fails with ErrAssetNotFound, ErrAssetAssigned, ErrEmployeeNotFound, ErrAssetReserved, ErrRequestNotApproved
+	// or ErrRequestTypeMismatch when the asset is not of the requested type
+	Assign(ctx context.Context, relation *models.EmployeeAssetRelation, userID string) error
+	// Reassign retrieves the asset from whoever holds it and gives it to another employee, returning the previous holder.
+	// It fails with ErrAssetNotFound, ErrEmployeeNotFound or ErrAssetReserved
+	Reassign(ctx context.Context, reassign *models.ReassignAsset, userID string) (string, error)
+	// Retrieve takes the asset back from the employee and makes it available again
+	Retrieve(ctx context.Context, retrieval models.AssetRetrievalDetails, userID string) error
+	// HolderCount counts the open assignments of the asset
+	HolderCount(ctx context.Context, assetID string) (int, error)
+	Delete(ctx context.Context, asset models.Asset, userID string) error
+	// Restore reverses the deletion of the asset and of its specification and records it in the restore history. It
+	// fails with ErrAssetNotFound, ErrNotDeleted or ErrRestoreConflict
+	Restore(ctx context.Context, restore models.RestoreAsset, userID string) error
+}
+
+type EmployeeRepository interface {
+	Create(ctx context.Context, employee *models.EmployeeDetails) error
+	List(ctx context.Context, filters *models.FiltersCheck) (models.TotalGetEmployee, error)
+	// AssetHistory lists the assets assigned to the employee, or to everyone when employeeID is empty
+	AssetHistory(ctx context.Context, employeeID string) ([]models.AssetHistory, error)
+	// AssignedAssetCount counts the assets the employee holds
+	AssignedAssetCount(ctx context.Context, employeeID string) (int, error)
+	// IsManagerCycle checks if making managerID the manager of employeeID would make the employee manage themselves
+	IsManagerCycle(ctx context.Context, employeeID, managerID string) (bool, error)
+	Update(ctx context.Context, employee *models.EmployeeDetails) error
+	Delete(ctx context.Context, employeeID, userID string, employee models.Employee) error
+	// Restore reverses the deletion of the employee and records it in the restore history. It fails with
+	// ErrEmployeeNotFound, ErrNotDeleted or ErrRestoreConflict
+	Restore(ctx context.Context, employeeID string, restore models.RestoreEmployee, userID string) error
+}
+
+type UserRepository interface {
+	Exists(ctx context.Context, email, phoneNo string) (bool, error)
+	Create(ctx context.Context, name, email, hashedPassword, phoneNo string) error
+	// Credentials returns the password hash, id and role of the user, sql.ErrNoRows for an unknown email
+	Credentials(ctx context.Context, email string) (models.UserCredentials, error)
+	Status(ctx context.Context, email string) (models.StatusDetails, error)
+	// AlterStatus records one more authentication attempt along with the new type and status of the user
+	AlterStatus(ctx context.Context, userType, status string, authenticationTimes int, userID string) error
+	Details(ctx context.Context, userID string) (*models.UserDetails, error)
+	Update(ctx context.Context, user models.RegisterUser, hashedPassword, userID string) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, userID string) error
+	// End closes every open session of the user
+	End(ctx context.Context, userID string) error
+}
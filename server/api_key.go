@@ -0,0 +1,15 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func apiKeyRoutes(r chi.Router) {
+	r.Group(func(apiKey chi.Router) {
+		apiKey.Post("/", handler.CreateAPIKey)
+		apiKey.Get("/", handler.GetAPIKeys)
+		apiKey.Delete("/{keyID}", handler.RevokeAPIKey)
+	})
+}
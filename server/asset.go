@@ -6,18 +6,47 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func assetRoutes(r chi.Router) {
-	r.Group(func(asset chi.Router) {
-		asset.Post("/", handler.CreateAsset)
-		asset.Get("/specifications", handler.GetAssetSpec)
-		asset.Get("/", handler.GetAssetList)
-		asset.Put("/", handler.UpdateAsset)
-		asset.Post("/reassign", handler.ReassignAsset)
-		asset.Get("/brand", handler.AvailableAssets)
-
-		asset.Get("/employee", handler.EmployeeHistory)
-		asset.Put("/warranty", handler.UpdateWarranty)
-		asset.Put("/retrieve-asset", handler.RetrieveAsset)
-		asset.Delete("/", handler.DeleteAsset)
-	})
+func assetRoutes(svc *handler.Service) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Group(func(asset chi.Router) {
+			asset.Post("/", svc.CreateAsset)
+			asset.Get("/specifications", svc.GetAssetSpec)
+			asset.Get("/lookup", svc.LookupAsset)
+			asset.Get("/labels", handler.GetAssetLabels)
+			asset.Get("/", svc.GetAssetList)
+			asset.Put("/", handler.UpdateAsset)
+			asset.Post("/reassign", svc.ReassignAsset)
+			asset.Get("/brand", handler.AvailableAssets)
+
+			asset.Get("/employee", handler.EmployeeHistory)
+			asset.Put("/warranty", handler.UpdateWarranty)
+			asset.Put("/retrieve-asset", svc.RetrieveAsset)
+			asset.Post("/transfer", handler.TransferAsset)
+			asset.Get("/transfers", handler.AssetTransfers)
+			asset.Delete("/", svc.DeleteAsset)
+			asset.Put("/restore", svc.RestoreAsset)
+			asset.Get("/restores", handler.GetAssetRestores)
+
+			asset.Post("/reservation", handler.CreateReservation)
+			asset.Get("/reservation", handler.GetReservations)
+			asset.Get("/reservation/calendar.ics", handler.GetReservationCalendar)
+			asset.Put("/reservation/{reservationID}/cancel", handler.CancelReservation)
+			asset.Post("/reservation/{reservationID}/convert", handler.ConvertReservation)
+
+			asset.Post("/handover", handler.CreateHandover)
+			asset.Get("/handover", handler.GetHandovers)
+			asset.Post("/handover/{handoverID}/photo", handler.AddHandoverPhoto)
+			asset.Put("/handover/{handoverID}/acknowledge", handler.AcknowledgeHandover)
+			asset.Get("/handover/{handoverID}/receipt", handler.GetHandoverReceipt)
+
+			asset.Get("/reports", handler.GetAssetReports)
+			asset.Put("/reports", handler.ResolveAssetReport)
+
+			asset.Get("/loan/overdue", handler.GetOverdueLoans)
+			asset.Get("/loan/stats", handler.GetLoanStats)
+			asset.Post("/loan/extension", handler.RequestLoanExtension)
+			asset.Get("/loan/extension", handler.GetLoanExtensions)
+			asset.Put("/loan/extension", handler.DecideLoanExtension)
+		})
+	}
 }
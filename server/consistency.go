@@ -0,0 +1,15 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func consistencyRoutes(r chi.Router) {
+	r.Group(func(consistency chi.Router) {
+		consistency.Get("/", handler.GetInconsistencies)
+		consistency.Post("/repair/preview", handler.PreviewConsistencyRepair)
+		consistency.Post("/repair", handler.RepairConsistency)
+	})
+}
@@ -0,0 +1,16 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func departmentRoutes(r chi.Router) {
+	r.Group(func(department chi.Router) {
+		department.Post("/", handler.CreateDepartment)
+		department.Get("/", handler.GetDepartments)
+		department.Put("/", handler.UpdateDepartment)
+		department.Delete("/{departmentID}", handler.DeleteDepartment)
+	})
+}
@@ -2,19 +2,43 @@ package server
 
 import (
 	"InternalAssetManagement/handler"
+	"InternalAssetManagement/middlewares"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func employeeRoutes(r chi.Router) {
-	r.Group(func(employee chi.Router) {
-		employee.Post("/", handler.CreateEmployee)
-		employee.Get("/", handler.GetEmployeeList)
-		employee.Put("/", handler.UpdateEmployee)
-		employee.Delete("/{employeeID}", handler.DeleteEmployee)
+func employeeRoutes(svc *handler.Service) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Group(func(employee chi.Router) {
+			employee.Post("/", svc.CreateEmployee)
+			employee.Get("/", svc.GetEmployeeList)
+			employee.Put("/", svc.UpdateEmployee)
+			employee.Delete("/{employeeID}", svc.DeleteEmployee)
+			employee.Put("/{employeeID}/restore", svc.RestoreEmployee)
+			employee.Get("/restores", handler.GetEmployeeRestores)
 
-		employee.Get("/{employeeID}/info", handler.GetEmployeeMoreInfo)
-		employee.Post("/asset", handler.CreateEmployeeAssetRelation)
-		employee.Get("/asset-list", handler.GetAssetHistory)
-	})
+			employee.Get("/{employeeID}/info", svc.GetEmployeeMoreInfo)
+			employee.Post("/equipment-request", handler.CreateEquipmentRequestForEmployee)
+			employee.Get("/equipment-request", handler.GetEquipmentRequests)
+			employee.Get("/equipment-request/approval-step", handler.GetApprovalSteps)
+			employee.Post("/equipment-request/approval-step", handler.CreateApprovalStep)
+			employee.Delete("/equipment-request/approval-step/{stepID}", handler.DeleteApprovalStep)
+			employee.Get("/equipment-request/{requestID}", handler.GetEquipmentRequest)
+			employee.Put("/equipment-request/{requestID}/decision", handler.DecideEquipmentRequest)
+			employee.Post("/sync/csv/preview", handler.PreviewCSVSync)
+			employee.Post("/sync/csv", handler.ApplyCSVSync)
+			employee.Get("/sync/runs", handler.GetSyncRuns)
+			employee.Get("/sync/runs/{runID}", handler.GetSyncRun)
+			employee.Post("/asset", svc.CreateEmployeeAssetRelation)
+			employee.Get("/asset-list", svc.GetAssetHistory)
+		})
+		// routes that hand out credentials need a user session, an API key cannot mint credentials of its own
+		r.Group(func(credentials chi.Router) {
+			credentials.Use(middlewares.DenyAPIKeys)
+			credentials.Put("/{employeeID}/account", handler.CreateEmployeeAccount)
+			credentials.Post("/sync/token", handler.CreateProvisioningToken)
+			credentials.Get("/sync/token", handler.GetProvisioningTokens)
+			credentials.Delete("/sync/token/{tokenID}", handler.RevokeProvisioningToken)
+		})
+	}
 }
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+	"InternalAssetManagement/middlewares"
+	"InternalAssetManagement/utils"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// employeePortalRoutes are the self-service routes for employee accounts, kept apart from the admin routes
+func employeePortalRoutes(r chi.Router) {
+	r.Post("/login", handler.EmployeeLogin)
+	r.Group(func(portal chi.Router) {
+		portal.Use(middlewares.EmployeeAuthMiddleware)
+		portal.Put("/password", handler.UpdateEmployeePassword)
+		portal.Put("/log-out", handler.EmployeeLogout)
+
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsRead)).Get("/assets", handler.GetOwnAssets)
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsAcknowledge)).Put("/assets/{assetID}/acknowledge", handler.AcknowledgeAsset)
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsReport)).Post("/assets/{assetID}/report", handler.ReportAsset)
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsRead)).Get("/handover", handler.GetOwnHandovers)
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsRead)).Get("/handover/{handoverID}/receipt", handler.GetOwnHandoverReceipt)
+		portal.With(middlewares.RequirePermission(utils.PermissionOwnAssetsAcknowledge)).Put("/handover/{handoverID}/acknowledge", handler.AcknowledgeOwnHandover)
+		portal.With(middlewares.RequirePermission(utils.PermissionEquipmentRequest)).Post("/equipment-request", handler.CreateEquipmentRequest)
+		portal.With(middlewares.RequirePermission(utils.PermissionEquipmentRequest)).Get("/equipment-request", handler.GetOwnEquipmentRequests)
+		portal.With(middlewares.RequirePermission(utils.PermissionEquipmentRequest)).Get("/equipment-request/{requestID}", handler.GetOwnEquipmentRequest)
+		portal.With(middlewares.RequirePermission(utils.PermissionEquipmentRequest)).Put("/equipment-request/{requestID}/cancel", handler.CancelOwnEquipmentRequest)
+	})
+}
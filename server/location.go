@@ -0,0 +1,16 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func locationRoutes(r chi.Router) {
+	r.Group(func(location chi.Router) {
+		location.Post("/", handler.CreateLocation)
+		location.Get("/", handler.GetLocations)
+		location.Put("/", handler.UpdateLocation)
+		location.Delete("/{locationID}", handler.DeleteLocation)
+	})
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+	"InternalAssetManagement/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// scimRoutes serves SCIM 2.0 provisioning for identity providers, authenticated by provisioning tokens
+func scimRoutes(r chi.Router) {
+	r.Group(func(scim chi.Router) {
+		scim.Use(middlewares.ProvisioningAuthMiddleware)
+		scim.Get("/ServiceProviderConfig", handler.GetScimServiceProviderConfig)
+		scim.Get("/Users", handler.GetScimUsers)
+		scim.Post("/Users", handler.CreateScimUser)
+		scim.Get("/Users/{userID}", handler.GetScimUser)
+		scim.Put("/Users/{userID}", handler.ReplaceScimUser)
+		scim.Patch("/Users/{userID}", handler.PatchScimUser)
+		scim.Delete("/Users/{userID}", handler.DeleteScimUser)
+	})
+}
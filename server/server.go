@@ -2,9 +2,11 @@ package server
 
 import (
 	"InternalAssetManagement/handler"
+	"InternalAssetManagement/metrics"
 	"InternalAssetManagement/middlewares"
 	"InternalAssetManagement/utils"
 	"context"
+	"net"
 	"net/http"
 	"time"
 
@@ -14,6 +16,9 @@ import (
 type Server struct {
 	chi.Router
 	server *http.Server
+	// requests is the context requests are served with, cancelled once shutting down gives up waiting for them
+	requests       context.Context
+	cancelRequests context.CancelFunc
 }
 
 const (
@@ -22,41 +27,91 @@ const (
 	writeTimeout      = 5 * time.Minute
 )
 
-func SetupRoutes() *Server {
+func SetupRoutes(svc *handler.Service) *Server {
 	router := chi.NewRouter()
+	router.Use(middlewares.RequestID, middlewares.Tracing, middlewares.AccessLog, middlewares.Metrics)
+	router.Handle("/metrics", metrics.Handler())
 	// router.Use(middlewares.CommonMiddlewares()...)
 
 	router.Route("/asset-management", func(v1 chi.Router) {
 		v1.Use(middlewares.CommonMiddlewares()...)
-		v1.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-			utils.RespondJSON(w, http.StatusOK, struct {
-				Status string `json:"status"`
-			}{Status: "server is running!"})
+		v1.Group(apiRoutes(svc))
+		v1.Route("/scim/v2", scimRoutes)
+		v1.Route("/v2", func(v2 chi.Router) {
+			v2.Get("/openapi.json", handler.GetOpenAPISpec)
+			v2.Get("/docs", handler.GetAPIDocs)
+			v2.Group(func(api chi.Router) {
+				api.Use(middlewares.V2Naming)
+				api.Group(apiRoutes(svc))
+			})
 		})
-		v1.Route("/", func(public chi.Router) {
-			public.Post("/login", handler.LoginUser)
+	})
+	requests, cancelRequests := context.WithCancel(context.Background())
+	return &Server{
+		Router:         router,
+		requests:       requests,
+		cancelRequests: cancelRequests,
+	}
+}
+
+// apiRoutes are served both as v1 and, with consistent field naming, as v2 where openapi.Routes documents them
+func apiRoutes(svc *handler.Service) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/health", handler.Health)
+		r.Get("/health/live", handler.Liveness)
+		r.Get("/health/ready", handler.Readiness)
+		r.Route("/", func(public chi.Router) {
+			public.Post("/login", svc.LoginUser)
+			public.Get("/login/options", handler.GetLoginOptions)
+			public.Get("/sso/login", handler.SSOLogin)
+			public.Get("/sso/callback", handler.SSOCallback)
 		})
-		v1.Route("/user", func(user chi.Router) {
+		r.Route("/employee", employeePortalRoutes)
+		r.Route("/user", func(user chi.Router) {
 			user.Use(middlewares.AuthMiddleware)
-			user.Post("/register", handler.RegisterUser)
-			user.Get("/info", handler.GetUserDetails)
-			user.Get("/{userID}", handler.GetUserInfo)
-			user.Put("/info", handler.UpdateUser)
-			user.Get("/accessed-by", handler.AccessedByDetails)
-			user.Put("/accessed-by", handler.UpdateAccessedBy)
-			user.Get("/dashboard", handler.GetDashboard)
-			user.Put("/image", handler.AddProfileImage)
+			user.Group(func(account chi.Router) {
+				account.Use(middlewares.DenyAPIKeys)
+				account.Post("/register", svc.RegisterUser)
+				account.Get("/info", svc.GetUserDetails)
+				account.Get("/{userID}", svc.GetUserInfo)
+				account.Put("/info", svc.UpdateUser)
+				account.Get("/accessed-by", handler.AccessedByDetails)
+				account.Put("/accessed-by", handler.UpdateAccessedBy)
+				account.Put("/image", handler.AddProfileImage)
+				account.Route("/api-key", func(apiKey chi.Router) {
+					apiKey.Group(apiKeyRoutes)
+				})
+				account.Route("/webhook", func(webhook chi.Router) {
+					webhook.Group(webhookRoutes)
+				})
+				account.Route("/consistency", func(consistency chi.Router) {
+					consistency.Group(consistencyRoutes)
+				})
+				account.Put("/log-out", svc.Logout)
+			})
+			user.Group(func(dashboard chi.Router) {
+				dashboard.Use(middlewares.RequireScope(utils.ScopeDashboard))
+				dashboard.Get("/dashboard", handler.GetDashboard)
+				dashboard.Get("/dashboard/locations", handler.GetLocationDashboard)
+				dashboard.Get("/dashboard/departments", handler.GetDepartmentDashboard)
+			})
 			user.Route("/employee", func(employee chi.Router) {
-				employee.Group(employeeRoutes)
+				employee.Use(middlewares.RequireScope(utils.ScopeEmployees))
+				employee.Group(employeeRoutes(svc))
 			})
 			user.Route("/asset", func(asset chi.Router) {
-				asset.Group(assetRoutes)
+				asset.Use(middlewares.RequireScope(utils.ScopeAssets))
+				asset.Group(assetRoutes(svc))
+			})
+			user.Route("/location", func(location chi.Router) {
+				location.Use(middlewares.RequireScope(utils.ScopeLocations))
+				location.Group(locationRoutes)
+			})
+			user.Route("/department", func(department chi.Router) {
+				department.Use(middlewares.RequireScope(utils.ScopeDepartments))
+				department.Group(departmentRoutes)
 			})
-			user.Put("/log-out", handler.Logout)
 		})
-	})
-	return &Server{
-		Router: router,
 	}
 }
 
@@ -67,12 +122,16 @@ func (svc *Server) Run(port string) error {
 		ReadTimeout:       readTimeout,
 		ReadHeaderTimeout: readHeaderTimeout,
 		WriteTimeout:      writeTimeout,
+		BaseContext:       func(net.Listener) context.Context { return svc.requests },
 	}
 	return svc.server.ListenAndServe()
 }
 
+// Shutdown stops accepting requests and waits up to timeout for the ones being served, then cancels the context of
+// those still running so that their queries stop before the database is closed
 func (svc *Server) Shutdown(timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	defer svc.cancelRequests()
 	return svc.server.Shutdown(ctx)
 }
@@ -0,0 +1,361 @@
+package server
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/client"
+	"InternalAssetManagement/handler"
+	"InternalAssetManagement/logging"
+	"InternalAssetManagement/middlewares"
+	"InternalAssetManagement/models"
+	"InternalAssetManagement/openapi"
+	"InternalAssetManagement/repository"
+	"InternalAssetManagement/utils"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jmoiron/sqlx/types"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/volatiletech/null"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func loadSpec(t *testing.T) *openapi.Document {
+	t.Helper()
+	doc, err := openapi.Load(openapi.Spec())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// TestV2RoutesAreDocumented keeps openapi.Routes in step with the router, both ways and down to the handler
+func TestV2RoutesAreDocumented(t *testing.T) {
+	served := make(map[string]string)
+	err := chi.Walk(SetupRoutes(handler.NewService(repository.NewMemory())), func(method, route string, handler http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.HasPrefix(route, openapi.BasePath+"/") || strings.HasPrefix(route, "/asset-management/scim/") {
+			return nil
+		}
+		path := strings.TrimSuffix(strings.ReplaceAll(strings.TrimPrefix(route, openapi.BasePath), "/*", ""), "/")
+		if chain, ok := handler.(*chi.ChainHandler); ok {
+			handler = chain.Endpoint
+		}
+		// methods of handler.Service are named like handler.(*Service).CreateAsset-fm
+		name := strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(handler).Pointer()).Name(), "-fm")
+		served[method+" "+path] = name[strings.LastIndex(name, ".")+1:]
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, route := range openapi.Routes {
+		key := route.Method + " " + route.Path
+		handler, ok := served[key]
+		switch {
+		case !ok:
+			t.Errorf("%s is documented but not served", key)
+		case handler != route.Handler:
+			t.Errorf("%s is served by %s but documented as %s", key, handler, route.Handler)
+		}
+		delete(served, key)
+	}
+	for key := range served {
+		t.Errorf("%s is served but not documented", key)
+	}
+}
+
+// TestV2ResponsesMatchSpec passes a filled in value of every documented response type through the v2 naming and
+// validates the result against the spec
+func TestV2ResponsesMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+	for _, route := range openapi.Routes {
+		bodies := map[int]interface{}{http.StatusOK: route.Response, http.StatusBadRequest: utils.ClientError{}}
+		for status, body := range bodies {
+			if body == nil {
+				continue
+			}
+			sample := fill(reflect.TypeOf(body))
+			handler := middlewares.V2Naming(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				utils.RespondJSON(w, status, sample.Interface())
+			}))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(route.Method, openapi.BasePath+route.Path, nil))
+			err := doc.ValidateResponse(route.Method, route.Path, recorder.Code, recorder.Header(), recorder.Body.Bytes())
+			if err != nil {
+				t.Error(err)
+			}
+		}
+	}
+}
+
+// TestV2RequestsMatchSpec checks that every documented request body, written with v2 names, validates against the spec
+// and decodes back into the type the handler reads
+func TestV2RequestsMatchSpec(t *testing.T) {
+	doc := loadSpec(t)
+	for _, route := range openapi.Routes {
+		if route.Body == nil {
+			continue
+		}
+		sample := fill(reflect.TypeOf(route.Body))
+		encoded, err := json.Marshal(sample.Interface())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(route.Method, openapi.BasePath+route.Path, bytes.NewReader(utils.RenameJSONKeys(encoded)))
+		r.Header.Set("Content-Type", "application/json")
+		if err := doc.ValidateRequest(r); err != nil {
+			t.Error(err)
+			continue
+		}
+
+		decoded := reflect.New(sample.Type())
+		if err := utils.ParseBody(r.Body, decoded.Interface()); err != nil {
+			t.Errorf("%s: %v", route.Handler, err)
+			continue
+		}
+		if !reflect.DeepEqual(decoded.Elem().Interface(), sample.Interface()) {
+			t.Errorf("%s: v2 request body does not decode to the value it was written from", route.Handler)
+		}
+	}
+}
+
+// TestV2Requests serves the routes that need no database through the router and the generated client
+func TestV2Requests(t *testing.T) {
+	doc := loadSpec(t)
+	server := httptest.NewServer(SetupRoutes(handler.NewService(repository.NewMemory())))
+	defer server.Close()
+
+	for _, path := range []string{"/health", "/health/live", "/health/ready", "/login/options", "/openapi.json", "/docs", "/user/asset", "/employee/assets"} {
+		r, err := http.NewRequest(http.MethodGet, server.URL+openapi.BasePath+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.ValidateRequest(r); err != nil {
+			t.Error(err)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body bytes.Buffer
+		_, err = body.ReadFrom(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.ValidateResponse(http.MethodGet, path, resp.StatusCode, resp.Header, body.Bytes()); err != nil {
+			t.Error(err)
+		}
+	}
+
+	c := client.New(server.URL + openapi.BasePath)
+	ctx := context.Background()
+	if health, err := c.Health(ctx); err != nil || health.Status == "" {
+		t.Errorf("Health: %+v, %v", health, err)
+	}
+	if _, err := c.GetLoginOptions(ctx); err != nil {
+		t.Errorf("GetLoginOptions: %v", err)
+	}
+	var apiErr *client.Error
+	if _, err := c.GetAssetList(ctx, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized ||
+		apiErr.Code != string(apperr.TokenInvalid) {
+		t.Errorf("GetAssetList without a token: got %v, want a 401", err)
+	}
+}
+
+// TestRequestID checks that a request id is echoed or generated and ends up on the access log line
+func TestRequestID(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	router := SetupRoutes(handler.NewService(repository.NewMemory()))
+
+	for _, c := range []struct{ sent, want string }{
+		{"req-42", "req-42"},
+		{"", ""},
+		{"bad id\nwith a line break", ""},
+	} {
+		hook.Reset()
+		r := httptest.NewRequest(http.MethodGet, openapi.BasePath+"/health", nil)
+		if c.sent != "" {
+			r.Header.Set(middlewares.RequestIDHeader, c.sent)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		got := w.Header().Get(middlewares.RequestIDHeader)
+		if got == "" || c.want != "" && got != c.want || c.want == "" && got == c.sent {
+			t.Errorf("sent request id %q, got %q", c.sent, got)
+		}
+		entry := hook.LastEntry()
+		if entry == nil || entry.Data[logging.FieldRequestID] != got || entry.Data["route"] != openapi.BasePath+"/health" {
+			t.Errorf("sent request id %q, access log line %+v", c.sent, entry)
+		}
+	}
+}
+
+// TestMetrics checks that requests are counted under their route pattern and that the server is not ready without
+// a database
+func TestMetrics(t *testing.T) {
+	router := SetupRoutes(handler.NewService(repository.NewMemory()))
+	for _, path := range []string{openapi.BasePath + "/health/live", openapi.BasePath + "/health/ready", "/no/such/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics: %d", w.Code)
+	}
+	for _, series := range []string{
+		`asset_management_http_requests_total{method="GET",route="` + openapi.BasePath + `/health/live",status="200"}`,
+		`asset_management_http_requests_total{method="GET",route="` + openapi.BasePath + `/health/ready",status="503"}`,
+		`asset_management_http_requests_total{method="GET",route="unmatched",status="404"}`,
+		`asset_management_http_request_duration_seconds_count{method="GET",route="` + openapi.BasePath + `/health/live"}`,
+	} {
+		if !strings.Contains(w.Body.String(), series) {
+			t.Errorf("metrics have no %s", series)
+		}
+	}
+}
+
+// TestTracing checks that a request continues the trace of the caller in a span named after its route
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	hook := logtest.NewGlobal()
+	defer logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	r := httptest.NewRequest(http.MethodGet, openapi.BasePath+"/health/ready", nil)
+	r.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	SetupRoutes(handler.NewService(repository.NewMemory())).ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET "+openapi.BasePath+"/health/ready" || span.SpanContext().TraceID().String() != traceID ||
+		span.Status().Code != codes.Error {
+		t.Errorf("span %q of trace %s with status %v, want the route in the trace of the caller, failed as not ready",
+			span.Name(), span.SpanContext().TraceID(), span.Status())
+	}
+	if entry := hook.LastEntry(); entry == nil || entry.Data[logging.FieldTraceID] != traceID {
+		t.Errorf("access log line %+v has no trace id", entry)
+	}
+}
+
+var sampleTime = time.Date(2024, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+// fill returns a value of t with every field set, using the first allowed value for fields limited by oneof
+func fill(t reflect.Type) reflect.Value {
+	return fillTagged(t, "")
+}
+
+func fillTagged(t reflect.Type, validation string) reflect.Value {
+	v := reflect.New(t).Elem()
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		v.Set(reflect.ValueOf(sampleTime))
+		return v
+	case reflect.TypeOf(null.String{}):
+		v.Set(reflect.ValueOf(null.StringFrom(sampleString(validation))))
+		return v
+	case reflect.TypeOf(null.Time{}):
+		v.Set(reflect.ValueOf(null.TimeFrom(sampleTime)))
+		return v
+	case reflect.TypeOf(null.Int{}):
+		v.Set(reflect.ValueOf(null.IntFrom(3)))
+		return v
+	case reflect.TypeOf(null.Int64{}):
+		v.Set(reflect.ValueOf(null.Int64From(3)))
+		return v
+	case reflect.TypeOf(null.Bool{}):
+		v.Set(reflect.ValueOf(null.BoolFrom(true)))
+		return v
+	case reflect.TypeOf(null.Float64{}):
+		v.Set(reflect.ValueOf(null.Float64From(1.5)))
+		return v
+	case reflect.TypeOf(types.JSONText{}), reflect.TypeOf(json.RawMessage{}):
+		v.SetBytes([]byte(`{"assetTag":"RS-0001"}`))
+		return v
+	case reflect.TypeOf(models.AssetType("")):
+		v.SetString(string(models.Laptop))
+		return v
+	case reflect.TypeOf(apperr.Code("")):
+		v.SetString(string(apperr.ValidationFailed))
+		return v
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(t.Elem()))
+		v.Elem().Set(fillTagged(t.Elem(), validation))
+	case reflect.String:
+		v.SetString(sampleString(validation))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(3)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(3)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf("sample"))
+	case reflect.Slice:
+		item := validation
+		if i := strings.Index(item, "dive,"); i >= 0 {
+			item = item[i+len("dive,"):]
+		}
+		v.Set(reflect.Append(v, fillTagged(t.Elem(), item)))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(reflect.ValueOf("sample").Convert(t.Key()), fillTagged(t.Elem(), ""))
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+				continue
+			}
+			v.Field(i).Set(fillTagged(field.Type, field.Tag.Get("validate")))
+		}
+	default:
+		panic(fmt.Sprintf("fill: unsupported type %s", t))
+	}
+	return v
+}
+
+func sampleString(validation string) string {
+	for _, rule := range strings.Split(validation, ",") {
+		switch {
+		case strings.HasPrefix(rule, "oneof="):
+			return strings.Fields(strings.TrimPrefix(rule, "oneof="))[0]
+		case rule == "email":
+			return "someone@remotestate.com"
+		case rule == "url":
+			return "https://example.com/hook"
+		}
+	}
+	return "sample"
+}
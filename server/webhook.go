@@ -0,0 +1,19 @@
+package server
+
+import (
+	"InternalAssetManagement/handler"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func webhookRoutes(r chi.Router) {
+	r.Group(func(webhook chi.Router) {
+		webhook.Post("/", handler.CreateWebhookSubscription)
+		webhook.Get("/", handler.GetWebhookSubscriptions)
+		webhook.Put("/{subscriptionID}", handler.UpdateWebhookSubscription)
+		webhook.Delete("/{subscriptionID}", handler.DeleteWebhookSubscription)
+		webhook.Get("/deliveries", handler.GetWebhookDeliveries)
+		webhook.Get("/deliveries/{deliveryID}", handler.GetWebhookDelivery)
+		webhook.Post("/deliveries/{deliveryID}/redeliver", handler.RedeliverWebhook)
+	})
+}
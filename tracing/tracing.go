@@ -0,0 +1,71 @@
+// Package tracing configures OpenTelemetry tracing. Requests get a span from middlewares.Tracing and every SQL
+// statement run with their context gets a child span from the instrumented database driver, so that a slow request
+// shows which of its queries took the time
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+
+	serviceName         = "asset-management"
+	instrumentationName = "InternalAssetManagement"
+)
+
+// Configure installs the tracer provider for the exporter and returns the function flushing the spans not exported yet,
+// to call on shutdown. none, the default, records nothing; stdout prints spans for local development; otlp sends them
+// over OTLP/HTTP to OTEL_EXPORTER_OTLP_ENDPOINT. The standard OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES and
+// OTEL_TRACES_SAMPLER variables are honoured
+func Configure(ctx context.Context, exporter string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q, use %s, %s or %s", exporter, ExporterNone, ExporterStdout, ExporterOTLP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create the %s trace exporter: %w", exporter, err)
+	}
+
+	// the service name is a default, OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override it
+	serviceResource, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	if serviceResource, err = resource.Merge(serviceResource, resource.Environment()); err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(spanExporter), sdktrace.WithResource(serviceResource))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer the spans of the service are started with
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"InternalAssetManagement/models"
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalDateFormat     = "20060102"
+	icalDateTimeFormat = "20060102T150405Z"
+	icalLineLimit      = 75
+)
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// GenerateICS renders all-day events as an iCalendar (RFC 5545) feed
+func GenerateICS(calendarName string, events []models.CalendarEvent) []byte {
+	var buf bytes.Buffer
+	stamp := time.Now().UTC().Format(icalDateTimeFormat)
+
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//RemoteState//Asset Management//EN")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+	writeICSLine(&buf, "METHOD:PUBLISH")
+	writeICSLine(&buf, "X-WR-CALNAME:"+icalEscaper.Replace(calendarName))
+	for i := range events {
+		writeICSLine(&buf, "BEGIN:VEVENT")
+		writeICSLine(&buf, "UID:"+events[i].UID+"@asset-management")
+		writeICSLine(&buf, "DTSTAMP:"+stamp)
+		writeICSLine(&buf, "DTSTART;VALUE=DATE:"+events[i].StartDate.Format(icalDateFormat))
+		// the end of an all-day event is exclusive
+		writeICSLine(&buf, "DTEND;VALUE=DATE:"+events[i].EndDate.AddDate(0, 0, 1).Format(icalDateFormat))
+		writeICSLine(&buf, "SUMMARY:"+icalEscaper.Replace(events[i].Summary))
+		if events[i].Description != "" {
+			writeICSLine(&buf, "DESCRIPTION:"+icalEscaper.Replace(events[i].Description))
+		}
+		writeICSLine(&buf, "TRANSP:TRANSPARENT")
+		writeICSLine(&buf, "END:VEVENT")
+	}
+	writeICSLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICSLine folds content lines longer than 75 octets without splitting multi-byte characters
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space that counts towards the limit
+		limit = icalLineLimit - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"InternalAssetManagement/models"
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	LabelFormatPDF = "pdf"
+	LabelFormatZPL = "zpl"
+)
+
+// label sheet layout in mm, matches a 3x8 A4 sheet of 70x37 labels
+const (
+	labelColumns   = 3
+	labelRows      = 8
+	labelWidth     = 70.0
+	labelHeight    = 37.0
+	labelPadding   = 2.0
+	qrSize         = 22.0
+	barcodeHeight  = 8.0
+	barcodeWidth   = 40.0
+	qrPixels       = 256
+	barcodePixels  = 400
+	barcodePixelsH = 80
+)
+
+// GenerateLabelsPDF renders printable label sheets with a QR code and a Code128 barcode of the asset tag
+func GenerateLabelsPDF(labels []models.AssetLabel) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdfText := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, pageHeight := pdf.GetPageSize()
+	marginX := (pageWidth - labelColumns*labelWidth) / 2
+	marginY := (pageHeight - labelRows*labelHeight) / 2
+
+	for i := range labels {
+		position := i % (labelColumns * labelRows)
+		if position == 0 {
+			pdf.AddPage()
+		}
+		x := marginX + float64(position%labelColumns)*labelWidth
+		y := marginY + float64(position/labelColumns)*labelHeight
+
+		qrImage, err := barcodePNG(labels[i].AssetTag, true)
+		if err != nil {
+			return nil, err
+		}
+		barcodeImage, err := barcodePNG(labels[i].AssetTag, false)
+		if err != nil {
+			return nil, err
+		}
+
+		qrName := "qr-" + labels[i].ID
+		barcodeName := "barcode-" + labels[i].ID
+		imageOptions := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(qrName, imageOptions, bytes.NewReader(qrImage))
+		pdf.RegisterImageOptionsReader(barcodeName, imageOptions, bytes.NewReader(barcodeImage))
+		pdf.ImageOptions(qrName, x+labelPadding, y+labelPadding, qrSize, qrSize, false, imageOptions, 0, "")
+
+		textX := x + qrSize + 2*labelPadding
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.Text(textX, y+labelPadding+5, labels[i].AssetTag)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.Text(textX, y+labelPadding+10, pdfText(labels[i].Brand+" "+labels[i].Model))
+		pdf.Text(textX, y+labelPadding+14, pdfText(string(labels[i].AssetType)))
+		if labels[i].SerialNo != "" {
+			pdf.Text(textX, y+labelPadding+18, pdfText("S/N: "+labels[i].SerialNo))
+		}
+
+		pdf.ImageOptions(barcodeName, x+labelPadding, y+qrSize+2*labelPadding, barcodeWidth, barcodeHeight, false, imageOptions, 0, "")
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// GenerateLabelsZPL renders one ZPL label per asset using the printer's native QR and Code128 commands
+func GenerateLabelsZPL(labels []models.AssetLabel) []byte {
+	var zpl strings.Builder
+	for i := range labels {
+		tag := zplText(labels[i].AssetTag)
+		zpl.WriteString("^XA\n^CI28\n")
+		zpl.WriteString(fmt.Sprintf("^FO20,20^BQN,2,5^FDQA,%s^FS\n", tag))
+		zpl.WriteString(fmt.Sprintf("^FO200,30^A0N,34,34^FD%s^FS\n", tag))
+		zpl.WriteString(fmt.Sprintf("^FO200,75^A0N,24,24^FD%s^FS\n", zplText(labels[i].Brand+" "+labels[i].Model)))
+		zpl.WriteString(fmt.Sprintf("^FO200,105^A0N,24,24^FD%s^FS\n", zplText(string(labels[i].AssetType))))
+		if labels[i].SerialNo != "" {
+			zpl.WriteString(fmt.Sprintf("^FO200,135^A0N,24,24^FDS/N: %s^FS\n", zplText(labels[i].SerialNo)))
+		}
+		zpl.WriteString(fmt.Sprintf("^FO20,190^BY2^BCN,60,N,N,N^FD%s^FS\n", tag))
+		zpl.WriteString("^XZ\n")
+	}
+	return []byte(zpl.String())
+}
+
+// barcodePNG encodes content as a QR code or a Code128 barcode PNG image
+func barcodePNG(content string, isQR bool) ([]byte, error) {
+	var code barcode.Barcode
+	var err error
+	if isQR {
+		code, err = qr.Encode(content, qr.M, qr.Auto)
+		if err == nil {
+			code, err = barcode.Scale(code, qrPixels, qrPixels)
+		}
+	} else {
+		code, err = code128.Encode(content)
+		if err == nil {
+			code, err = barcode.Scale(code, barcodePixels, barcodePixelsH)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode barcode for %q: %w", content, err)
+	}
+
+	// the PDF writer only understands 8-bit images while barcodes are 16-bit grayscale
+	gray := image.NewGray(code.Bounds())
+	draw.Draw(gray, gray.Bounds(), code, code.Bounds().Min, draw.Src)
+
+	var out bytes.Buffer
+	if err := png.Encode(&out, gray); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// zplText strips the ZPL control characters from field data
+func zplText(text string) string {
+	return strings.NewReplacer("^", "", "~", "").Replace(text)
+}
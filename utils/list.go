@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/models"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// ListFilters reads the filters of a request for a list: those of Filters and the sort, filter and cursor parameters
+// over the fields of list.
+//
+//	sort=-purchaseDate                                orders by a field, descending with a leading -, and then by id
+//	filter=brand:eq:Dell,warrantyExpiry:lt:2026-12-31 keeps the rows that meet every field:op:value condition
+//	cursor=<nextCursor of the previous page>          starts after that page rather than at page
+//
+// A cursor keeps the sort it was made with so it can be passed on its own, the filters have to be repeated
+func ListFilters(r *http.Request, list models.ListSpec) (models.FiltersCheck, error) {
+	filters, err := Filters(r)
+	if err != nil {
+		return filters, apperr.New(apperr.InvalidInput, "invalid filters.", err)
+	}
+
+	query := r.URL.Query()
+	if filters.Sort, err = parseSort(list, query.Get("sort")); err != nil {
+		return filters, err
+	}
+	if filters.Conditions, err = parseConditions(list, query.Get("filter")); err != nil {
+		return filters, err
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := DecodeCursor(raw)
+		if err != nil || cursor.List != list.Name {
+			return filters, apperr.New(apperr.InvalidInput, "invalid cursor.", err)
+		}
+		if query.Get("sort") == "" {
+			filters.Sort, err = parseSort(list, cursor.Sort)
+			if err != nil {
+				return filters, apperr.New(apperr.InvalidInput, "invalid cursor.", err)
+			}
+		} else if cursor.Sort != filters.Sort.String() {
+			return filters, apperr.New(apperr.InvalidInput, "the cursor is of the sort "+cursor.Sort+".", nil)
+		}
+		filters.Cursor = &cursor
+	}
+	return filters, nil
+}
+
+// parseSort reads a sort such as -purchaseDate, an empty one is the default sort of list
+func parseSort(list models.ListSpec, raw string) (models.ListSort, error) {
+	if raw == "" {
+		return models.ListSort{Field: list.DefaultSort}, nil
+	}
+	sort := models.ListSort{Field: strings.TrimPrefix(raw, "-"), Desc: strings.HasPrefix(raw, "-")}
+	if _, ok := list.Fields[sort.Field]; !ok {
+		return sort, apperr.New(apperr.InvalidInput, "cannot sort by "+sort.Field+".", nil)
+	}
+	return sort, nil
+}
+
+// parseConditions reads comma separated field:op:value conditions, the value is what follows the second colon
+func parseConditions(list models.ListSpec, raw string) ([]models.Condition, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	conditions := make([]models.Condition, 0)
+	for _, part := range strings.Split(raw, ",") {
+		fields := strings.SplitN(part, ":", 3)
+		if len(fields) != 3 {
+			return nil, apperr.New(apperr.InvalidInput, "filter "+part+" is not field:op:value.", nil)
+		}
+		field, op, value := fields[0], fields[1], fields[2]
+		kind, ok := list.Fields[field]
+		if !ok {
+			return nil, apperr.New(apperr.InvalidInput, "cannot filter by "+field+".", nil)
+		}
+
+		condition := models.Condition{Field: field, Op: op}
+		var err error
+		switch op {
+		case models.OpEq, models.OpNe, models.OpLt, models.OpLte, models.OpGt, models.OpGte:
+			condition.Value, err = ListValue(kind, value)
+		case models.OpContains, models.OpIn:
+			if kind != models.FieldText {
+				return nil, apperr.New(apperr.InvalidInput, op+" only filters text fields, not "+field+".", nil)
+			}
+			condition.Value = value
+			if op == models.OpIn {
+				condition.Value = strings.Split(value, "|")
+			}
+		default:
+			return nil, apperr.New(apperr.InvalidInput, "unknown filter operator "+op+".", nil)
+		}
+		if err != nil {
+			return nil, apperr.New(apperr.InvalidInput, "invalid value "+value+" for "+field+".", err)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// ListValue converts raw to the Go type of a field of kind: dates and times are 2006-01-02 or RFC 3339 and numbers
+// are integers
+func ListValue(kind models.FieldKind, raw string) (interface{}, error) {
+	switch kind {
+	case models.FieldNumber:
+		return strconv.ParseInt(raw, 10, 64)
+	case models.FieldDate, models.FieldTime:
+		if value, err := time.Parse(dateLayout, raw); err == nil {
+			return value, nil
+		}
+		return time.Parse(time.RFC3339, raw)
+	}
+	return raw, nil
+}
+
+// EncodeCursor turns cursor into the opaque string clients pass back as the cursor parameter
+func EncodeCursor(cursor models.ListCursor) string {
+	encoded, err := json.Marshal(cursor)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor
+func DecodeCursor(raw string) (models.ListCursor, error) {
+	var cursor models.ListCursor
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, err
+	}
+	_, err = uuid.Parse(cursor.ID)
+	return cursor, err
+}
+
+// NextCursor is the cursor of the page that follows the row with id and sort key of a list read with filters
+func NextCursor(list models.ListSpec, filters *models.FiltersCheck, key, id string) string {
+	return EncodeCursor(models.ListCursor{List: list.Name, Sort: SortOf(list, filters).String(), Key: key, ID: id})
+}
+
+// SortOf is the sort of filters, the default sort of list when filters were not read by ListFilters
+func SortOf(list models.ListSpec, filters *models.FiltersCheck) models.ListSort {
+	if filters.Sort.Field == "" {
+		return models.ListSort{Field: list.DefaultSort}
+	}
+	return filters.Sort
+}
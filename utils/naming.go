@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// V2JSONName is the v2 spelling of a v1 JSON field name: lower camel case with "Id" for identifiers, so AssetType
+// becomes assetType, Id becomes id and employeeID becomes employeeId
+func V2JSONName(name string) string {
+	if strings.HasSuffix(name, "ID") {
+		name = strings.TrimSuffix(name, "ID") + "Id"
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	if r == utf8.RuneError || unicode.IsLower(r) {
+		return name
+	}
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// RenameJSONKeys rewrites every object key of a JSON document to its V2JSONName, leaving values and layout untouched
+func RenameJSONKeys(data []byte) []byte {
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out.WriteByte(data[i])
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(data) && data[end] != '"' {
+			if data[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(data) {
+			out.Write(data[i:])
+			break
+		}
+
+		next := end + 1
+		for next < len(data) && isJSONSpace(data[next]) {
+			next++
+		}
+		key := string(data[i+1 : end])
+		if next < len(data) && data[next] == ':' && !strings.Contains(key, `\`) {
+			out.WriteByte('"')
+			out.WriteString(V2JSONName(key))
+			out.WriteByte('"')
+		} else {
+			out.Write(data[i : end+1])
+		}
+		i = end + 1
+	}
+	return out.Bytes()
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
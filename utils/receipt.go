@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"InternalAssetManagement/models"
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	receiptDateFormat   = "02 Jan 2006"
+	receiptTimeFormat   = "02 Jan 2006 15:04:05 MST"
+	receiptMargin       = 20.0
+	receiptLabelWidth   = 50.0
+	receiptLineHeight   = 7.0
+	signatureWidth      = 60.0
+	receiptTitleSize    = 16
+	receiptHeadingSize  = 11
+	receiptBodySize     = 10
+	receiptFootnoteSize = 8
+)
+
+// GenerateHandoverReceipt renders the signed receipt of an asset handover as a PDF
+func GenerateHandoverReceipt(handover *models.Handover) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(receiptMargin, receiptMargin, receiptMargin)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	title := "Asset Handover Receipt"
+	if handover.Kind == HandoverRetrieval {
+		title = "Asset Return Receipt"
+	}
+	pdf.SetFont("Helvetica", "B", receiptTitleSize)
+	pdf.CellFormat(0, receiptLineHeight*2, title, "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", receiptFootnoteSize)
+	pdf.CellFormat(0, receiptLineHeight, "Receipt ID: "+handover.ID, "", 1, "C", false, 0, "")
+	pdf.Ln(receiptLineHeight)
+
+	row := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", receiptBodySize)
+		pdf.CellFormat(receiptLabelWidth, receiptLineHeight, label, "", 0, "", false, 0, "")
+		pdf.SetFont("Helvetica", "", receiptBodySize)
+		pdf.MultiCell(0, receiptLineHeight, tr(value), "", "", false)
+	}
+	heading := func(text string) {
+		pdf.Ln(receiptLineHeight / 2)
+		pdf.SetFont("Helvetica", "B", receiptHeadingSize)
+		pdf.CellFormat(0, receiptLineHeight, text, "B", 1, "", false, 0, "")
+		pdf.Ln(receiptLineHeight / 2)
+	}
+
+	heading("Asset")
+	row("Asset tag", handover.AssetTag)
+	row("Type", string(handover.AssetType))
+	row("Brand / model", handover.Brand+" "+handover.Model)
+	row("Serial number", handover.SerialNo)
+
+	heading("Employee")
+	row("Name", handover.EmployeeName)
+	row("Email", handover.EmployeeEmail)
+	row("Assigned on", handover.AssignedDate.Format(receiptDateFormat))
+	if handover.RetrievedDate.Valid {
+		row("Returned on", handover.RetrievedDate.Time.Format(receiptDateFormat))
+	}
+
+	heading("Condition")
+	conditionNotes := handover.ConditionNotes
+	if conditionNotes == "" {
+		conditionNotes = "No remarks"
+	}
+	row("Condition notes", conditionNotes)
+	accessories := "None"
+	if len(handover.Accessories) > 0 {
+		accessories = strings.Join(handover.Accessories, ", ")
+	}
+	row("Accessories", accessories)
+	for i := range handover.Photos {
+		row("Photo", handover.Photos[i])
+	}
+
+	heading("Acknowledgement")
+	row("Acknowledged by", handover.AcknowledgedName.String)
+	row("Acknowledged at", handover.AcknowledgedAt.Time.Format(receiptTimeFormat))
+	row("IP address", handover.AcknowledgedIP.String)
+
+	if len(handover.SignatureImage) > 0 {
+		imageType, err := SignatureImageType(handover.SignatureImage)
+		if err != nil {
+			return nil, err
+		}
+		imageOptions := gofpdf.ImageOptions{ImageType: imageType}
+		pdf.RegisterImageOptionsReader("signature", imageOptions, bytes.NewReader(handover.SignatureImage))
+		if pdfErr := pdf.Error(); pdfErr != nil {
+			return nil, pdfErr
+		}
+		pdf.Ln(receiptLineHeight / 2)
+		pdf.ImageOptions("signature", pdf.GetX()+receiptLabelWidth, pdf.GetY(), signatureWidth, 0, true, imageOptions, 0, "")
+	}
+
+	pdf.Ln(receiptLineHeight)
+	pdf.SetFont("Helvetica", "I", receiptFootnoteSize)
+	pdf.MultiCell(0, receiptLineHeight/2, tr("By acknowledging, the employee confirms the "+handover.Kind+" of the asset and accessories listed above in the stated condition."), "", "", false)
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// SignatureImageType returns the image type of a drawn signature, only PNG and JPEG are accepted
+func SignatureImageType(signature []byte) (string, error) {
+	switch http.DetectContentType(signature) {
+	case "image/png":
+		return "png", nil
+	case "image/jpeg":
+		return "jpg", nil
+	}
+	return "", errors.New("signature must be a PNG or JPEG image")
+}
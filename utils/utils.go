@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"InternalAssetManagement/apperr"
+	"InternalAssetManagement/logging"
 	"InternalAssetManagement/models"
 	"context"
 	"crypto/rand"
@@ -12,6 +14,7 @@ import (
 	"io"
 	"math/big"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"os"
 	"strconv"
@@ -22,6 +25,8 @@ import (
 	cloud "cloud.google.com/go/storage"
 	firebase "firebase.google.com/go"
 	"github.com/go-playground/validator/v10"
+	"github.com/sendgrid/sendgrid-go"
+	"github.com/sendgrid/sendgrid-go/helpers/mail"
 	"github.com/sirupsen/logrus"
 	"github.com/teris-io/shortid"
 	"golang.org/x/crypto/bcrypt"
@@ -44,14 +49,88 @@ const (
 	Mobile        = "mobile"
 	Sim           = "sim"
 	RemoteState   = "remote_state"
+	Client        = "client"
 	Available     = "available"
 	Assigned      = "assigned"
 	Deleted       = "deleted"
 	Active        = "active"
 	NotAnEmployee = "not_an_employee"
+
+	LocationSite     = "site"
+	LocationRoom     = "room"
+	LocationShelf    = "shelf"
+	LocationEmployee = "employee"
+
+	AssignmentPermanent = "permanent"
+	AssignmentLoan      = "loan"
+	ExtensionPending    = "pending"
+	ExtensionApproved   = "approved"
+	ExtensionRejected   = "rejected"
+
+	HandoverAssignment = "assignment"
+	HandoverRetrieval  = "retrieval"
+
+	RequestPending   = "pending"
+	RequestApproved  = "approved"
+	RequestRejected  = "rejected"
+	RequestFulfilled = "fulfilled"
+	RequestCancelled = "cancelled"
+)
+
+const defaultSenderEmail = "tushar.tushid@remotestate.com"
+
+const (
+	UserContextKey        Key = "userID"
+	EmployeeContextKey    Key = "employeeID"
+	PermissionsContextKey Key = "permissions"
+	TokenContextKey       Key = "tokenID"
+	APIKeyContextKey      Key = "apiKeyID"
+)
+
+const (
+	RoleEmployee = "employee"
+	RoleAdmin    = "admin"
+	// RoleViewer is a read only admin role
+	RoleViewer = "viewer"
+)
+
+const ScimContentType = "application/scim+json"
+
+const (
+	PermissionOwnAssetsRead        = "own-assets:read"
+	PermissionOwnAssetsAcknowledge = "own-assets:acknowledge"
+	PermissionOwnAssetsReport      = "own-assets:report"
+	PermissionEquipmentRequest     = "equipment:request"
+)
+
+// API key scopes are a resource with :read or :write, write includes read
+const (
+	ScopeAssets      = "assets"
+	ScopeEmployees   = "employees"
+	ScopeLocations   = "locations"
+	ScopeDepartments = "departments"
+	ScopeDashboard   = "dashboard"
+
+	ScopeRead  = ":read"
+	ScopeWrite = ":write"
 )
 
-const UserContextKey Key = "userID"
+// APIKeyScopes is every scope an API key can be granted
+var APIKeyScopes = []string{
+	ScopeAssets + ScopeRead, ScopeAssets + ScopeWrite,
+	ScopeEmployees + ScopeRead, ScopeEmployees + ScopeWrite,
+	ScopeLocations + ScopeRead, ScopeLocations + ScopeWrite,
+	ScopeDepartments + ScopeRead, ScopeDepartments + ScopeWrite,
+	ScopeDashboard + ScopeRead,
+}
+
+// EmployeePermissions is the permission set granted to employee self-service accounts
+var EmployeePermissions = []string{
+	PermissionOwnAssetsRead,
+	PermissionOwnAssetsAcknowledge,
+	PermissionOwnAssetsReport,
+	PermissionEquipmentRequest,
+}
 
 var generator *shortid.Shortid
 
@@ -59,6 +138,17 @@ const generatorSeed = 1000
 
 const DefaultLimit = 10
 
+const assetTagCompanyPrefix = "RS"
+
+var assetTagTypeCodes = map[models.AssetType]string{
+	models.Laptop:   "LAP",
+	models.Mouse:    "MOU",
+	models.Harddisk: "HDD",
+	models.Pendrive: "PEN",
+	models.Mobile:   "MOB",
+	models.Sim:      "SIM",
+}
+
 type FieldError struct {
 	Err validator.ValidationErrors
 }
@@ -75,13 +165,17 @@ func (q FieldError) GetSingleError() string {
 	return errorString
 }
 
-type clientError struct {
-	ID            string `json:"id"`
-	MessageToUser string `json:"messageToUser"`
-	DeveloperInfo string `json:"developerInfo"`
-	Err           string `json:"error"`
-	StatusCode    int    `json:"statusCode"`
-	IsClientError bool   `json:"isClientError"`
+// ClientError is the body of every error response, Code is the stable apperr code callers should branch on
+type ClientError struct {
+	ID            string              `json:"id"`
+	Code          apperr.Code         `json:"code"`
+	MessageToUser string              `json:"messageToUser"`
+	DeveloperInfo string              `json:"developerInfo"`
+	Err           string              `json:"error"`
+	StatusCode    int                 `json:"statusCode"`
+	IsClientError bool                `json:"isClientError"`
+	Fields        []apperr.FieldError `json:"fields,omitempty"`
+	Details       interface{}         `json:"details,omitempty"`
 }
 
 func init() {
@@ -120,41 +214,154 @@ func RespondJSON(w http.ResponseWriter, statusCode int, body interface{}) {
 	w.WriteHeader(statusCode)
 	if body != nil {
 		if err := EncodeJSONBody(w, body); err != nil {
-			logrus.Errorf("Failed to respond JSON with error: %+v", err)
+			logging.FromWriter(w).Errorf("Failed to respond JSON with error: %+v", err)
 		}
 	}
 }
 
 // newClientError creates structured client error response message
-func newClientError(err error, statusCode int, messageToUser string, additionalInfoForDevs ...string) *clientError {
+func newClientError(appErr *apperr.Error, additionalInfoForDevs ...string) *ClientError {
 	additionalInfoJoined := strings.Join(additionalInfoForDevs, "\n")
 	if additionalInfoJoined == "" {
-		additionalInfoJoined = messageToUser
+		additionalInfoJoined = appErr.Message
 	}
 
 	errorID, _ := generator.Generate()
 	var errString string
-	if err != nil {
-		errString = err.Error()
+	if appErr.Err != nil {
+		errString = appErr.Err.Error()
 	}
-	return &clientError{
+	return &ClientError{
 		ID:            errorID,
-		MessageToUser: messageToUser,
+		Code:          appErr.Code,
+		MessageToUser: appErr.Message,
 		DeveloperInfo: additionalInfoJoined,
 		Err:           errString,
-		StatusCode:    statusCode,
+		StatusCode:    appErr.Status(),
 		IsClientError: true,
+		Fields:        appErr.Fields,
+		Details:       appErr.Details,
 	}
 }
 
-// RespondError sends an error message to the API caller and logs the error
+// RespondError sends an error message to the API caller and logs the error. The code is derived from the status,
+// unless err already has one: a missing row reported as a 500 is sent as a 404 and validation errors list their fields
 func RespondError(w http.ResponseWriter, statusCode int, err error, messageToUser string, additionalInfoForDevs ...string) {
-	logrus.Errorf("status: %d, message: %s, err: %+v ", statusCode, messageToUser, err)
-	clientError := newClientError(err, statusCode, messageToUser, additionalInfoForDevs...)
-	w.WriteHeader(statusCode)
+	RespondAppError(w, apperr.FromStatus(statusCode, err, messageToUser), additionalInfoForDevs...)
+}
+
+// RespondAppError sends an error built with apperr, its code decides the status. Errors from anywhere else are
+// classified by apperr.From
+func RespondAppError(w http.ResponseWriter, err error, additionalInfoForDevs ...string) {
+	appErr := apperr.From(err)
+	clientError := newClientError(appErr, additionalInfoForDevs...)
+	entry := logging.FromWriter(w).WithFields(logrus.Fields{
+		"status":   appErr.Status(),
+		"code":     appErr.Code,
+		"error_id": clientError.ID,
+	})
+	if appErr.Err != nil {
+		entry = entry.WithError(appErr.Err)
+	}
+	if appErr.Status() >= http.StatusInternalServerError {
+		entry.Error(appErr.Message)
+	} else {
+		entry.Warn(appErr.Message)
+	}
+	w.WriteHeader(clientError.StatusCode)
 	if err := json.NewEncoder(w).Encode(clientError); err != nil {
-		logrus.Errorf("Failed to send error to caller with error: %+v", err)
+		logging.FromWriter(w).Errorf("Failed to send error to caller with error: %+v", err)
+	}
+}
+
+// RespondScimError sends an error in the SCIM error schema, used by the provisioning endpoints
+func RespondScimError(w http.ResponseWriter, statusCode int, err error, scimType, detail string) {
+	logging.FromWriter(w).WithError(err).WithField("status", statusCode).Error(detail)
+	w.Header().Set("Content-Type", ScimContentType)
+	w.WriteHeader(statusCode)
+	if err := EncodeJSONBody(w, models.ScimError{
+		Schemas:  []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+		Status:   strconv.Itoa(statusCode),
+		ScimType: scimType,
+		Detail:   detail,
+	}); err != nil {
+		logging.FromWriter(w).Errorf("Failed to send error to caller with error: %+v", err)
+	}
+}
+
+// ClientIP returns the address the request originated from. X-Forwarded-For is only honoured behind proxies, set with
+// TRUST_PROXY_HEADERS to true for one proxy or to the number of proxies in front of the server. Each proxy appends the
+// address it got the request from, so the client address is the one the outermost trusted proxy appended, counted
+// from the right: the entries left of it are written by the client and cannot be trusted
+func ClientIP(r *http.Request) string {
+	if hops := trustedProxies(); hops > 0 {
+		forwarded := make([]string, 0)
+		for _, header := range r.Header.Values("X-Forwarded-For") {
+			for _, address := range strings.Split(header, ",") {
+				forwarded = append(forwarded, strings.TrimSpace(address))
+			}
+		}
+		if len(forwarded) >= hops {
+			if ip := net.ParseIP(forwarded[len(forwarded)-hops]); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+	return host
+}
+
+// TrustProxyHeaders reports whether the server is behind proxies whose forwarding headers can be trusted
+func TrustProxyHeaders() bool {
+	return trustedProxies() > 0
+}
+
+// trustedProxies reads how many proxies in front of the server append to X-Forwarded-For from TRUST_PROXY_HEADERS
+func trustedProxies() int {
+	setting := os.Getenv("TRUST_PROXY_HEADERS")
+	if strings.EqualFold(setting, "true") {
+		return 1
+	}
+	hops, err := strconv.Atoi(setting)
+	if err != nil || hops < 0 {
+		return 0
+	}
+	return hops
+}
+
+// GenerateToken returns a random hex token of the given number of bytes
+func GenerateToken(size int) (string, error) {
+	token := make([]byte, size)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+func TokenContext(r *http.Request) (string, error) {
+	token := r.Context().Value(TokenContextKey)
+	tokenID, ok := token.(string)
+	if !ok {
+		return "", errors.New("unable to convert tokenID")
+	}
+	return tokenID, nil
+}
+
+// AssetTagPrefix returns the tag prefix for a given asset type e.g. RS-LAP
+func AssetTagPrefix(assetType models.AssetType) string {
+	code, ok := assetTagTypeCodes[assetType]
+	if !ok {
+		code = "GEN"
+	}
+	return assetTagCompanyPrefix + "-" + code
+}
+
+// FormatAssetTag builds a human-friendly asset tag like RS-LAP-00123
+func FormatAssetTag(prefix string, sequence int) string {
+	return fmt.Sprintf("%s-%05d", prefix, sequence)
 }
 
 // HashString generates SHA256 for a given string
@@ -195,6 +402,35 @@ func UserContext(r *http.Request) (string, error) {
 	return userID, nil
 }
 
+func EmployeeContext(r *http.Request) (string, error) {
+	employee := r.Context().Value(EmployeeContextKey)
+	employeeID, ok := employee.(string)
+	if !ok {
+		return "", errors.New("unable to convert employeeID")
+	}
+	return employeeID, nil
+}
+
+// IsAPIKeyRequest reports if the request was authenticated with an API key instead of a user session
+func IsAPIKeyRequest(r *http.Request) bool {
+	_, ok := r.Context().Value(APIKeyContextKey).(string)
+	return ok
+}
+
+// HasPermission checks if the permissions attached to the request context include the given permission
+func HasPermission(r *http.Request, permission string) bool {
+	permissions, ok := r.Context().Value(PermissionsContextKey).([]string)
+	if !ok {
+		return false
+	}
+	for i := range permissions {
+		if permissions[i] == permission {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckValidation returns the current validation status
 func CheckValidation(i interface{}) validator.ValidationErrors {
 	v := validator.New()
@@ -237,25 +473,25 @@ func UploadImage(request *http.Request) (string, error) {
 	// fmt.Println(credentialsFile)
 	app, err := firebase.NewApp(client.Ctx, nil, credentialsFile)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot initialize firebase app.")
 		return "", err
 	}
 
 	client.Client, err = app.Firestore(client.Ctx)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot create firestore client.")
 		return "", err
 	}
 
 	client.Storage, err = cloud.NewClient(client.Ctx, credentialsFile)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot create storage client.")
 		return "", err
 	}
 
 	file, fileHeader, err := request.FormFile("image")
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot read image from form.")
 		return "", err
 	}
 	var multiPartMinValue, multiPartMaxValue int64
@@ -263,7 +499,7 @@ func UploadImage(request *http.Request) (string, error) {
 	multiPartMaxValue = 20
 	err = request.ParseMultipartForm(multiPartMinValue << multiPartMaxValue)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot parse multipart form.")
 		return "", err
 	}
 
@@ -279,12 +515,12 @@ func UploadImage(request *http.Request) (string, error) {
 
 	_, err = io.Copy(bucketStorage, file)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot upload image.")
 		return "", err
 	}
 
 	if err1 := bucketStorage.Close(); err1 != nil {
-		logrus.Error(err1)
+		logging.FromContext(request.Context()).WithError(err1).Error("UploadImage: cannot close upload.")
 		return "", err
 	}
 
@@ -298,12 +534,32 @@ func UploadImage(request *http.Request) (string, error) {
 
 	url, err := client.Storage.Bucket(bucket).SignedURL(imagePath, signedURL)
 	if err != nil {
-		logrus.Error(err)
+		logging.FromContext(request.Context()).WithError(err).Error("UploadImage: cannot sign image url.")
 		return "", err
 	}
 	return url, nil
 }
 
+// SendEmail sends a single email through SendGrid
+func SendEmail(toName, toEmail, subject, plainTextContent, htmlContent string) error {
+	senderEmail := os.Getenv("sendgrid_from_email")
+	if senderEmail == "" {
+		senderEmail = defaultSenderEmail
+	}
+	from := mail.NewEmail("Asset Management", senderEmail)
+	to := mail.NewEmail(toName, toEmail)
+	message := mail.NewSingleEmail(from, subject, to, plainTextContent, htmlContent)
+	client := sendgrid.NewSendClient(os.Getenv("sendgrid_api_key"))
+	response, err := client.Send(message)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("sendgrid responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
 func ParamStrToBool(paramStrValue string) (bool, error) {
 	var paramValue bool
 	var err error
@@ -335,7 +591,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	} else {
 		limit, err = strconv.Atoi(strLimit)
 		if err != nil {
-			logrus.Printf("Limit: cannot get limit:%v", err)
+			logging.FromContext(r.Context()).WithError(err).Warn("Filters: cannot get limit.")
 			return filtersCheck, err
 		}
 	}
@@ -346,7 +602,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	} else {
 		pagination, err = strconv.ParseBool(strPagination)
 		if err != nil {
-			logrus.Printf("unable to convert string to bool:%v", err)
+			logging.FromContext(r.Context()).WithError(err).Warn("Filters: unable to convert string to bool.")
 			return filtersCheck, err
 		}
 	}
@@ -355,7 +611,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	strAvailable := r.URL.Query().Get("available")
 	availableAssets, err = ParamStrToBool(strAvailable)
 	if err != nil {
-		logrus.Printf("unable to convert string to bool:%v", err)
+		logging.FromContext(r.Context()).WithError(err).Warn("Filters: unable to convert string to bool.")
 		return filtersCheck, err
 	}
 
@@ -363,7 +619,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	strAssignedAsset := r.URL.Query().Get("assigned")
 	assignedAssets, err = ParamStrToBool(strAssignedAsset)
 	if err != nil {
-		logrus.Printf("unable to convert string to bool:%v", err)
+		logging.FromContext(r.Context()).WithError(err).Warn("Filters: unable to convert string to bool.")
 		return filtersCheck, err
 	}
 
@@ -372,7 +628,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	strDeleted := r.URL.Query().Get("deleted")
 	deleted, err = ParamStrToBool(strDeleted)
 	if err != nil {
-		logrus.Printf("unable to convert string to bool:%v", err)
+		logging.FromContext(r.Context()).WithError(err).Warn("Filters: unable to convert string to bool.")
 		return filtersCheck, err
 	}
 
@@ -381,7 +637,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	strNotAnEmployee := r.URL.Query().Get("notAnEmployee")
 	notAnEmployee, err = ParamStrToBool(strNotAnEmployee)
 	if err != nil {
-		logrus.Printf("unable to convert string to bool:%v", err)
+		logging.FromContext(r.Context()).WithError(err).Warn("Filters: unable to convert string to bool.")
 		return filtersCheck, err
 	}
 
@@ -397,13 +653,16 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 		assetTypes = filterTypes
 	}
 
+	locationID := r.URL.Query().Get("locationId")
+	departmentID := r.URL.Query().Get("departmentId")
+
 	warranty := r.URL.Query().Get("warranty")
 	if warranty == "" {
 		warrantyAsset = 0
 	} else {
 		warrantyAsset, err = strconv.Atoi(warranty)
 		if err != nil {
-			logrus.Printf("Warranty: cannot convert string to int")
+			logging.FromContext(r.Context()).WithError(err).Warn("Filters: cannot convert warranty to int.")
 			return filtersCheck, err
 		}
 		if warrantyAsset == 0 {
@@ -417,7 +676,7 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 	} else {
 		page, err = strconv.Atoi(strPage)
 		if err != nil {
-			logrus.Printf("Page: cannot get page:%v", err)
+			logging.FromContext(r.Context()).WithError(err).Warn("Filters: cannot get page.")
 			return filtersCheck, err
 		}
 	}
@@ -433,6 +692,8 @@ func Filters(r *http.Request) (models.FiltersCheck, error) {
 		Assigned:      assignedAssets,
 		NotAnEmployee: notAnEmployee,
 		Warranty:      warrantyAsset,
+		LocationID:    locationID,
+		DepartmentID:  departmentID,
 		IsExpired:     isExpired,
 		Pagination:    pagination}
 	return filtersCheck, nil
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		name      string
+		trust     string
+		forwarded []string
+		want      string
+	}{
+		{"proxy headers not trusted", "", []string{"10.0.0.1"}, "192.0.2.10"},
+		{"one proxy", "true", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"one proxy and a spoofed entry", "true", []string{"10.0.0.1, 198.51.100.7"}, "198.51.100.7"},
+		{"two proxies", "2", []string{"10.0.0.1, 198.51.100.7", "203.0.113.5"}, "198.51.100.7"},
+		{"fewer entries than proxies", "2", []string{"198.51.100.7"}, "192.0.2.10"},
+		{"not an address", "true", []string{"10.0.0.1, unknown"}, "192.0.2.10"},
+		{"no header", "true", nil, "192.0.2.10"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("TRUST_PROXY_HEADERS", c.trust)
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = "192.0.2.10:43210"
+			for _, forwarded := range c.forwarded {
+				r.Header.Add("X-Forwarded-For", forwarded)
+			}
+			if got := ClientIP(r); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}